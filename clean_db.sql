@@ -2,6 +2,18 @@
 SET session_replication_role = 'replica';
 
 -- Очищаем таблицы в правильном порядке
+TRUNCATE failed_messages CASCADE;
+TRUNCATE support_relays CASCADE;
+TRUNCATE support_messages CASCADE;
+TRUNCATE support_tickets CASCADE;
+TRUNCATE broadcasts CASCADE;
+TRUNCATE audit_log CASCADE;
+TRUNCATE conversation_states CASCADE;
+TRUNCATE subscription_notifications CASCADE;
+TRUNCATE subscription_status_history CASCADE;
+TRUNCATE ledger_entries CASCADE;
+TRUNCATE ledger_transactions CASCADE;
+TRUNCATE gift_codes CASCADE;
 TRUNCATE payments CASCADE;
 TRUNCATE subscriptions CASCADE;
 TRUNCATE users CASCADE;
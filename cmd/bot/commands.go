@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ilokitv/botVPN/internal/database"
+)
+
+// commandUsage - подсказка по командам обслуживания
+const commandUsage = "usage: vpnbot [-config path] migrate up|down|status"
+
+// runCommand выполняет команду обслуживания, переданную после флагов
+func runCommand(db *database.DB, args []string) error {
+	if len(args) != 2 || args[0] != "migrate" {
+		return errors.New(commandUsage)
+	}
+
+	switch args[1] {
+	case "up":
+		applied, err := db.MigrateUp()
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("Схема базы данных актуальна, новых миграций нет")
+			return nil
+		}
+		for _, migration := range applied {
+			fmt.Printf("Применена миграция %s\n", migration)
+		}
+
+	case "down":
+		migration, err := db.MigrateDown()
+		if err != nil {
+			return err
+		}
+		if migration == nil {
+			fmt.Println("Нет примененных миграций")
+			return nil
+		}
+		fmt.Printf("Откачена миграция %s\n", migration)
+
+	case "status":
+		statuses, err := db.MigrationStatus()
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "не применена"
+			if status.AppliedAt != nil {
+				state = "применена " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if status.Unknown {
+				state += " (неизвестна этой версии бота)"
+			}
+			fmt.Printf("%04d_%-24s %s\n", status.Version, status.Name, state)
+		}
+
+	default:
+		return errors.New(commandUsage)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
 	"os"
@@ -11,18 +12,32 @@ import (
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
+	"github.com/ilokitv/botVPN/internal/callbackdata"
 	"github.com/ilokitv/botVPN/internal/config"
 	"github.com/ilokitv/botVPN/internal/database"
+	"github.com/ilokitv/botVPN/internal/dispatcher"
 	"github.com/ilokitv/botVPN/internal/handlers"
+	"github.com/ilokitv/botVPN/internal/i18n"
 	"github.com/ilokitv/botVPN/internal/scheduler"
+	"github.com/ilokitv/botVPN/internal/sender"
+	"github.com/ilokitv/botVPN/internal/service"
 	"github.com/ilokitv/botVPN/internal/vpn"
+	"github.com/ilokitv/botVPN/internal/webhook"
 )
 
+// webhookShutdownTimeout - сколько ждать завершения запросов к вебхуку при остановке бота
+const webhookShutdownTimeout = 10 * time.Second
+
 func main() {
 	// Парсим аргументы командной строки
 	configPath := flag.String("config", "config.yaml", "путь к файлу конфигурации")
 	flag.Parse()
 
+	// Проверяем, что каталоги сообщений на всех языках согласованы
+	if err := i18n.Validate(); err != nil {
+		log.Fatalf("Ошибка в каталогах сообщений: %v", err)
+	}
+
 	// Загружаем конфигурацию
 	cfg, err := config.Load(*configPath)
 	if err != nil {
@@ -36,10 +51,22 @@ func main() {
 	}
 	defer db.Close()
 
-	// Инициализируем таблицы базы данных
-	err = db.InitTables()
-	if err != nil {
-		log.Fatalf("Ошибка инициализации таблиц базы данных: %v", err)
+	// Команды обслуживания (например, "migrate up") выполняются вместо запуска бота
+	if flag.NArg() > 0 {
+		if err := runCommand(db, flag.Args()); err != nil {
+			log.Fatalf("Ошибка выполнения команды: %v", err)
+		}
+		return
+	}
+
+	// Бот не запускается, пока схема базы данных не соответствует его версии
+	if err := db.CheckMigrations(); err != nil {
+		log.Fatalf("Ошибка проверки схемы базы данных: %v", err)
+	}
+
+	// Пользователи из admin_ids получают роль владельца, остальные роли назначаются из бота
+	if err := db.BootstrapOwners(cfg.Bot.AdminIDs); err != nil {
+		log.Fatalf("Ошибка назначения владельцев бота: %v", err)
 	}
 
 	// Создаем директорию для хранения конфигураций VPN
@@ -54,6 +81,20 @@ func main() {
 	// Инициализируем менеджер VPN
 	vpnManager := vpn.NewWireguardManager(configDir)
 
+	// Жизненный цикл подписок: выдача, продление, пауза, льготный период, истечение и отзыв
+	subscriptions := service.NewSubscriptions(db, vpnManager)
+
+	// Валюта счетов проверяется до запуска, чтобы не выставлять счета в неподдерживаемой валюте
+	if _, err := cfg.Payments.GetCurrency(); err != nil {
+		log.Fatalf("Ошибка в настройках платежей: %v", err)
+	}
+
+	// Способ получения обновлений проверяется до запуска, чтобы ошибка в настройках не оставила бота без обновлений
+	mode, err := cfg.Bot.GetMode()
+	if err != nil {
+		log.Fatalf("Ошибка в настройках получения обновлений: %v", err)
+	}
+
 	// Инициализируем Telegram бота
 	bot, err := tgbotapi.NewBotAPI(cfg.Bot.Token)
 	if err != nil {
@@ -62,15 +103,69 @@ func main() {
 
 	log.Printf("Бот запущен: %s", bot.Self.UserName)
 
+	// Все сообщения отправляются через общую очередь с ограничением скорости, чтобы не упираться в лимиты Telegram
+	messageSender := sender.New(bot, db, cfg.Sender)
+
+	// Расписание напоминаний об окончании подписки
+	reminderOffsets, err := cfg.Notifications.GetReminderOffsets()
+	if err != nil {
+		log.Fatalf("Ошибка в настройках напоминаний: %v", err)
+	}
+
+	// Данные инлайн-кнопок подписываются, чтобы их нельзя было подделать
+	callbackSigner := callbackdata.NewSigner(cfg.Bot.GetCallbackSecret())
+
 	// Инициализируем и запускаем планировщик проверки подписок
 	// Проверка будет выполняться каждый час
-	subscriptionChecker := scheduler.NewSubscriptionChecker(db, vpnManager, bot, 1*time.Hour)
+	subscriptionChecker := scheduler.NewSubscriptionChecker(db, subscriptions, messageSender, callbackSigner, 1*time.Hour, reminderOffsets)
 	subscriptionChecker.Start()
 	defer subscriptionChecker.Stop()
 	log.Println("Планировщик проверки подписок запущен и будет выполняться каждый час")
 
-	// Создаем обработчик бота
-	botHandler := handlers.NewBotHandler(bot, db, vpnManager, cfg)
+	// Создаем хранилище состояний диалогов
+	stateStorage, err := cfg.Bot.GetStateStorage()
+	if err != nil {
+		log.Fatalf("Ошибка в настройках хранилища состояний: %v", err)
+	}
+	stateTTL, err := cfg.Bot.GetStateTTL()
+	if err != nil {
+		log.Fatalf("Ошибка в настройках хранилища состояний: %v", err)
+	}
+
+	var states handlers.StateStore
+	if stateStorage == config.StateStorageMemory {
+		states = handlers.NewMemoryStateStore(stateTTL)
+	} else {
+		states = handlers.NewDBStateStore(db, stateTTL)
+	}
+
+	// Контекст отменяется при получении сигнала завершения работы
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	// Создаем обработчик бота и продолжаем рассылки, прерванные прошлой остановкой
+	botHandler := handlers.NewBotHandler(ctx, bot, db, vpnManager, subscriptions, cfg, states, callbackSigner, messageSender)
+	botHandler.ResumeBroadcasts()
+
+	// Обрабатываем обновления: сообщения одного чата по порядку, разных чатов параллельно
+	updateDispatcher := dispatcher.New(cfg.Bot.GetWorkers(), cfg.Bot.GetQueueSize(), botHandler.HandleUpdate)
+	log.Printf("Обработка обновлений запущена: обработчиков %d, размер очереди %d", cfg.Bot.GetWorkers(), cfg.Bot.GetQueueSize())
+
+	if mode == config.ModeWebhook {
+		runWebhook(ctx, bot, cfg, updateDispatcher)
+	} else {
+		runPolling(ctx, bot, updateDispatcher)
+	}
+	botHandler.Wait()
+	log.Println("Бот остановлен")
+}
+
+// runPolling получает обновления через long polling, пока не будет отменен ctx
+func runPolling(ctx context.Context, bot *tgbotapi.BotAPI, updateDispatcher *dispatcher.Dispatcher) {
+	// Пока зарегистрирован вебхук, getUpdates возвращает ошибку, поэтому при переходе с вебхука он удаляется
+	if _, err := bot.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		log.Fatalf("Ошибка удаления вебхука: %v", err)
+	}
 
 	// Настраиваем обновления
 	updateConfig := tgbotapi.NewUpdate(0)
@@ -78,19 +173,51 @@ func main() {
 
 	// Получаем канал обновлений
 	updates := bot.GetUpdatesChan(updateConfig)
+	log.Println("Получение обновлений через long polling")
 
-	// Канал для сигналов завершения работы
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
-
-	// Обрабатываем обновления
-	for {
-		select {
-		case update := <-updates:
-			botHandler.HandleUpdate(update)
-		case <-stop:
-			log.Println("Завершение работы бота...")
-			return
-		}
+	go func() {
+		<-ctx.Done()
+		log.Println("Завершение работы бота...")
+		bot.StopReceivingUpdates()
+	}()
+
+	// Run возвращается после отмены контекста, когда обработаны все обновления из очередей
+	updateDispatcher.Run(ctx, updates)
+}
+
+// runWebhook принимает обновления через вебхук, пока не будет отменен ctx
+func runWebhook(ctx context.Context, bot *tgbotapi.BotAPI, cfg *config.Config, updateDispatcher *dispatcher.Dispatcher) {
+	secret, err := cfg.Bot.GetWebhookSecret()
+	if err != nil {
+		log.Fatalf("Ошибка в настройках вебхука: %v", err)
+	}
+
+	webhookCfg := cfg.Bot.Webhook
+	server, err := webhook.New(bot, webhookCfg.URL, webhookCfg.GetListen(), secret, webhookCfg.CertFile, webhookCfg.KeyFile)
+	if err != nil {
+		log.Fatalf("Ошибка в настройках вебхука: %v", err)
 	}
+	if err := server.Start(); err != nil {
+		log.Fatalf("Ошибка запуска сервера вебхука: %v", err)
+	}
+	if err := server.Register(webhookCfg.URL, webhookCfg.SelfSigned); err != nil {
+		log.Fatalf("Ошибка регистрации вебхука: %v", err)
+	}
+	log.Printf("Получение обновлений через вебхук %s (сервер слушает %s)", webhookCfg.URL, webhookCfg.GetListen())
+
+	go func() {
+		<-ctx.Done()
+		log.Println("Завершение работы бота...")
+
+		// Вебхук не удаляется: пока бот остановлен, Telegram накапливает обновления и доставит их после запуска
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), webhookShutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Ошибка остановки сервера вебхука: %v", err)
+		}
+	}()
+
+	// Канал обновлений закрывается после остановки сервера, и Run возвращается,
+	// когда обработаны все обновления, подтвержденные Telegram
+	updateDispatcher.Run(context.Background(), server.Updates())
 }
@@ -0,0 +1,207 @@
+// Package analytics содержит расчеты аналитических отчетов, не зависящие от базы данных и Telegram
+package analytics
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ilokitv/botVPN/internal/i18n"
+	"github.com/ilokitv/botVPN/internal/models"
+	"github.com/ilokitv/botVPN/internal/money"
+)
+
+// RetentionMonths - через сколько месяцев после регистрации проверяется удержание
+var RetentionMonths = []int{1, 2, 3}
+
+// Cohort представляет пользователей, зарегистрировавшихся в течение одной недели
+type Cohort struct {
+	WeekStart time.Time // Понедельник недели регистрации (UTC)
+	Users     int       // Количество пользователей в когорте
+	// Retained[i] - количество пользователей с действующей платной подпиской через RetentionMonths[i] месяцев
+	// после регистрации, или -1, если этот момент еще не наступил хотя бы для одного пользователя когорты
+	Retained []int
+	Revenue  money.Totals // Суммарные платежи пользователей когорты по валютам
+}
+
+// RetentionRate возвращает процент удержания для i-го контрольного срока или -1, если данных еще нет
+func (c Cohort) RetentionRate(i int) float64 {
+	if c.Users == 0 || c.Retained[i] < 0 {
+		return -1
+	}
+	return float64(c.Retained[i]) * 100 / float64(c.Users)
+}
+
+// LTV возвращает накопленную выручку на одного пользователя когорты в каждой валюте
+func (c Cohort) LTV() money.Totals {
+	ltv := make(money.Totals, len(c.Revenue))
+	if c.Users == 0 {
+		return ltv
+	}
+	for currency, revenue := range c.Revenue {
+		ltv[currency] = money.Prorate(revenue, 1, int64(c.Users))
+	}
+	return ltv
+}
+
+// WeekStart возвращает начало недели (понедельник, 00:00 UTC) для указанного момента.
+// Недели считаются в UTC, как и интервалы отчета о доходах в базе данных, поэтому регистрация
+// около полуночи попадает в одну и ту же неделю независимо от часового пояса сервера и СУБД.
+func WeekStart(t time.Time) time.Time {
+	t = t.UTC()
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	year, month, day := t.Date()
+	return time.Date(year, month, day-daysSinceMonday, 0, 0, 0, 0, time.UTC)
+}
+
+// isPaying проверяет, есть ли у пользователя платная подписка, действующая в момент at
+func isPaying(subscriptions []models.Subscription, at time.Time) bool {
+	for _, subscription := range subscriptions {
+		if subscription.IsTrial {
+			continue
+		}
+		if !subscription.StartDate.After(at) && subscription.EndDate.After(at) {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildCohorts группирует пользователей по неделям регистрации и считает удержание и накопленную выручку.
+// Пробные подписки не считаются платными. В payments должны передаваться только реальные поступления.
+func BuildCohorts(users []models.User, subscriptions []models.Subscription, payments []models.Payment, now time.Time) []Cohort {
+	subscriptionsByUser := make(map[int][]models.Subscription)
+	for _, subscription := range subscriptions {
+		subscriptionsByUser[subscription.UserID] = append(subscriptionsByUser[subscription.UserID], subscription)
+	}
+
+	revenueByUser := make(map[int]money.Totals)
+	for _, payment := range payments {
+		if revenueByUser[payment.UserID] == nil {
+			revenueByUser[payment.UserID] = make(money.Totals)
+		}
+		revenueByUser[payment.UserID].Add(payment.Currency, payment.Amount)
+	}
+
+	cohorts := make(map[time.Time]*Cohort)
+	for _, user := range users {
+		week := WeekStart(user.CreatedAt)
+		cohort, ok := cohorts[week]
+		if !ok {
+			cohort = &Cohort{
+				WeekStart: week,
+				Retained:  make([]int, len(RetentionMonths)),
+				Revenue:   make(money.Totals),
+			}
+			cohorts[week] = cohort
+		}
+
+		cohort.Users++
+		for currency, amount := range revenueByUser[user.ID] {
+			cohort.Revenue.Add(currency, amount)
+		}
+
+		for i, months := range RetentionMonths {
+			if cohort.Retained[i] < 0 {
+				continue
+			}
+			checkpoint := user.CreatedAt.AddDate(0, months, 0)
+			if checkpoint.After(now) {
+				cohort.Retained[i] = -1
+				continue
+			}
+			if isPaying(subscriptionsByUser[user.ID], checkpoint) {
+				cohort.Retained[i]++
+			}
+		}
+	}
+
+	result := make([]Cohort, 0, len(cohorts))
+	for _, cohort := range cohorts {
+		result = append(result, *cohort)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].WeekStart.Before(result[j].WeekStart) })
+	return result
+}
+
+// FormatCohortTable формирует текстовую таблицу когорт для моноширинного вывода на языке lang
+func FormatCohortTable(lang string, cohorts []Cohort) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("%-10s %5s", i18n.T(lang, "cohorts.column_week"), i18n.T(lang, "cohorts.column_users")))
+	for _, months := range RetentionMonths {
+		sb.WriteString(fmt.Sprintf(" %5s", fmt.Sprintf("M%d", months)))
+	}
+	sb.WriteString(" LTV\n")
+
+	for _, cohort := range cohorts {
+		sb.WriteString(fmt.Sprintf("%-10s %5d", cohort.WeekStart.Format("02.01.06"), cohort.Users))
+		for i := range RetentionMonths {
+			rate := cohort.RetentionRate(i)
+			if rate < 0 {
+				sb.WriteString(fmt.Sprintf(" %5s", "-"))
+			} else {
+				sb.WriteString(fmt.Sprintf(" %4.0f%%", rate))
+			}
+		}
+		sb.WriteString(" " + cohort.LTV().String() + "\n")
+	}
+
+	return sb.String()
+}
+
+// cohortCurrencies возвращает валюты, в которых есть выручка хотя бы одной когорты (рубли выводятся всегда)
+func cohortCurrencies(cohorts []Cohort) []string {
+	all := money.Totals{money.RUB: 0}
+	for _, cohort := range cohorts {
+		for currency := range cohort.Revenue {
+			all[currency] = 0
+		}
+	}
+	return all.Currencies()
+}
+
+// CohortsCSV формирует CSV-выгрузку когорт. Выручка и LTV выводятся отдельными столбцами для каждой валюты
+// в основных единицах валюты.
+func CohortsCSV(cohorts []Cohort) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	currencies := cohortCurrencies(cohorts)
+
+	header := []string{"week_start", "users"}
+	for _, months := range RetentionMonths {
+		header = append(header, fmt.Sprintf("retained_m%d", months), fmt.Sprintf("retention_m%d_percent", months))
+	}
+	for _, currency := range currencies {
+		header = append(header, "revenue_"+strings.ToLower(currency), "ltv_"+strings.ToLower(currency))
+	}
+
+	rows := [][]string{header}
+	for _, cohort := range cohorts {
+		row := []string{cohort.WeekStart.Format("2006-01-02"), strconv.Itoa(cohort.Users)}
+		for i := range RetentionMonths {
+			if cohort.Retained[i] < 0 {
+				row = append(row, "", "")
+				continue
+			}
+			row = append(row, strconv.Itoa(cohort.Retained[i]),
+				strconv.FormatFloat(cohort.RetentionRate(i), 'f', 1, 64))
+		}
+		ltv := cohort.LTV()
+		for _, currency := range currencies {
+			row = append(row,
+				money.Decimal(cohort.Revenue[currency], currency),
+				money.Decimal(ltv[currency], currency))
+		}
+		rows = append(rows, row)
+	}
+
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
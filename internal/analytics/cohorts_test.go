@@ -0,0 +1,166 @@
+package analytics
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ilokitv/botVPN/internal/i18n"
+	"github.com/ilokitv/botVPN/internal/models"
+	"github.com/ilokitv/botVPN/internal/money"
+)
+
+var moscow = time.FixedZone("MSK", 3*60*60)
+
+func utc(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestWeekStart(t *testing.T) {
+	tests := []struct {
+		name string
+		at   time.Time
+		want time.Time
+	}{
+		{name: "monday midnight", at: utc(2024, 5, 20, 0, 0), want: utc(2024, 5, 20, 0, 0)},
+		{name: "midweek", at: utc(2024, 5, 22, 15, 30), want: utc(2024, 5, 20, 0, 0)},
+		{name: "sunday before midnight", at: time.Date(2024, 5, 26, 23, 59, 59, 0, time.UTC), want: utc(2024, 5, 20, 0, 0)},
+		{name: "next monday", at: utc(2024, 5, 27, 0, 0), want: utc(2024, 5, 27, 0, 0)},
+		// 01:00 понедельника по Москве - это еще воскресенье в UTC
+		{name: "local monday is utc sunday", at: time.Date(2024, 5, 27, 1, 0, 0, 0, moscow), want: utc(2024, 5, 20, 0, 0)},
+		{name: "local sunday is utc sunday", at: time.Date(2024, 5, 26, 23, 30, 0, 0, moscow), want: utc(2024, 5, 20, 0, 0)},
+		{name: "across month", at: utc(2024, 6, 2, 12, 0), want: utc(2024, 5, 27, 0, 0)},
+		{name: "across year", at: utc(2025, 1, 1, 8, 0), want: utc(2024, 12, 30, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WeekStart(tt.at)
+			if !got.Equal(tt.want) || got.Location() != time.UTC {
+				t.Errorf("WeekStart(%s) = %s, want %s", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsPaying(t *testing.T) {
+	at := utc(2024, 6, 20, 12, 0)
+	subscription := func(start, end time.Time, trial bool) []models.Subscription {
+		return []models.Subscription{{StartDate: start, EndDate: end, IsTrial: trial}}
+	}
+
+	tests := []struct {
+		name          string
+		subscriptions []models.Subscription
+		want          bool
+	}{
+		{name: "active paid", subscriptions: subscription(at.AddDate(0, 0, -10), at.AddDate(0, 0, 20), false), want: true},
+		{name: "starts at checkpoint", subscriptions: subscription(at, at.AddDate(0, 0, 30), false), want: true},
+		{name: "ends at checkpoint", subscriptions: subscription(at.AddDate(0, 0, -30), at, false), want: false},
+		{name: "starts after checkpoint", subscriptions: subscription(at.Add(time.Minute), at.AddDate(0, 0, 30), false), want: false},
+		{name: "trial", subscriptions: subscription(at.AddDate(0, 0, -1), at.AddDate(0, 0, 2), true), want: false},
+		{name: "none", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isPaying(tt.subscriptions, at); got != tt.want {
+				t.Errorf("isPaying() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildCohorts(t *testing.T) {
+	now := utc(2024, 8, 30, 12, 0)
+	week1 := utc(2024, 5, 20, 0, 0)
+	week2 := utc(2024, 5, 27, 0, 0)
+
+	users := []models.User{
+		{ID: 1, CreatedAt: utc(2024, 5, 20, 10, 0)},
+		{ID: 2, CreatedAt: time.Date(2024, 5, 27, 1, 0, 0, 0, moscow)}, // Воскресенье 22:00 UTC - первая неделя
+		{ID: 3, CreatedAt: utc(2024, 5, 27, 0, 0)},
+		{ID: 4, CreatedAt: utc(2024, 7, 20, 9, 0)}, // Прошел только первый месяц
+	}
+	subscriptions := []models.Subscription{
+		// Пользователь 1 платит первые два месяца
+		{UserID: 1, StartDate: utc(2024, 5, 20, 10, 0), EndDate: utc(2024, 7, 25, 0, 0)},
+		// Пользователь 2 пользовался только пробным периодом
+		{UserID: 2, StartDate: utc(2024, 6, 20, 0, 0), EndDate: utc(2024, 7, 10, 0, 0), IsTrial: true},
+		// Пользователь 3 платит с третьего месяца
+		{UserID: 3, StartDate: utc(2024, 7, 1, 0, 0), EndDate: utc(2024, 9, 1, 0, 0)},
+		// Подписка пользователя 4 заканчивается ровно в контрольный момент первого месяца
+		{UserID: 4, StartDate: utc(2024, 7, 20, 9, 0), EndDate: utc(2024, 8, 20, 9, 0)},
+	}
+	payments := []models.Payment{
+		{UserID: 1, Amount: 19900, Currency: money.RUB},
+		{UserID: 1, Amount: 19900, Currency: money.RUB},
+		{UserID: 2, Amount: 150, Currency: money.XTR},
+		{UserID: 3, Amount: 500, Currency: money.USD},
+		{UserID: 4, Amount: 10000, Currency: money.RUB},
+	}
+
+	cohorts := BuildCohorts(users, subscriptions, payments, now)
+
+	want := []Cohort{
+		{WeekStart: week1, Users: 2, Retained: []int{1, 1, 0}, Revenue: money.Totals{money.RUB: 39800, money.XTR: 150}},
+		{WeekStart: week2, Users: 1, Retained: []int{0, 1, 1}, Revenue: money.Totals{money.USD: 500}},
+		{WeekStart: utc(2024, 7, 15, 0, 0), Users: 1, Retained: []int{0, -1, -1}, Revenue: money.Totals{money.RUB: 10000}},
+	}
+	if !reflect.DeepEqual(cohorts, want) {
+		t.Fatalf("BuildCohorts() =\n%+v\nwant\n%+v", cohorts, want)
+	}
+
+	if got := cohorts[0].RetentionRate(0); got != 50 {
+		t.Errorf("RetentionRate(0) = %v, want 50", got)
+	}
+	if got := cohorts[2].RetentionRate(1); got != -1 {
+		t.Errorf("RetentionRate for future checkpoint = %v, want -1", got)
+	}
+	if got, want := cohorts[0].LTV(), (money.Totals{money.RUB: 19900, money.XTR: 75}); !reflect.DeepEqual(got, want) {
+		t.Errorf("LTV() = %v, want %v", got, want)
+	}
+}
+
+func TestBuildCohortsPartialCheckpoint(t *testing.T) {
+	// Контрольный срок наступил для одного пользователя когорты, но не для другого - данных еще нет
+	now := utc(2024, 6, 25, 12, 0)
+	users := []models.User{
+		{ID: 1, CreatedAt: utc(2024, 5, 20, 10, 0)},
+		{ID: 2, CreatedAt: utc(2024, 5, 26, 10, 0)},
+	}
+	subscriptions := []models.Subscription{
+		{UserID: 1, StartDate: utc(2024, 5, 20, 10, 0), EndDate: utc(2024, 7, 20, 10, 0)},
+	}
+
+	cohorts := BuildCohorts(users, subscriptions, nil, now)
+	if len(cohorts) != 1 {
+		t.Fatalf("got %d cohorts, want 1", len(cohorts))
+	}
+	if got := cohorts[0].Retained[0]; got != -1 {
+		t.Errorf("Retained[0] = %d, want -1", got)
+	}
+}
+
+func TestCohortsCSV(t *testing.T) {
+	cohorts := []Cohort{
+		{WeekStart: utc(2024, 5, 20, 0, 0), Users: 2, Retained: []int{1, 1, -1}, Revenue: money.Totals{money.RUB: 39800, money.XTR: 150}},
+	}
+
+	data, err := CohortsCSV(cohorts)
+	if err != nil {
+		t.Fatalf("CohortsCSV() error: %v", err)
+	}
+
+	want := "week_start,users,retained_m1,retention_m1_percent,retained_m2,retention_m2_percent,retained_m3,retention_m3_percent," +
+		"revenue_rub,ltv_rub,revenue_xtr,ltv_xtr\n" +
+		"2024-05-20,2,1,50.0,1,50.0,,,398.00,199.00,150,75\n"
+	if got := string(data); got != want {
+		t.Errorf("CohortsCSV() =\n%s\nwant\n%s", got, want)
+	}
+
+	if !strings.Contains(FormatCohortTable(i18n.DefaultLanguage, cohorts), "20.05.24") {
+		t.Errorf("FormatCohortTable() does not contain the week start")
+	}
+}
@@ -0,0 +1,64 @@
+package callbackdata
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+)
+
+// MaxLength - ограничение Telegram на длину данных инлайн-кнопки в байтах
+const MaxLength = 64
+
+// separator отделяет данные кнопки от подписи
+const separator = "|"
+
+// signatureSize - количество байт HMAC, которые попадают в подпись (после base64 - 11 символов)
+const signatureSize = 8
+
+// Signer подписывает данные инлайн-кнопок, чтобы их нельзя было подделать.
+// Подпись включает ID чата, поэтому кнопку нельзя перенести в другой чат.
+type Signer struct {
+	key []byte
+}
+
+// NewSigner создает подписывающий объект с указанным секретом
+func NewSigner(secret string) *Signer {
+	key := sha256.Sum256([]byte("callback:" + secret))
+	return &Signer{key: key[:]}
+}
+
+// Sign возвращает данные кнопки с подписью для указанного чата
+func (s *Signer) Sign(chatID int64, payload string) string {
+	return payload + separator + s.signature(chatID, payload)
+}
+
+// Verify проверяет подпись данных кнопки и возвращает исходные данные.
+// Возвращает false, если подписи нет или она не совпадает.
+func (s *Signer) Verify(chatID int64, data string) (string, bool) {
+	index := strings.LastIndex(data, separator)
+	if index < 0 {
+		return "", false
+	}
+
+	payload, signature := data[:index], data[index+len(separator):]
+	if !hmac.Equal([]byte(signature), []byte(s.signature(chatID, payload))) {
+		return "", false
+	}
+	return payload, true
+}
+
+// IsSigned проверяет, содержат ли данные кнопки подпись (без проверки ее правильности)
+func IsSigned(data string) bool {
+	return strings.Contains(data, separator)
+}
+
+// signature вычисляет укороченную подпись данных кнопки
+func (s *Signer) signature(chatID int64, payload string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(strconv.FormatInt(chatID, 10)))
+	mac.Write([]byte(separator))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:signatureSize])
+}
@@ -1,28 +1,188 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/ilokitv/botVPN/internal/money"
 )
 
 // Config содержит настройки всего приложения
 type Config struct {
-	Bot      BotConfig      `yaml:"bot"`
-	Database DatabaseConfig `yaml:"database"`
-	Payments PaymentsConfig `yaml:"payments"`
+	Bot           BotConfig           `yaml:"bot"`
+	Database      DatabaseConfig      `yaml:"database"`
+	Payments      PaymentsConfig      `yaml:"payments"`
+	Gifts         GiftsConfig         `yaml:"gifts"`
+	Notifications NotificationsConfig `yaml:"notifications"`
+	Support       SupportConfig       `yaml:"support"`
+	Sender        SenderConfig        `yaml:"sender"`
 }
 
 // BotConfig содержит настройки Telegram бота
 type BotConfig struct {
-	Token    string  `yaml:"token"`
-	AdminIDs []int64 `yaml:"admin_ids"`
+	Token        string  `yaml:"token"`
+	AdminIDs     []int64 `yaml:"admin_ids"`
+	StateStorage string  `yaml:"state_storage"` // Хранилище состояний диалогов: "database" (в базе данных бота, по умолчанию) или "memory"
+	StateTTL     string  `yaml:"state_ttl"`     // Сколько хранится незавершенный диалог, например "1h" или "1d"
+	Workers      int     `yaml:"workers"`       // Количество параллельных обработчиков обновлений
+	QueueSize    int     `yaml:"queue_size"`    // Размер очереди обновлений каждого обработчика
+
+	CallbackSecret string `yaml:"callback_secret"` // Секрет для подписи данных инлайн-кнопок (по умолчанию - токен бота)
+
+	Mode    string        `yaml:"mode"`    // Способ получения обновлений: "polling" (по умолчанию) или "webhook"
+	Webhook WebhookConfig `yaml:"webhook"` // Настройки вебхука для режима "webhook"
+}
+
+// WebhookConfig содержит настройки получения обновлений через вебхук
+type WebhookConfig struct {
+	URL         string `yaml:"url"`          // Публичный HTTPS-адрес вебхука, например "https://bot.example.com/telegram"
+	Listen      string `yaml:"listen"`       // Адрес, на котором слушает HTTP-сервер бота
+	SecretToken string `yaml:"secret_token"` // Секрет заголовка X-Telegram-Bot-Api-Secret-Token (по умолчанию выводится из токена бота)
+	CertFile    string `yaml:"cert_file"`    // Сертификат TLS; если не задан, сервер работает по HTTP за обратным прокси
+	KeyFile     string `yaml:"key_file"`     // Закрытый ключ сертификата TLS
+	SelfSigned  bool   `yaml:"self_signed"`  // Передать сертификат в Telegram (для самоподписанного сертификата)
+}
+
+// Способы получения обновлений
+const (
+	ModePolling = "polling"
+	ModeWebhook = "webhook"
+)
+
+// defaultWebhookListen используется, если адрес HTTP-сервера вебхука не задан в конфигурации
+const defaultWebhookListen = ":8443"
+
+// GetMode возвращает способ получения обновлений
+func (bc *BotConfig) GetMode() (string, error) {
+	switch bc.Mode {
+	case "", ModePolling:
+		return ModePolling, nil
+	case ModeWebhook:
+		if bc.Webhook.URL == "" {
+			return "", fmt.Errorf("webhook url is required in %q mode", ModeWebhook)
+		}
+		if (bc.Webhook.CertFile == "") != (bc.Webhook.KeyFile == "") {
+			return "", fmt.Errorf("webhook cert_file and key_file must be set together")
+		}
+		return ModeWebhook, nil
+	default:
+		return "", fmt.Errorf("invalid bot mode %q: expected %q or %q", bc.Mode, ModePolling, ModeWebhook)
+	}
+}
+
+// GetListen возвращает адрес HTTP-сервера вебхука
+func (wc *WebhookConfig) GetListen() string {
+	if wc.Listen == "" {
+		return defaultWebhookListen
+	}
+	return wc.Listen
+}
+
+// GetWebhookSecret возвращает секрет, который Telegram передает в заголовке X-Telegram-Bot-Api-Secret-Token.
+// Если секрет не задан, он выводится из токена бота и не меняется между перезапусками.
+func (bc *BotConfig) GetWebhookSecret() (string, error) {
+	secret := bc.Webhook.SecretToken
+	if secret == "" {
+		sum := sha256.Sum256([]byte("webhook:" + bc.Token))
+		return hex.EncodeToString(sum[:]), nil
+	}
+
+	// Telegram допускает в секрете только A-Z, a-z, 0-9, "_" и "-", длиной до 256 символов
+	if len(secret) > 256 {
+		return "", fmt.Errorf("invalid webhook secret token: longer than 256 characters")
+	}
+	for _, r := range secret {
+		if !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return "", fmt.Errorf("invalid webhook secret token: only A-Z, a-z, 0-9, _ and - are allowed")
+		}
+	}
+	return secret, nil
+}
+
+// GetCallbackSecret возвращает секрет для подписи данных инлайн-кнопок
+func (bc *BotConfig) GetCallbackSecret() string {
+	if bc.CallbackSecret == "" {
+		return bc.Token
+	}
+	return bc.CallbackSecret
+}
+
+// Значения по умолчанию для обработки обновлений
+const (
+	defaultWorkers   = 8
+	defaultQueueSize = 100
+)
+
+// GetWorkers возвращает количество параллельных обработчиков обновлений
+func (bc *BotConfig) GetWorkers() int {
+	if bc.Workers <= 0 {
+		return defaultWorkers
+	}
+	return bc.Workers
+}
+
+// GetQueueSize возвращает размер очереди обновлений каждого обработчика
+func (bc *BotConfig) GetQueueSize() int {
+	if bc.QueueSize <= 0 {
+		return defaultQueueSize
+	}
+	return bc.QueueSize
+}
+
+// Хранилища состояний диалогов
+const (
+	StateStorageDatabase = "database" // В базе данных бота: PostgreSQL или SQLite
+	StateStorageMemory   = "memory"
+
+	// stateStorageLegacyPostgres - прежнее название хранилища в базе данных, принимается для совместимости со старыми конфигурациями
+	stateStorageLegacyPostgres = "postgres"
+)
+
+// defaultStateTTL используется, если время жизни состояний диалогов не задано в конфигурации
+const defaultStateTTL = "1h"
+
+// GetStateStorage возвращает хранилище состояний диалогов
+func (bc *BotConfig) GetStateStorage() (string, error) {
+	switch bc.StateStorage {
+	case "", StateStorageDatabase, stateStorageLegacyPostgres:
+		return StateStorageDatabase, nil
+	case StateStorageMemory:
+		return StateStorageMemory, nil
+	default:
+		return "", fmt.Errorf("invalid state storage %q: expected %q or %q", bc.StateStorage, StateStorageDatabase, StateStorageMemory)
+	}
+}
+
+// GetStateTTL возвращает время жизни незавершенного диалога
+func (bc *BotConfig) GetStateTTL() (time.Duration, error) {
+	value := bc.StateTTL
+	if value == "" {
+		value = defaultStateTTL
+	}
+
+	ttl, err := parseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid state ttl %q: %w", value, err)
+	}
+	if ttl <= 0 {
+		return 0, fmt.Errorf("invalid state ttl %q: must be positive", value)
+	}
+	return ttl, nil
 }
 
 // DatabaseConfig содержит настройки базы данных
 type DatabaseConfig struct {
+	Driver   string `yaml:"driver"` // СУБД: "postgres" (по умолчанию) или "sqlite"
+	Path     string `yaml:"path"`   // Файл базы данных SQLite
 	Host     string `yaml:"host"`
 	Port     int    `yaml:"port"`
 	User     string `yaml:"user"`
@@ -31,9 +191,164 @@ type DatabaseConfig struct {
 	SSLMode  string `yaml:"sslmode"`
 }
 
+// Поддерживаемые СУБД
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// defaultSQLitePath - файл базы данных SQLite по умолчанию
+const defaultSQLitePath = "vpnbot.db"
+
+// GetDriver возвращает СУБД, в которой хранятся данные бота
+func (dc *DatabaseConfig) GetDriver() (string, error) {
+	switch dc.Driver {
+	case "", DriverPostgres:
+		return DriverPostgres, nil
+	case DriverSQLite:
+		return DriverSQLite, nil
+	default:
+		return "", fmt.Errorf("invalid database driver %q: expected %q or %q", dc.Driver, DriverPostgres, DriverSQLite)
+	}
+}
+
+// GetPath возвращает путь к файлу базы данных SQLite
+func (dc *DatabaseConfig) GetPath() string {
+	if dc.Path == "" {
+		return defaultSQLitePath
+	}
+	return dc.Path
+}
+
 // PaymentsConfig содержит настройки платежей
 type PaymentsConfig struct {
 	Provider string `yaml:"provider"`
+	Currency string `yaml:"currency"` // Валюта счетов за подписки: RUB, USD или XTR (Telegram Stars)
+}
+
+// GetCurrency возвращает валюту, в которой выставляются счета за подписки (по умолчанию рубли)
+func (pc *PaymentsConfig) GetCurrency() (string, error) {
+	if pc.Currency == "" {
+		return money.RUB, nil
+	}
+	return money.ParseCurrency(pc.Currency)
+}
+
+// GiftsConfig содержит настройки подарочных подписок
+type GiftsConfig struct {
+	ExpirationDays int `yaml:"expiration_days"` // Срок действия подарочного кода в днях
+}
+
+// NotificationsConfig содержит настройки уведомлений пользователей
+type NotificationsConfig struct {
+	ReminderOffsets []string `yaml:"reminder_offsets"` // За сколько до окончания подписки напоминать, например "7d", "2h"
+}
+
+// defaultReminderOffsets используются, если расписание напоминаний не задано в конфигурации
+var defaultReminderOffsets = []string{"3d", "1d"}
+
+// GetReminderOffsets возвращает расписание напоминаний об окончании подписки, отсортированное по убыванию
+func (nc *NotificationsConfig) GetReminderOffsets() ([]time.Duration, error) {
+	values := nc.ReminderOffsets
+	if len(values) == 0 {
+		values = defaultReminderOffsets
+	}
+
+	offsets := make([]time.Duration, 0, len(values))
+	for _, value := range values {
+		offset, err := ParseReminderOffset(value)
+		if err != nil {
+			return nil, err
+		}
+		offsets = append(offsets, offset)
+	}
+
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] > offsets[j] })
+	return offsets, nil
+}
+
+// ParseReminderOffset разбирает смещение напоминания в формате parseDuration
+func ParseReminderOffset(value string) (time.Duration, error) {
+	offset, err := parseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid reminder offset %q: %w", value, err)
+	}
+	if offset <= 0 {
+		return 0, fmt.Errorf("invalid reminder offset %q: must be positive", value)
+	}
+	return offset, nil
+}
+
+// SupportConfig содержит настройки обращений в поддержку
+type SupportConfig struct {
+	GroupID int64 `yaml:"group_id"` // Группа сотрудников для обращений (0 - обращения приходят сотрудникам с правом users в личные сообщения)
+}
+
+// SenderConfig содержит настройки отправки сообщений в Telegram
+type SenderConfig struct {
+	GlobalRatePerSecond int `yaml:"global_rate_per_second"` // Сколько сообщений бот отправляет в секунду во все чаты
+	ChatRatePerSecond   int `yaml:"chat_rate_per_second"`   // Сколько сообщений в секунду отправляется в один личный чат
+	ChatBurst           int `yaml:"chat_burst"`             // Сколько сообщений подряд можно отправить в чат без ожидания
+	MaxRetries          int `yaml:"max_retries"`            // Сколько раз повторять отправку после временной ошибки
+}
+
+// Значения по умолчанию для отправки сообщений, с запасом ниже ограничений Telegram
+const (
+	defaultSenderGlobalRate = 30
+	defaultSenderChatRate   = 1
+	defaultSenderChatBurst  = 3
+	defaultSenderMaxRetries = 3
+)
+
+// GetGlobalRatePerSecond возвращает общее ограничение скорости отправки
+func (sc *SenderConfig) GetGlobalRatePerSecond() int {
+	if sc.GlobalRatePerSecond <= 0 {
+		return defaultSenderGlobalRate
+	}
+	return sc.GlobalRatePerSecond
+}
+
+// GetChatRatePerSecond возвращает ограничение скорости отправки в один чат
+func (sc *SenderConfig) GetChatRatePerSecond() int {
+	if sc.ChatRatePerSecond <= 0 {
+		return defaultSenderChatRate
+	}
+	return sc.ChatRatePerSecond
+}
+
+// GetChatBurst возвращает, сколько сообщений подряд можно отправить в чат без ожидания
+func (sc *SenderConfig) GetChatBurst() int {
+	if sc.ChatBurst <= 0 {
+		return defaultSenderChatBurst
+	}
+	return sc.ChatBurst
+}
+
+// GetMaxRetries возвращает число повторов отправки после временной ошибки
+func (sc *SenderConfig) GetMaxRetries() int {
+	if sc.MaxRetries < 0 {
+		return 0
+	}
+	if sc.MaxRetries == 0 {
+		return defaultSenderMaxRetries
+	}
+	return sc.MaxRetries
+}
+
+// parseDuration разбирает длительность. Помимо формата time.ParseDuration ("2h", "30m")
+// поддерживаются дни с суффиксом "d" ("7d").
+func parseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+
+	return time.ParseDuration(value)
 }
 
 // GetConnectionString возвращает строку подключения к базе данных
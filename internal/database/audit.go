@@ -0,0 +1,66 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+// auditSelect - запрос записей журнала вместе с именем сотрудника
+const auditSelect = `
+	SELECT a.*, COALESCE(u.username, '') AS actor_username
+	FROM audit_log a
+	LEFT JOIN users u ON u.id = a.actor_user_id
+`
+
+// AddAuditEntry добавляет запись в журнал действий сотрудников
+func (db *DB) AddAuditEntry(entry *models.AuditEntry) error {
+	if entry.Before == "" {
+		entry.Before = "{}"
+	}
+	if entry.After == "" {
+		entry.After = "{}"
+	}
+
+	err := db.QueryRow(`
+		INSERT INTO audit_log (actor_user_id, actor_telegram_id, action, target_type, target_id, before_value, after_value)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`, entry.ActorUserID, entry.ActorTelegramID, entry.Action, entry.TargetType, entry.TargetID,
+		entry.Before, entry.After,
+	).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add audit entry: %w", err)
+	}
+	return nil
+}
+
+// GetAuditEntries возвращает страницу журнала действий, начиная с последних записей
+func (db *DB) GetAuditEntries(limit, offset int) ([]models.AuditEntry, error) {
+	var entries []models.AuditEntry
+	err := db.Select(&entries, auditSelect+" ORDER BY a.created_at DESC, a.id DESC LIMIT $1 OFFSET $2",
+		limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit entries: %w", err)
+	}
+	return entries, nil
+}
+
+// CountAuditEntries возвращает количество записей в журнале действий
+func (db *DB) CountAuditEntries() (int, error) {
+	var count int
+	if err := db.Get(&count, "SELECT COUNT(*) FROM audit_log"); err != nil {
+		return 0, fmt.Errorf("failed to count audit entries: %w", err)
+	}
+	return count, nil
+}
+
+// GetAllAuditEntries возвращает весь журнал действий в хронологическом порядке
+func (db *DB) GetAllAuditEntries() ([]models.AuditEntry, error) {
+	var entries []models.AuditEntry
+	err := db.Select(&entries, auditSelect+" ORDER BY a.created_at, a.id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit entries: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,97 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+// broadcastSegments - условия выборки получателей для сегментов рассылки.
+// Параметр $1 - значение сегмента (начало периода для истекших подписок, ID плана или ID сервера).
+var broadcastSegments = map[string]string{
+	models.BroadcastSegmentAll:    "TRUE",
+	models.BroadcastSegmentActive: "EXISTS (SELECT 1 FROM subscriptions s WHERE s.user_id = u.id AND s.status = 'active')",
+	models.BroadcastSegmentExpired: `EXISTS (SELECT 1 FROM subscriptions s WHERE s.user_id = u.id AND s.status IN ('expired', 'grace')
+			AND s.end_date >= $1)
+		AND NOT EXISTS (SELECT 1 FROM subscriptions s WHERE s.user_id = u.id AND s.status = 'active')`,
+	models.BroadcastSegmentPlan:   "EXISTS (SELECT 1 FROM subscriptions s WHERE s.user_id = u.id AND s.status = 'active' AND s.plan_id = $1)",
+	models.BroadcastSegmentServer: "EXISTS (SELECT 1 FROM subscriptions s WHERE s.user_id = u.id AND s.status = 'active' AND s.server_id = $1)",
+}
+
+// GetBroadcastAudience возвращает получателей рассылки для сегмента. Пользователи, заблокировавшие бота, не включаются.
+func (db *DB) GetBroadcastAudience(segment string, value int) ([]models.User, error) {
+	condition, ok := broadcastSegments[segment]
+	if !ok {
+		return nil, fmt.Errorf("unknown broadcast segment: %s", segment)
+	}
+
+	var args []interface{}
+	switch {
+	case segment == models.BroadcastSegmentExpired:
+		// Для истекших подписок значение сегмента - количество дней после окончания
+		args = append(args, time.Now().AddDate(0, 0, -value))
+	case strings.Contains(condition, "$1"):
+		args = append(args, value)
+	}
+
+	var users []models.User
+	err := db.Select(&users,
+		"SELECT u.* FROM users u WHERE u.bot_blocked_at IS NULL AND "+condition+" ORDER BY u.id", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get broadcast audience: %w", err)
+	}
+	return users, nil
+}
+
+// AddBroadcast сохраняет новую рассылку
+func (db *DB) AddBroadcast(broadcast *models.Broadcast) error {
+	if broadcast.Buttons == "" {
+		broadcast.Buttons = "[]"
+	}
+
+	err := db.QueryRow(`
+		INSERT INTO broadcasts (created_by, source_chat_id, source_message_id, buttons, segment, segment_value, status, total)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`, broadcast.CreatedBy, broadcast.SourceChatID, broadcast.SourceMessageID, broadcast.Buttons,
+		broadcast.Segment, broadcast.SegmentValue, broadcast.Status, broadcast.Total,
+	).Scan(&broadcast.ID, &broadcast.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add broadcast: %w", err)
+	}
+	return nil
+}
+
+// UpdateBroadcastStats сохраняет статус, счетчики доставки и последнего обработанного получателя рассылки
+func (db *DB) UpdateBroadcastStats(broadcast *models.Broadcast) error {
+	_, err := db.Exec(`
+		UPDATE broadcasts SET status = $1, sent = $2, failed = $3, blocked = $4, last_user_id = $5, finished_at = $6
+		WHERE id = $7
+	`, broadcast.Status, broadcast.Sent, broadcast.Failed, broadcast.Blocked, broadcast.LastUserID, broadcast.FinishedAt, broadcast.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update broadcast stats: %w", err)
+	}
+	return nil
+}
+
+// GetUnfinishedBroadcasts возвращает рассылки, которые не завершились к моменту остановки бота
+func (db *DB) GetUnfinishedBroadcasts() ([]models.Broadcast, error) {
+	var broadcasts []models.Broadcast
+	err := db.Select(&broadcasts, "SELECT * FROM broadcasts WHERE status IN ($1, $2) ORDER BY id",
+		models.BroadcastRunning, models.BroadcastInterrupted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unfinished broadcasts: %w", err)
+	}
+	return broadcasts, nil
+}
+
+// MarkUserBotBlocked отмечает, что пользователь заблокировал бота. Отметка снимается, когда пользователь снова пишет боту.
+func (db *DB) MarkUserBotBlocked(telegramID int64) error {
+	_, err := db.Exec("UPDATE users SET bot_blocked_at = NOW() WHERE telegram_id = $1 AND bot_blocked_at IS NULL", telegramID)
+	if err != nil {
+		return fmt.Errorf("failed to mark user as blocked: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+func TestGetUnfinishedBroadcasts(t *testing.T) {
+	db := newSQLiteTestDB(t)
+
+	statuses := []string{models.BroadcastRunning, models.BroadcastInterrupted, models.BroadcastFinished, models.BroadcastFailed}
+	for _, status := range statuses {
+		broadcast := &models.Broadcast{CreatedBy: 100, SourceChatID: 100, SourceMessageID: 1,
+			Segment: models.BroadcastSegmentAll, Status: models.BroadcastRunning, Total: 10}
+		if err := db.AddBroadcast(broadcast); err != nil {
+			t.Fatalf("AddBroadcast() error: %v", err)
+		}
+		broadcast.Status, broadcast.Sent, broadcast.LastUserID = status, 3, 7
+		if err := db.UpdateBroadcastStats(broadcast); err != nil {
+			t.Fatalf("UpdateBroadcastStats() error: %v", err)
+		}
+	}
+
+	broadcasts, err := db.GetUnfinishedBroadcasts()
+	if err != nil {
+		t.Fatalf("GetUnfinishedBroadcasts() error: %v", err)
+	}
+	if len(broadcasts) != 2 {
+		t.Fatalf("got %d unfinished broadcasts, want 2", len(broadcasts))
+	}
+	for i, broadcast := range broadcasts {
+		if broadcast.Status != statuses[i] || broadcast.Sent != 3 || broadcast.LastUserID != 7 || broadcast.Buttons != "[]" {
+			t.Errorf("broadcast %d = %+v", i, broadcast)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+// GetCohortData возвращает пользователей, зарегистрированных начиная с since, их подписки
+// и поступления от них (без оплаты с баланса) для построения когортного отчета
+func (db *DB) GetCohortData(since time.Time) ([]models.User, []models.Subscription, []models.Payment, error) {
+	var users []models.User
+	err := db.Select(&users, "SELECT * FROM users WHERE created_at >= $1 ORDER BY created_at", since)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get cohort users: %w", err)
+	}
+
+	var subscriptions []models.Subscription
+	err = db.Select(&subscriptions, `
+		SELECT s.* FROM subscriptions s
+		JOIN users u ON u.id = s.user_id
+		WHERE u.created_at >= $1
+	`, since)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get cohort subscriptions: %w", err)
+	}
+
+	var payments []models.Payment
+	err = db.Select(&payments, `
+		SELECT p.* FROM payments p
+		JOIN users u ON u.id = p.user_id
+		WHERE u.created_at >= $1 AND p.status = 'completed' AND p.payment_method <> 'balance'
+	`, since)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get cohort payments: %w", err)
+	}
+
+	return users, subscriptions, payments, nil
+}
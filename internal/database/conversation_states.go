@@ -0,0 +1,63 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+// GetConversationState возвращает состояние диалога для чата, действующее в момент now.
+// Если состояния нет или оно истекло, возвращается sql.ErrNoRows (обернутая).
+// Срок действия записывается и сравнивается по часам бота в UTC, а не по NOW() базы данных,
+// поэтому разница часовых поясов бота и базы не сокращает и не продлевает жизнь состояний.
+func (db *DB) GetConversationState(chatID int64, now time.Time) (*models.ConversationState, error) {
+	var state models.ConversationState
+	err := db.Get(&state, "SELECT * FROM conversation_states WHERE chat_id = $1 AND expires_at > $2", chatID, now.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation state: %w", err)
+	}
+	return &state, nil
+}
+
+// SaveConversationState сохраняет состояние диалога для чата, заменяя предыдущее.
+// Состояние действует до момента expiresAt.
+func (db *DB) SaveConversationState(state *models.ConversationState, expiresAt time.Time) error {
+	_, err := db.Exec(`
+		INSERT INTO conversation_states (chat_id, state, previous_state, data, expires_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (chat_id) DO UPDATE SET
+			state = EXCLUDED.state,
+			previous_state = EXCLUDED.previous_state,
+			data = EXCLUDED.data,
+			expires_at = EXCLUDED.expires_at,
+			updated_at = NOW()
+	`, state.ChatID, state.State, state.PreviousState, state.Data, expiresAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to save conversation state: %w", err)
+	}
+	return nil
+}
+
+// DeleteConversationState удаляет состояние диалога для чата
+func (db *DB) DeleteConversationState(chatID int64) error {
+	_, err := db.Exec("DELETE FROM conversation_states WHERE chat_id = $1", chatID)
+	if err != nil {
+		return fmt.Errorf("failed to delete conversation state: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpiredConversationStates удаляет состояния диалогов, истекшие к моменту now, и возвращает их количество
+func (db *DB) DeleteExpiredConversationStates(now time.Time) (int64, error) {
+	result, err := db.Exec("DELETE FROM conversation_states WHERE expires_at <= $1", now.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired conversation states: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired conversation states: %w", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+func TestConversationStateExpiry(t *testing.T) {
+	db := newSQLiteTestDB(t)
+
+	// Бот работает во Владивостоке: срок действия не должен зависеть от часового пояса часов бота
+	vladivostok := time.FixedZone("VLAT", 10*60*60)
+	savedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, vladivostok)
+	state := &models.ConversationState{ChatID: 100, State: "adjust_balance_amount", Data: "{}"}
+	if err := db.SaveConversationState(state, savedAt.Add(time.Hour)); err != nil {
+		t.Fatalf("SaveConversationState() error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		wantFound bool
+	}{
+		{name: "right after saving", now: savedAt, wantFound: true},
+		{name: "before expiry in UTC", now: savedAt.Add(59 * time.Minute).UTC(), wantFound: true},
+		{name: "at expiry", now: savedAt.Add(time.Hour), wantFound: false},
+		{name: "after expiry in UTC", now: savedAt.Add(2 * time.Hour).UTC(), wantFound: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := db.GetConversationState(state.ChatID, tt.now)
+			switch {
+			case tt.wantFound && err != nil:
+				t.Errorf("GetConversationState() error: %v", err)
+			case !tt.wantFound && !errors.Is(err, sql.ErrNoRows):
+				t.Errorf("GetConversationState() error = %v, want sql.ErrNoRows", err)
+			}
+		})
+	}
+
+	if deleted, err := db.DeleteExpiredConversationStates(savedAt.Add(30 * time.Minute)); err != nil || deleted != 0 {
+		t.Errorf("DeleteExpiredConversationStates() before expiry = %d, %v; want 0", deleted, err)
+	}
+	if deleted, err := db.DeleteExpiredConversationStates(savedAt.Add(time.Hour).UTC()); err != nil || deleted != 1 {
+		t.Errorf("DeleteExpiredConversationStates() at expiry = %d, %v; want 1", deleted, err)
+	}
+}
@@ -1,23 +1,40 @@
 package database
 
 import (
+	"database/sql"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 
 	"github.com/ilokitv/botVPN/internal/config"
 	"github.com/ilokitv/botVPN/internal/models"
+	"github.com/ilokitv/botVPN/internal/money"
 )
 
-// DB представляет соединение с базой данных
+// DB представляет соединение с базой данных PostgreSQL или SQLite
 type DB struct {
 	*sqlx.DB
+	dialect dialect
 }
 
-// New создает новое соединение с базой данных
+// New создает новое соединение с базой данных, выбранной в конфигурации
 func New(cfg *config.DatabaseConfig) (*DB, error) {
+	driver, err := cfg.GetDriver()
+	if err != nil {
+		return nil, err
+	}
+
+	if driver == config.DriverSQLite {
+		db, err := openSQLite(cfg.GetPath())
+		if err != nil {
+			return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+		}
+		return &DB{DB: db, dialect: sqliteDialect}, nil
+	}
+
 	db, err := sqlx.Connect("postgres", cfg.GetConnectionString())
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
@@ -28,110 +45,7 @@ func New(cfg *config.DatabaseConfig) (*DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &DB{db}, nil
-}
-
-// InitTables создает таблицы в базе данных, если они не существуют
-func (db *DB) InitTables() error {
-	// Создаем таблицу для серверов
-	_, err := db.Exec(`
-	CREATE TABLE IF NOT EXISTS servers (
-		id SERIAL PRIMARY KEY,
-		ip TEXT NOT NULL,
-		port INTEGER NOT NULL,
-		ssh_user TEXT NOT NULL,
-		ssh_password TEXT NOT NULL,
-		max_clients INTEGER NOT NULL DEFAULT 10,
-		current_clients INTEGER NOT NULL DEFAULT 0,
-		is_active BOOLEAN NOT NULL DEFAULT TRUE,
-		created_at TIMESTAMP NOT NULL DEFAULT NOW(),
-		updated_at TIMESTAMP NOT NULL DEFAULT NOW()
-	)
-	`)
-
-	if err != nil {
-		return fmt.Errorf("failed to create servers table: %w", err)
-	}
-
-	// Создаем таблицу для планов подписок
-	_, err = db.Exec(`
-	CREATE TABLE IF NOT EXISTS subscription_plans (
-		id SERIAL PRIMARY KEY,
-		name TEXT NOT NULL,
-		description TEXT NOT NULL,
-		price REAL NOT NULL,
-		duration INTEGER NOT NULL, 
-		is_active BOOLEAN NOT NULL DEFAULT TRUE,
-		created_at TIMESTAMP NOT NULL DEFAULT NOW(),
-		updated_at TIMESTAMP NOT NULL DEFAULT NOW()
-	)
-	`)
-
-	if err != nil {
-		return fmt.Errorf("failed to create subscription_plans table: %w", err)
-	}
-
-	// Создаем таблицу для пользователей
-	_, err = db.Exec(`
-	CREATE TABLE IF NOT EXISTS users (
-		id SERIAL PRIMARY KEY,
-		telegram_id BIGINT NOT NULL UNIQUE,
-		username TEXT,
-		first_name TEXT,
-		last_name TEXT,
-		is_admin BOOLEAN NOT NULL DEFAULT FALSE,
-		created_at TIMESTAMP NOT NULL DEFAULT NOW(),
-		updated_at TIMESTAMP NOT NULL DEFAULT NOW()
-	)
-	`)
-
-	if err != nil {
-		return fmt.Errorf("failed to create users table: %w", err)
-	}
-
-	// Создаем таблицу для подписок
-	_, err = db.Exec(`
-	CREATE TABLE IF NOT EXISTS subscriptions (
-		id SERIAL PRIMARY KEY,
-		user_id INTEGER NOT NULL REFERENCES users(id),
-		server_id INTEGER NOT NULL REFERENCES servers(id),
-		plan_id INTEGER NOT NULL REFERENCES subscription_plans(id),
-		start_date TIMESTAMP NOT NULL,
-		end_date TIMESTAMP NOT NULL,
-		status TEXT NOT NULL,
-		config_file_path TEXT,
-		data_usage BIGINT NOT NULL DEFAULT 0,
-		last_connection_at TIMESTAMP,
-		created_at TIMESTAMP NOT NULL DEFAULT NOW(),
-		updated_at TIMESTAMP NOT NULL DEFAULT NOW()
-	)
-	`)
-
-	if err != nil {
-		return fmt.Errorf("failed to create subscriptions table: %w", err)
-	}
-
-	// Создаем таблицу для платежей
-	_, err = db.Exec(`
-	CREATE TABLE IF NOT EXISTS payments (
-		id SERIAL PRIMARY KEY,
-		user_id INTEGER NOT NULL REFERENCES users(id),
-		subscription_id INTEGER REFERENCES subscriptions(id),
-		amount REAL NOT NULL,
-		payment_method TEXT NOT NULL,
-		payment_id TEXT,
-		status TEXT NOT NULL,
-		created_at TIMESTAMP NOT NULL DEFAULT NOW(),
-		updated_at TIMESTAMP NOT NULL DEFAULT NOW()
-	)
-	`)
-
-	if err != nil {
-		return fmt.Errorf("failed to create payments table: %w", err)
-	}
-
-	log.Println("All database tables initialized successfully")
-	return nil
+	return &DB{DB: db, dialect: postgresDialect}, nil
 }
 
 // GetServerByID возвращает сервер по ID
@@ -147,7 +61,7 @@ func (db *DB) GetServerByID(id int) (*models.Server, error) {
 // GetAllServers возвращает все серверы
 func (db *DB) GetAllServers() ([]models.Server, error) {
 	var servers []models.Server
-	err := db.Select(&servers, "SELECT * FROM servers")
+	err := db.Select(&servers, "SELECT * FROM servers WHERE deleted_at IS NULL")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all servers: %w", err)
 	}
@@ -197,7 +111,7 @@ func (db *DB) AddServer(server *models.Server) error {
 
 	// Проверяем, существует ли уже сервер с таким IP
 	var count int
-	err = tx.Get(&count, "SELECT COUNT(*) FROM servers WHERE ip = $1", server.IP)
+	err = tx.Get(&count, "SELECT COUNT(*) FROM servers WHERE ip = $1 AND deleted_at IS NULL", server.IP)
 	if err != nil {
 		log.Printf("Ошибка при проверке существования сервера: %v", err)
 		return fmt.Errorf("ошибка при проверке существования сервера: %w", err)
@@ -256,9 +170,13 @@ func (db *DB) UpdateServer(server *models.Server) error {
 	return nil
 }
 
-// DeleteServer удаляет сервер по ID
+// DeleteServer удаляет сервер по ID. Запись остается, потому что на нее ссылаются подписки,
+// но сервер становится неактивным и пропадает из списков.
 func (db *DB) DeleteServer(id int) error {
-	_, err := db.Exec("DELETE FROM servers WHERE id = $1", id)
+	_, err := db.Exec(`
+		UPDATE servers SET is_active = FALSE, deleted_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete server: %w", err)
 	}
@@ -307,10 +225,10 @@ func (db *DB) GetAllUsers() ([]models.User, error) {
 	return users, nil
 }
 
-// GetAllAdmins возвращает всех пользователей со статусом администратора
+// GetAllAdmins возвращает всех сотрудников (пользователей с ролью)
 func (db *DB) GetAllAdmins() ([]models.User, error) {
 	var admins []models.User
-	err := db.Select(&admins, "SELECT * FROM users WHERE is_admin = TRUE ORDER BY created_at DESC")
+	err := db.Select(&admins, "SELECT * FROM users WHERE role <> '' ORDER BY created_at DESC")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all admins: %w", err)
 	}
@@ -337,9 +255,9 @@ func (db *DB) GetUserStats(userID int) (*models.UserStats, error) {
 		return nil, fmt.Errorf("failed to get user active subscriptions count: %w", err)
 	}
 
-	// Получаем общую сумму платежей
-	err = db.Get(&stats.TotalPayments,
-		"SELECT COALESCE(SUM(amount), 0) FROM payments WHERE user_id = $1 AND status = 'completed'", userID)
+	// Получаем общую сумму платежей по валютам
+	stats.TotalPayments, err = db.getPaymentTotals(
+		"SELECT currency, SUM(amount) AS amount FROM payments WHERE user_id = $1 AND status = 'completed' AND payment_method <> 'balance' GROUP BY currency", userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user total payments: %w", err)
 	}
@@ -354,9 +272,27 @@ func (db *DB) GetUserStats(userID int) (*models.UserStats, error) {
 	return stats, nil
 }
 
+// getPaymentTotals возвращает суммы платежей по валютам. Запрос должен выбирать столбцы currency и amount.
+func (db *DB) getPaymentTotals(query string, args ...interface{}) (money.Totals, error) {
+	var rows []struct {
+		Currency string `db:"currency"`
+		Amount   int64  `db:"amount"`
+	}
+	if err := db.Select(&rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	totals := make(money.Totals, len(rows))
+	for _, row := range rows {
+		totals.Add(row.Currency, row.Amount)
+	}
+	return totals, nil
+}
+
 // GetSystemStats возвращает общую статистику по системе
 func (db *DB) GetSystemStats() (*models.SystemStats, error) {
 	stats := &models.SystemStats{}
+	now := time.Now()
 
 	// Общее количество пользователей
 	err := db.Get(&stats.TotalUsers, "SELECT COUNT(*) FROM users")
@@ -370,16 +306,16 @@ func (db *DB) GetSystemStats() (*models.SystemStats, error) {
 		return nil, fmt.Errorf("failed to get active subscriptions count: %w", err)
 	}
 
-	// Общий доход
-	err = db.Get(&stats.TotalRevenue,
-		"SELECT COALESCE(SUM(amount), 0) FROM payments WHERE status = 'completed'")
+	// Общий доход по валютам
+	stats.TotalRevenue, err = db.getPaymentTotals(
+		"SELECT currency, SUM(amount) AS amount FROM payments WHERE status = 'completed' AND payment_method <> 'balance' GROUP BY currency")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get total revenue: %w", err)
 	}
 
-	// Доход за последний месяц
-	err = db.Get(&stats.MonthlyRevenue,
-		"SELECT COALESCE(SUM(amount), 0) FROM payments WHERE status = 'completed' AND created_at > NOW() - INTERVAL '30 days'")
+	// Доход за последний месяц по валютам
+	stats.MonthlyRevenue, err = db.getPaymentTotals(
+		"SELECT currency, SUM(amount) AS amount FROM payments WHERE status = 'completed' AND payment_method <> 'balance' AND created_at > $1 GROUP BY currency", now.AddDate(0, 0, -30))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get monthly revenue: %w", err)
 	}
@@ -391,7 +327,7 @@ func (db *DB) GetSystemStats() (*models.SystemStats, error) {
 	}
 
 	// Общее количество клиентов на серверах
-	err = db.Get(&stats.TotalClients, "SELECT COALESCE(SUM(current_clients), 0) FROM servers")
+	err = db.Get(&stats.TotalClients, "SELECT COALESCE(SUM(current_clients), 0) FROM servers WHERE deleted_at IS NULL")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get total clients count: %w", err)
 	}
@@ -404,14 +340,14 @@ func (db *DB) GetSystemStats() (*models.SystemStats, error) {
 
 	// Регистрации за последние 7 дней
 	err = db.Get(&stats.NewUsers7Days,
-		"SELECT COUNT(*) FROM users WHERE created_at > NOW() - INTERVAL '7 days'")
+		"SELECT COUNT(*) FROM users WHERE created_at > $1", now.AddDate(0, 0, -7))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get new users in 7 days: %w", err)
 	}
 
 	// Новые подписки за последние 7 дней
 	err = db.Get(&stats.NewSubscriptions7Days,
-		"SELECT COUNT(*) FROM subscriptions WHERE created_at > NOW() - INTERVAL '7 days'")
+		"SELECT COUNT(*) FROM subscriptions WHERE created_at > $1", now.AddDate(0, 0, -7))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get new subscriptions in 7 days: %w", err)
 	}
@@ -419,12 +355,33 @@ func (db *DB) GetSystemStats() (*models.SystemStats, error) {
 	return stats, nil
 }
 
-// SetUserAdmin устанавливает или снимает статус администратора для пользователя
-func (db *DB) SetUserAdmin(userID int, isAdmin bool) error {
-	_, err := db.Exec("UPDATE users SET is_admin = $1, updated_at = NOW() WHERE id = $2",
-		isAdmin, userID)
+// SetUserRole назначает пользователю роль сотрудника (пустая строка снимает роль)
+func (db *DB) SetUserRole(userID int, role string) error {
+	_, err := db.Exec("UPDATE users SET role = $1, is_admin = $1 <> '', updated_at = NOW() WHERE id = $2",
+		role, userID)
 	if err != nil {
-		return fmt.Errorf("failed to update user admin status: %w", err)
+		return fmt.Errorf("failed to update user role: %w", err)
+	}
+	return nil
+}
+
+// BootstrapOwners назначает роль владельца пользователям с указанными Telegram ID
+func (db *DB) BootstrapOwners(telegramIDs []int64) error {
+	if len(telegramIDs) == 0 {
+		return nil
+	}
+
+	query, args, err := sqlx.In(`
+		UPDATE users SET role = 'owner', is_admin = TRUE, updated_at = NOW()
+		WHERE telegram_id IN (?) AND role <> 'owner'
+	`, telegramIDs)
+	if err != nil {
+		return fmt.Errorf("failed to bootstrap owners: %w", err)
+	}
+
+	_, err = db.Exec(db.Rebind(query), args...)
+	if err != nil {
+		return fmt.Errorf("failed to bootstrap owners: %w", err)
 	}
 	return nil
 }
@@ -432,17 +389,18 @@ func (db *DB) SetUserAdmin(userID int, isAdmin bool) error {
 // AddUser добавляет нового пользователя
 func (db *DB) AddUser(user *models.User) error {
 	query := `
-	INSERT INTO users (telegram_id, username, first_name, last_name, is_admin)
-	VALUES ($1, $2, $3, $4, $5)
+	INSERT INTO users (telegram_id, username, first_name, last_name, is_admin, role, language_code)
+	VALUES ($1, $2, $3, $4, $5 <> '', $5, $6)
 	ON CONFLICT (telegram_id) DO UPDATE
-	SET username = $2, first_name = $3, last_name = $4, updated_at = NOW()
-	RETURNING id, created_at, updated_at
+	SET username = $2, first_name = $3, last_name = $4, language_code = $6, bot_blocked_at = NULL, updated_at = NOW()
+	RETURNING id, is_admin, role, language, created_at, updated_at
 	`
 
+	// Роль задается только при создании пользователя, у существующих пользователей она не меняется
 	row := db.QueryRow(query, user.TelegramID, user.Username, user.FirstName,
-		user.LastName, user.IsAdmin)
+		user.LastName, user.Role, user.LanguageCode)
 
-	err := row.Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	err := row.Scan(&user.ID, &user.IsAdmin, &user.Role, &user.Language, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to add user: %w", err)
 	}
@@ -450,6 +408,25 @@ func (db *DB) AddUser(user *models.User) error {
 	return nil
 }
 
+// SetUserLanguage сохраняет язык интерфейса, выбранный пользователем (пустая строка - язык Telegram)
+func (db *DB) SetUserLanguage(telegramID int64, language string) error {
+	result, err := db.Exec("UPDATE users SET language = $1, updated_at = NOW() WHERE telegram_id = $2",
+		language, telegramID)
+	if err != nil {
+		return fmt.Errorf("failed to set user language: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to set user language: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("failed to set user language: %w", sql.ErrNoRows)
+	}
+
+	return nil
+}
+
 // GetAllSubscriptionPlans возвращает все планы подписок
 func (db *DB) GetAllSubscriptionPlans() ([]models.SubscriptionPlan, error) {
 	var plans []models.SubscriptionPlan
@@ -457,6 +434,9 @@ func (db *DB) GetAllSubscriptionPlans() ([]models.SubscriptionPlan, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get subscription plans: %w", err)
 	}
+	if err := db.loadPlanPrices(plans); err != nil {
+		return nil, err
+	}
 	return plans, nil
 }
 
@@ -467,18 +447,22 @@ func (db *DB) GetSubscriptionPlanByID(id int) (*models.SubscriptionPlan, error)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get subscription plan by id: %w", err)
 	}
-	return &plan, nil
+	plans := []models.SubscriptionPlan{plan}
+	if err := db.loadPlanPrices(plans); err != nil {
+		return nil, err
+	}
+	return &plans[0], nil
 }
 
 // AddSubscriptionPlan добавляет новый план подписки
 func (db *DB) AddSubscriptionPlan(plan *models.SubscriptionPlan) error {
 	query := `
-	INSERT INTO subscription_plans (name, description, price, duration, is_active)
-	VALUES ($1, $2, $3, $4, $5)
+	INSERT INTO subscription_plans (name, description, price, currency, duration, is_active)
+	VALUES ($1, $2, $3, $4, $5, $6)
 	RETURNING id, created_at, updated_at
 	`
 
-	row := db.QueryRow(query, plan.Name, plan.Description, plan.Price, plan.Duration, plan.IsActive)
+	row := db.QueryRow(query, plan.Name, plan.Description, plan.Price, plan.Currency, plan.Duration, plan.IsActive)
 
 	err := row.Scan(&plan.ID, &plan.CreatedAt, &plan.UpdatedAt)
 	if err != nil {
@@ -492,12 +476,12 @@ func (db *DB) AddSubscriptionPlan(plan *models.SubscriptionPlan) error {
 func (db *DB) UpdateSubscriptionPlan(plan *models.SubscriptionPlan) error {
 	query := `
 	UPDATE subscription_plans
-	SET name = $1, description = $2, price = $3, duration = $4, is_active = $5, updated_at = NOW()
-	WHERE id = $6
+	SET name = $1, description = $2, price = $3, currency = $4, duration = $5, is_active = $6, updated_at = NOW()
+	WHERE id = $7
 	RETURNING updated_at
 	`
 
-	row := db.QueryRow(query, plan.Name, plan.Description, plan.Price, plan.Duration, plan.IsActive, plan.ID)
+	row := db.QueryRow(query, plan.Name, plan.Description, plan.Price, plan.Currency, plan.Duration, plan.IsActive, plan.ID)
 
 	err := row.Scan(&plan.UpdatedAt)
 	if err != nil {
@@ -546,43 +530,25 @@ func (db *DB) GetSubscriptionByID(subscriptionID int) (*models.Subscription, err
 	return &subscription, nil
 }
 
-// AddSubscription добавляет новую подписку
-func (db *DB) AddSubscription(subscription *models.Subscription) error {
-	query := `
-	INSERT INTO subscriptions 
-	(user_id, server_id, plan_id, start_date, end_date, status, config_file_path)
-	VALUES ($1, $2, $3, $4, $5, $6, $7)
-	RETURNING id, created_at, updated_at
-	`
-
-	row := db.QueryRow(query, subscription.UserID, subscription.ServerID, subscription.PlanID,
-		subscription.StartDate, subscription.EndDate, subscription.Status, subscription.ConfigFilePath)
-
-	err := row.Scan(&subscription.ID, &subscription.CreatedAt, &subscription.UpdatedAt)
-	if err != nil {
-		return fmt.Errorf("failed to add subscription: %w", err)
-	}
+// AddPayment добавляет новый платеж
+func (db *DB) AddPayment(payment *models.Payment) error {
+	return insertPayment(db, payment)
+}
 
-	// Обновляем счетчик клиентов на сервере
-	_, err = db.Exec("UPDATE servers SET current_clients = current_clients + 1 WHERE id = $1",
-		subscription.ServerID)
-	if err != nil {
-		return fmt.Errorf("failed to update server client count: %w", err)
+// insertPayment сохраняет платеж через соединение или транзакцию
+func insertPayment(q sqlx.Queryer, payment *models.Payment) error {
+	if payment.Currency == "" {
+		return fmt.Errorf("failed to add payment: currency is not set")
 	}
 
-	return nil
-}
-
-// AddPayment добавляет новый платеж
-func (db *DB) AddPayment(payment *models.Payment) error {
 	query := `
 	INSERT INTO payments 
-	(user_id, subscription_id, amount, payment_method, payment_id, status)
-	VALUES ($1, $2, $3, $4, $5, $6)
+	(user_id, subscription_id, amount, currency, payment_method, payment_id, status)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
 	RETURNING id, created_at, updated_at
 	`
 
-	row := db.QueryRow(query, payment.UserID, payment.SubscriptionID, payment.Amount,
+	row := q.QueryRowx(query, payment.UserID, payment.SubscriptionID, payment.Amount, payment.Currency,
 		payment.PaymentMethod, payment.PaymentID, payment.Status)
 
 	err := row.Scan(&payment.ID, &payment.CreatedAt, &payment.UpdatedAt)
@@ -593,9 +559,22 @@ func (db *DB) AddPayment(payment *models.Payment) error {
 	return nil
 }
 
-// UpdateSubscription обновляет данные подписки
+// UpdateSubscription обновляет данные подписки.
+// Если статус подписки изменился, переход записывается в историю статусов.
 func (db *DB) UpdateSubscription(subscription *models.Subscription) error {
-	_, err := db.NamedExec(`
+	tx, err := db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin subscription update: %w", err)
+	}
+	defer tx.Rollback()
+
+	var oldStatus string
+	err = tx.Get(&oldStatus, "SELECT status FROM subscriptions WHERE id = $1"+db.dialect.forUpdate, subscription.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update subscription: %w", err)
+	}
+
+	_, err = tx.NamedExec(`
 		UPDATE subscriptions SET 
 		status = :status, 
 		data_usage = :data_usage, 
@@ -608,5 +587,16 @@ func (db *DB) UpdateSubscription(subscription *models.Subscription) error {
 		return fmt.Errorf("failed to update subscription: %w", err)
 	}
 
+	if oldStatus != subscription.Status {
+		err = addSubscriptionStatusChange(tx, subscription.ID, oldStatus, subscription.Status, "")
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit subscription update: %w", err)
+	}
+
 	return nil
 }
@@ -0,0 +1,24 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ilokitv/botVPN/internal/config"
+)
+
+// newSQLiteTestDB создает временную базу SQLite со всеми миграциями
+func newSQLiteTestDB(t *testing.T) *DB {
+	t.Helper()
+
+	db, err := New(&config.DatabaseConfig{Driver: config.DriverSQLite, Path: filepath.Join(t.TempDir(), "bot.db")})
+	if err != nil {
+		t.Fatalf("open sqlite database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.MigrateUp(); err != nil {
+		t.Fatalf("migrate sqlite database: %v", err)
+	}
+	return db
+}
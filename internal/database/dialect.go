@@ -0,0 +1,94 @@
+package database
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"modernc.org/sqlite"
+
+	"github.com/ilokitv/botVPN/internal/config"
+)
+
+// sqliteTimeFormat - формат, в котором драйвер SQLite записывает время (параметр _time_format=sqlite).
+// Функция NOW() возвращает время в том же формате, чтобы значения можно было сравнивать как строки.
+const sqliteTimeFormat = "2006-01-02 15:04:05.999999999-07:00"
+
+// dialect описывает различия SQL между поддерживаемыми СУБД.
+// Запросы пишутся в общем подмножестве SQL (с параметрами $1, $2, ...), а отличающиеся фрагменты берутся отсюда.
+type dialect struct {
+	name        string // Имя драйвера database/sql
+	forUpdate   string // Блокировка выбранных строк до конца транзакции
+	ilike       string // Сравнение с шаблоном без учета регистра
+	migrations  string // Каталог встроенных миграций
+	tableExists string // Запрос, проверяющий существование таблицы с именем $1
+	lock        string // Блокировка на время применения миграций
+	unlock      string
+
+	// Выражение, переводящее столбец TIMESTAMP (вместо %s) во время UTC строкой, сравнимой с utcBound
+	utc string
+
+	// Начало дня, недели (с понедельника) и месяца для времени UTC (вместо %s) строкой YYYY-MM-DD.
+	// Границы интервалов считаются в UTC в обеих СУБД, как и недели когорт в пакете analytics.
+	periodStarts map[string]string
+}
+
+// utcBoundFormat - формат границы интервала в UTC для сравнения с выражением dialect.utc
+const utcBoundFormat = "2006-01-02 15:04:05"
+
+// utcBound возвращает границу интервала для сравнения со столбцом времени, переведенным в UTC
+func utcBound(t time.Time) string {
+	return t.UTC().Format(utcBoundFormat)
+}
+
+var postgresDialect = dialect{
+	name:        config.DriverPostgres,
+	forUpdate:   " FOR UPDATE",
+	ilike:       "ILIKE",
+	migrations:  "migrations/postgres",
+	tableExists: "SELECT to_regclass($1) IS NOT NULL",
+	lock:        fmt.Sprintf("SELECT pg_advisory_lock(%d)", migrationLockID),
+	unlock:      fmt.Sprintf("SELECT pg_advisory_unlock(%d)", migrationLockID),
+	// NOW() заполняет столбцы TIMESTAMP временем часового пояса сеанса, поэтому оно переводится в UTC явно
+	utc: "(%s AT TIME ZONE current_setting('TimeZone') AT TIME ZONE 'UTC')",
+	periodStarts: map[string]string{
+		"day":   "to_char(date_trunc('day', %s), 'YYYY-MM-DD')",
+		"week":  "to_char(date_trunc('week', %s), 'YYYY-MM-DD')",
+		"month": "to_char(date_trunc('month', %s), 'YYYY-MM-DD')",
+	},
+}
+
+// В SQLite транзакции блокируют запись во всю базу сразу (_txlock=immediate), поэтому блокировка строк не нужна.
+// LIKE в SQLite не учитывает регистр только для латиницы. Время хранится со смещением часового пояса,
+// и datetime() переводит его в UTC.
+var sqliteDialect = dialect{
+	name:        config.DriverSQLite,
+	forUpdate:   "",
+	ilike:       "LIKE",
+	migrations:  "migrations/sqlite",
+	tableExists: "SELECT COUNT(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = $1",
+	utc:         "datetime(%s)",
+	periodStarts: map[string]string{
+		"day":   "date(%s)",
+		"week":  "date(%s, 'weekday 0', '-6 days')",
+		"month": "date(%s, 'start of month')",
+	},
+}
+
+func init() {
+	// sqlx не знает, какие параметры поддерживает драйвер modernc.org/sqlite; для sqlx.In используются "?"
+	sqlx.BindDriver(config.DriverSQLite, sqlx.QUESTION)
+
+	// NOW() используется в запросах и значениях по умолчанию так же, как в PostgreSQL
+	sqlite.MustRegisterScalarFunction("now", 0, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		return time.Now().Format(sqliteTimeFormat), nil
+	})
+}
+
+// openSQLite открывает файл базы данных SQLite с включенными внешними ключами
+func openSQLite(path string) (*sqlx.DB, error) {
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"+
+		"&_txlock=immediate&_time_format=sqlite", path)
+	return sqlx.Connect(config.DriverSQLite, dsn)
+}
@@ -0,0 +1,21 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+// AddFailedMessage сохраняет сообщение, которое не удалось доставить
+func (db *DB) AddFailedMessage(message *models.FailedMessage) error {
+	err := db.QueryRow(`
+		INSERT INTO failed_messages (chat_id, method, payload, error_code, error, attempts)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, message.ChatID, message.Method, message.Payload, message.ErrorCode, message.Error, message.Attempts,
+	).Scan(&message.ID, &message.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add failed message: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+// AddGiftCode добавляет новый подарочный код
+func (db *DB) AddGiftCode(gift *models.GiftCode) error {
+	query := `
+	INSERT INTO gift_codes (code, plan_id, buyer_user_id, payment_id, status, expires_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING id, created_at, updated_at
+	`
+
+	row := db.QueryRow(query, gift.Code, gift.PlanID, gift.BuyerUserID, gift.PaymentID,
+		gift.Status, gift.ExpiresAt)
+
+	err := row.Scan(&gift.ID, &gift.CreatedAt, &gift.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add gift code: %w", err)
+	}
+
+	return nil
+}
+
+// GetGiftCodeByCode возвращает подарочный код по его значению
+func (db *DB) GetGiftCodeByCode(code string) (*models.GiftCode, error) {
+	var gift models.GiftCode
+	err := db.Get(&gift, "SELECT * FROM gift_codes WHERE code = $1", code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gift code: %w", err)
+	}
+	return &gift, nil
+}
+
+// GetGiftCodesByBuyerID возвращает подарочные коды, купленные пользователем
+func (db *DB) GetGiftCodesByBuyerID(userID int) ([]models.GiftCode, error) {
+	var gifts []models.GiftCode
+	err := db.Select(&gifts, "SELECT * FROM gift_codes WHERE buyer_user_id = $1 ORDER BY created_at DESC", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gift codes by buyer: %w", err)
+	}
+	return gifts, nil
+}
+
+// ClaimGiftCode атомарно помечает активный и не просроченный код как использованный.
+// Возвращает sql.ErrNoRows (обернутую), если код не найден, уже использован или истек.
+func (db *DB) ClaimGiftCode(code string, userID int) (*models.GiftCode, error) {
+	var gift models.GiftCode
+	err := db.Get(&gift, `
+		UPDATE gift_codes
+		SET status = 'redeemed', redeemed_by_user_id = $2, redeemed_at = NOW(), updated_at = NOW()
+		WHERE code = $1 AND status = 'active' AND expires_at > NOW()
+		RETURNING *
+	`, code, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim gift code: %w", err)
+	}
+	return &gift, nil
+}
+
+// ReleaseGiftCode возвращает код в активное состояние, если выдать подписку не удалось
+func (db *DB) ReleaseGiftCode(giftID int) error {
+	_, err := db.Exec(`
+		UPDATE gift_codes
+		SET status = 'active', redeemed_by_user_id = NULL, redeemed_at = NULL, updated_at = NOW()
+		WHERE id = $1
+	`, giftID)
+	if err != nil {
+		return fmt.Errorf("failed to release gift code: %w", err)
+	}
+	return nil
+}
+
+// SetGiftCodeSubscription связывает использованный код с выданной подпиской
+func (db *DB) SetGiftCodeSubscription(giftID, subscriptionID int) error {
+	_, err := db.Exec("UPDATE gift_codes SET subscription_id = $1, updated_at = NOW() WHERE id = $2",
+		subscriptionID, giftID)
+	if err != nil {
+		return fmt.Errorf("failed to set gift code subscription: %w", err)
+	}
+	return nil
+}
+
+// ExpireGiftCodes помечает просроченные неиспользованные коды как истекшие
+func (db *DB) ExpireGiftCodes() (int64, error) {
+	result, err := db.Exec(`
+		UPDATE gift_codes SET status = 'expired', updated_at = NOW()
+		WHERE status = 'active' AND expires_at <= NOW()
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire gift codes: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,149 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ilokitv/botVPN/internal/models"
+	"github.com/ilokitv/botVPN/internal/money"
+)
+
+// ErrInsufficientFunds возвращается, если на балансе недостаточно средств для списания
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// Системные счета, на которые приходится вторая проводка каждой операции
+const (
+	accountExternal   = "external"   // Деньги, поступившие извне (платежи Telegram)
+	accountRevenue    = "revenue"    // Выручка от продажи подписок
+	accountBonus      = "bonus"      // Расходы на бонусы
+	accountAdjustment = "adjustment" // Ручные корректировки
+)
+
+// userAccount возвращает имя счета баланса пользователя
+func userAccount(userID int) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+// counterAccount возвращает системный счет для второй проводки операции указанного типа
+func counterAccount(transactionType string) (string, error) {
+	switch transactionType {
+	case models.LedgerTopUp:
+		return accountExternal, nil
+	case models.LedgerPurchase, models.LedgerRefund:
+		return accountRevenue, nil
+	case models.LedgerBonus:
+		return accountBonus, nil
+	case models.LedgerAdjustment:
+		return accountAdjustment, nil
+	default:
+		return "", fmt.Errorf("unknown ledger transaction type: %s", transactionType)
+	}
+}
+
+// PostLedgerTransaction проводит операцию по балансу пользователя. Суммы указаны в копейках (models.LedgerCurrency).
+// Сумма операции списывается (отрицательная) или зачисляется (положительная) на счет пользователя,
+// а противоположная проводка записывается на системный счет. Для покупок проверяется достаточность средств.
+func (db *DB) PostLedgerTransaction(transaction *models.LedgerTransaction) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin ledger transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := db.postLedgerTransaction(tx, transaction); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit ledger transaction: %w", err)
+	}
+
+	logLedgerTransaction(transaction)
+	return nil
+}
+
+// postLedgerTransaction проводит операцию по балансу пользователя в рамках транзакции tx
+func (db *DB) postLedgerTransaction(tx *sqlx.Tx, transaction *models.LedgerTransaction) error {
+	if transaction.Amount == 0 {
+		return fmt.Errorf("invalid ledger transaction amount: %v", transaction.Amount)
+	}
+
+	counter, err := counterAccount(transaction.Type)
+	if err != nil {
+		return err
+	}
+
+	// Блокируем пользователя, чтобы параллельные операции не могли уйти в минус
+	var lockedID int
+	err = tx.Get(&lockedID, "SELECT id FROM users WHERE id = $1"+db.dialect.forUpdate, transaction.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to lock user for ledger transaction: %w", err)
+	}
+
+	var balance int64
+	err = tx.Get(&balance, "SELECT COALESCE(SUM(amount), 0) FROM ledger_entries WHERE account = $1",
+		userAccount(transaction.UserID))
+	if err != nil {
+		return fmt.Errorf("failed to get user balance: %w", err)
+	}
+
+	transaction.BalanceAfter = balance + transaction.Amount
+	if transaction.Amount < 0 && transaction.BalanceAfter < 0 && transaction.Type == models.LedgerPurchase {
+		return ErrInsufficientFunds
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO ledger_transactions (user_id, type, amount, balance_after, description, payment_id, actor_user_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`, transaction.UserID, transaction.Type, transaction.Amount, transaction.BalanceAfter,
+		transaction.Description, transaction.PaymentID, transaction.ActorUserID,
+	).Scan(&transaction.ID, &transaction.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add ledger transaction: %w", err)
+	}
+
+	// Две проводки с нулевой суммой
+	_, err = tx.Exec(`
+		INSERT INTO ledger_entries (transaction_id, account, amount)
+		VALUES ($1, $2, $3), ($1, $4, $5)
+	`, transaction.ID, userAccount(transaction.UserID), transaction.Amount, counter, -transaction.Amount)
+	if err != nil {
+		return fmt.Errorf("failed to add ledger entries: %w", err)
+	}
+
+	return nil
+}
+
+// logLedgerTransaction записывает в журнал проведенную операцию по балансу
+func logLedgerTransaction(transaction *models.LedgerTransaction) {
+	log.Printf("Операция по балансу #%d: пользователь #%d, тип %s, сумма %s, баланс %s",
+		transaction.ID, transaction.UserID, transaction.Type,
+		money.Format(transaction.Amount, models.LedgerCurrency), money.Format(transaction.BalanceAfter, models.LedgerCurrency))
+}
+
+// GetUserBalance возвращает текущий баланс пользователя в копейках
+func (db *DB) GetUserBalance(userID int) (int64, error) {
+	var balance int64
+	err := db.Get(&balance, "SELECT COALESCE(SUM(amount), 0) FROM ledger_entries WHERE account = $1",
+		userAccount(userID))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get user balance: %w", err)
+	}
+	return balance, nil
+}
+
+// GetLedgerTransactionsByUserID возвращает последние операции по балансу пользователя
+func (db *DB) GetLedgerTransactionsByUserID(userID int, limit int) ([]models.LedgerTransaction, error) {
+	var transactions []models.LedgerTransaction
+	err := db.Select(&transactions,
+		"SELECT * FROM ledger_transactions WHERE user_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2",
+		userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger transactions: %w", err)
+	}
+	return transactions, nil
+}
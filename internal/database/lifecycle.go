@@ -0,0 +1,213 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+// ErrNoAvailableServer возвращается, если ни на одном активном сервере нет свободных мест
+var ErrNoAvailableServer = errors.New("no available server")
+
+// changeSubscriptionStatus переводит подписку из статуса fromStatus в toStatus в рамках транзакции
+// и записывает переход в историю. Возвращает sql.ErrNoRows (обернутую), если подписка уже в другом статусе.
+func changeSubscriptionStatus(tx *sqlx.Tx, subscriptionID int, fromStatus, toStatus, reason string) error {
+	result, err := tx.Exec(`
+		UPDATE subscriptions SET status = $1, updated_at = NOW()
+		WHERE id = $2 AND status = $3
+	`, toStatus, subscriptionID, fromStatus)
+	if err != nil {
+		return fmt.Errorf("failed to change subscription status: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to change subscription status: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("failed to change subscription status: %w", sql.ErrNoRows)
+	}
+
+	return addSubscriptionStatusChange(tx, subscriptionID, fromStatus, toStatus, reason)
+}
+
+// releaseServerSlot освобождает место на сервере подписки
+func releaseServerSlot(tx *sqlx.Tx, subscriptionID int) error {
+	_, err := tx.Exec(`
+		UPDATE servers SET current_clients = current_clients - 1, updated_at = NOW()
+		WHERE id = (SELECT server_id FROM subscriptions WHERE id = $1) AND current_clients > 0
+	`, subscriptionID)
+	if err != nil {
+		return fmt.Errorf("failed to release server slot: %w", err)
+	}
+	return nil
+}
+
+// ReserveSubscription выбирает активный сервер со свободным местом, занимает на нем место
+// и сохраняет подписку в статусе "pending". ServerID, Status, ID и даты создания заполняются в subscription.
+// Возвращает ErrNoAvailableServer, если свободных мест нет.
+func (db *DB) ReserveSubscription(subscription *models.Subscription, reason string) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin subscription reservation: %w", err)
+	}
+	defer tx.Rollback()
+
+	var serverID int
+	err = tx.Get(&serverID, `
+		SELECT id FROM servers
+		WHERE is_active = TRUE AND deleted_at IS NULL AND current_clients < max_clients
+		ORDER BY id
+		LIMIT 1`+db.dialect.forUpdate)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNoAvailableServer
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find available server: %w", err)
+	}
+
+	_, err = tx.Exec("UPDATE servers SET current_clients = current_clients + 1, updated_at = NOW() WHERE id = $1", serverID)
+	if err != nil {
+		return fmt.Errorf("failed to reserve server slot: %w", err)
+	}
+
+	subscription.ServerID = serverID
+	subscription.Status = "pending"
+	err = tx.QueryRowx(`
+		INSERT INTO subscriptions
+		(user_id, server_id, plan_id, start_date, end_date, status, config_file_path, is_trial)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at
+	`, subscription.UserID, subscription.ServerID, subscription.PlanID, subscription.StartDate, subscription.EndDate,
+		subscription.Status, subscription.ConfigFilePath, subscription.IsTrial,
+	).Scan(&subscription.ID, &subscription.CreatedAt, &subscription.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add subscription: %w", err)
+	}
+
+	if err := addSubscriptionStatusChange(tx, subscription.ID, "", "pending", reason); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit subscription reservation: %w", err)
+	}
+
+	return nil
+}
+
+// ActivateSubscription переводит подписку из "pending" в "active" после выдачи конфигурации.
+// Платеж за подписку (если он передан) сохраняется в той же транзакции.
+func (db *DB) ActivateSubscription(subscriptionID int, configFilePath string, payment *models.Payment) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin subscription activation: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("UPDATE subscriptions SET config_file_path = $1 WHERE id = $2", configFilePath, subscriptionID)
+	if err != nil {
+		return fmt.Errorf("failed to save subscription config: %w", err)
+	}
+
+	if err := changeSubscriptionStatus(tx, subscriptionID, "pending", "active", "Конфигурация выдана"); err != nil {
+		return err
+	}
+
+	if payment != nil {
+		payment.SubscriptionID = &subscriptionID
+		if err := insertPayment(tx, payment); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit subscription activation: %w", err)
+	}
+
+	return nil
+}
+
+// FinishSubscription переводит подписку из fromStatus в конечный статус toStatus ("expired" или "revoked")
+// и освобождает ее место на сервере.
+func (db *DB) FinishSubscription(subscriptionID int, fromStatus, toStatus, reason string) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin subscription finish: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := changeSubscriptionStatus(tx, subscriptionID, fromStatus, toStatus, reason); err != nil {
+		return err
+	}
+
+	if err := releaseServerSlot(tx, subscriptionID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit subscription finish: %w", err)
+	}
+
+	return nil
+}
+
+// ChangeSubscriptionPlan переводит активную подписку на другой план без смены пира.
+// Новый период подписки начинается с startDate и заканчивается в endDate. Платеж за доплату
+// и возврат излишка на баланс (если они переданы) сохраняются в той же транзакции:
+// при любой ошибке не сохраняется ничего.
+func (db *DB) ChangeSubscriptionPlan(subscriptionID, planID int, startDate, endDate time.Time, payment *models.Payment, refund *models.LedgerTransaction) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin subscription plan change: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE subscriptions SET
+		plan_id = $1,
+		start_date = $2,
+		end_date = $3,
+		is_trial = FALSE,
+		updated_at = NOW()
+		WHERE id = $4 AND status = 'active'
+	`, planID, startDate, endDate, subscriptionID)
+	if err != nil {
+		return fmt.Errorf("failed to change subscription plan: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to change subscription plan: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("failed to change subscription plan: %w", sql.ErrNoRows)
+	}
+
+	if payment != nil {
+		payment.SubscriptionID = &subscriptionID
+		if err := insertPayment(tx, payment); err != nil {
+			return err
+		}
+	}
+
+	if refund != nil {
+		if err := db.postLedgerTransaction(tx, refund); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit subscription plan change: %w", err)
+	}
+
+	if refund != nil {
+		logLedgerTransaction(refund)
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//go:embed migrations
+var migrationFiles embed.FS
+
+// migrationLockID - ключ advisory-блокировки PostgreSQL, чтобы два процесса не применяли миграции одновременно
+const migrationLockID = 48_151_623
+
+// migrationFileName - формат имени файла миграции: 0002_indexes.up.sql и 0002_indexes.down.sql
+var migrationFileName = regexp.MustCompile(`^(\d{4})_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration - версия схемы базы данных со скриптами применения и отката
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus - состояние миграции в базе данных
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time // nil, если миграция не применена
+	Unknown   bool       // Миграция применена, но отсутствует в этой версии бота
+}
+
+// String возвращает имя миграции вместе с версией, например 0002_indexes
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// loadMigrations читает встроенные миграции для СУБД из каталога dir, упорядоченные по версии
+func loadMigrations(dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		parts := migrationFileName.FindStringSubmatch(entry.Name())
+		if parts == nil {
+			return nil, fmt.Errorf("unexpected migration file name %q", entry.Name())
+		}
+		version, _ := strconv.Atoi(parts[1])
+
+		data, err := migrationFiles.ReadFile(dir + "/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: parts[2]}
+			byVersion[version] = migration
+		} else if migration.Name != parts[2] {
+			return nil, fmt.Errorf("migration %04d has two names: %q and %q", version, migration.Name, parts[2])
+		}
+
+		if parts[3] == "up" {
+			migration.Up = string(data)
+		} else {
+			migration.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %s must have both up and down scripts", migration)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// appliedMigration - запись о примененной миграции в таблице schema_migrations
+type appliedMigration struct {
+	Version   int       `db:"version"`
+	Name      string    `db:"name"`
+	AppliedAt time.Time `db:"applied_at"`
+}
+
+// appliedMigrations возвращает примененные миграции по версиям. Если таблицы schema_migrations еще нет, миграций нет.
+func (db *DB) appliedMigrations() (map[int]appliedMigration, error) {
+	var exists bool
+	if err := db.Get(&exists, db.dialect.tableExists, "schema_migrations"); err != nil {
+		return nil, fmt.Errorf("failed to check schema_migrations table: %w", err)
+	}
+
+	applied := make(map[int]appliedMigration)
+	if !exists {
+		return applied, nil
+	}
+
+	var rows []appliedMigration
+	if err := db.Select(&rows, "SELECT version, name, applied_at FROM schema_migrations"); err != nil {
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// withMigrationLock выполняет fn на отдельном соединении под блокировкой миграций, если СУБД ее поддерживает.
+// SQLite блокирует файл базы на время каждой транзакции, поэтому отдельная блокировка не нужна.
+func (db *DB) withMigrationLock(fn func(ctx context.Context, conn *sql.Conn) error) error {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if db.dialect.lock != "" {
+		if _, err := conn.ExecContext(ctx, db.dialect.lock); err != nil {
+			return fmt.Errorf("failed to lock migrations: %w", err)
+		}
+		defer conn.ExecContext(ctx, db.dialect.unlock)
+	}
+
+	_, err = conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	return fn(ctx, conn)
+}
+
+// runMigration выполняет скрипт миграции и изменение schema_migrations в одной транзакции
+func runMigration(ctx context.Context, conn *sql.Conn, script, bookkeeping string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, bookkeeping, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// MigrateUp применяет все непримененные миграции по порядку и возвращает примененные
+func (db *DB) MigrateUp() ([]Migration, error) {
+	migrations, err := loadMigrations(db.dialect.migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	err = db.withMigrationLock(func(ctx context.Context, conn *sql.Conn) error {
+		applied, err := db.appliedMigrations()
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			err := runMigration(ctx, conn, migration.Up,
+				"INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)",
+				migration.Version, migration.Name, time.Now())
+			if err != nil {
+				return fmt.Errorf("failed to apply migration %s: %w", migration, err)
+			}
+			log.Printf("Применена миграция %s", migration)
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// MigrateDown откатывает последнюю примененную миграцию. Если откатывать нечего, возвращает nil.
+func (db *DB) MigrateDown() (*Migration, error) {
+	migrations, err := loadMigrations(db.dialect.migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	var rolledBack *Migration
+	err = db.withMigrationLock(func(ctx context.Context, conn *sql.Conn) error {
+		applied, err := db.appliedMigrations()
+		if err != nil {
+			return err
+		}
+
+		last := 0
+		for version := range applied {
+			if version > last {
+				last = version
+			}
+		}
+		if last == 0 {
+			return nil
+		}
+
+		for i := range migrations {
+			if migrations[i].Version != last {
+				continue
+			}
+			migration := migrations[i]
+			err := runMigration(ctx, conn, migration.Down, "DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+			if err != nil {
+				return fmt.Errorf("failed to roll back migration %s: %w", migration, err)
+			}
+			log.Printf("Откачена миграция %s", migration)
+			rolledBack = &migration
+			return nil
+		}
+		return fmt.Errorf("migration %04d_%s is applied but unknown to this build", last, applied[last].Name)
+	})
+	return rolledBack, err
+}
+
+// MigrationStatus возвращает состояние всех известных миграций и примененных миграций, неизвестных этой версии бота
+func (db *DB) MigrationStatus() ([]MigrationStatus, error) {
+	migrations, err := loadMigrations(db.dialect.migrations)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := db.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	known := make(map[int]bool)
+	for _, migration := range migrations {
+		known[migration.Version] = true
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	for version, row := range applied {
+		if known[version] {
+			continue
+		}
+		appliedAt := row.AppliedAt
+		statuses = append(statuses, MigrationStatus{Version: version, Name: row.Name, AppliedAt: &appliedAt, Unknown: true})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// CheckMigrations проверяет при запуске, что схема базы данных соответствует версии бота
+func (db *DB) CheckMigrations() error {
+	statuses, err := db.MigrationStatus()
+	if err != nil {
+		return err
+	}
+
+	pending := 0
+	for _, status := range statuses {
+		if status.Unknown {
+			return fmt.Errorf("database has migration %04d_%s that is unknown to this build; update the bot", status.Version, status.Name)
+		}
+		if status.AppliedAt == nil {
+			pending++
+		}
+	}
+	if pending > 0 {
+		return fmt.Errorf("database schema is out of date: %d pending migrations; run \"migrate up\"", pending)
+	}
+	return nil
+}
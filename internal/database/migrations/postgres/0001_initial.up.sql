@@ -0,0 +1,242 @@
+-- Исходная схема базы данных.
+-- Таблицы создаются с IF NOT EXISTS, а недостающие колонки добавляются в конце,
+-- поэтому миграция применяется и к базам, созданным до появления миграций.
+
+-- Создаем таблицу для серверов
+CREATE TABLE IF NOT EXISTS servers (
+    id SERIAL PRIMARY KEY,
+    ip TEXT NOT NULL,
+    port INTEGER NOT NULL,
+    ssh_user TEXT NOT NULL,
+    ssh_password TEXT NOT NULL,
+    max_clients INTEGER NOT NULL DEFAULT 10,
+    current_clients INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Создаем таблицу для планов подписок
+CREATE TABLE IF NOT EXISTS subscription_plans (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    price REAL NOT NULL,
+    duration INTEGER NOT NULL, 
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    is_trial BOOLEAN NOT NULL DEFAULT FALSE,
+    max_pause_days INTEGER NOT NULL DEFAULT 0,
+    grace_days INTEGER NOT NULL DEFAULT 3,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Создаем таблицу для пользователей
+CREATE TABLE IF NOT EXISTS users (
+    id SERIAL PRIMARY KEY,
+    telegram_id BIGINT NOT NULL UNIQUE,
+    username TEXT,
+    first_name TEXT,
+    last_name TEXT,
+    is_admin BOOLEAN NOT NULL DEFAULT FALSE,
+    role TEXT NOT NULL DEFAULT '',
+    trial_used_at TIMESTAMP,
+    language TEXT NOT NULL DEFAULT '',
+    language_code TEXT NOT NULL DEFAULT '',
+    bot_blocked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Создаем таблицу для подписок
+CREATE TABLE IF NOT EXISTS subscriptions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    server_id INTEGER NOT NULL REFERENCES servers(id),
+    plan_id INTEGER NOT NULL REFERENCES subscription_plans(id),
+    start_date TIMESTAMP NOT NULL,
+    end_date TIMESTAMP NOT NULL,
+    status TEXT NOT NULL,
+    config_file_path TEXT,
+    data_usage BIGINT NOT NULL DEFAULT 0,
+    last_connection_at TIMESTAMP,
+    is_trial BOOLEAN NOT NULL DEFAULT FALSE,
+    paused_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Создаем таблицу для платежей
+CREATE TABLE IF NOT EXISTS payments (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    subscription_id INTEGER REFERENCES subscriptions(id),
+    amount REAL NOT NULL,
+    payment_method TEXT NOT NULL,
+    payment_id TEXT,
+    status TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Создаем таблицу для подарочных кодов
+CREATE TABLE IF NOT EXISTS gift_codes (
+    id SERIAL PRIMARY KEY,
+    code TEXT NOT NULL UNIQUE,
+    plan_id INTEGER NOT NULL REFERENCES subscription_plans(id),
+    buyer_user_id INTEGER NOT NULL REFERENCES users(id),
+    payment_id INTEGER REFERENCES payments(id),
+    status TEXT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    redeemed_by_user_id INTEGER REFERENCES users(id),
+    subscription_id INTEGER REFERENCES subscriptions(id),
+    redeemed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Создаем таблицы для учета баланса пользователей (двойная запись)
+CREATE TABLE IF NOT EXISTS ledger_transactions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    type TEXT NOT NULL,
+    amount REAL NOT NULL,
+    balance_after REAL NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    payment_id INTEGER REFERENCES payments(id),
+    actor_user_id INTEGER REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL REFERENCES ledger_transactions(id),
+    account TEXT NOT NULL,
+    amount REAL NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_account ON ledger_entries(account);
+
+-- Создаем таблицу истории смены статусов подписок
+CREATE TABLE IF NOT EXISTS subscription_status_history (
+    id SERIAL PRIMARY KEY,
+    subscription_id INTEGER NOT NULL REFERENCES subscriptions(id),
+    old_status TEXT NOT NULL,
+    new_status TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_status_history_subscription ON subscription_status_history(subscription_id);
+
+-- Создаем журнал отправленных напоминаний об окончании подписки
+CREATE TABLE IF NOT EXISTS subscription_notifications (
+    id SERIAL PRIMARY KEY,
+    subscription_id INTEGER NOT NULL REFERENCES subscriptions(id),
+    offset_minutes INTEGER NOT NULL,
+    end_date TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (subscription_id, offset_minutes, end_date)
+);
+
+-- Создаем таблицу состояний диалогов
+CREATE TABLE IF NOT EXISTS conversation_states (
+    chat_id BIGINT PRIMARY KEY,
+    state TEXT NOT NULL,
+    previous_state TEXT NOT NULL DEFAULT '',
+    data JSONB NOT NULL DEFAULT '{}',
+    expires_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Создаем журнал действий сотрудников
+CREATE TABLE IF NOT EXISTS audit_log (
+    id SERIAL PRIMARY KEY,
+    actor_user_id INTEGER REFERENCES users(id),
+    actor_telegram_id BIGINT NOT NULL,
+    action TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id INTEGER NOT NULL,
+    before_value JSONB NOT NULL DEFAULT '{}',
+    after_value JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
+
+-- Создаем таблицу рассылок
+CREATE TABLE IF NOT EXISTS broadcasts (
+    id SERIAL PRIMARY KEY,
+    created_by BIGINT NOT NULL,
+    source_chat_id BIGINT NOT NULL,
+    source_message_id INTEGER NOT NULL,
+    buttons JSONB NOT NULL DEFAULT '[]',
+    segment TEXT NOT NULL,
+    segment_value INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'running',
+    total INTEGER NOT NULL DEFAULT 0,
+    sent INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    blocked INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP
+);
+
+-- Создаем таблицы обращений в поддержку
+CREATE TABLE IF NOT EXISTS support_tickets (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    subscription_id INTEGER REFERENCES subscriptions(id),
+    status TEXT NOT NULL DEFAULT 'open',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    closed_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS support_messages (
+    id SERIAL PRIMARY KEY,
+    ticket_id INTEGER NOT NULL REFERENCES support_tickets(id),
+    sender_telegram_id BIGINT NOT NULL,
+    from_staff BOOLEAN NOT NULL DEFAULT FALSE,
+    text TEXT NOT NULL DEFAULT '',
+    photo_file_id TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_support_messages_ticket ON support_messages(ticket_id);
+
+-- Связь сообщений, пересланных сотрудникам и пользователю, с обращениями
+CREATE TABLE IF NOT EXISTS support_relays (
+    chat_id BIGINT NOT NULL,
+    message_id INTEGER NOT NULL,
+    ticket_id INTEGER NOT NULL REFERENCES support_tickets(id),
+    PRIMARY KEY (chat_id, message_id)
+);
+
+-- Создаем таблицу сообщений, которые не удалось доставить
+CREATE TABLE IF NOT EXISTS failed_messages (
+    id SERIAL PRIMARY KEY,
+    chat_id BIGINT NOT NULL,
+    method VARCHAR(64) NOT NULL,
+    payload TEXT NOT NULL DEFAULT '',
+    error_code INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_failed_messages_chat ON failed_messages(chat_id);
+
+-- Колонки, которые добавлялись в уже существующие таблицы до появления миграций
+ALTER TABLE subscription_plans ADD COLUMN IF NOT EXISTS is_trial BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE subscription_plans ADD COLUMN IF NOT EXISTS max_pause_days INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE subscription_plans ADD COLUMN IF NOT EXISTS grace_days INTEGER NOT NULL DEFAULT 3;
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS is_trial BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS paused_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS trial_used_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS language TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS language_code TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT '';
+UPDATE users SET role = 'admin' WHERE is_admin = TRUE AND role = '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS bot_blocked_at TIMESTAMP;
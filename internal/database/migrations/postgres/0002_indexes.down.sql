@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_payments_user;
+DROP INDEX IF EXISTS idx_subscriptions_server;
+DROP INDEX IF EXISTS idx_subscriptions_status_end_date;
+DROP INDEX IF EXISTS idx_subscriptions_user_status;
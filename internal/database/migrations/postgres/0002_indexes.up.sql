@@ -0,0 +1,5 @@
+-- Индексы для частых запросов: подписки и платежи пользователя, проверка подписок по статусу и сроку
+CREATE INDEX IF NOT EXISTS idx_subscriptions_user_status ON subscriptions(user_id, status);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_status_end_date ON subscriptions(status, end_date);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_server ON subscriptions(server_id);
+CREATE INDEX IF NOT EXISTS idx_payments_user ON payments(user_id);
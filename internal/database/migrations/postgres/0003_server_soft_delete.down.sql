@@ -0,0 +1,3 @@
+-- Удаленные серверы, на которые никто не ссылается, удаляются окончательно, остальные возвращаются неактивными
+DELETE FROM servers s WHERE s.deleted_at IS NOT NULL AND NOT EXISTS (SELECT 1 FROM subscriptions WHERE server_id = s.id);
+ALTER TABLE servers DROP COLUMN deleted_at;
@@ -0,0 +1,2 @@
+-- Пересчет счетчиков не откатывается: прежние значения были завышены
+SELECT 1;
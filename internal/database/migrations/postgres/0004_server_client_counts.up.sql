@@ -0,0 +1,6 @@
+-- Раньше место на сервере не освобождалось при истечении и отзыве подписок.
+-- Пересчитываем занятые места по подпискам, у которых на сервере есть пир.
+UPDATE servers SET current_clients = (
+    SELECT COUNT(*) FROM subscriptions
+    WHERE server_id = servers.id AND status IN ('pending', 'active', 'paused', 'grace')
+);
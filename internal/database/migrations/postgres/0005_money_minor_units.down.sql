@@ -0,0 +1,13 @@
+-- Суммы возвращаются к рублям с плавающей точкой. Цены в дополнительных валютах теряются,
+-- суммы платежей в других валютах после отката нельзя отличить от рублевых.
+DROP TABLE IF EXISTS plan_prices;
+
+ALTER TABLE ledger_entries ALTER COLUMN amount TYPE REAL USING amount / 100.0;
+ALTER TABLE ledger_transactions ALTER COLUMN balance_after TYPE REAL USING balance_after / 100.0;
+ALTER TABLE ledger_transactions ALTER COLUMN amount TYPE REAL USING amount / 100.0;
+
+ALTER TABLE payments DROP COLUMN IF EXISTS currency;
+ALTER TABLE payments ALTER COLUMN amount TYPE REAL USING amount / 100.0;
+
+ALTER TABLE subscription_plans DROP COLUMN IF EXISTS currency;
+ALTER TABLE subscription_plans ALTER COLUMN price TYPE REAL USING price / 100.0;
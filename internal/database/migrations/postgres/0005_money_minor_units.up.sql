@@ -0,0 +1,22 @@
+-- Денежные суммы хранятся целым числом минимальных единиц валюты, у планов и платежей появляется валюта.
+-- До этой миграции бот выставлял счета только в рублях, поэтому все существующие суммы (цены планов, платежи
+-- и баланс) считаются рублевыми: они переводятся в копейки и получают валюту RUB. Если в базе вручную
+-- сохранялись суммы в другой валюте, их нужно исправить после миграции.
+ALTER TABLE subscription_plans ALTER COLUMN price TYPE BIGINT USING ROUND(price::NUMERIC * 100)::BIGINT;
+ALTER TABLE subscription_plans ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'RUB';
+
+ALTER TABLE payments ALTER COLUMN amount TYPE BIGINT USING ROUND(amount::NUMERIC * 100)::BIGINT;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'RUB';
+
+-- Баланс пользователей ведется в рублях
+ALTER TABLE ledger_transactions ALTER COLUMN amount TYPE BIGINT USING ROUND(amount::NUMERIC * 100)::BIGINT;
+ALTER TABLE ledger_transactions ALTER COLUMN balance_after TYPE BIGINT USING ROUND(balance_after::NUMERIC * 100)::BIGINT;
+ALTER TABLE ledger_entries ALTER COLUMN amount TYPE BIGINT USING ROUND(amount::NUMERIC * 100)::BIGINT;
+
+-- Цены планов в дополнительных валютах (основная цена хранится в самом плане)
+CREATE TABLE IF NOT EXISTS plan_prices (
+    plan_id INTEGER NOT NULL REFERENCES subscription_plans(id),
+    currency TEXT NOT NULL,
+    amount BIGINT NOT NULL,
+    PRIMARY KEY (plan_id, currency)
+);
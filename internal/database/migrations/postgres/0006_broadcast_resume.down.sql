@@ -0,0 +1 @@
+ALTER TABLE broadcasts DROP COLUMN last_user_id;
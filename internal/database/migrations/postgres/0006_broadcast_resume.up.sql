@@ -0,0 +1,2 @@
+-- Рассылка, прерванная остановкой бота, продолжается после запуска с пользователя, следующего за последним обработанным
+ALTER TABLE broadcasts ADD COLUMN last_user_id INTEGER NOT NULL DEFAULT 0;
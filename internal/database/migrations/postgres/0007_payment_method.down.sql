@@ -0,0 +1 @@
+UPDATE payments SET payment_method = 'telegram_stars' WHERE payment_method = 'telegram_provider';
@@ -0,0 +1,18 @@
+-- Удаляет всю схему базы данных вместе с данными
+DROP TABLE IF EXISTS failed_messages;
+DROP TABLE IF EXISTS support_relays;
+DROP TABLE IF EXISTS support_messages;
+DROP TABLE IF EXISTS support_tickets;
+DROP TABLE IF EXISTS broadcasts;
+DROP TABLE IF EXISTS audit_log;
+DROP TABLE IF EXISTS conversation_states;
+DROP TABLE IF EXISTS subscription_notifications;
+DROP TABLE IF EXISTS subscription_status_history;
+DROP TABLE IF EXISTS ledger_entries;
+DROP TABLE IF EXISTS ledger_transactions;
+DROP TABLE IF EXISTS gift_codes;
+DROP TABLE IF EXISTS payments;
+DROP TABLE IF EXISTS subscriptions;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS subscription_plans;
+DROP TABLE IF EXISTS servers;
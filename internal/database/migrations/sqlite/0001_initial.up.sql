@@ -0,0 +1,229 @@
+-- Исходная схема базы данных для SQLite.
+-- Время хранится текстом в формате драйвера, а NOW() - функция, которую регистрирует бот,
+-- поэтому значения по умолчанию вычисляются только при работе через бота.
+
+-- Создаем таблицу для серверов
+CREATE TABLE IF NOT EXISTS servers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    ip TEXT NOT NULL,
+    port INTEGER NOT NULL,
+    ssh_user TEXT NOT NULL,
+    ssh_password TEXT NOT NULL,
+    max_clients INTEGER NOT NULL DEFAULT 10,
+    current_clients INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+-- Создаем таблицу для планов подписок
+CREATE TABLE IF NOT EXISTS subscription_plans (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    price REAL NOT NULL,
+    duration INTEGER NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    is_trial BOOLEAN NOT NULL DEFAULT FALSE,
+    max_pause_days INTEGER NOT NULL DEFAULT 0,
+    grace_days INTEGER NOT NULL DEFAULT 3,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+-- Создаем таблицу для пользователей
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    telegram_id BIGINT NOT NULL UNIQUE,
+    username TEXT,
+    first_name TEXT,
+    last_name TEXT,
+    is_admin BOOLEAN NOT NULL DEFAULT FALSE,
+    role TEXT NOT NULL DEFAULT '',
+    trial_used_at TIMESTAMP,
+    language TEXT NOT NULL DEFAULT '',
+    language_code TEXT NOT NULL DEFAULT '',
+    bot_blocked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+-- Создаем таблицу для подписок
+CREATE TABLE IF NOT EXISTS subscriptions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    server_id INTEGER NOT NULL REFERENCES servers(id),
+    plan_id INTEGER NOT NULL REFERENCES subscription_plans(id),
+    start_date TIMESTAMP NOT NULL,
+    end_date TIMESTAMP NOT NULL,
+    status TEXT NOT NULL,
+    config_file_path TEXT,
+    data_usage BIGINT NOT NULL DEFAULT 0,
+    last_connection_at TIMESTAMP,
+    is_trial BOOLEAN NOT NULL DEFAULT FALSE,
+    paused_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+-- Создаем таблицу для платежей
+CREATE TABLE IF NOT EXISTS payments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    subscription_id INTEGER REFERENCES subscriptions(id),
+    amount REAL NOT NULL,
+    payment_method TEXT NOT NULL,
+    payment_id TEXT,
+    status TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+-- Создаем таблицу для подарочных кодов
+CREATE TABLE IF NOT EXISTS gift_codes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    code TEXT NOT NULL UNIQUE,
+    plan_id INTEGER NOT NULL REFERENCES subscription_plans(id),
+    buyer_user_id INTEGER NOT NULL REFERENCES users(id),
+    payment_id INTEGER REFERENCES payments(id),
+    status TEXT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    redeemed_by_user_id INTEGER REFERENCES users(id),
+    subscription_id INTEGER REFERENCES subscriptions(id),
+    redeemed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+-- Создаем таблицы для учета баланса пользователей (двойная запись)
+CREATE TABLE IF NOT EXISTS ledger_transactions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    type TEXT NOT NULL,
+    amount REAL NOT NULL,
+    balance_after REAL NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    payment_id INTEGER REFERENCES payments(id),
+    actor_user_id INTEGER REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    transaction_id INTEGER NOT NULL REFERENCES ledger_transactions(id),
+    account TEXT NOT NULL,
+    amount REAL NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_account ON ledger_entries(account);
+
+-- Создаем таблицу истории смены статусов подписок
+CREATE TABLE IF NOT EXISTS subscription_status_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    subscription_id INTEGER NOT NULL REFERENCES subscriptions(id),
+    old_status TEXT NOT NULL,
+    new_status TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_status_history_subscription ON subscription_status_history(subscription_id);
+
+-- Создаем журнал отправленных напоминаний об окончании подписки
+CREATE TABLE IF NOT EXISTS subscription_notifications (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    subscription_id INTEGER NOT NULL REFERENCES subscriptions(id),
+    offset_minutes INTEGER NOT NULL,
+    end_date TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP NOT NULL DEFAULT (now()),
+    UNIQUE (subscription_id, offset_minutes, end_date)
+);
+
+-- Создаем таблицу состояний диалогов
+CREATE TABLE IF NOT EXISTS conversation_states (
+    chat_id BIGINT PRIMARY KEY,
+    state TEXT NOT NULL,
+    previous_state TEXT NOT NULL DEFAULT '',
+    data TEXT NOT NULL DEFAULT '{}',
+    expires_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+-- Создаем журнал действий сотрудников
+CREATE TABLE IF NOT EXISTS audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    actor_user_id INTEGER REFERENCES users(id),
+    actor_telegram_id BIGINT NOT NULL,
+    action TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id INTEGER NOT NULL,
+    before_value TEXT NOT NULL DEFAULT '{}',
+    after_value TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
+
+-- Создаем таблицу рассылок
+CREATE TABLE IF NOT EXISTS broadcasts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_by BIGINT NOT NULL,
+    source_chat_id BIGINT NOT NULL,
+    source_message_id INTEGER NOT NULL,
+    buttons TEXT NOT NULL DEFAULT '[]',
+    segment TEXT NOT NULL,
+    segment_value INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'running',
+    total INTEGER NOT NULL DEFAULT 0,
+    sent INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    blocked INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    finished_at TIMESTAMP
+);
+
+-- Создаем таблицы обращений в поддержку
+CREATE TABLE IF NOT EXISTS support_tickets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    subscription_id INTEGER REFERENCES subscriptions(id),
+    status TEXT NOT NULL DEFAULT 'open',
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now()),
+    closed_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS support_messages (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    ticket_id INTEGER NOT NULL REFERENCES support_tickets(id),
+    sender_telegram_id BIGINT NOT NULL,
+    from_staff BOOLEAN NOT NULL DEFAULT FALSE,
+    text TEXT NOT NULL DEFAULT '',
+    photo_file_id TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX IF NOT EXISTS idx_support_messages_ticket ON support_messages(ticket_id);
+
+-- Связь сообщений, пересланных сотрудникам и пользователю, с обращениями
+CREATE TABLE IF NOT EXISTS support_relays (
+    chat_id BIGINT NOT NULL,
+    message_id INTEGER NOT NULL,
+    ticket_id INTEGER NOT NULL REFERENCES support_tickets(id),
+    PRIMARY KEY (chat_id, message_id)
+);
+
+-- Создаем таблицу сообщений, которые не удалось доставить
+CREATE TABLE IF NOT EXISTS failed_messages (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    chat_id BIGINT NOT NULL,
+    method VARCHAR(64) NOT NULL,
+    payload TEXT NOT NULL DEFAULT '',
+    error_code INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX IF NOT EXISTS idx_failed_messages_chat ON failed_messages(chat_id);
@@ -0,0 +1,2 @@
+-- Серверы удаляются мягко: на них ссылаются подписки, поэтому запись остается, а сервер скрывается из списков
+ALTER TABLE servers ADD COLUMN deleted_at TIMESTAMP;
@@ -0,0 +1,12 @@
+-- Суммы возвращаются к рублям с плавающей точкой. Цены в дополнительных валютах теряются,
+-- суммы платежей в других валютах после отката нельзя отличить от рублевых.
+-- Столбцы с новым типом остаются INTEGER: SQLite хранит в них дробные значения без потерь.
+DROP TABLE IF EXISTS plan_prices;
+
+UPDATE ledger_entries SET amount = amount / 100.0;
+UPDATE ledger_transactions SET amount = amount / 100.0, balance_after = balance_after / 100.0;
+UPDATE payments SET amount = amount / 100.0;
+UPDATE subscription_plans SET price = price / 100.0;
+
+ALTER TABLE payments DROP COLUMN currency;
+ALTER TABLE subscription_plans DROP COLUMN currency;
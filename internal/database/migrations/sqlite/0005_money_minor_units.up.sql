@@ -0,0 +1,94 @@
+-- Денежные суммы хранятся целым числом минимальных единиц валюты, у планов и платежей появляется валюта.
+-- До этой миграции бот выставлял счета только в рублях, поэтому все существующие суммы (цены планов, платежи
+-- и баланс) считаются рублевыми: они переводятся в копейки и получают валюту RUB. Если в базе вручную
+-- сохранялись суммы в другой валюте, их нужно исправить после миграции.
+-- SQLite не умеет менять тип столбца, поэтому таблицы пересоздаются. На них ссылаются другие таблицы,
+-- поэтому проверка внешних ключей откладывается до конца транзакции миграции.
+PRAGMA defer_foreign_keys = ON;
+
+CREATE TABLE subscription_plans_old AS SELECT * FROM subscription_plans;
+DROP TABLE subscription_plans;
+CREATE TABLE subscription_plans (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    price INTEGER NOT NULL,
+    currency TEXT NOT NULL DEFAULT 'RUB',
+    duration INTEGER NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    is_trial BOOLEAN NOT NULL DEFAULT FALSE,
+    max_pause_days INTEGER NOT NULL DEFAULT 0,
+    grace_days INTEGER NOT NULL DEFAULT 3,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+INSERT INTO subscription_plans
+(id, name, description, price, duration, is_active, is_trial, max_pause_days, grace_days, created_at, updated_at)
+SELECT id, name, description, CAST(ROUND(price * 100) AS INTEGER), duration, is_active, is_trial, max_pause_days, grace_days, created_at, updated_at
+FROM subscription_plans_old;
+DROP TABLE subscription_plans_old;
+
+CREATE TABLE payments_old AS SELECT * FROM payments;
+DROP TABLE payments;
+CREATE TABLE payments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    subscription_id INTEGER REFERENCES subscriptions(id),
+    amount INTEGER NOT NULL,
+    currency TEXT NOT NULL DEFAULT 'RUB',
+    payment_method TEXT NOT NULL,
+    payment_id TEXT,
+    status TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+INSERT INTO payments
+(id, user_id, subscription_id, amount, payment_method, payment_id, status, created_at, updated_at)
+SELECT id, user_id, subscription_id, CAST(ROUND(amount * 100) AS INTEGER), payment_method, payment_id, status, created_at, updated_at
+FROM payments_old;
+DROP TABLE payments_old;
+CREATE INDEX IF NOT EXISTS idx_payments_user ON payments(user_id);
+
+-- Баланс пользователей ведется в рублях
+CREATE TABLE ledger_transactions_old AS SELECT * FROM ledger_transactions;
+DROP TABLE ledger_transactions;
+CREATE TABLE ledger_transactions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    type TEXT NOT NULL,
+    amount INTEGER NOT NULL,
+    balance_after INTEGER NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    payment_id INTEGER REFERENCES payments(id),
+    actor_user_id INTEGER REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+INSERT INTO ledger_transactions
+(id, user_id, type, amount, balance_after, description, payment_id, actor_user_id, created_at)
+SELECT id, user_id, type, CAST(ROUND(amount * 100) AS INTEGER), CAST(ROUND(balance_after * 100) AS INTEGER),
+    description, payment_id, actor_user_id, created_at
+FROM ledger_transactions_old;
+DROP TABLE ledger_transactions_old;
+
+CREATE TABLE ledger_entries_old AS SELECT * FROM ledger_entries;
+DROP TABLE ledger_entries;
+CREATE TABLE ledger_entries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    transaction_id INTEGER NOT NULL REFERENCES ledger_transactions(id),
+    account TEXT NOT NULL,
+    amount INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+INSERT INTO ledger_entries (id, transaction_id, account, amount, created_at)
+SELECT id, transaction_id, account, CAST(ROUND(amount * 100) AS INTEGER), created_at
+FROM ledger_entries_old;
+DROP TABLE ledger_entries_old;
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_account ON ledger_entries(account);
+
+-- Цены планов в дополнительных валютах (основная цена хранится в самом плане)
+CREATE TABLE IF NOT EXISTS plan_prices (
+    plan_id INTEGER NOT NULL REFERENCES subscription_plans(id),
+    currency TEXT NOT NULL,
+    amount INTEGER NOT NULL,
+    PRIMARY KEY (plan_id, currency)
+);
@@ -0,0 +1,3 @@
+-- До появления валют все платежи через Telegram принимались в рублях через платежного провайдера,
+-- но записывались со способом оплаты telegram_stars. Способ оплаты приводится в соответствие с валютой.
+UPDATE payments SET payment_method = 'telegram_provider' WHERE payment_method = 'telegram_stars' AND currency <> 'XTR';
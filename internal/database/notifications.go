@@ -0,0 +1,39 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// ClaimSubscriptionNotification отмечает напоминание как отправленное.
+// Возвращает false, если напоминание с таким смещением для текущей даты окончания подписки уже было отправлено.
+// Дата окончания входит в ключ, чтобы после продления напоминания отправлялись заново.
+func (db *DB) ClaimSubscriptionNotification(subscriptionID int, offset time.Duration, endDate time.Time) (bool, error) {
+	result, err := db.Exec(`
+		INSERT INTO subscription_notifications (subscription_id, offset_minutes, end_date)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (subscription_id, offset_minutes, end_date) DO NOTHING
+	`, subscriptionID, int(offset/time.Minute), endDate)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim subscription notification: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim subscription notification: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// ReleaseSubscriptionNotification удаляет отметку о напоминании, если отправить его не удалось
+func (db *DB) ReleaseSubscriptionNotification(subscriptionID int, offset time.Duration, endDate time.Time) error {
+	_, err := db.Exec(`
+		DELETE FROM subscription_notifications
+		WHERE subscription_id = $1 AND offset_minutes = $2 AND end_date = $3
+	`, subscriptionID, int(offset/time.Minute), endDate)
+	if err != nil {
+		return fmt.Errorf("failed to release subscription notification: %w", err)
+	}
+	return nil
+}
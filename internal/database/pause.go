@@ -0,0 +1,101 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+// SetSubscriptionPlanMaxPause устанавливает максимальную длительность паузы для плана
+func (db *DB) SetSubscriptionPlanMaxPause(planID int, days int) error {
+	_, err := db.Exec("UPDATE subscription_plans SET max_pause_days = $1, updated_at = NOW() WHERE id = $2",
+		days, planID)
+	if err != nil {
+		return fmt.Errorf("failed to update subscription plan max pause: %w", err)
+	}
+	return nil
+}
+
+// PauseSubscription переводит активную подписку в статус "paused".
+// Возвращает sql.ErrNoRows (обернутую), если подписка не активна.
+func (db *DB) PauseSubscription(subscriptionID int, pausedAt time.Time, reason string) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin subscription pause: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE subscriptions SET status = 'paused', paused_at = $2, updated_at = NOW()
+		WHERE id = $1 AND status = 'active'
+	`, subscriptionID, pausedAt)
+	if err != nil {
+		return fmt.Errorf("failed to pause subscription: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to pause subscription: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("failed to pause subscription: %w", sql.ErrNoRows)
+	}
+
+	if err := addSubscriptionStatusChange(tx, subscriptionID, "active", "paused", reason); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit subscription pause: %w", err)
+	}
+
+	return nil
+}
+
+// ResumeSubscription возвращает приостановленную подписку в статус "active"
+// и продлевает дату окончания на время, проведенное на паузе (до момента resumedAt).
+// Возвращает обновленную подписку или sql.ErrNoRows (обернутую), если подписка не на паузе.
+func (db *DB) ResumeSubscription(subscriptionID int, resumedAt time.Time, reason string) (*models.Subscription, error) {
+	tx, err := db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin subscription resume: %w", err)
+	}
+	defer tx.Rollback()
+
+	var subscription models.Subscription
+	err = tx.Get(&subscription, "SELECT * FROM subscriptions WHERE id = $1 AND status = 'paused'"+db.dialect.forUpdate,
+		subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resume subscription: %w", err)
+	}
+
+	endDate := subscription.EndDate
+	if subscription.PausedAt != nil && resumedAt.After(*subscription.PausedAt) {
+		endDate = endDate.Add(resumedAt.Sub(*subscription.PausedAt))
+	}
+
+	err = tx.Get(&subscription, `
+		UPDATE subscriptions SET
+		status = 'active',
+		end_date = $2,
+		paused_at = NULL,
+		updated_at = NOW()
+		WHERE id = $1
+		RETURNING *
+	`, subscriptionID, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resume subscription: %w", err)
+	}
+
+	if err := addSubscriptionStatusChange(tx, subscriptionID, "paused", "active", reason); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit subscription resume: %w", err)
+	}
+
+	return &subscription, nil
+}
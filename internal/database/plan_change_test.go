@@ -0,0 +1,112 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+func TestChangeSubscriptionPlan(t *testing.T) {
+	for _, backend := range repositoryBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			testChangeSubscriptionPlan(t, backend.open(t))
+		})
+	}
+}
+
+func testChangeSubscriptionPlan(t *testing.T, db *DB) {
+	repo := newRepositories(db)
+	user := addTestUser(t, repo, 100)
+	plan := addTestPlan(t, repo)
+	target := &models.SubscriptionPlan{Name: "Год", Price: 199000, Currency: "RUB", Duration: 365, IsActive: true}
+	if err := repo.AddSubscriptionPlan(target); err != nil {
+		t.Fatalf("AddSubscriptionPlan() error: %v", err)
+	}
+	addTestServer(t, repo, 10)
+
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	subscription := reserveTestSubscription(t, repo, user.ID, plan.ID, start)
+	if err := repo.ActivateSubscription(subscription.ID, "client.conf", nil); err != nil {
+		t.Fatalf("ActivateSubscription() error: %v", err)
+	}
+
+	newPayment := func() *models.Payment {
+		return &models.Payment{UserID: user.ID, Amount: 50000, Currency: "RUB", PaymentMethod: "telegram_provider",
+			PaymentID: "charge", Status: "completed"}
+	}
+	newRefund := func() *models.LedgerTransaction {
+		return &models.LedgerTransaction{UserID: user.ID, Type: models.LedgerRefund, Amount: 1500, Description: "test"}
+	}
+	changedAt := start.AddDate(0, 0, 10)
+	endDate := changedAt.AddDate(0, 0, target.Duration)
+
+	// Ошибка любого шага отменяет всю смену плана: ни платеж, ни возврат не сохраняются
+	failures := []struct {
+		name    string
+		prepare func(t *testing.T) (*models.Payment, *models.LedgerTransaction)
+		wantErr error
+	}{
+		{name: "subscription is paused", wantErr: sql.ErrNoRows, prepare: func(t *testing.T) (*models.Payment, *models.LedgerTransaction) {
+			if err := repo.PauseSubscription(subscription.ID, changedAt, "test"); err != nil {
+				t.Fatalf("PauseSubscription() error: %v", err)
+			}
+			t.Cleanup(func() {
+				if _, err := repo.ResumeSubscription(subscription.ID, changedAt, "test"); err != nil {
+					t.Fatalf("ResumeSubscription() error: %v", err)
+				}
+			})
+			return newPayment(), newRefund()
+		}},
+		{name: "payment without currency", prepare: func(t *testing.T) (*models.Payment, *models.LedgerTransaction) {
+			payment := newPayment()
+			payment.Currency = ""
+			return payment, newRefund()
+		}},
+		{name: "refund of unknown type", prepare: func(t *testing.T) (*models.Payment, *models.LedgerTransaction) {
+			refund := newRefund()
+			refund.Type = "unknown"
+			return newPayment(), refund
+		}},
+	}
+
+	for _, tt := range failures {
+		t.Run(tt.name, func(t *testing.T) {
+			payment, refund := tt.prepare(t)
+			err := repo.ChangeSubscriptionPlan(subscription.ID, target.ID, changedAt, endDate, payment, refund)
+			if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+				t.Fatalf("ChangeSubscriptionPlan() error = %v, want %v", err, tt.wantErr)
+			}
+
+			got, err := repo.GetSubscriptionByID(subscription.ID)
+			if err != nil || got.PlanID != plan.ID {
+				t.Errorf("subscription after failed change = %+v, %v; want plan #%d", got, err, plan.ID)
+			}
+			if payments, err := repo.GetPaymentsByUserID(user.ID, 10); err != nil || len(payments) != 0 {
+				t.Errorf("payments after failed change = %+v, %v; want none", payments, err)
+			}
+			if balance, err := db.GetUserBalance(user.ID); err != nil || balance != 0 {
+				t.Errorf("balance after failed change = %d, %v; want 0", balance, err)
+			}
+		})
+	}
+
+	payment, refund := newPayment(), newRefund()
+	if err := repo.ChangeSubscriptionPlan(subscription.ID, target.ID, changedAt, endDate, payment, refund); err != nil {
+		t.Fatalf("ChangeSubscriptionPlan() error: %v", err)
+	}
+
+	got, err := repo.GetSubscriptionByID(subscription.ID)
+	if err != nil || got.PlanID != target.ID || !got.EndDate.Equal(endDate) {
+		t.Errorf("subscription after change = %+v, %v; want plan #%d until %s", got, err, target.ID, endDate)
+	}
+	payments, err := repo.GetPaymentsByUserID(user.ID, 10)
+	if err != nil || len(payments) != 1 || payments[0].SubscriptionID == nil || *payments[0].SubscriptionID != subscription.ID {
+		t.Errorf("payments after change = %+v, %v; want the payment for subscription #%d", payments, err, subscription.ID)
+	}
+	if balance, err := db.GetUserBalance(user.ID); err != nil || balance != refund.Amount || refund.BalanceAfter != refund.Amount {
+		t.Errorf("balance after change = %d, %v; want the refund %d", balance, err, refund.Amount)
+	}
+}
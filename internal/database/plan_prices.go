@@ -0,0 +1,68 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+// loadPlanPrices заполняет цены планов в дополнительных валютах
+func (db *DB) loadPlanPrices(plans []models.SubscriptionPlan) error {
+	if len(plans) == 0 {
+		return nil
+	}
+
+	var prices []models.PlanPrice
+	query := "SELECT plan_id, currency, amount FROM plan_prices ORDER BY plan_id, currency"
+	var args []interface{}
+	if len(plans) == 1 {
+		query = "SELECT plan_id, currency, amount FROM plan_prices WHERE plan_id = $1 ORDER BY currency"
+		args = append(args, plans[0].ID)
+	}
+
+	if err := db.Select(&prices, query, args...); err != nil {
+		return fmt.Errorf("failed to get plan prices: %w", err)
+	}
+
+	byPlan := make(map[int][]models.PlanPrice)
+	for _, price := range prices {
+		byPlan[price.PlanID] = append(byPlan[price.PlanID], price)
+	}
+	for i := range plans {
+		plans[i].Prices = byPlan[plans[i].ID]
+	}
+
+	return nil
+}
+
+// SetPlanPrice устанавливает цену плана в дополнительной валюте (в минимальных единицах валюты)
+func (db *DB) SetPlanPrice(planID int, currency string, amount int64) error {
+	_, err := db.Exec(`
+		INSERT INTO plan_prices (plan_id, currency, amount)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (plan_id, currency) DO UPDATE SET amount = excluded.amount
+	`, planID, currency, amount)
+	if err != nil {
+		return fmt.Errorf("failed to set plan price: %w", err)
+	}
+
+	_, err = db.Exec("UPDATE subscription_plans SET updated_at = NOW() WHERE id = $1", planID)
+	if err != nil {
+		return fmt.Errorf("failed to set plan price: %w", err)
+	}
+	return nil
+}
+
+// DeletePlanPrice удаляет цену плана в дополнительной валюте
+func (db *DB) DeletePlanPrice(planID int, currency string) error {
+	_, err := db.Exec("DELETE FROM plan_prices WHERE plan_id = $1 AND currency = $2", planID, currency)
+	if err != nil {
+		return fmt.Errorf("failed to delete plan price: %w", err)
+	}
+
+	_, err = db.Exec("UPDATE subscription_plans SET updated_at = NOW() WHERE id = $1", planID)
+	if err != nil {
+		return fmt.Errorf("failed to delete plan price: %w", err)
+	}
+	return nil
+}
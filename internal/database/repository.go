@@ -0,0 +1,71 @@
+package database
+
+import (
+	"time"
+
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+// Репозитории описывают основные операции с хранилищем независимо от СУБД.
+// DB реализует их и для PostgreSQL, и для SQLite: отличия SQL вынесены в dialect.
+
+// ServerRepository - операции с VPN-серверами
+type ServerRepository interface {
+	AddServer(server *models.Server) error
+	GetServerByID(id int) (*models.Server, error)
+	GetAllServers() ([]models.Server, error)
+	UpdateServer(server *models.Server) error
+	DeleteServer(id int) error
+}
+
+// PlanRepository - операции с планами подписок
+type PlanRepository interface {
+	AddSubscriptionPlan(plan *models.SubscriptionPlan) error
+	GetSubscriptionPlanByID(id int) (*models.SubscriptionPlan, error)
+	GetAllSubscriptionPlans() ([]models.SubscriptionPlan, error)
+	GetTrialPlan() (*models.SubscriptionPlan, error)
+	UpdateSubscriptionPlan(plan *models.SubscriptionPlan) error
+	DeleteSubscriptionPlan(id int) error
+}
+
+// UserRepository - операции с пользователями
+type UserRepository interface {
+	AddUser(user *models.User) error
+	GetUserByID(userID int) (*models.User, error)
+	GetUserByTelegramID(telegramID int64) (*models.User, error)
+	GetAllUsers() ([]models.User, error)
+	SetUserRole(userID int, role string) error
+	SetUserLanguage(telegramID int64, language string) error
+	MarkUserBotBlocked(telegramID int64) error
+}
+
+// SubscriptionRepository - операции с подписками
+type SubscriptionRepository interface {
+	ReserveSubscription(subscription *models.Subscription, reason string) error
+	ActivateSubscription(subscriptionID int, configFilePath string, payment *models.Payment) error
+	FinishSubscription(subscriptionID int, fromStatus, toStatus, reason string) error
+	GetSubscriptionByID(subscriptionID int) (*models.Subscription, error)
+	GetSubscriptionsByUserID(userID int) ([]models.Subscription, error)
+	GetSubscriptionsByStatus(status string) ([]models.Subscription, error)
+	GetSubscriptionsByServerID(serverID int, statuses []string) ([]models.Subscription, error)
+	UpdateSubscription(subscription *models.Subscription) error
+	TransitionSubscriptionStatus(subscriptionID int, fromStatus, toStatus, reason string) error
+	RenewSubscription(subscriptionID int, days int, now time.Time, payment *models.Payment) (*models.Subscription, string, error)
+	PauseSubscription(subscriptionID int, pausedAt time.Time, reason string) error
+	ResumeSubscription(subscriptionID int, resumedAt time.Time, reason string) (*models.Subscription, error)
+	ChangeSubscriptionPlan(subscriptionID, planID int, startDate, endDate time.Time, payment *models.Payment, refund *models.LedgerTransaction) error
+}
+
+// PaymentRepository - операции с платежами
+type PaymentRepository interface {
+	AddPayment(payment *models.Payment) error
+	GetPaymentsByUserID(userID int, limit int) ([]models.Payment, error)
+}
+
+var (
+	_ ServerRepository       = (*DB)(nil)
+	_ PlanRepository         = (*DB)(nil)
+	_ UserRepository         = (*DB)(nil)
+	_ SubscriptionRepository = (*DB)(nil)
+	_ PaymentRepository      = (*DB)(nil)
+)
@@ -0,0 +1,425 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+// postgresTestDSNEnv - переменная окружения со строкой подключения к PostgreSQL для проверки репозиториев.
+// Если она не задана, контрактные тесты выполняются только на SQLite.
+const postgresTestDSNEnv = "BOTVPN_TEST_POSTGRES_DSN"
+
+// repositories объединяет интерфейсы репозиториев: контрактные тесты работают только через них
+type repositories struct {
+	ServerRepository
+	PlanRepository
+	UserRepository
+	SubscriptionRepository
+	PaymentRepository
+}
+
+func newRepositories(db *DB) repositories {
+	return repositories{db, db, db, db, db}
+}
+
+// repositoryBackend - СУБД, на которой проверяются репозитории. open создает пустую базу со всеми миграциями.
+type repositoryBackend struct {
+	name string
+	open func(t *testing.T) *DB
+}
+
+func repositoryBackends(t *testing.T) []repositoryBackend {
+	backends := []repositoryBackend{{name: "sqlite", open: newSQLiteTestDB}}
+	if dsn := os.Getenv(postgresTestDSNEnv); dsn != "" {
+		backends = append(backends, repositoryBackend{name: "postgres", open: func(t *testing.T) *DB {
+			return newPostgresTestDB(t, dsn)
+		}})
+	} else {
+		t.Logf("%s is not set, PostgreSQL is skipped", postgresTestDSNEnv)
+	}
+	return backends
+}
+
+// newPostgresTestDB создает в базе dsn временную схему со всеми миграциями и удаляет ее после теста
+func newPostgresTestDB(t *testing.T, dsn string) *DB {
+	t.Helper()
+
+	admin, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		t.Fatalf("connect to postgres: %v", err)
+	}
+	schema := fmt.Sprintf("botvpn_test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		admin.Close()
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec("DROP SCHEMA " + schema + " CASCADE"); err != nil {
+			t.Errorf("drop schema: %v", err)
+		}
+		admin.Close()
+	})
+
+	// Неизвестные параметры строки подключения lib/pq передает серверу, поэтому search_path действует во всех соединениях пула
+	separator := " "
+	if strings.Contains(dsn, "://") {
+		separator = "?"
+		if strings.Contains(dsn, "?") {
+			separator = "&"
+		}
+	}
+	conn, err := sqlx.Connect("postgres", dsn+separator+"search_path="+schema)
+	if err != nil {
+		t.Fatalf("connect to postgres schema: %v", err)
+	}
+	db := &DB{DB: conn, dialect: postgresDialect}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.MigrateUp(); err != nil {
+		t.Fatalf("migrate postgres database: %v", err)
+	}
+	return db
+}
+
+// repositoryContract - поведение репозиториев, одинаковое для всех СУБД
+var repositoryContract = []struct {
+	name string
+	run  func(t *testing.T, repo repositories)
+}{
+	{name: "servers", run: testServerRepository},
+	{name: "plans", run: testPlanRepository},
+	{name: "users", run: testUserRepository},
+	{name: "subscription lifecycle", run: testSubscriptionLifecycle},
+	{name: "pause and renewal", run: testSubscriptionPauseAndRenewal},
+	{name: "payments", run: testPaymentRepository},
+}
+
+func TestRepositoryContract(t *testing.T) {
+	for _, backend := range repositoryBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			for _, tc := range repositoryContract {
+				t.Run(tc.name, func(t *testing.T) {
+					tc.run(t, newRepositories(backend.open(t)))
+				})
+			}
+		})
+	}
+}
+
+func addTestServer(t *testing.T, repo repositories, maxClients int) *models.Server {
+	t.Helper()
+	server := &models.Server{IP: "10.0.0.1", Port: 22, SSHUser: "root", SSHPassword: "secret", MaxClients: maxClients, IsActive: true}
+	if err := repo.AddServer(server); err != nil {
+		t.Fatalf("AddServer() error: %v", err)
+	}
+	return server
+}
+
+func addTestPlan(t *testing.T, repo repositories) *models.SubscriptionPlan {
+	t.Helper()
+	plan := &models.SubscriptionPlan{Name: "Месяц", Price: 19900, Currency: "RUB", Duration: 30, IsActive: true}
+	if err := repo.AddSubscriptionPlan(plan); err != nil {
+		t.Fatalf("AddSubscriptionPlan() error: %v", err)
+	}
+	return plan
+}
+
+func addTestUser(t *testing.T, repo repositories, telegramID int64) *models.User {
+	t.Helper()
+	user := &models.User{TelegramID: telegramID, Username: "user", FirstName: "User"}
+	if err := repo.AddUser(user); err != nil {
+		t.Fatalf("AddUser() error: %v", err)
+	}
+	return user
+}
+
+// reserveTestSubscription резервирует подписку на 30 дней, начиная с start
+func reserveTestSubscription(t *testing.T, repo repositories, userID, planID int, start time.Time) *models.Subscription {
+	t.Helper()
+	subscription := &models.Subscription{UserID: userID, PlanID: planID, StartDate: start, EndDate: start.AddDate(0, 0, 30)}
+	if err := repo.ReserveSubscription(subscription, "test"); err != nil {
+		t.Fatalf("ReserveSubscription() error: %v", err)
+	}
+	return subscription
+}
+
+func testServerRepository(t *testing.T, repo repositories) {
+	if err := repo.AddServer(&models.Server{Port: 22, SSHUser: "root", SSHPassword: "secret", MaxClients: 1}); err == nil {
+		t.Error("AddServer() without IP succeeded")
+	}
+
+	server := addTestServer(t, repo, 10)
+	server.IP, server.MaxClients = "10.0.0.2", 20
+	if err := repo.UpdateServer(server); err != nil {
+		t.Fatalf("UpdateServer() error: %v", err)
+	}
+	got, err := repo.GetServerByID(server.ID)
+	if err != nil {
+		t.Fatalf("GetServerByID() error: %v", err)
+	}
+	if got.IP != "10.0.0.2" || got.MaxClients != 20 || got.SSHPassword != "secret" {
+		t.Errorf("GetServerByID() = %+v", got)
+	}
+
+	if err := repo.DeleteServer(server.ID); err != nil {
+		t.Fatalf("DeleteServer() error: %v", err)
+	}
+	servers, err := repo.GetAllServers()
+	if err != nil {
+		t.Fatalf("GetAllServers() error: %v", err)
+	}
+	if len(servers) != 0 {
+		t.Errorf("GetAllServers() returned deleted servers: %+v", servers)
+	}
+	// Удаление мягкое: на сервер продолжают ссылаться подписки
+	if got, err := repo.GetServerByID(server.ID); err != nil || got.IsActive {
+		t.Errorf("GetServerByID() after delete = %+v, %v", got, err)
+	}
+}
+
+func testPlanRepository(t *testing.T, repo repositories) {
+	plan := addTestPlan(t, repo)
+	plan.Name, plan.Duration = "Квартал", 90
+	if err := repo.UpdateSubscriptionPlan(plan); err != nil {
+		t.Fatalf("UpdateSubscriptionPlan() error: %v", err)
+	}
+	got, err := repo.GetSubscriptionPlanByID(plan.ID)
+	if err != nil {
+		t.Fatalf("GetSubscriptionPlanByID() error: %v", err)
+	}
+	if got.Name != "Квартал" || got.Duration != 90 || got.Price != 19900 || got.Currency != "RUB" {
+		t.Errorf("GetSubscriptionPlanByID() = %+v", got)
+	}
+
+	if _, err := repo.GetTrialPlan(); err == nil {
+		t.Error("GetTrialPlan() without a trial plan succeeded")
+	}
+
+	if err := repo.DeleteSubscriptionPlan(plan.ID); err != nil {
+		t.Fatalf("DeleteSubscriptionPlan() error: %v", err)
+	}
+	plans, err := repo.GetAllSubscriptionPlans()
+	if err != nil {
+		t.Fatalf("GetAllSubscriptionPlans() error: %v", err)
+	}
+	if len(plans) != 0 {
+		t.Errorf("GetAllSubscriptionPlans() returned deleted plans: %+v", plans)
+	}
+}
+
+func testUserRepository(t *testing.T, repo repositories) {
+	user := &models.User{TelegramID: 100, Username: "old", Role: "owner"}
+	if err := repo.AddUser(user); err != nil {
+		t.Fatalf("AddUser() error: %v", err)
+	}
+	if !user.IsAdmin {
+		t.Error("AddUser() with a role did not mark the user as staff")
+	}
+
+	// Повторное добавление обновляет профиль, но не меняет роль
+	again := &models.User{TelegramID: 100, Username: "new"}
+	if err := repo.AddUser(again); err != nil {
+		t.Fatalf("AddUser() again error: %v", err)
+	}
+	if again.ID != user.ID || again.Role != "owner" {
+		t.Errorf("AddUser() again = %+v, want ID %d and role owner", again, user.ID)
+	}
+
+	if err := repo.SetUserRole(user.ID, ""); err != nil {
+		t.Fatalf("SetUserRole() error: %v", err)
+	}
+	if err := repo.SetUserLanguage(100, "en"); err != nil {
+		t.Fatalf("SetUserLanguage() error: %v", err)
+	}
+	if err := repo.SetUserLanguage(200, "en"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("SetUserLanguage() for an unknown user error = %v, want sql.ErrNoRows", err)
+	}
+	if err := repo.MarkUserBotBlocked(100); err != nil {
+		t.Fatalf("MarkUserBotBlocked() error: %v", err)
+	}
+
+	got, err := repo.GetUserByTelegramID(100)
+	if err != nil {
+		t.Fatalf("GetUserByTelegramID() error: %v", err)
+	}
+	if got.Username != "new" || got.Role != "" || got.IsAdmin || got.Language != "en" || got.BotBlockedAt == nil {
+		t.Errorf("GetUserByTelegramID() = %+v", got)
+	}
+	if _, err := repo.GetUserByID(user.ID + 1); err == nil {
+		t.Error("GetUserByID() for an unknown user succeeded")
+	}
+
+	addTestUser(t, repo, 50)
+	users, err := repo.GetAllUsers()
+	if err != nil {
+		t.Fatalf("GetAllUsers() error: %v", err)
+	}
+	if len(users) != 2 || users[0].ID != user.ID {
+		t.Errorf("GetAllUsers() = %+v, want two users ordered by ID", users)
+	}
+}
+
+func testSubscriptionLifecycle(t *testing.T, repo repositories) {
+	user := addTestUser(t, repo, 100)
+	plan := addTestPlan(t, repo)
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	err := repo.ReserveSubscription(&models.Subscription{UserID: user.ID, PlanID: plan.ID, StartDate: start, EndDate: start}, "test")
+	if !errors.Is(err, ErrNoAvailableServer) {
+		t.Fatalf("ReserveSubscription() without servers error = %v, want ErrNoAvailableServer", err)
+	}
+
+	server := addTestServer(t, repo, 1)
+	subscription := reserveTestSubscription(t, repo, user.ID, plan.ID, start)
+	if subscription.Status != "pending" || subscription.ServerID != server.ID {
+		t.Errorf("ReserveSubscription() = %+v", subscription)
+	}
+	err = repo.ReserveSubscription(&models.Subscription{UserID: user.ID, PlanID: plan.ID, StartDate: start, EndDate: start}, "test")
+	if !errors.Is(err, ErrNoAvailableServer) {
+		t.Fatalf("ReserveSubscription() on a full server error = %v, want ErrNoAvailableServer", err)
+	}
+
+	payment := &models.Payment{UserID: user.ID, Amount: 19900, Currency: "RUB", PaymentMethod: "telegram_provider", Status: "completed"}
+	if err := repo.ActivateSubscription(subscription.ID, "/etc/wireguard/client.conf", payment); err != nil {
+		t.Fatalf("ActivateSubscription() error: %v", err)
+	}
+	if payment.ID == 0 || payment.SubscriptionID == nil || *payment.SubscriptionID != subscription.ID {
+		t.Errorf("ActivateSubscription() saved payment %+v", payment)
+	}
+	if err := repo.ActivateSubscription(subscription.ID, "/etc/wireguard/client.conf", nil); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("ActivateSubscription() twice error = %v, want sql.ErrNoRows", err)
+	}
+
+	got, err := repo.GetSubscriptionByID(subscription.ID)
+	if err != nil {
+		t.Fatalf("GetSubscriptionByID() error: %v", err)
+	}
+	if got.Status != "active" || got.ConfigFilePath != "/etc/wireguard/client.conf" || !got.EndDate.Equal(start.AddDate(0, 0, 30)) {
+		t.Errorf("GetSubscriptionByID() = %+v", got)
+	}
+
+	if err := repo.TransitionSubscriptionStatus(subscription.ID, "paused", "active", "test"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("TransitionSubscriptionStatus() from a wrong status error = %v, want sql.ErrNoRows", err)
+	}
+	if err := repo.TransitionSubscriptionStatus(subscription.ID, "active", "grace", "test"); err != nil {
+		t.Fatalf("TransitionSubscriptionStatus() error: %v", err)
+	}
+	grace, err := repo.GetSubscriptionsByStatus("grace")
+	if err != nil || len(grace) != 1 || grace[0].ID != subscription.ID {
+		t.Errorf("GetSubscriptionsByStatus(grace) = %+v, %v", grace, err)
+	}
+
+	if err := repo.FinishSubscription(subscription.ID, "grace", "expired", "test"); err != nil {
+		t.Fatalf("FinishSubscription() error: %v", err)
+	}
+	if err := repo.FinishSubscription(subscription.ID, "grace", "expired", "test"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("FinishSubscription() twice error = %v, want sql.ErrNoRows", err)
+	}
+
+	// Место на сервере освобождено, и его можно занять снова
+	if got, err := repo.GetServerByID(server.ID); err != nil || got.CurrentClients != 0 {
+		t.Errorf("server after finish = %+v, %v", got, err)
+	}
+	next := reserveTestSubscription(t, repo, user.ID, plan.ID, start)
+
+	onServer, err := repo.GetSubscriptionsByServerID(server.ID, []string{"pending", "active"})
+	if err != nil || len(onServer) != 1 || onServer[0].ID != next.ID {
+		t.Errorf("GetSubscriptionsByServerID() = %+v, %v", onServer, err)
+	}
+	byUser, err := repo.GetSubscriptionsByUserID(user.ID)
+	if err != nil || len(byUser) != 2 {
+		t.Errorf("GetSubscriptionsByUserID() = %+v, %v", byUser, err)
+	}
+}
+
+func testSubscriptionPauseAndRenewal(t *testing.T, repo repositories) {
+	user := addTestUser(t, repo, 100)
+	plan := addTestPlan(t, repo)
+	addTestServer(t, repo, 10)
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	subscription := reserveTestSubscription(t, repo, user.ID, plan.ID, start)
+	if err := repo.ActivateSubscription(subscription.ID, "client.conf", nil); err != nil {
+		t.Fatalf("ActivateSubscription() error: %v", err)
+	}
+
+	pausedAt := start.AddDate(0, 0, 10)
+	if err := repo.PauseSubscription(subscription.ID, pausedAt, "test"); err != nil {
+		t.Fatalf("PauseSubscription() error: %v", err)
+	}
+	if err := repo.PauseSubscription(subscription.ID, pausedAt, "test"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("PauseSubscription() twice error = %v, want sql.ErrNoRows", err)
+	}
+	if _, _, err := repo.RenewSubscription(subscription.ID, 30, pausedAt, nil); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("RenewSubscription() on pause error = %v, want sql.ErrNoRows", err)
+	}
+
+	// Время на паузе добавляется к сроку подписки
+	resumed, err := repo.ResumeSubscription(subscription.ID, pausedAt.AddDate(0, 0, 5), "test")
+	if err != nil {
+		t.Fatalf("ResumeSubscription() error: %v", err)
+	}
+	if want := start.AddDate(0, 0, 35); resumed.Status != "active" || resumed.PausedAt != nil || !resumed.EndDate.Equal(want) {
+		t.Errorf("ResumeSubscription() = %+v, want active until %s", resumed, want)
+	}
+	if _, err := repo.ResumeSubscription(subscription.ID, pausedAt, "test"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("ResumeSubscription() twice error = %v, want sql.ErrNoRows", err)
+	}
+
+	// Действующая подписка продлевается от даты окончания, подписка в льготном периоде - от текущего момента
+	payment := &models.Payment{UserID: user.ID, Amount: 19900, Currency: "RUB", PaymentMethod: "telegram_provider", Status: "completed"}
+	renewed, oldStatus, err := repo.RenewSubscription(subscription.ID, 30, start, payment)
+	if err != nil {
+		t.Fatalf("RenewSubscription() error: %v", err)
+	}
+	if want := start.AddDate(0, 0, 65); oldStatus != "active" || !renewed.EndDate.Equal(want) || payment.ID == 0 {
+		t.Errorf("RenewSubscription() = %+v, %q, want end %s", renewed, oldStatus, want)
+	}
+
+	if err := repo.TransitionSubscriptionStatus(subscription.ID, "active", "grace", "test"); err != nil {
+		t.Fatalf("TransitionSubscriptionStatus() error: %v", err)
+	}
+	now := start.AddDate(0, 0, 70)
+	renewed, oldStatus, err = repo.RenewSubscription(subscription.ID, 30, now, nil)
+	if err != nil {
+		t.Fatalf("RenewSubscription() from grace error: %v", err)
+	}
+	if want := now.AddDate(0, 0, 30); oldStatus != "grace" || renewed.Status != "active" || !renewed.EndDate.Equal(want) {
+		t.Errorf("RenewSubscription() from grace = %+v, %q, want end %s", renewed, oldStatus, want)
+	}
+}
+
+func testPaymentRepository(t *testing.T, repo repositories) {
+	user := addTestUser(t, repo, 100)
+
+	if err := repo.AddPayment(&models.Payment{UserID: user.ID, Amount: 100, Status: "completed"}); err == nil {
+		t.Error("AddPayment() without currency succeeded")
+	}
+
+	for _, amount := range []int64{100, 200, 300} {
+		payment := &models.Payment{UserID: user.ID, Amount: amount, Currency: "XTR", PaymentMethod: "telegram_stars",
+			PaymentID: fmt.Sprintf("charge-%d", amount), Status: "completed"}
+		if err := repo.AddPayment(payment); err != nil {
+			t.Fatalf("AddPayment() error: %v", err)
+		}
+	}
+
+	payments, err := repo.GetPaymentsByUserID(user.ID, 2)
+	if err != nil {
+		t.Fatalf("GetPaymentsByUserID() error: %v", err)
+	}
+	if len(payments) != 2 || payments[0].Amount != 300 || payments[1].Amount != 200 || payments[0].PaymentID != "charge-300" {
+		t.Errorf("GetPaymentsByUserID() = %+v, want the two latest payments", payments)
+	}
+	if payments[0].SubscriptionID != nil {
+		t.Errorf("payment without subscription has subscription ID %d", *payments[0].SubscriptionID)
+	}
+}
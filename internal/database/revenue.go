@@ -0,0 +1,226 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ilokitv/botVPN/internal/models"
+	"github.com/ilokitv/botVPN/internal/money"
+)
+
+// Поступлениями считаются завершенные платежи через Telegram. Оплата с баланса не учитывается,
+// так как эти деньги уже были учтены при пополнении баланса. Суммы в разных валютах не складываются:
+// все показатели группируются по валюте платежа.
+const revenuePaymentsCondition = "status = 'completed' AND payment_method <> 'balance'"
+
+// utcRange возвращает условие попадания столбца времени column в интервал [$1, $2), границы которого
+// заданы в UTC через utcBound. Время столбца переводится в UTC, как и начала периодов отчета.
+func (db *DB) utcRange(column string) string {
+	utc := fmt.Sprintf(db.dialect.utc, column)
+	return utc + " >= $1 AND " + utc + " < $2"
+}
+
+// planOrdersQuery выбирает покупки планов (включая подарки и оплату с баланса) с планом, к которому они относятся.
+// Для покупок подписки используется текущий план подписки, для подарков - план подарочного кода.
+func (db *DB) planOrdersQuery() string {
+	return `
+	SELECT p.id, p.amount, p.currency, sp.id AS plan_id, sp.name AS plan_name
+	FROM payments p
+	LEFT JOIN subscriptions s ON s.id = p.subscription_id
+	LEFT JOIN gift_codes g ON g.payment_id = p.id
+	JOIN subscription_plans sp ON sp.id = COALESCE(s.plan_id, g.plan_id)
+	WHERE p.status = 'completed' AND ` + db.utcRange("p.created_at")
+}
+
+// GetRevenueByPeriod возвращает поступления за период [from, to), сгруппированные по дням, неделям или месяцам и по валютам
+func (db *DB) GetRevenueByPeriod(granularity string, from, to time.Time) ([]models.RevenuePoint, error) {
+	periodStart, ok := db.dialect.periodStarts[granularity]
+	if !ok {
+		return nil, fmt.Errorf("unknown revenue granularity: %s", granularity)
+	}
+	periodStart = fmt.Sprintf(periodStart, fmt.Sprintf(db.dialect.utc, "created_at"))
+
+	// Начало интервала выбирается строкой YYYY-MM-DD в UTC одинаково в PostgreSQL и SQLite
+	var rows []struct {
+		Period   string `db:"period"`
+		Currency string `db:"currency"`
+		Revenue  int64  `db:"revenue"`
+		Payments int    `db:"payments"`
+	}
+	err := db.Select(&rows, `
+		SELECT `+periodStart+` AS period,
+		       currency,
+		       COALESCE(SUM(amount), 0) AS revenue,
+		       COUNT(*) AS payments
+		FROM payments
+		WHERE `+revenuePaymentsCondition+` AND `+db.utcRange("created_at")+`
+		GROUP BY period, currency
+		ORDER BY period, currency
+	`, utcBound(from), utcBound(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revenue by %s: %w", granularity, err)
+	}
+
+	points := make([]models.RevenuePoint, 0, len(rows))
+	for _, row := range rows {
+		period, err := time.Parse("2006-01-02", row.Period)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse revenue period %q: %w", row.Period, err)
+		}
+		points = append(points, models.RevenuePoint{
+			Period:   period,
+			Currency: row.Currency,
+			Revenue:  row.Revenue,
+			Payments: row.Payments,
+		})
+	}
+	return points, nil
+}
+
+// GetRevenueByPlan возвращает продажи планов за период [from, to) отдельно по каждой валюте
+func (db *DB) GetRevenueByPlan(from, to time.Time) ([]models.PlanRevenue, error) {
+	var plans []models.PlanRevenue
+	err := db.Select(&plans, `
+		SELECT plan_id, plan_name, currency, COUNT(*) AS orders, COALESCE(SUM(amount), 0) AS revenue
+		FROM (`+db.planOrdersQuery()+`) orders
+		GROUP BY plan_id, plan_name, currency
+		ORDER BY currency, revenue DESC, plan_id
+	`, utcBound(from), utcBound(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revenue by plan: %w", err)
+	}
+	return plans, nil
+}
+
+// GetRevenueStats возвращает сводные финансовые показатели за период [from, to).
+// Продления и истечения считаются по истории статусов подписок.
+func (db *DB) GetRevenueStats(from, to time.Time) (*models.RevenueStats, error) {
+	stats := &models.RevenueStats{
+		PeriodStart: from,
+		PeriodEnd:   to,
+	}
+
+	byCurrency := make(map[string]*models.CurrencyRevenue)
+	currencyRevenue := func(currency string) *models.CurrencyRevenue {
+		if byCurrency[currency] == nil {
+			byCurrency[currency] = &models.CurrencyRevenue{Currency: currency}
+		}
+		return byCurrency[currency]
+	}
+
+	type currencySum struct {
+		Currency string `db:"currency"`
+		Amount   int64  `db:"amount"`
+		Count    int    `db:"count"`
+	}
+
+	// Поступления
+	var payments []currencySum
+	err := db.Select(&payments, `
+		SELECT currency, COALESCE(SUM(amount), 0) AS amount, COUNT(*) AS count
+		FROM payments
+		WHERE `+revenuePaymentsCondition+` AND `+db.utcRange("created_at")+`
+		GROUP BY currency
+	`, utcBound(from), utcBound(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revenue: %w", err)
+	}
+	for _, row := range payments {
+		currencyRevenue(row.Currency).Revenue = row.Amount
+		currencyRevenue(row.Currency).PaymentsCount = row.Count
+	}
+
+	// Покупки планов и средний чек
+	var orders []currencySum
+	err = db.Select(&orders, `
+		SELECT currency, COALESCE(SUM(amount), 0) AS amount, COUNT(*) AS count
+		FROM (`+db.planOrdersQuery()+`) orders
+		GROUP BY currency
+	`, utcBound(from), utcBound(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get orders: %w", err)
+	}
+	for _, row := range orders {
+		revenue := currencyRevenue(row.Currency)
+		revenue.OrdersRevenue = row.Amount
+		revenue.OrdersCount = row.Count
+		revenue.AverageOrderValue = money.Prorate(row.Amount, 1, int64(row.Count))
+	}
+
+	// MRR: стоимость активных платных подписок в пересчете на 30 дней в основной валюте плана
+	var plans []struct {
+		Currency      string `db:"currency"`
+		Price         int64  `db:"price"`
+		Duration      int    `db:"duration"`
+		Subscriptions int    `db:"subscriptions"`
+	}
+	err = db.Select(&plans, `
+		SELECT sp.currency, sp.price, sp.duration, COUNT(*) AS subscriptions
+		FROM subscriptions s
+		JOIN subscription_plans sp ON sp.id = s.plan_id
+		WHERE s.status = 'active' AND NOT s.is_trial AND sp.price > 0
+		GROUP BY sp.id, sp.currency, sp.price, sp.duration
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MRR: %w", err)
+	}
+	for _, plan := range plans {
+		stats.ActivePaidSubscriptions += plan.Subscriptions
+		if plan.Duration > 0 {
+			currencyRevenue(plan.Currency).MRR += money.Prorate(plan.Price*int64(plan.Subscriptions), 30, int64(plan.Duration))
+		}
+	}
+
+	currencies := make([]string, 0, len(byCurrency))
+	for currency := range byCurrency {
+		currencies = append(currencies, currency)
+	}
+	money.SortCurrencies(currencies)
+	for _, currency := range currencies {
+		stats.Currencies = append(stats.Currencies, *byCurrency[currency])
+	}
+
+	// Продления: переходы в "active" из "active" или "grace" записываются только при продлении
+	err = db.Get(&stats.Renewals, `
+		SELECT COUNT(*)
+		FROM subscription_status_history h
+		JOIN subscriptions s ON s.id = h.subscription_id
+		WHERE NOT s.is_trial AND h.new_status = 'active' AND h.old_status IN ('active', 'grace')
+		AND `+db.utcRange("h.created_at")+`
+	`, utcBound(from), utcBound(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get renewals: %w", err)
+	}
+
+	// Истекшие платные подписки
+	err = db.Get(&stats.Expirations, `
+		SELECT COUNT(*)
+		FROM subscription_status_history h
+		JOIN subscriptions s ON s.id = h.subscription_id
+		WHERE NOT s.is_trial AND h.new_status = 'expired'
+		AND `+db.utcRange("h.created_at")+`
+	`, utcBound(from), utcBound(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expirations: %w", err)
+	}
+
+	if stats.Renewals+stats.Expirations > 0 {
+		stats.RenewalRate = float64(stats.Renewals) * 100 / float64(stats.Renewals+stats.Expirations)
+	}
+
+	// Отток считается от платных подписок, действовавших на начало периода
+	var activeAtStart int
+	err = db.Get(&activeAtStart, `
+		SELECT COUNT(*) FROM subscriptions
+		WHERE NOT is_trial AND start_date < $1 AND end_date >= $1
+	`, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscriptions at period start: %w", err)
+	}
+
+	if activeAtStart > 0 {
+		stats.ChurnRate = float64(stats.Expirations) * 100 / float64(activeAtStart)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ilokitv/botVPN/internal/analytics"
+	"github.com/ilokitv/botVPN/internal/config"
+)
+
+func TestRevenuePeriodsUseUTC(t *testing.T) {
+	for _, backend := range repositoryBackends(t) {
+		t.Run(backend.name, func(t *testing.T) {
+			testRevenuePeriodsUseUTC(t, backend.open(t))
+		})
+	}
+}
+
+func testRevenuePeriodsUseUTC(t *testing.T, db *DB) {
+	// Время платежа записывается так же, как его записывает NOW(): в SQLite с московским смещением,
+	// в PostgreSQL - временем московского часового пояса сеанса. Сеанс один, чтобы SET действовал во всех запросах.
+	insertPayment := `INSERT INTO payments (user_id, amount, currency, payment_method, status, created_at)
+		VALUES (1, 100, 'RUB', 'telegram_provider', 'completed', $1)`
+	if db.dialect.name == config.DriverPostgres {
+		db.SetMaxOpenConns(1)
+		if _, err := db.Exec("SET TIME ZONE 'Europe/Moscow'"); err != nil {
+			t.Fatalf("set time zone: %v", err)
+		}
+		insertPayment = `INSERT INTO payments (user_id, amount, currency, payment_method, status, created_at)
+			VALUES (1, 100, 'RUB', 'telegram_provider', 'completed', $1::timestamptz)`
+	}
+
+	if _, err := db.Exec("INSERT INTO users (id, telegram_id, username, first_name, last_name) VALUES (1, 100, 'user', 'User', '')"); err != nil {
+		t.Fatalf("seed user: %v", err)
+	}
+
+	// Платежи около полуночи UTC, как если бы бот и база работали в московском часовом поясе
+	moscow := time.FixedZone("MSK", 3*60*60)
+	paidAt := []time.Time{
+		time.Date(2024, 5, 27, 2, 30, 0, 0, moscow), // Воскресенье 26.05 23:30 UTC
+		time.Date(2024, 5, 27, 3, 30, 0, 0, moscow), // Понедельник 27.05 00:30 UTC
+		time.Date(2024, 6, 1, 2, 59, 0, 0, moscow),  // 31.05 23:59 UTC
+		time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+	}
+	// 30.04 23:30 UTC: по московскому времени уже май, но в отчет с 01.05 UTC платеж не входит
+	beforeRange := time.Date(2024, 5, 1, 2, 30, 0, 0, moscow)
+	for _, at := range append([]time.Time{beforeRange}, paidAt...) {
+		if _, err := db.Exec(insertPayment, at); err != nil {
+			t.Fatalf("seed payment: %v", err)
+		}
+	}
+
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		granularity string
+		want        map[string]int
+	}{
+		{granularity: "day", want: map[string]int{"2024-05-26": 1, "2024-05-27": 1, "2024-05-31": 1, "2024-06-01": 1}},
+		{granularity: "week", want: map[string]int{"2024-05-20": 1, "2024-05-27": 3}},
+		{granularity: "month", want: map[string]int{"2024-05-01": 3, "2024-06-01": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.granularity, func(t *testing.T) {
+			points, err := db.GetRevenueByPeriod(tt.granularity, from, to)
+			if err != nil {
+				t.Fatalf("GetRevenueByPeriod() error: %v", err)
+			}
+
+			got := make(map[string]int)
+			for _, point := range points {
+				if point.Period.Location() != time.UTC {
+					t.Errorf("period %s is not in UTC", point.Period)
+				}
+				got[point.Period.Format("2006-01-02")] += point.Payments
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("periods = %v, want %v", got, tt.want)
+			}
+			for period, payments := range tt.want {
+				if got[period] != payments {
+					t.Errorf("periods = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+
+	// Недели отчета о доходах совпадают с неделями когорт
+	cohortWeeks := make(map[string]int)
+	for _, at := range paidAt {
+		cohortWeeks[analytics.WeekStart(at).Format("2006-01-02")]++
+	}
+	if want := tests[1].want; len(cohortWeeks) != len(want) || cohortWeeks["2024-05-20"] != want["2024-05-20"] {
+		t.Errorf("cohort weeks = %v, want %v", cohortWeeks, want)
+	}
+}
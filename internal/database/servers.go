@@ -0,0 +1,24 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+// GetSubscriptionsByServerID возвращает подписки сервера в указанных статусах
+func (db *DB) GetSubscriptionsByServerID(serverID int, statuses []string) ([]models.Subscription, error) {
+	query, args, err := sqlx.In("SELECT * FROM subscriptions WHERE server_id = ? AND status IN (?) ORDER BY id",
+		serverID, statuses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server subscriptions: %w", err)
+	}
+
+	var subscriptions []models.Subscription
+	if err := db.Select(&subscriptions, db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to get server subscriptions: %w", err)
+	}
+	return subscriptions, nil
+}
@@ -0,0 +1,132 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+// addSubscriptionStatusChange записывает переход подписки в историю статусов
+func addSubscriptionStatusChange(exec sqlx.Execer, subscriptionID int, oldStatus, newStatus, reason string) error {
+	_, err := exec.Exec(`
+		INSERT INTO subscription_status_history (subscription_id, old_status, new_status, reason)
+		VALUES ($1, $2, $3, $4)
+	`, subscriptionID, oldStatus, newStatus, reason)
+	if err != nil {
+		return fmt.Errorf("failed to add subscription status change: %w", err)
+	}
+	return nil
+}
+
+// SetSubscriptionPlanGraceDays устанавливает длительность льготного периода для плана
+func (db *DB) SetSubscriptionPlanGraceDays(planID int, days int) error {
+	_, err := db.Exec("UPDATE subscription_plans SET grace_days = $1, updated_at = NOW() WHERE id = $2",
+		days, planID)
+	if err != nil {
+		return fmt.Errorf("failed to update subscription plan grace days: %w", err)
+	}
+	return nil
+}
+
+// TransitionSubscriptionStatus переводит подписку из статуса fromStatus в toStatus и записывает переход в историю.
+// Возвращает sql.ErrNoRows (обернутую), если подписка уже не находится в статусе fromStatus.
+func (db *DB) TransitionSubscriptionStatus(subscriptionID int, fromStatus, toStatus, reason string) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin subscription status transition: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := changeSubscriptionStatus(tx, subscriptionID, fromStatus, toStatus, reason); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit subscription status transition: %w", err)
+	}
+
+	return nil
+}
+
+// RenewSubscription продлевает активную подписку или подписку в льготном периоде на days дней.
+// Действующая подписка продлевается от даты окончания, а подписка в льготном периоде - от момента now.
+// Платеж за продление (если он передан) сохраняется в той же транзакции.
+// Возвращает обновленную подписку и ее статус до продления.
+func (db *DB) RenewSubscription(subscriptionID int, days int, now time.Time, payment *models.Payment) (*models.Subscription, string, error) {
+	tx, err := db.Beginx()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to begin subscription renewal: %w", err)
+	}
+	defer tx.Rollback()
+
+	var subscription models.Subscription
+	err = tx.Get(&subscription, "SELECT * FROM subscriptions WHERE id = $1"+db.dialect.forUpdate, subscriptionID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to renew subscription: %w", err)
+	}
+
+	oldStatus := subscription.Status
+	if oldStatus != "active" && oldStatus != "grace" {
+		return nil, oldStatus, fmt.Errorf("failed to renew subscription: %w", sql.ErrNoRows)
+	}
+
+	base := subscription.EndDate
+	if base.Before(now) {
+		base = now
+	}
+	subscription.EndDate = base.AddDate(0, 0, days)
+	subscription.Status = "active"
+
+	_, err = tx.Exec(`
+		UPDATE subscriptions SET status = 'active', end_date = $1, updated_at = NOW()
+		WHERE id = $2
+	`, subscription.EndDate, subscriptionID)
+	if err != nil {
+		return nil, oldStatus, fmt.Errorf("failed to renew subscription: %w", err)
+	}
+
+	err = addSubscriptionStatusChange(tx, subscriptionID, oldStatus, "active",
+		fmt.Sprintf("Продление на %d дней", days))
+	if err != nil {
+		return nil, oldStatus, err
+	}
+
+	if payment != nil {
+		payment.SubscriptionID = &subscription.ID
+		if err := insertPayment(tx, payment); err != nil {
+			return nil, oldStatus, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, oldStatus, fmt.Errorf("failed to commit subscription renewal: %w", err)
+	}
+
+	return &subscription, oldStatus, nil
+}
+
+// GetSubscriptionsByStatus возвращает все подписки с указанным статусом
+func (db *DB) GetSubscriptionsByStatus(status string) ([]models.Subscription, error) {
+	var subscriptions []models.Subscription
+	err := db.Select(&subscriptions, "SELECT * FROM subscriptions WHERE status = $1", status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscriptions by status: %w", err)
+	}
+	return subscriptions, nil
+}
+
+// GetSubscriptionStatusHistory возвращает историю смены статусов подписки
+func (db *DB) GetSubscriptionStatusHistory(subscriptionID int) ([]models.SubscriptionStatusChange, error) {
+	var history []models.SubscriptionStatusChange
+	err := db.Select(&history,
+		"SELECT * FROM subscription_status_history WHERE subscription_id = $1 ORDER BY created_at, id",
+		subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscription status history: %w", err)
+	}
+	return history, nil
+}
@@ -0,0 +1,108 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+// AddSupportTicket создает обращение в поддержку
+func (db *DB) AddSupportTicket(ticket *models.SupportTicket) error {
+	if ticket.Status == "" {
+		ticket.Status = models.TicketOpen
+	}
+
+	err := db.QueryRow(`
+		INSERT INTO support_tickets (user_id, subscription_id, status)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at, updated_at
+	`, ticket.UserID, ticket.SubscriptionID, ticket.Status,
+	).Scan(&ticket.ID, &ticket.CreatedAt, &ticket.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add support ticket: %w", err)
+	}
+	return nil
+}
+
+// GetSupportTicketByID возвращает обращение по ID
+func (db *DB) GetSupportTicketByID(ticketID int) (*models.SupportTicket, error) {
+	var ticket models.SupportTicket
+	if err := db.Get(&ticket, "SELECT * FROM support_tickets WHERE id = $1", ticketID); err != nil {
+		return nil, fmt.Errorf("failed to get support ticket: %w", err)
+	}
+	return &ticket, nil
+}
+
+// GetOpenSupportTicketsByUserID возвращает незакрытые обращения пользователя
+func (db *DB) GetOpenSupportTicketsByUserID(userID int) ([]models.SupportTicket, error) {
+	var tickets []models.SupportTicket
+	err := db.Select(&tickets, "SELECT * FROM support_tickets WHERE user_id = $1 AND status <> $2 ORDER BY id",
+		userID, models.TicketClosed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user support tickets: %w", err)
+	}
+	return tickets, nil
+}
+
+// SetSupportTicketStatus изменяет статус обращения
+func (db *DB) SetSupportTicketStatus(ticketID int, status string) error {
+	_, err := db.Exec(`
+		UPDATE support_tickets
+		SET status = $1, updated_at = NOW(), closed_at = CASE WHEN $1 = 'closed' THEN NOW() ELSE NULL END
+		WHERE id = $2
+	`, status, ticketID)
+	if err != nil {
+		return fmt.Errorf("failed to set support ticket status: %w", err)
+	}
+	return nil
+}
+
+// AddSupportMessage сохраняет сообщение в истории обращения
+func (db *DB) AddSupportMessage(message *models.SupportMessage) error {
+	err := db.QueryRow(`
+		INSERT INTO support_messages (ticket_id, sender_telegram_id, from_staff, text, photo_file_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`, message.TicketID, message.SenderTelegramID, message.FromStaff, message.Text, message.PhotoFileID,
+	).Scan(&message.ID, &message.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add support message: %w", err)
+	}
+	return nil
+}
+
+// GetSupportMessages возвращает историю сообщений обращения
+func (db *DB) GetSupportMessages(ticketID int) ([]models.SupportMessage, error) {
+	var messages []models.SupportMessage
+	err := db.Select(&messages, "SELECT * FROM support_messages WHERE ticket_id = $1 ORDER BY created_at, id", ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get support messages: %w", err)
+	}
+	return messages, nil
+}
+
+// AddSupportRelay запоминает, что сообщение в чате относится к обращению, чтобы ответ на него попал в обращение
+func (db *DB) AddSupportRelay(chatID int64, messageID int, ticketID int) error {
+	_, err := db.Exec(`
+		INSERT INTO support_relays (chat_id, message_id, ticket_id) VALUES ($1, $2, $3)
+		ON CONFLICT (chat_id, message_id) DO NOTHING
+	`, chatID, messageID, ticketID)
+	if err != nil {
+		return fmt.Errorf("failed to add support relay: %w", err)
+	}
+	return nil
+}
+
+// GetSupportTicketByRelay возвращает обращение, к которому относится сообщение в чате
+func (db *DB) GetSupportTicketByRelay(chatID int64, messageID int) (*models.SupportTicket, error) {
+	var ticket models.SupportTicket
+	err := db.Get(&ticket, `
+		SELECT t.* FROM support_tickets t
+		JOIN support_relays r ON r.ticket_id = t.id
+		WHERE r.chat_id = $1 AND r.message_id = $2
+	`, chatID, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get support ticket by message: %w", err)
+	}
+	return &ticket, nil
+}
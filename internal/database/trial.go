@@ -0,0 +1,96 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+// GetTrialPlan возвращает активный пробный план подписки
+func (db *DB) GetTrialPlan() (*models.SubscriptionPlan, error) {
+	var plan models.SubscriptionPlan
+	err := db.Get(&plan, "SELECT * FROM subscription_plans WHERE is_trial = TRUE AND is_active = TRUE ORDER BY id LIMIT 1")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trial plan: %w", err)
+	}
+	plans := []models.SubscriptionPlan{plan}
+	if err := db.loadPlanPrices(plans); err != nil {
+		return nil, err
+	}
+	return &plans[0], nil
+}
+
+// SetSubscriptionPlanTrial устанавливает или снимает признак пробного плана
+func (db *DB) SetSubscriptionPlanTrial(planID int, isTrial bool) error {
+	_, err := db.Exec("UPDATE subscription_plans SET is_trial = $1, updated_at = NOW() WHERE id = $2",
+		isTrial, planID)
+	if err != nil {
+		return fmt.Errorf("failed to update subscription plan trial flag: %w", err)
+	}
+	return nil
+}
+
+// ClaimTrial помечает пробный период пользователя как использованный.
+// Возвращает false, если пользователь уже активировал пробный период ранее.
+func (db *DB) ClaimTrial(userID int) (bool, error) {
+	result, err := db.Exec(
+		"UPDATE users SET trial_used_at = NOW(), updated_at = NOW() WHERE id = $1 AND trial_used_at IS NULL",
+		userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim trial: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check claimed trial: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// ReleaseTrial возвращает пользователю возможность активировать пробный период
+// (используется, если выдать пробную подписку не удалось)
+func (db *DB) ReleaseTrial(userID int) error {
+	_, err := db.Exec("UPDATE users SET trial_used_at = NULL, updated_at = NOW() WHERE id = $1", userID)
+	if err != nil {
+		return fmt.Errorf("failed to release trial: %w", err)
+	}
+	return nil
+}
+
+// GetTrialStats возвращает статистику конверсии пробного периода в платные подписки
+func (db *DB) GetTrialStats() (*models.TrialStats, error) {
+	stats := &models.TrialStats{}
+
+	// Пользователи, активировавшие пробный период
+	err := db.Get(&stats.TrialUsers, "SELECT COUNT(*) FROM users WHERE trial_used_at IS NOT NULL")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trial users count: %w", err)
+	}
+
+	// Действующие пробные подписки
+	err = db.Get(&stats.ActiveTrials,
+		"SELECT COUNT(*) FROM subscriptions WHERE is_trial = TRUE AND status = 'active'")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active trials count: %w", err)
+	}
+
+	// Пользователи, которые оплатили подписку после активации пробного периода
+	err = db.Get(&stats.ConvertedUsers, `
+		SELECT COUNT(DISTINCT u.id)
+		FROM users u
+		JOIN payments p ON p.user_id = u.id
+		WHERE u.trial_used_at IS NOT NULL
+		AND p.status = 'completed'
+		AND p.created_at >= u.trial_used_at
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get converted trial users count: %w", err)
+	}
+
+	if stats.TrialUsers > 0 {
+		stats.ConversionRate = float64(stats.ConvertedUsers) * 100 / float64(stats.TrialUsers)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,167 @@
+package database
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ilokitv/botVPN/internal/models"
+	"github.com/ilokitv/botVPN/internal/money"
+)
+
+// newUserDays - за сколько дней регистрации пользователь считается новым
+const newUserDays = 7
+
+// userListFilters - условия выборки для фильтров списка пользователей.
+// В условие фильтра новых пользователей подставляется параметр с началом периода.
+var userListFilters = map[string]string{
+	models.UserFilterAll:    "TRUE",
+	models.UserFilterActive: "EXISTS (SELECT 1 FROM subscriptions s WHERE s.user_id = u.id AND s.status = 'active')",
+	models.UserFilterExpired: `EXISTS (SELECT 1 FROM subscriptions s WHERE s.user_id = u.id AND s.status IN ('expired', 'grace'))
+		AND NOT EXISTS (SELECT 1 FROM subscriptions s WHERE s.user_id = u.id AND s.status = 'active')`,
+	models.UserFilterStaff: "u.role <> ''",
+	models.UserFilterNew:   "u.created_at >= %s",
+}
+
+// userListOrders - порядок сортировки списка пользователей
+var userListOrders = map[string]string{
+	models.UserSortNewest:   "u.created_at DESC, u.id DESC",
+	models.UserSortPayments: "payments_count DESC, u.id",
+	models.UserSortName:     "LOWER(COALESCE(NULLIF(u.username, ''), u.first_name)), u.id",
+}
+
+// userListWhere формирует условие выборки и его аргументы для списка пользователей
+func (db *DB) userListWhere(query models.UserListQuery) (string, []interface{}, error) {
+	filter, ok := userListFilters[query.Filter]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown user list filter: %s", query.Filter)
+	}
+
+	var args []interface{}
+	// arg добавляет аргумент запроса и возвращает его параметр ($1, $2, ...)
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if query.Filter == models.UserFilterNew {
+		filter = fmt.Sprintf(filter, arg(time.Now().AddDate(0, 0, -newUserDays)))
+	}
+	conditions := []string{filter}
+
+	search := strings.TrimPrefix(strings.TrimSpace(query.Search), "@")
+	if search != "" {
+		if id, err := strconv.ParseInt(search, 10, 64); err == nil {
+			// Число может быть Telegram ID пользователя или ID его подписки
+			p := arg(id)
+			conditions = append(conditions,
+				"(u.telegram_id = "+p+" OR EXISTS (SELECT 1 FROM subscriptions s WHERE s.user_id = u.id AND s.id = "+p+"))")
+		} else {
+			p := arg("%" + search + "%")
+			conditions = append(conditions, fmt.Sprintf("(u.username %[1]s %[2]s OR u.first_name %[1]s %[2]s OR u.last_name %[1]s %[2]s)",
+				db.dialect.ilike, p))
+		}
+	}
+
+	return strings.Join(conditions, " AND "), args, nil
+}
+
+// GetUserList возвращает страницу списка пользователей и общее количество пользователей, подходящих под условия
+func (db *DB) GetUserList(query models.UserListQuery) ([]models.UserListItem, int, error) {
+	order, ok := userListOrders[query.Sort]
+	if !ok {
+		return nil, 0, fmt.Errorf("unknown user list sort: %s", query.Sort)
+	}
+
+	where, args, err := db.userListWhere(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := db.Get(&total, "SELECT COUNT(*) FROM users u WHERE "+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	limitArg := len(args) + 1
+	var users []models.UserListItem
+	err = db.Select(&users, fmt.Sprintf(`
+		SELECT u.*,
+			(SELECT COUNT(*) FROM subscriptions s WHERE s.user_id = u.id AND s.status = 'active') AS active_subscriptions,
+			(SELECT COUNT(*) FROM payments p
+				WHERE p.user_id = u.id AND p.status = 'completed' AND p.payment_method <> 'balance') AS payments_count
+		FROM users u
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, where, order, limitArg, limitArg+1), append(args, query.Limit, query.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get user list: %w", err)
+	}
+
+	if err := db.loadUserPaymentTotals(users); err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// loadUserPaymentTotals заполняет суммы платежей пользователей страницы по валютам
+func (db *DB) loadUserPaymentTotals(users []models.UserListItem) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	userIDs := make([]int, len(users))
+	for i, user := range users {
+		userIDs[i] = user.ID
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT user_id, currency, SUM(amount) AS amount FROM payments
+		WHERE user_id IN (?) AND status = 'completed' AND payment_method <> 'balance'
+		GROUP BY user_id, currency
+	`, userIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get user payment totals: %w", err)
+	}
+
+	var rows []struct {
+		UserID   int    `db:"user_id"`
+		Currency string `db:"currency"`
+		Amount   int64  `db:"amount"`
+	}
+	if err := db.Select(&rows, db.Rebind(query), args...); err != nil {
+		return fmt.Errorf("failed to get user payment totals: %w", err)
+	}
+
+	totals := make(map[int]money.Totals)
+	for _, row := range rows {
+		if totals[row.UserID] == nil {
+			totals[row.UserID] = make(money.Totals)
+		}
+		totals[row.UserID].Add(row.Currency, row.Amount)
+	}
+	for i := range users {
+		users[i].TotalPayments = totals[users[i].ID]
+	}
+
+	return nil
+}
+
+// GetPaymentsByUserID возвращает последние платежи пользователя
+func (db *DB) GetPaymentsByUserID(userID int, limit int) ([]models.Payment, error) {
+	var payments []models.Payment
+	err := db.Select(&payments,
+		`SELECT id, user_id, subscription_id, amount, currency, payment_method, COALESCE(payment_id, '') AS payment_id,
+			status, created_at, updated_at
+		FROM payments WHERE user_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2`,
+		userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user payments: %w", err)
+	}
+	return payments, nil
+}
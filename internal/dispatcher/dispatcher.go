@@ -0,0 +1,138 @@
+package dispatcher
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// HandlerFunc обрабатывает одно обновление от Telegram
+type HandlerFunc func(update tgbotapi.Update)
+
+// Dispatcher распределяет обновления по пулу обработчиков.
+// Обновления одного чата всегда попадают к одному обработчику и выполняются по порядку,
+// а обновления разных чатов обрабатываются параллельно.
+type Dispatcher struct {
+	handle HandlerFunc
+	queues []chan tgbotapi.Update // Ограниченная очередь для каждого обработчика
+	wg     sync.WaitGroup
+}
+
+// New создает диспетчер с указанным количеством обработчиков и размером очереди каждого из них
+func New(workers, queueSize int, handle HandlerFunc) *Dispatcher {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < 1 {
+		queueSize = 1
+	}
+
+	d := &Dispatcher{
+		handle: handle,
+		queues: make([]chan tgbotapi.Update, workers),
+	}
+	for i := range d.queues {
+		d.queues[i] = make(chan tgbotapi.Update, queueSize)
+	}
+	return d
+}
+
+// Run запускает обработчики и распределяет между ними обновления, пока не будет отменен ctx
+// или не закроется канал updates. Обновления, уже поставленные в очередь, обрабатываются
+// до конца: при получении через long polling они подтверждены и повторно не придут.
+func (d *Dispatcher) Run(ctx context.Context, updates <-chan tgbotapi.Update) {
+	for i, queue := range d.queues {
+		d.wg.Add(1)
+		go d.work(i, queue)
+	}
+
+	d.receive(ctx, updates)
+
+	for _, queue := range d.queues {
+		close(queue)
+	}
+	d.wg.Wait()
+}
+
+// receive читает обновления и ставит их в очередь обработчика, отвечающего за чат
+func (d *Dispatcher) receive(ctx context.Context, updates <-chan tgbotapi.Update) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			if !d.enqueue(ctx, update) {
+				return
+			}
+		}
+	}
+}
+
+// enqueue ставит обновление в очередь. Если очередь заполнена, ожидает освобождения места,
+// приостанавливая получение новых обновлений. Возвращает false, если ожидание прервано отменой ctx.
+func (d *Dispatcher) enqueue(ctx context.Context, update tgbotapi.Update) bool {
+	queue := d.queues[shard(chatKey(update), len(d.queues))]
+
+	select {
+	case queue <- update:
+		return true
+	default:
+	}
+
+	log.Printf("Очередь обработки обновлений заполнена, ожидание освобождения (обновление %d)", update.UpdateID)
+	select {
+	case queue <- update:
+		return true
+	case <-ctx.Done():
+		log.Printf("Обновление %d не обработано: бот завершает работу", update.UpdateID)
+		return false
+	}
+}
+
+// work последовательно обрабатывает обновления из своей очереди
+func (d *Dispatcher) work(worker int, queue <-chan tgbotapi.Update) {
+	defer d.wg.Done()
+
+	for update := range queue {
+		d.process(worker, update)
+	}
+}
+
+// process обрабатывает одно обновление. Паника при обработке не останавливает обработчик.
+func (d *Dispatcher) process(worker int, update tgbotapi.Update) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Паника при обработке обновления %d (обработчик %d): %v\n%s", update.UpdateID, worker, r, debug.Stack())
+		}
+	}()
+
+	d.handle(update)
+}
+
+// chatKey возвращает ключ, по которому обновления упорядочиваются: ID чата или, если чата нет, ID отправителя.
+// В личных чатах ID чата совпадает с ID пользователя, поэтому платежи пользователя
+// обрабатываются в том же порядке, что и его сообщения.
+func chatKey(update tgbotapi.Update) int64 {
+	if update.CallbackQuery != nil && update.CallbackQuery.Message == nil {
+		// Колбэк от инлайн-сообщения не привязан к чату
+		return update.CallbackQuery.From.ID
+	}
+	if chat := update.FromChat(); chat != nil {
+		return chat.ID
+	}
+	if user := update.SentFrom(); user != nil {
+		return user.ID
+	}
+	return 0
+}
+
+// shard возвращает номер обработчика для ключа
+func shard(key int64, workers int) int {
+	return int(uint64(key) % uint64(workers))
+}
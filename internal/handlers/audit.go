@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/ilokitv/botVPN/internal/i18n"
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+// auditPageSize - количество записей журнала на одной странице
+const auditPageSize = 8
+
+// maxAuditValueLength - максимальная длина значений до и после изменения на экране, полные значения доступны в CSV
+const maxAuditValueLength = 150
+
+// auditActionNames - ключи каталога с названиями действий для панели управления
+var auditActionNames = map[string]string{
+	models.AuditServerAdd:           "audit.action_server_add",
+	models.AuditServerEdit:          "audit.action_server_edit",
+	models.AuditServerReissue:       "audit.action_server_reissue",
+	models.AuditServerDelete:        "audit.action_server_delete",
+	models.AuditPlanAdd:             "audit.action_plan_add",
+	models.AuditPlanEdit:            "audit.action_plan_edit",
+	models.AuditPlanDelete:          "audit.action_plan_delete",
+	models.AuditSubscriptionBlock:   "audit.action_subscription_block",
+	models.AuditSubscriptionUnblock: "audit.action_subscription_unblock",
+	models.AuditSubscriptionRevoke:  "audit.action_subscription_revoke",
+	models.AuditRoleChange:          "audit.action_role_change",
+	models.AuditBalanceChange:       "audit.action_balance_change",
+	models.AuditBroadcast:           "audit.action_broadcast",
+}
+
+// auditTargetNames - ключи каталога с названиями объектов для панели управления
+var auditTargetNames = map[string]string{
+	models.AuditTargetServer:       "audit.target_server",
+	models.AuditTargetPlan:         "audit.target_plan",
+	models.AuditTargetSubscription: "audit.target_subscription",
+	models.AuditTargetUser:         "audit.target_user",
+	models.AuditTargetBroadcast:    "audit.target_broadcast",
+}
+
+// auditValue преобразует значение до или после изменения в JSON для журнала
+func auditValue(value interface{}) string {
+	if value == nil {
+		return "{}"
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("Ошибка при сохранении значения в журнал действий: %v", err)
+		return "{}"
+	}
+	return string(data)
+}
+
+// audit записывает действие сотрудника в журнал. Ошибка записи не прерывает само действие и только логируется.
+func (h *BotHandler) audit(actorTelegramID int64, action, targetType string, targetID int, before, after interface{}) {
+	entry := &models.AuditEntry{
+		ActorTelegramID: actorTelegramID,
+		Action:          action,
+		TargetType:      targetType,
+		TargetID:        targetID,
+		Before:          auditValue(before),
+		After:           auditValue(after),
+	}
+	if actor, err := h.db.GetUserByTelegramID(actorTelegramID); err == nil {
+		entry.ActorUserID = &actor.ID
+	}
+
+	if err := h.db.AddAuditEntry(entry); err != nil {
+		log.Printf("Ошибка при записи в журнал действий (%s %s #%d, сотрудник %d): %v",
+			action, targetType, targetID, actorTelegramID, err)
+	}
+}
+
+// auditActorName возвращает имя сотрудника из записи журнала
+func auditActorName(entry models.AuditEntry) string {
+	if entry.ActorUsername != "" {
+		return "@" + entry.ActorUsername
+	}
+	return fmt.Sprintf("ID %d", entry.ActorTelegramID)
+}
+
+// auditActionName возвращает название действия из журнала на языке lang
+func auditActionName(lang, action string) string {
+	if key, ok := auditActionNames[action]; ok {
+		return i18n.T(lang, key)
+	}
+	return action
+}
+
+// auditTargetName возвращает название объекта из журнала на языке lang
+func auditTargetName(lang, targetType string) string {
+	if key, ok := auditTargetNames[targetType]; ok {
+		return i18n.T(lang, key)
+	}
+	return targetType
+}
+
+// shortenAuditValue сокращает значение для показа в сообщении
+func shortenAuditValue(value string) string {
+	if len([]rune(value)) <= maxAuditValueLength {
+		return value
+	}
+	return string([]rune(value)[:maxAuditValueLength]) + "…"
+}
+
+// showAuditLog отображает страницу журнала действий сотрудников
+func (h *BotHandler) showAuditLog(chatID int64, page int) {
+	total, err := h.db.CountAuditEntries()
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "audit.error", err))
+		return
+	}
+
+	pages := (total + auditPageSize - 1) / auditPageSize
+	if pages == 0 {
+		pages = 1
+	}
+	if page < 0 || page >= pages {
+		page = 0
+	}
+
+	entries, err := h.db.GetAuditEntries(auditPageSize, page*auditPageSize)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "audit.error", err))
+		return
+	}
+
+	lang := h.lang(chatID)
+	var text strings.Builder
+	text.WriteString(i18n.T(lang, "audit.header", page+1, pages, total))
+	if len(entries) == 0 {
+		text.WriteString(i18n.T(lang, "audit.empty"))
+	}
+	for _, entry := range entries {
+		fmt.Fprintf(&text, "#%d %s — %s\n%s: %s #%d\n",
+			entry.ID, entry.CreatedAt.Format("02.01.2006 15:04"), auditActorName(entry),
+			auditActionName(lang, entry.Action), auditTargetName(lang, entry.TargetType), entry.TargetID)
+		if entry.Before != "{}" {
+			text.WriteString(i18n.T(lang, "audit.before", shortenAuditValue(entry.Before)))
+		}
+		if entry.After != "{}" {
+			text.WriteString(i18n.T(lang, "audit.after", shortenAuditValue(entry.After)))
+		}
+		text.WriteString("\n")
+	}
+
+	// Переключение страниц
+	var navRow []tgbotapi.InlineKeyboardButton
+	if page > 0 {
+		navRow = append(navRow, h.button(chatID, i18n.T(lang, "audit.newer"), fmt.Sprintf("audit_page:%d", page-1)))
+	}
+	if page < pages-1 {
+		navRow = append(navRow, h.button(chatID, i18n.T(lang, "audit.older"), fmt.Sprintf("audit_page:%d", page+1)))
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup()
+	if len(navRow) > 0 {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, navRow)
+	}
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard,
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, i18n.T(lang, "revenue.export_csv"), "audit_csv"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, i18n.T(lang, "admin.back_button"), "admin_menu:main"),
+		),
+	)
+
+	// Значения в формате JSON отправляются без разметки, чтобы символы в них не ломали Markdown
+	msg := tgbotapi.NewMessage(chatID, text.String())
+	msg.ReplyMarkup = keyboard
+	h.sender.Send(msg)
+}
+
+// buildAuditCSV формирует CSV-выгрузку журнала действий
+func buildAuditCSV(entries []models.AuditEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	rows := [][]string{
+		{"id", "created_at", "actor_user_id", "actor_telegram_id", "actor_username",
+			"action", "target_type", "target_id", "before", "after"},
+	}
+	for _, entry := range entries {
+		actorUserID := ""
+		if entry.ActorUserID != nil {
+			actorUserID = strconv.Itoa(*entry.ActorUserID)
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(entry.ID),
+			entry.CreatedAt.Format(time.RFC3339),
+			actorUserID,
+			strconv.FormatInt(entry.ActorTelegramID, 10),
+			entry.ActorUsername,
+			entry.Action,
+			entry.TargetType,
+			strconv.Itoa(entry.TargetID),
+			entry.Before,
+			entry.After,
+		})
+	}
+
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// exportAuditCSV отправляет сотруднику CSV-выгрузку всего журнала действий
+func (h *BotHandler) exportAuditCSV(chatID int64) {
+	entries, err := h.db.GetAllAuditEntries()
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "audit.error", err))
+		return
+	}
+
+	data, err := buildAuditCSV(entries)
+	if err != nil {
+		log.Printf("Ошибка при формировании CSV-выгрузки журнала действий: %v", err)
+		h.sendMessage(chatID, h.t(chatID, "revenue.export_error"))
+		return
+	}
+
+	document := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{
+		Name:  fmt.Sprintf("audit_log_%s.csv", time.Now().Format("20060102")),
+		Bytes: data,
+	})
+	document.Caption = h.t(chatID, "audit.export_caption", len(entries))
+
+	if _, err := h.sender.Send(document); err != nil {
+		h.sendMessage(chatID, h.t(chatID, "revenue.export_send_error", err))
+	}
+}
@@ -0,0 +1,393 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/ilokitv/botVPN/internal/database"
+	"github.com/ilokitv/botVPN/internal/i18n"
+	"github.com/ilokitv/botVPN/internal/models"
+	"github.com/ilokitv/botVPN/internal/money"
+)
+
+// balanceTopUpAmounts - суммы пополнения баланса, предлагаемые пользователю (в рублях)
+var balanceTopUpAmounts = []int{100, 300, 500, 1000}
+
+// maxBalanceTopUp - максимальная сумма одного пополнения (в рублях)
+const maxBalanceTopUp = 100000
+
+// ledgerTypeText возвращает описание типа операции по балансу на языке lang
+func ledgerTypeText(lang, transactionType string) string {
+	switch transactionType {
+	case models.LedgerTopUp, models.LedgerPurchase, models.LedgerRefund, models.LedgerBonus, models.LedgerAdjustment:
+		return i18n.T(lang, "ledger."+transactionType)
+	default:
+		return transactionType
+	}
+}
+
+// formatLedgerTransactions формирует список последних операций по балансу на языке lang
+func formatLedgerTransactions(lang string, transactions []models.LedgerTransaction) string {
+	if len(transactions) == 0 {
+		return i18n.T(lang, "balance.no_transactions")
+	}
+
+	var sb strings.Builder
+	for _, transaction := range transactions {
+		sb.WriteString(i18n.T(lang, "balance.transaction",
+			transaction.CreatedAt.Format("02.01.2006"),
+			ledgerTypeText(lang, transaction.Type),
+			money.FormatSigned(transaction.Amount, models.LedgerCurrency),
+		))
+		if transaction.Description != "" {
+			sb.WriteString(" - " + transaction.Description)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// handleBalanceCommand показывает пользователю баланс и историю операций
+func (h *BotHandler) handleBalanceCommand(chatID int64, telegramID int64) {
+	user, err := h.db.GetUserByTelegramID(telegramID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.user_error"))
+		return
+	}
+
+	balance, err := h.db.GetUserBalance(user.ID)
+	if err != nil {
+		log.Printf("Ошибка при получении баланса пользователя #%d: %v", user.ID, err)
+		h.sendMessage(chatID, h.t(chatID, "balance.error"))
+		return
+	}
+
+	transactions, err := h.db.GetLedgerTransactionsByUserID(user.ID, 10)
+	if err != nil {
+		log.Printf("Ошибка при получении операций пользователя #%d: %v", user.ID, err)
+	}
+
+	lang := h.lang(chatID)
+	text := i18n.T(lang, "balance.text",
+		formatBalance(balance),
+		formatLedgerTransactions(lang, transactions),
+	)
+
+	var row []tgbotapi.InlineKeyboardButton
+	for _, amount := range balanceTopUpAmounts {
+		row = append(row, h.button(chatID,
+			fmt.Sprintf("%d ₽", amount), fmt.Sprintf("balance_topup:%d", amount)))
+	}
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		row,
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, i18n.T(lang, "menu.buy"), "show_buy_plans"),
+		),
+	)
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+	h.sender.Send(msg)
+}
+
+// handleBalanceTopUp выставляет счет на пополнение баланса
+func (h *BotHandler) handleBalanceTopUp(chatID int64, amount int) {
+	if amount <= 0 || amount > maxBalanceTopUp {
+		h.sendMessage(chatID, h.t(chatID, "balance.invalid_amount"))
+		return
+	}
+
+	// Баланс ведется в рублях, поэтому пополнение всегда оплачивается в рублях
+	invoice := h.newInvoice(
+		chatID,
+		h.t(chatID, "balance.invoice_title"),
+		h.t(chatID, "balance.invoice_desc", amount),
+		fmt.Sprintf("topup:%d", amount), // Payload для идентификации пополнения
+		h.t(chatID, "balance.invoice_title"),
+		int64(amount)*100, // Переводим в копейки
+		models.LedgerCurrency,
+	)
+
+	_, err := h.sender.Send(invoice)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.invoice_error", err))
+	}
+}
+
+// handleTopUpPayment зачисляет оплаченное пополнение на баланс пользователя
+func (h *BotHandler) handleTopUpPayment(chatID int64, telegramID int64, payment *tgbotapi.SuccessfulPayment) {
+	user, err := h.db.GetUserByTelegramID(telegramID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.user_error_support"))
+		return
+	}
+
+	paymentRecord := telegramPayment(user.ID, payment)
+	err = h.db.AddPayment(paymentRecord)
+	if err != nil {
+		log.Printf("Ошибка при сохранении платежа за пополнение баланса: %v", err)
+	}
+
+	if paymentRecord.Currency != models.LedgerCurrency {
+		log.Printf("Пополнение баланса пользователя #%d в валюте %s не зачислено", user.ID, paymentRecord.Currency)
+		h.sendMessage(chatID, h.t(chatID, "payment.not_credited", money.Format(paymentRecord.Amount, paymentRecord.Currency)))
+		return
+	}
+
+	transaction := &models.LedgerTransaction{
+		UserID:      user.ID,
+		Type:        models.LedgerTopUp,
+		Amount:      paymentRecord.Amount,
+		Description: "Пополнение через Telegram",
+	}
+	if paymentRecord.ID != 0 {
+		transaction.PaymentID = &paymentRecord.ID
+	}
+
+	if err := h.db.PostLedgerTransaction(transaction); err != nil {
+		log.Printf("Ошибка при зачислении пополнения пользователю #%d: %v", user.ID, err)
+		h.sendMessage(chatID, h.t(chatID, "balance.topup_error"))
+		return
+	}
+
+	h.sendMessage(chatID, h.t(chatID, "balance.topped_up",
+		formatBalance(transaction.Amount), formatBalance(transaction.BalanceAfter)))
+}
+
+// handleBalancePay оплачивает план подписки с баланса пользователя
+func (h *BotHandler) handleBalancePay(chatID int64, telegramID int64, planID int) {
+	plan, err := h.db.GetSubscriptionPlanByID(planID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.plan_error", err))
+		return
+	}
+
+	if !plan.IsActive || plan.IsTrial {
+		h.sendMessage(chatID, h.t(chatID, "common.plan_unavailable"))
+		return
+	}
+
+	price, ok := plan.PriceIn(models.LedgerCurrency)
+	if !ok {
+		h.sendMessage(chatID, h.t(chatID, "balance.no_price"))
+		return
+	}
+
+	user, err := h.db.GetUserByTelegramID(telegramID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.user_error"))
+		return
+	}
+
+	availableServer, err := h.subscriptions.AvailableServer()
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.servers_check_error"))
+		return
+	}
+
+	if availableServer == nil {
+		h.sendMessage(chatID, h.t(chatID, "common.no_servers"))
+		return
+	}
+
+	// Списываем средства до выдачи подписки, чтобы параллельная покупка не увела баланс в минус
+	purchase := &models.LedgerTransaction{
+		UserID:      user.ID,
+		Type:        models.LedgerPurchase,
+		Amount:      -price,
+		Description: fmt.Sprintf("Подписка «%s»", plan.Name),
+	}
+
+	err = h.db.PostLedgerTransaction(purchase)
+	if errors.Is(err, database.ErrInsufficientFunds) {
+		balance, _ := h.db.GetUserBalance(user.ID)
+		msg := tgbotapi.NewMessage(chatID, h.t(chatID, "balance.insufficient", formatBalance(price), formatBalance(balance)))
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				h.button(chatID, h.t(chatID, "button.topup"), "balance_action:show"),
+			),
+		)
+		h.sender.Send(msg)
+		return
+	}
+	if err != nil {
+		log.Printf("Ошибка при списании с баланса пользователя #%d: %v", user.ID, err)
+		h.sendMessage(chatID, h.t(chatID, "balance.pay_error"))
+		return
+	}
+
+	h.sendMessage(chatID, h.t(chatID, "balance.provisioning"))
+
+	paymentRecord := &models.Payment{
+		UserID:        user.ID,
+		Amount:        price,
+		Currency:      models.LedgerCurrency,
+		PaymentMethod: models.PaymentMethodBalance,
+		PaymentID:     fmt.Sprintf("ledger:%d", purchase.ID),
+		Status:        "completed",
+	}
+
+	subscription, err := h.provisionSubscription(user, plan, fmt.Sprintf("user_%d", user.ID), false, paymentRecord)
+	if err != nil {
+		// Возвращаем списанные средства
+		refund := &models.LedgerTransaction{
+			UserID:      user.ID,
+			Type:        models.LedgerRefund,
+			Amount:      price,
+			Description: fmt.Sprintf("Возврат: не удалось оформить подписку «%s»", plan.Name),
+		}
+		if refundErr := h.db.PostLedgerTransaction(refund); refundErr != nil {
+			log.Printf("Ошибка при возврате средств пользователю #%d: %v", user.ID, refundErr)
+		}
+		h.sendMessage(chatID, err.Error()+h.t(chatID, "balance.refunded"))
+		return
+	}
+
+	if err := h.sendConfigWithInstructions(chatID, subscription.ConfigFilePath); err != nil {
+		return
+	}
+
+	successMsg := h.t(chatID, "balance.purchase_success",
+		plan.Name,
+		plan.Duration,
+		subscription.EndDate.Format("02.01.2006"),
+		formatBalance(purchase.BalanceAfter),
+	)
+
+	msg := tgbotapi.NewMessage(chatID, successMsg)
+	msg.ParseMode = "Markdown"
+	h.sender.Send(msg)
+}
+
+// showUserBalanceForAdmin показывает администратору баланс пользователя и историю операций
+func (h *BotHandler) showUserBalanceForAdmin(chatID int64, user *models.User) {
+	balance, err := h.db.GetUserBalance(user.ID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "balance_admin.error", err))
+		return
+	}
+
+	transactions, err := h.db.GetLedgerTransactionsByUserID(user.ID, 20)
+	if err != nil {
+		log.Printf("Ошибка при получении операций пользователя #%d: %v", user.ID, err)
+	}
+
+	lang := h.lang(chatID)
+	text := i18n.T(lang, "balance_admin.text",
+		user.Username, user.ID, formatBalance(balance), formatLedgerTransactions(lang, transactions))
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, i18n.T(lang, "balance_admin.adjust_button"), fmt.Sprintf("user_action:adjust_balance:%d", user.ID)),
+			h.button(chatID, i18n.T(lang, "balance_admin.bonus_button"), fmt.Sprintf("user_action:bonus_balance:%d", user.ID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, i18n.T(lang, "admin.back_button"), fmt.Sprintf("user_card:%d", user.ID)),
+		),
+	)
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+	h.sender.Send(msg)
+}
+
+// startBalanceAdjustment начинает ручное изменение баланса пользователя администратором
+func (h *BotHandler) startBalanceAdjustment(chatID int64, user *models.User, transactionType string) {
+	h.setState(chatID, UserState{
+		State: "adjust_balance_amount",
+		Data: map[string]string{
+			"user_id": strconv.Itoa(user.ID),
+			"type":    transactionType,
+		},
+	})
+
+	prompt := h.t(chatID, "balance_admin.adjust_prompt")
+	if transactionType == models.LedgerBonus {
+		prompt = h.t(chatID, "balance_admin.bonus_prompt")
+	}
+
+	h.sendMessage(chatID, h.t(chatID, "balance_admin.start",
+		tgbotapi.EscapeText(tgbotapi.ModeMarkdown, user.Username), user.ID, prompt))
+}
+
+// handleBalanceAdjustmentInput обрабатывает шаги ручного изменения баланса
+func (h *BotHandler) handleBalanceAdjustmentInput(message *tgbotapi.Message, userState UserState) {
+	userID := message.From.ID
+	chatID := message.Chat.ID
+
+	switch userState.State {
+	case "adjust_balance_amount":
+		amount, err := money.Parse(message.Text, models.LedgerCurrency)
+		if err != nil || amount == 0 {
+			h.sendMessage(chatID, h.t(chatID, "balance_admin.invalid_amount"))
+			return
+		}
+		if userState.Data["type"] == models.LedgerBonus && amount < 0 {
+			h.sendMessage(chatID, h.t(chatID, "balance_admin.negative_bonus"))
+			return
+		}
+
+		userState.Data["amount"] = strconv.FormatInt(amount, 10) // В копейках
+		userState.State = "adjust_balance_reason"
+		h.setState(chatID, userState)
+		h.sendMessage(chatID, h.t(chatID, "balance_admin.reason_prompt"))
+
+	case "adjust_balance_reason":
+		reason := strings.TrimSpace(message.Text)
+		if reason == "" {
+			h.sendMessage(chatID, h.t(chatID, "balance_admin.empty_reason"))
+			return
+		}
+
+		h.clearState(chatID)
+
+		targetUserID, _ := strconv.Atoi(userState.Data["user_id"])
+		amount, _ := strconv.ParseInt(userState.Data["amount"], 10, 64)
+
+		transaction := &models.LedgerTransaction{
+			UserID:      targetUserID,
+			Type:        userState.Data["type"],
+			Amount:      amount,
+			Description: reason,
+		}
+
+		// Запоминаем, кто из администраторов изменил баланс
+		if admin, err := h.db.GetUserByTelegramID(userID); err == nil {
+			transaction.ActorUserID = &admin.ID
+		}
+
+		if err := h.db.PostLedgerTransaction(transaction); err != nil {
+			log.Printf("Ошибка при изменении баланса пользователя #%d: %v", targetUserID, err)
+			h.sendMessage(chatID, h.t(chatID, "balance_admin.save_error", err))
+			return
+		}
+
+		log.Printf("Администратор %d изменил баланс пользователя #%d на %s (%s): %s",
+			userID, targetUserID, money.FormatSigned(amount, models.LedgerCurrency), transaction.Type, reason)
+		h.audit(userID, models.AuditBalanceChange, models.AuditTargetUser, targetUserID,
+			map[string]interface{}{"balance": transaction.BalanceAfter - amount},
+			map[string]interface{}{
+				"balance":        transaction.BalanceAfter,
+				"type":           transaction.Type,
+				"amount":         amount,
+				"reason":         reason,
+				"transaction_id": transaction.ID,
+			})
+
+		h.sendMessage(chatID, h.t(chatID, "balance_admin.changed",
+			targetUserID, money.FormatSigned(amount, models.LedgerCurrency), formatBalance(transaction.BalanceAfter)))
+
+		// Уведомляем пользователя об изменении баланса
+		if target, err := h.db.GetUserByID(targetUserID); err == nil {
+			lang := userLang(target)
+			h.sendMessage(target.TelegramID, i18n.T(lang, "balance.changed_notice",
+				ledgerTypeText(lang, transaction.Type), money.FormatSigned(amount, models.LedgerCurrency),
+				formatBalance(transaction.BalanceAfter)))
+		}
+	}
+}
@@ -2,29 +2,50 @@ package handlers
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"golang.org/x/crypto/ssh"
 
+	"github.com/ilokitv/botVPN/internal/callbackdata"
 	"github.com/ilokitv/botVPN/internal/config"
 	"github.com/ilokitv/botVPN/internal/database"
+	"github.com/ilokitv/botVPN/internal/i18n"
 	"github.com/ilokitv/botVPN/internal/models"
+	"github.com/ilokitv/botVPN/internal/money"
+	"github.com/ilokitv/botVPN/internal/roles"
+	"github.com/ilokitv/botVPN/internal/sender"
+	"github.com/ilokitv/botVPN/internal/service"
 	"github.com/ilokitv/botVPN/internal/vpn"
 )
 
 // BotHandler обрабатывает взаимодействие с Telegram ботом
 type BotHandler struct {
-	bot        *tgbotapi.BotAPI
-	db         *database.DB
-	vpnManager *vpn.WireguardManager
-	config     *config.Config
-	userStates map[int64]UserState
+	ctx           context.Context // Контекст приложения: отменяется при остановке бота
+	bot           *tgbotapi.BotAPI
+	db            *database.DB
+	vpnManager    *vpn.WireguardManager
+	subscriptions *service.Subscriptions // Выдача, продление, пауза и отзыв подписок
+	config        *config.Config
+	states        StateStore           // Состояния диалогов по ID чата
+	callbacks     *callbackdata.Signer // Подпись данных инлайн-кнопок
+	sender        *sender.Sender       // Отправка сообщений с ограничением скорости и повторами
+
+	languages   map[int64]string // Язык интерфейса пользователей по Telegram ID
+	languagesMu sync.RWMutex
+
+	rejectedReports   map[int64]time.Time // Когда администраторам последний раз сообщали об отклоненных кнопках пользователя
+	rejectedReportsMu sync.Mutex
+
+	background sync.WaitGroup // Фоновые рассылки
 }
 
 // UserState содержит состояние пользователя в диалоге с ботом
@@ -35,24 +56,31 @@ type UserState struct {
 }
 
 // NewBotHandler создает нового обработчика бота
-func NewBotHandler(bot *tgbotapi.BotAPI, db *database.DB, vpnManager *vpn.WireguardManager, cfg *config.Config) *BotHandler {
+func NewBotHandler(ctx context.Context, bot *tgbotapi.BotAPI, db *database.DB, vpnManager *vpn.WireguardManager, subscriptions *service.Subscriptions, cfg *config.Config, states StateStore, callbacks *callbackdata.Signer, sender *sender.Sender) *BotHandler {
 	return &BotHandler{
-		bot:        bot,
-		db:         db,
-		vpnManager: vpnManager,
-		config:     cfg,
-		userStates: make(map[int64]UserState),
+		ctx:           ctx,
+		bot:           bot,
+		db:            db,
+		vpnManager:    vpnManager,
+		subscriptions: subscriptions,
+		config:        cfg,
+		states:        states,
+		callbacks:     callbacks,
+		sender:        sender,
+		languages:     make(map[int64]string),
+
+		rejectedReports: make(map[int64]time.Time),
 	}
 }
 
-// IsAdmin проверяет, является ли пользователь администратором
+// Wait ждет, пока фоновые рассылки остановятся и сохранят прогресс после отмены контекста приложения
+func (h *BotHandler) Wait() {
+	h.background.Wait()
+}
+
+// IsAdmin проверяет, есть ли у пользователя роль сотрудника с доступом к панели управления
 func (h *BotHandler) IsAdmin(userID int64) bool {
-	for _, adminID := range h.config.Bot.AdminIDs {
-		if adminID == userID {
-			return true
-		}
-	}
-	return false
+	return roles.IsStaff(h.role(userID))
 }
 
 // HandleUpdate обрабатывает обновление от Telegram
@@ -90,16 +118,30 @@ func (h *BotHandler) handleMessage(message *tgbotapi.Message) {
 
 	// Сохраняем пользователя в базу данных, если это новый пользователь
 	user := &models.User{
-		TelegramID: userID,
-		Username:   message.From.UserName,
-		FirstName:  message.From.FirstName,
-		LastName:   message.From.LastName,
-		IsAdmin:    h.IsAdmin(userID),
+		TelegramID:   userID,
+		Username:     message.From.UserName,
+		FirstName:    message.From.FirstName,
+		LastName:     message.From.LastName,
+		LanguageCode: message.From.LanguageCode,
+	}
+
+	// Пользователи из admin_ids конфигурации при первом обращении становятся владельцами
+	if h.isBootstrapOwner(userID) {
+		user.Role = roles.Owner
 	}
 
 	err := h.db.AddUser(user)
 	if err != nil {
 		log.Printf("Error adding user to database: %v", err)
+	} else {
+		// Язык Telegram мог измениться с прошлого сообщения
+		h.setLanguage(userID, userLang(user))
+	}
+
+	// В группе поддержки обрабатываются только ответы сотрудников на обращения
+	if h.isSupportGroup(message.Chat.ID) {
+		h.handleSupportReply(message)
+		return
 	}
 
 	// Обрабатываем команды
@@ -130,7 +172,7 @@ func (h *BotHandler) handleCommand(message *tgbotapi.Message) {
 		if isAdmin {
 			h.showAdminMenu(chatID)
 		} else {
-			h.sendMessage(chatID, "У вас нет прав администратора.")
+			h.sendMessage(chatID, h.t(chatID, "common.no_admin_rights"))
 		}
 
 	case "my":
@@ -139,16 +181,33 @@ func (h *BotHandler) handleCommand(message *tgbotapi.Message) {
 	case "buy":
 		h.handleBuyCommand(message)
 
+	case "trial":
+		h.handleTrialActivation(chatID, userID)
+
+	case "redeem":
+		h.handleGiftRedeem(chatID, userID, message.CommandArguments())
+
+	case "gifts":
+		h.handleMyGiftsCommand(chatID, userID)
+
+	case "balance":
+		h.handleBalanceCommand(chatID, userID)
+
+	case "language":
+		h.handleLanguageCommand(chatID)
+
+	case "cancel":
+		h.handleCancelCommand(chatID)
+
 	default:
-		h.sendMessage(chatID, "Неизвестная команда. Используйте /help для получения списка команд.")
+		h.sendMessage(chatID, h.t(chatID, "common.unknown_command"))
 	}
 }
 
 // handleStateBasedInput обрабатывает ввод на основе текущего состояния пользователя
 func (h *BotHandler) handleStateBasedInput(message *tgbotapi.Message) {
-	userID := message.From.ID
 	chatID := message.Chat.ID
-	userState, exists := h.userStates[userID]
+	userState, exists := h.getState(chatID)
 
 	// Проверяем, есть ли у сообщения текст для обработки
 	if message.Text != "" {
@@ -158,6 +217,11 @@ func (h *BotHandler) handleStateBasedInput(message *tgbotapi.Message) {
 		}
 	}
 
+	// Ответ на сообщение обращения в поддержку попадает в обращение независимо от состояния
+	if h.handleSupportReply(message) {
+		return
+	}
+
 	// Если у пользователя нет активного состояния, выходим
 	if !exists {
 		return
@@ -167,13 +231,13 @@ func (h *BotHandler) handleStateBasedInput(message *tgbotapi.Message) {
 	case "add_server_ip":
 		userState.Data["ip"] = message.Text
 		userState.State = "add_server_port"
-		h.userStates[userID] = userState
-		h.sendMessage(chatID, "Введите порт SSH:")
+		h.setState(chatID, userState)
+		h.sendMessage(chatID, h.t(chatID, "admin_servers.enter_port"))
 
 	case "add_server_port":
 		_, err := strconv.Atoi(message.Text) // Используем _ вместо port, но проверяем валидность
 		if err != nil {
-			h.sendMessage(chatID, "Пожалуйста, введите корректный порт (число):")
+			h.sendMessage(chatID, h.t(chatID, "admin_servers.invalid_port"))
 			return
 		}
 
@@ -181,26 +245,26 @@ func (h *BotHandler) handleStateBasedInput(message *tgbotapi.Message) {
 		userState.Data["port"] = message.Text
 
 		// Переходим к следующему шагу
-		h.sendMessage(chatID, "Введите имя пользователя SSH:")
+		h.sendMessage(chatID, h.t(chatID, "admin_servers.enter_user"))
 		userState.State = "add_server_username"
-		h.userStates[userID] = userState
+		h.setState(chatID, userState)
 
 	case "add_server_username":
 		userState.Data["username"] = message.Text
 		userState.State = "add_server_password"
-		h.userStates[userID] = userState
-		h.sendMessage(chatID, "Введите пароль SSH:")
+		h.setState(chatID, userState)
+		h.sendMessage(chatID, h.t(chatID, "admin_servers.enter_password"))
 
 	case "add_server_password":
 		userState.Data["password"] = message.Text
 		userState.State = "add_server_max_clients"
-		h.userStates[userID] = userState
-		h.sendMessage(chatID, "Введите максимальное количество клиентов для сервера:")
+		h.setState(chatID, userState)
+		h.sendMessage(chatID, h.t(chatID, "admin_servers.enter_max_clients"))
 
 	case "add_server_max_clients":
 		maxClients, err := strconv.Atoi(message.Text)
 		if err != nil {
-			h.sendMessage(chatID, "Пожалуйста, введите корректное число клиентов:")
+			h.sendMessage(chatID, h.t(chatID, "admin_servers.invalid_max_clients"))
 			return
 		}
 
@@ -216,79 +280,117 @@ func (h *BotHandler) handleStateBasedInput(message *tgbotapi.Message) {
 		}
 
 		// Предварительная настройка сервера
-		h.sendMessage(chatID, "Настраиваю сервер, это может занять некоторое время...")
+		h.sendMessage(chatID, h.t(chatID, "admin_servers.setting_up"))
 
 		err = h.vpnManager.SetupServer(server)
 		if err != nil {
-			h.sendMessage(chatID, fmt.Sprintf("Ошибка при настройке сервера: %v", err))
-			delete(h.userStates, userID)
+			h.sendMessage(chatID, h.t(chatID, "admin_servers.setup_error", err))
+			h.clearState(chatID)
 			return
 		}
 
 		err = h.db.AddServer(server)
 		if err != nil {
-			h.sendMessage(chatID, fmt.Sprintf("Ошибка при добавлении сервера в базу данных: %v", err))
-			delete(h.userStates, userID)
+			h.sendMessage(chatID, h.t(chatID, "admin_servers.add_error", err))
+			h.clearState(chatID)
 			return
 		}
 
-		h.sendMessage(chatID, fmt.Sprintf("Сервер успешно добавлен с ID: %d", server.ID))
-		delete(h.userStates, userID)
+		h.audit(message.From.ID, models.AuditServerAdd, models.AuditTargetServer, server.ID, nil, server)
+		h.sendMessage(chatID, h.t(chatID, "admin_servers.added", server.ID))
+		h.clearState(chatID)
+
+	// Ручное изменение баланса пользователя
+	case "adjust_balance_amount", "adjust_balance_reason":
+		h.handleBalanceAdjustmentInput(message, userState)
+
+	// Изменение параметра сервера
+	case "edit_server_value":
+		h.handleServerEditInput(message, userState)
+
+	// Поиск пользователя в панели управления
+	case "user_search":
+		h.handleUserSearchInput(message)
+
+	// Обращение в поддержку
+	case "support_message":
+		h.handleSupportMessageInput(message, userState)
+
+	// Составление рассылки
+	case "broadcast_message":
+		h.handleBroadcastMessageInput(message, userState)
+
+	case "broadcast_buttons":
+		h.handleBroadcastButtonsInput(message, userState)
+
+	case "broadcast_days":
+		h.handleBroadcastDaysInput(message, userState)
+
+	// Изменение лимитов плана в днях
+	case "edit_plan_limit":
+		h.handlePlanLimitInput(message, userState)
+
+	// Изменение цены плана в дополнительной валюте
+	case "edit_plan_price_point":
+		h.handlePlanPriceInput(message, userState)
 
 	// Другие состояния для обработки
 	case "add_plan_name":
 		userState.Data["name"] = message.Text
 		userState.State = "add_plan_description"
-		h.userStates[userID] = userState
-		h.sendMessage(chatID, "Введите описание плана подписки:")
+		h.setState(chatID, userState)
+		h.sendMessage(chatID, h.t(chatID, "admin_plans.enter_description"))
 
 	case "add_plan_description":
 		userState.Data["description"] = message.Text
+		userState.Data["currency"] = h.invoiceCurrency() // Основная цена плана задается в валюте счетов
 		userState.State = "add_plan_price"
-		h.userStates[userID] = userState
-		h.sendMessage(chatID, "Введите цену плана подписки:")
+		h.setState(chatID, userState)
+		h.sendMessage(chatID, h.t(chatID, "admin_plans.enter_price", userState.Data["currency"]))
 
 	case "add_plan_price":
-		_, err := strconv.ParseFloat(message.Text, 64) // Используем _ вместо price, но проверяем валидность
-		if err != nil {
-			h.sendMessage(chatID, "Пожалуйста, введите корректную цену:")
+		price, err := money.Parse(message.Text, userState.Data["currency"])
+		if err != nil || price < 0 {
+			h.sendMessage(chatID, h.t(chatID, "admin_plans.invalid_price"))
 			return
 		}
 
-		// Сохраняем цену в данных состояния
-		userState.Data["price"] = message.Text
+		// Сохраняем цену в минимальных единицах валюты в данных состояния
+		userState.Data["price"] = strconv.FormatInt(price, 10)
 
 		// Переходим к следующему шагу
-		h.sendMessage(chatID, "Введите длительность плана в днях:")
+		h.sendMessage(chatID, h.t(chatID, "admin_plans.enter_duration"))
 		userState.State = "add_plan_duration"
-		h.userStates[userID] = userState
+		h.setState(chatID, userState)
 
 	case "add_plan_duration":
 		duration, err := strconv.Atoi(message.Text)
 		if err != nil {
-			h.sendMessage(chatID, "Пожалуйста, введите корректную длительность (число дней):")
+			h.sendMessage(chatID, h.t(chatID, "admin_plans.invalid_duration"))
 			return
 		}
 
 		// Добавляем план подписки в базу данных
-		priceValue, _ := strconv.ParseFloat(userState.Data["price"], 64)
+		priceValue, _ := strconv.ParseInt(userState.Data["price"], 10, 64)
 		plan := &models.SubscriptionPlan{
 			Name:        userState.Data["name"],
 			Description: userState.Data["description"],
 			Price:       priceValue,
+			Currency:    userState.Data["currency"],
 			Duration:    duration,
 			IsActive:    true,
 		}
 
 		err = h.db.AddSubscriptionPlan(plan)
 		if err != nil {
-			h.sendMessage(chatID, fmt.Sprintf("Ошибка при добавлении плана подписки: %v", err))
-			delete(h.userStates, userID)
+			h.sendMessage(chatID, h.t(chatID, "admin_plans.add_error", err))
+			h.clearState(chatID)
 			return
 		}
 
-		h.sendMessage(chatID, fmt.Sprintf("План подписки успешно добавлен: %s", plan.Name))
-		delete(h.userStates, userID)
+		h.audit(message.From.ID, models.AuditPlanAdd, models.AuditTargetPlan, plan.ID, nil, plan)
+		h.sendMessage(chatID, h.t(chatID, "admin_plans.added", plan.Name))
+		h.clearState(chatID)
 
 		// Возвращаемся к списку планов
 		h.listSubscriptionPlans(chatID)
@@ -301,8 +403,8 @@ func (h *BotHandler) handleStateBasedInput(message *tgbotapi.Message) {
 			userState.Data["new_name"] = userState.Data["name"]
 		}
 		userState.State = "edit_plan_description"
-		h.userStates[userID] = userState
-		h.sendMessage(chatID, fmt.Sprintf("Введите новое описание плана (или отправьте точку '.' чтобы оставить текущее описание: \n\n%s)", userState.Data["description"]))
+		h.setState(chatID, userState)
+		h.sendMessage(chatID, h.t(chatID, "admin_plans.edit_description", userState.Data["description"]))
 
 	case "edit_plan_description":
 		if message.Text != "." {
@@ -311,26 +413,24 @@ func (h *BotHandler) handleStateBasedInput(message *tgbotapi.Message) {
 			userState.Data["new_description"] = userState.Data["description"]
 		}
 		userState.State = "edit_plan_price"
-		h.userStates[userID] = userState
-		h.sendMessage(chatID, fmt.Sprintf("Введите новую цену плана (или отправьте точку '.' чтобы оставить текущую цену: %s руб.):", userState.Data["price"]))
+		h.setState(chatID, userState)
+		h.sendMessage(chatID, h.t(chatID, "admin_plans.edit_price", userState.Data["currency"], userState.Data["price"]))
 
 	case "edit_plan_price":
-		var err error
-
 		if message.Text != "." {
-			_, err = strconv.ParseFloat(message.Text, 64)
-			if err != nil {
-				h.sendMessage(chatID, "Пожалуйста, введите корректную цену (число с точкой):")
+			price, err := money.Parse(message.Text, userState.Data["currency"])
+			if err != nil || price < 0 {
+				h.sendMessage(chatID, h.t(chatID, "admin_plans.invalid_price"))
 				return
 			}
-			userState.Data["new_price"] = message.Text
+			userState.Data["new_price"] = money.Decimal(price, userState.Data["currency"])
 		} else {
 			userState.Data["new_price"] = userState.Data["price"]
 		}
 
 		userState.State = "edit_plan_duration"
-		h.userStates[userID] = userState
-		h.sendMessage(chatID, fmt.Sprintf("Введите новую длительность плана в днях (или отправьте точку '.' чтобы оставить текущую длительность: %s дней):", userState.Data["duration"]))
+		h.setState(chatID, userState)
+		h.sendMessage(chatID, h.t(chatID, "admin_plans.edit_duration", userState.Data["duration"]))
 
 	case "edit_plan_duration":
 		var err error
@@ -338,7 +438,7 @@ func (h *BotHandler) handleStateBasedInput(message *tgbotapi.Message) {
 		if message.Text != "." {
 			_, err = strconv.Atoi(message.Text)
 			if err != nil {
-				h.sendMessage(chatID, "Пожалуйста, введите корректную длительность (целое число дней):")
+				h.sendMessage(chatID, h.t(chatID, "admin_plans.invalid_duration"))
 				return
 			}
 			userState.Data["new_duration"] = message.Text
@@ -348,29 +448,29 @@ func (h *BotHandler) handleStateBasedInput(message *tgbotapi.Message) {
 
 		// Переходим к выбору статуса активности
 		userState.State = "edit_plan_status"
-		h.userStates[userID] = userState
+		h.setState(chatID, userState)
 
 		// Создаем клавиатуру для выбора статуса
 		keyboard := tgbotapi.NewInlineKeyboardMarkup(
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("🟢 Активен", "plan_status:active"),
-				tgbotapi.NewInlineKeyboardButtonData("🔴 Неактивен", "plan_status:inactive"),
+				h.button(chatID, h.t(chatID, "admin.status_active"), "plan_status:active"),
+				h.button(chatID, h.t(chatID, "admin.status_inactive"), "plan_status:inactive"),
 			),
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("Оставить текущий статус", "plan_status:current"),
+				h.button(chatID, h.t(chatID, "admin_plans.keep_status"), "plan_status:current"),
 			),
 		)
 
-		statusMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Выберите статус плана (текущий статус: %s):",
-			getStatusText(userState.Data["is_active"] == "true")))
+		statusMsg := tgbotapi.NewMessage(chatID, h.t(chatID, "admin_plans.choose_status",
+			h.activeText(chatID, userState.Data["is_active"] == "true")))
 		statusMsg.ReplyMarkup = keyboard
-		h.bot.Send(statusMsg)
+		h.sender.Send(statusMsg)
 
 	case "edit_plan_status":
 		// Обработка выбора статуса в handleCallbackQuery
 		// Обновляем план подписки в базе данных
 		planID, _ := strconv.Atoi(userState.Data["plan_id"])
-		newPrice, _ := strconv.ParseFloat(userState.Data["new_price"], 64)
+		newPrice, _ := money.Parse(userState.Data["new_price"], userState.Data["currency"])
 		newDuration, _ := strconv.Atoi(userState.Data["new_duration"])
 		isActive := userState.Data["new_is_active"] == "true"
 
@@ -379,144 +479,73 @@ func (h *BotHandler) handleStateBasedInput(message *tgbotapi.Message) {
 			Name:        userState.Data["new_name"],
 			Description: userState.Data["new_description"],
 			Price:       newPrice,
+			Currency:    userState.Data["currency"],
 			Duration:    newDuration,
 			IsActive:    isActive,
 		}
 
-		err := h.db.UpdateSubscriptionPlan(plan)
+		before, err := h.db.GetSubscriptionPlanByID(planID)
 		if err != nil {
-			h.sendMessage(chatID, fmt.Sprintf("Ошибка при обновлении плана подписки: %v", err))
-			delete(h.userStates, userID)
+			h.sendMessage(chatID, h.t(chatID, "common.plan_error", err))
+			h.clearState(chatID)
 			return
 		}
 
-		h.sendMessage(chatID, fmt.Sprintf("✅ План подписки успешно обновлен: %s", plan.Name))
-		delete(h.userStates, userID)
+		err = h.db.UpdateSubscriptionPlan(plan)
+		if err != nil {
+			h.sendMessage(chatID, h.t(chatID, "admin_plans.update_error", err))
+			h.clearState(chatID)
+			return
+		}
+
+		if after, err := h.db.GetSubscriptionPlanByID(planID); err == nil {
+			h.audit(message.From.ID, models.AuditPlanEdit, models.AuditTargetPlan, planID, before, after)
+		}
+
+		h.sendMessage(chatID, h.t(chatID, "admin_plans.updated", plan.Name))
+		h.clearState(chatID)
 
 		// Отображаем обновленный план
 		h.viewPlanDetails(chatID, planID)
 
 	default:
 		// Неизвестное состояние
-		delete(h.userStates, userID)
+		h.clearState(chatID)
 	}
 }
 
-// handleCallbackQuery обрабатывает нажатия на инлайн-кнопки
-func (h *BotHandler) handleCallbackQuery(query *tgbotapi.CallbackQuery) {
+// handlePlanStatusSelect обрабатывает выбор статуса плана при редактировании и сохраняет план
+func (h *BotHandler) handlePlanStatusSelect(query *tgbotapi.CallbackQuery, status string) {
 	chatID := query.Message.Chat.ID
-	data := query.Data
-
-	log.Printf("Получен callback: data=%s, от пользователя ID=%d", data, query.From.ID)
-
-	// Отвечаем на запрос обратного вызова
-	h.bot.Request(tgbotapi.NewCallback(query.ID, ""))
-
-	parts := strings.Split(data, ":")
-	if len(parts) < 2 {
-		log.Printf("Некорректный формат колбэка: %s (недостаточно частей)", data)
+	userState, ok := h.getState(chatID)
+	if !ok || userState.State != "edit_plan_status" {
 		return
 	}
 
-	action := parts[0]
-	log.Printf("Обработка действия: %s, parts=%v", action, parts)
-
-	switch action {
-	case "admin_menu":
-		h.handleAdminMenuSelection(chatID, parts[1])
-
-	case "server_action":
-		if len(parts) < 3 {
-			return
-		}
-		serverID, _ := strconv.Atoi(parts[2])
-		h.handleServerAction(chatID, parts[1], serverID)
-
-	case "plan_action":
-		if len(parts) < 3 {
-			return
-		}
-		planID, _ := strconv.Atoi(parts[2])
-		h.handlePlanAction(chatID, parts[1], planID)
-
-	case "user_action":
-		if len(parts) < 3 {
-			log.Printf("Некорректный формат для user_action: %s (необходимо 3 части)", data)
-			return
-		}
-		userID, err := strconv.Atoi(parts[2])
-		if err != nil {
-			log.Printf("Ошибка преобразования ID пользователя: %v", err)
-			return
-		}
-		log.Printf("Вызов handleUserAction с параметрами: action=%s, userID=%d", parts[1], userID)
-		h.handleUserAction(chatID, parts[1], userID)
-
-	case "stats_action":
-		if len(parts) < 3 {
-			return
-		}
-		param, _ := strconv.Atoi(parts[2])
-		h.handleStatsAction(chatID, parts[1], param)
-
-	case "subscription_action":
-		if len(parts) < 3 {
-			return
-		}
-		subscriptionID, _ := strconv.Atoi(parts[2])
-		h.handleSubscriptionAction(chatID, parts[1], subscriptionID)
-
-	case "buy_plan":
-		planID, _ := strconv.Atoi(parts[1])
-		userID := query.From.ID
-		h.handleBuyPlan(chatID, userID, planID)
-
-	case "show_buy_plans":
-		h.listAvailableSubscriptionPlans(chatID)
-
-	case "server_confirm_delete":
-		if len(parts) < 2 {
-			log.Printf("Некорректный формат для server_confirm_delete: %s (необходимо 2 части)", data)
-			return
-		}
-		serverID, _ := strconv.Atoi(parts[1])
-		h.handleServerConfirmDelete(chatID, serverID)
-
-		if strings.HasPrefix(data, "plan_status:") {
-			// Обработка выбора статуса плана при редактировании
-			status := strings.TrimPrefix(data, "plan_status:")
-			userID := query.From.ID
-			if userState, ok := h.userStates[userID]; ok && userState.State == "edit_plan_status" {
-				switch status {
-				case "active":
-					userState.Data["new_is_active"] = "true"
-				case "inactive":
-					userState.Data["new_is_active"] = "false"
-				case "current":
-					userState.Data["new_is_active"] = userState.Data["is_active"]
-				}
-				h.userStates[userID] = userState
-
-				// Отправляем подтверждение выбора
-				editMsg := tgbotapi.NewEditMessageText(
-					query.Message.Chat.ID,
-					query.Message.MessageID,
-					fmt.Sprintf("Статус плана: %s\n\nСохраняю изменения...",
-						getStatusText(userState.Data["new_is_active"] == "true")),
-				)
-				h.bot.Send(editMsg)
+	switch status {
+	case "active":
+		userState.Data["new_is_active"] = "true"
+	case "inactive":
+		userState.Data["new_is_active"] = "false"
+	case "current":
+		userState.Data["new_is_active"] = userState.Data["is_active"]
+	}
+	h.setState(chatID, userState)
 
-				// Моделируем получение сообщения для обработки в edit_plan_status
-				msg := tgbotapi.Message{
-					From: &tgbotapi.User{ID: userID},
-					Chat: &tgbotapi.Chat{ID: query.Message.Chat.ID},
-				}
-				h.handleStateBasedInput(&msg)
+	// Отправляем подтверждение выбора
+	editMsg := tgbotapi.NewEditMessageText(
+		query.Message.Chat.ID,
+		query.Message.MessageID,
+		h.t(chatID, "admin_plans.saving", h.activeText(chatID, userState.Data["new_is_active"] == "true")),
+	)
+	h.sender.Send(editMsg)
 
-				return
-			}
-		}
+	// Моделируем получение сообщения для обработки в edit_plan_status
+	msg := tgbotapi.Message{
+		From: query.From,
+		Chat: query.Message.Chat,
 	}
+	h.handleStateBasedInput(&msg)
 }
 
 // handlePreCheckoutQuery обрабатывает запросы на оплату
@@ -527,7 +556,7 @@ func (h *BotHandler) handlePreCheckoutQuery(query *tgbotapi.PreCheckoutQuery) {
 		OK:                 true,
 		ErrorMessage:       "",
 	}
-	h.bot.Request(config)
+	h.sender.Request(config)
 }
 
 // Обработчики конкретных команд
@@ -537,15 +566,16 @@ func (h *BotHandler) handleStartCommand(message *tgbotapi.Message) {
 	chatID := message.Chat.ID
 	userID := message.From.ID
 
-	welcomeText := `
-🔒 *Добро пожаловать в VPN бот!*
+	// Переход по ссылке на подарок: /start gift_<код>
+	if args := message.CommandArguments(); strings.HasPrefix(args, giftDeepLinkPrefix) {
+		h.handleGiftRedeem(chatID, userID, strings.TrimPrefix(args, giftDeepLinkPrefix))
+		return
+	}
 
-Этот бот поможет вам приобрести и управлять подписками на VPN-сервис.
-Используйте кнопки меню для быстрого доступа к функциям.
-`
+	welcomeText := h.t(chatID, "start.welcome")
 
 	if h.IsAdmin(userID) {
-		welcomeText += "\nУ вас есть права администратора!"
+		welcomeText += h.t(chatID, "start.admin")
 	}
 
 	h.sendMainMenu(chatID, welcomeText, userID)
@@ -553,22 +583,25 @@ func (h *BotHandler) handleStartCommand(message *tgbotapi.Message) {
 
 // sendMainMenu отправляет пользователю главное меню с кнопками
 func (h *BotHandler) sendMainMenu(chatID int64, text string, userID int64) {
+	lang := h.lang(userID)
+
 	// Создаем красивую клавиатуру с основными функциями
 	keyboard := tgbotapi.NewReplyKeyboard(
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("💰 Купить подписку"),
-			tgbotapi.NewKeyboardButton("🔑 Мои подписки"),
+			tgbotapi.NewKeyboardButton(i18n.T(lang, "menu.buy")),
+			tgbotapi.NewKeyboardButton(i18n.T(lang, "menu.my")),
 		),
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("ℹ️ Помощь"),
-			tgbotapi.NewKeyboardButton("📞 Поддержка"),
+			tgbotapi.NewKeyboardButton(i18n.T(lang, "menu.balance")),
+			tgbotapi.NewKeyboardButton(i18n.T(lang, "menu.help")),
+			tgbotapi.NewKeyboardButton(i18n.T(lang, "menu.support")),
 		),
 	)
 
 	// Для администраторов добавляем отдельную кнопку
 	if h.IsAdmin(userID) {
 		keyboard.Keyboard = append(keyboard.Keyboard, tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("⚙️ Админ-панель"),
+			tgbotapi.NewKeyboardButton(i18n.T(lang, "menu.admin")),
 		))
 	}
 
@@ -580,7 +613,7 @@ func (h *BotHandler) sendMainMenu(chatID int64, text string, userID int64) {
 	msg := tgbotapi.NewMessage(chatID, text)
 	msg.ParseMode = "Markdown"
 	msg.ReplyMarkup = keyboard
-	h.bot.Send(msg)
+	h.sender.Send(msg)
 }
 
 // handleHelpCommand обрабатывает команду /help
@@ -588,33 +621,15 @@ func (h *BotHandler) handleHelpCommand(message *tgbotapi.Message) {
 	chatID := message.Chat.ID
 	userID := message.From.ID
 
-	helpText := `
-*Справка по использованию VPN-бота*
-
-*Основные кнопки меню:*
-• 💰 *Купить подписку* - просмотр и покупка доступных тарифных планов
-• 🔑 *Мои подписки* - управление вашими активными подписками
-• ℹ️ *Помощь* - получение этой справки
-• 📞 *Поддержка* - связь с командой поддержки
-
-*Доступные команды:*
-• /start - отобразить главное меню бота
-• /help - показать эту справку
-• /buy - купить подписку на VPN
-• /my - просмотреть ваши активные подписки
-`
+	helpText := h.t(chatID, "help.text")
 
 	if h.IsAdmin(userID) {
-		helpText += `
-*Команды администратора:*
-• ⚙️ *Админ-панель* - меню управления ботом
-• /admin - открыть панель администратора
-`
+		helpText += h.t(chatID, "help.admin")
 	}
 
 	msg := tgbotapi.NewMessage(chatID, helpText)
 	msg.ParseMode = "Markdown"
-	h.bot.Send(msg)
+	h.sender.Send(msg)
 }
 
 // handleMySubscriptionsCommand обрабатывает команду /my
@@ -628,7 +643,7 @@ func (h *BotHandler) handleMySubscriptionsCommand(message *tgbotapi.Message) {
 	user, err := h.db.GetUserByTelegramID(userID)
 	if err != nil {
 		log.Printf("Ошибка при получении пользователя по TelegramID %d: %v", userID, err)
-		h.sendMessage(chatID, "❌ Ошибка при получении информации о пользователе. Пожалуйста, попробуйте позже.")
+		h.sendMessage(chatID, "❌ "+h.t(chatID, "common.user_error"))
 		return
 	}
 
@@ -638,7 +653,7 @@ func (h *BotHandler) handleMySubscriptionsCommand(message *tgbotapi.Message) {
 	subscriptions, err := h.db.GetSubscriptionsByUserID(user.ID)
 	if err != nil {
 		log.Printf("Ошибка при получении подписок для пользователя ID=%d: %v", user.ID, err)
-		h.sendMessage(chatID, "❌ Ошибка при получении информации о подписках. Пожалуйста, попробуйте позже.")
+		h.sendMessage(chatID, h.t(chatID, "my.subscriptions_error"))
 		return
 	}
 
@@ -646,35 +661,28 @@ func (h *BotHandler) handleMySubscriptionsCommand(message *tgbotapi.Message) {
 
 	if len(subscriptions) == 0 {
 		// Отправляем красивое сообщение с предложением купить подписку
-		noSubsMsg := `
-*У вас пока нет активных подписок* 🔎
-
-Чтобы начать пользоваться VPN-сервисом:
-1️⃣ Нажмите на кнопку *"💰 Купить подписку"*
-2️⃣ Выберите подходящий тарифный план
-3️⃣ Оплатите подписку через Telegram
-4️⃣ Получите доступ к VPN мгновенно!
-`
+		noSubsMsg := h.t(chatID, "my.empty")
 		msg := tgbotapi.NewMessage(chatID, noSubsMsg)
 		msg.ParseMode = "Markdown"
 
 		// Добавляем кнопку для быстрого перехода к покупке
 		keyboard := tgbotapi.NewInlineKeyboardMarkup(
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("💰 Выбрать план подписки", "show_buy_plans"),
+				h.button(chatID, h.t(chatID, "button.choose_plan"), "show_buy_plans"),
 			),
 		)
 		msg.ReplyMarkup = keyboard
-		h.bot.Send(msg)
+		h.sender.Send(msg)
 		return
 	}
 
 	// Заголовок списка подписок
-	headerMsg := fmt.Sprintf("*🔑 Ваши VPN-подписки (%d)*\n", len(subscriptions))
+	headerMsg := h.t(chatID, "my.header", len(subscriptions))
 	h.sendMessage(chatID, headerMsg)
 
 	// Определяем, является ли пользователь администратором
 	isAdmin := h.IsAdmin(userID)
+	lang := h.lang(chatID)
 
 	// Для каждой подписки формируем отдельную карточку
 	for _, subscription := range subscriptions {
@@ -690,26 +698,29 @@ func (h *BotHandler) handleMySubscriptionsCommand(message *tgbotapi.Message) {
 		if err != nil {
 			// Если не удалось получить план, используем значение по умолчанию
 			log.Printf("Ошибка при получении плана подписки ID=%d: %v", subscription.PlanID, err)
-			plan = &models.SubscriptionPlan{Name: "План подписки"}
+			plan = &models.SubscriptionPlan{Name: i18n.T(lang, "my.default_plan")}
 		} else {
 			log.Printf("Успешно получен план ID=%d: %s", plan.ID, plan.Name)
 		}
 
 		// Выбираем эмодзи в зависимости от статуса
-		var statusEmoji, statusText string
+		var statusEmoji string
+		statusText := i18n.T(lang, "status."+subscription.Status)
 		switch subscription.Status {
+		case "pending":
+			statusEmoji = "⏳"
 		case "active":
 			statusEmoji = "✅"
-			statusText = "Активна"
 		case "blocked":
 			statusEmoji = "🔒"
-			statusText = "Заблокирована"
+		case "paused":
+			statusEmoji = "⏸️"
+		case "grace":
+			statusEmoji = "⌛"
 		case "expired":
 			statusEmoji = "⏱️"
-			statusText = "Истекла"
 		case "revoked":
 			statusEmoji = "❌"
-			statusText = "Отозвана"
 		default:
 			statusEmoji = "❓"
 			statusText = subscription.Status
@@ -718,21 +729,22 @@ func (h *BotHandler) handleMySubscriptionsCommand(message *tgbotapi.Message) {
 		// Вычисляем дни до истечения подписки
 		daysLeft := int(subscription.EndDate.Sub(time.Now()).Hours() / 24)
 		var daysLeftText string
-		if daysLeft > 0 {
-			daysLeftText = fmt.Sprintf("🗓️ *Осталось дней:* %d\n", daysLeft)
+		if subscription.Status == "paused" && subscription.PausedAt != nil {
+			// На паузе срок не расходуется, показываем остаток на момент приостановки
+			daysLeft = int(subscription.EndDate.Sub(*subscription.PausedAt).Hours() / 24)
+			daysLeftText = i18n.T(lang, "my.days_paused",
+				daysLeft, subscription.PausedAt.Format("02.01.2006"))
+		} else if subscription.Status == "grace" {
+			daysLeftText = i18n.T(lang, "my.renew_until",
+				subscription.EndDate.AddDate(0, 0, plan.GraceDays).Format("02.01.2006"))
+		} else if daysLeft > 0 {
+			daysLeftText = i18n.T(lang, "my.days_left", daysLeft)
 		} else {
-			daysLeftText = "🗓️ *Статус:* Просрочена\n"
+			daysLeftText = i18n.T(lang, "my.overdue")
 		}
 
 		// Формируем красивое сообщение о подписке
-		infoMsg := fmt.Sprintf(
-			"*VPN-подписка #%d*\n\n"+
-				"%s *Статус:* %s\n"+
-				"📋 *План:* %s\n"+
-				"🌐 *Сервер:* %s\n"+
-				"📅 *Действует до:* %s\n"+
-				"%s"+
-				"📊 *Использовано данных:* %s\n",
+		infoMsg := i18n.T(lang, "my.card",
 			subscription.ID,
 			statusEmoji, statusText,
 			plan.Name,
@@ -744,7 +756,7 @@ func (h *BotHandler) handleMySubscriptionsCommand(message *tgbotapi.Message) {
 
 		// Если есть последнее подключение, добавляем эту информацию
 		if subscription.LastConnectionAt != nil && !subscription.LastConnectionAt.IsZero() {
-			infoMsg += fmt.Sprintf("🔄 *Последнее подключение:* %s\n",
+			infoMsg += i18n.T(lang, "my.last_connection",
 				subscription.LastConnectionAt.Format("02.01.2006 15:04"))
 		}
 
@@ -753,11 +765,36 @@ func (h *BotHandler) handleMySubscriptionsCommand(message *tgbotapi.Message) {
 
 		// Основные кнопки для всех пользователей
 		row := tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("📄 Конфигурация", fmt.Sprintf("subscription_action:config:%d", subscription.ID)),
-			tgbotapi.NewInlineKeyboardButtonData("📊 Статистика", fmt.Sprintf("subscription_action:stats:%d", subscription.ID)),
+			h.button(chatID, i18n.T(lang, "button.config"), fmt.Sprintf("subscription_action:config:%d", subscription.ID)),
+			h.button(chatID, i18n.T(lang, "button.stats"), fmt.Sprintf("subscription_action:stats:%d", subscription.ID)),
 		)
 		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, row)
 
+		// Активную подписку можно перевести на другой план или приостановить
+		if subscription.Status == "active" {
+			manageRow := tgbotapi.NewInlineKeyboardRow(
+				h.button(chatID, i18n.T(lang, "button.change_plan"), fmt.Sprintf("change_plan:%d", subscription.ID)),
+			)
+			if !subscription.IsTrial && plan.MaxPauseDays > 0 {
+				manageRow = append(manageRow,
+					h.button(chatID, i18n.T(lang, "button.pause"), fmt.Sprintf("pause_subscription:%d", subscription.ID)))
+			}
+			keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, manageRow)
+			if !subscription.IsTrial {
+				keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+					h.button(chatID, i18n.T(lang, "button.renew"), fmt.Sprintf("renew:%d", subscription.ID)),
+				))
+			}
+		} else if subscription.Status == "grace" {
+			keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+				h.button(chatID, i18n.T(lang, "button.renew"), fmt.Sprintf("renew:%d", subscription.ID)),
+			))
+		} else if subscription.Status == "paused" {
+			keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+				h.button(chatID, i18n.T(lang, "button.resume"), fmt.Sprintf("resume_subscription:%d", subscription.ID)),
+			))
+		}
+
 		// Если пользователь администратор, добавляем кнопки управления
 		if isAdmin {
 			var adminRow []tgbotapi.InlineKeyboardButton
@@ -765,11 +802,11 @@ func (h *BotHandler) handleMySubscriptionsCommand(message *tgbotapi.Message) {
 			// Проверяем текущий статус подписки
 			if subscription.Status == "blocked" {
 				adminRow = tgbotapi.NewInlineKeyboardRow(
-					tgbotapi.NewInlineKeyboardButtonData("🔓 Разблокировать", fmt.Sprintf("subscription_action:unblock:%d", subscription.ID)),
+					h.button(chatID, i18n.T(lang, "button.unblock"), fmt.Sprintf("subscription_action:unblock:%d", subscription.ID)),
 				)
 			} else {
 				adminRow = tgbotapi.NewInlineKeyboardRow(
-					tgbotapi.NewInlineKeyboardButtonData("🔒 Блокировать", fmt.Sprintf("subscription_action:block:%d", subscription.ID)),
+					h.button(chatID, i18n.T(lang, "button.block"), fmt.Sprintf("subscription_action:block:%d", subscription.ID)),
 				)
 			}
 			keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, adminRow)
@@ -778,22 +815,22 @@ func (h *BotHandler) handleMySubscriptionsCommand(message *tgbotapi.Message) {
 		msg := tgbotapi.NewMessage(chatID, infoMsg)
 		msg.ParseMode = "Markdown"
 		msg.ReplyMarkup = keyboard
-		h.bot.Send(msg)
+		h.sender.Send(msg)
 	}
 
 	// Добавляем кнопку для покупки новой подписки после списка
 	if len(subscriptions) > 0 {
-		buyMoreMsg := "*Хотите добавить еще одну подписку?*"
+		buyMoreMsg := i18n.T(lang, "my.buy_more")
 		msg := tgbotapi.NewMessage(chatID, buyMoreMsg)
 		msg.ParseMode = "Markdown"
 
 		keyboard := tgbotapi.NewInlineKeyboardMarkup(
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("💰 Купить еще подписку", "show_buy_plans"),
+				h.button(chatID, i18n.T(lang, "button.buy_more"), "show_buy_plans"),
 			),
 		)
 		msg.ReplyMarkup = keyboard
-		h.bot.Send(msg)
+		h.sender.Send(msg)
 	}
 }
 
@@ -805,27 +842,33 @@ func (h *BotHandler) handleBuyCommand(message *tgbotapi.Message) {
 
 // showStatsMenu отображает меню статистики
 func (h *BotHandler) showStatsMenu(chatID int64) {
-	text := "Меню статистики. Выберите действие:"
+	text := h.t(chatID, "admin_stats.menu")
 
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("Общая статистика", "stats_action:overview:0"),
+			h.button(chatID, h.t(chatID, "admin_stats.overview"), "stats_action:overview:0"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "admin_stats.revenue"), "stats_action:revenue:0"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "admin_stats.servers"), "stats_action:servers:0"),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("Статистика доходов", "stats_action:revenue:0"),
+			h.button(chatID, h.t(chatID, "admin_stats.trial"), "stats_action:trial:0"),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("Статистика серверов", "stats_action:servers:0"),
+			h.button(chatID, h.t(chatID, "admin_stats.cohorts"), "stats_action:cohorts:0"),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("Назад", "admin_menu:main"),
+			h.button(chatID, h.t(chatID, "admin.back"), "admin_menu:main"),
 		),
 	)
 
 	msg := tgbotapi.NewMessage(chatID, text)
 	msg.ReplyMarkup = keyboard
 
-	h.bot.Send(msg)
+	h.sender.Send(msg)
 }
 
 // handleStatsAction обрабатывает действия в меню статистики
@@ -835,13 +878,19 @@ func (h *BotHandler) handleStatsAction(chatID int64, action string, param int) {
 		h.showSystemStats(chatID)
 
 	case "revenue":
-		h.showRevenueStats(chatID)
+		h.showRevenueStats(chatID, param)
 
 	case "servers":
 		h.showServerStats(chatID)
 
+	case "trial":
+		h.showTrialStats(chatID)
+
+	case "cohorts":
+		h.showCohortStats(chatID)
+
 	default:
-		h.sendMessage(chatID, "Неизвестное действие. Пожалуйста, выберите действие из меню.")
+		h.sendMessage(chatID, h.t(chatID, "admin.unknown_action"))
 	}
 }
 
@@ -850,7 +899,7 @@ func (h *BotHandler) showSystemStats(chatID int64) {
 	// Получаем статистику системы
 	stats, err := h.db.GetSystemStats()
 	if err != nil {
-		h.sendMessage(chatID, fmt.Sprintf("Ошибка при получении статистики системы: %v", err))
+		h.sendMessage(chatID, h.t(chatID, "admin_stats.system_error", err))
 		return
 	}
 
@@ -860,22 +909,7 @@ func (h *BotHandler) showSystemStats(chatID int64) {
 		loadPercentage = float64(stats.TotalClients) * 100 / float64(stats.TotalCapacity)
 	}
 
-	text := fmt.Sprintf(
-		"📊 *Общая статистика системы*\n\n"+
-			"👥 *Пользователи:*\n"+
-			"- Всего пользователей: %d\n"+
-			"- Новые пользователи (7 дней): %d\n\n"+
-			"🔑 *Подписки:*\n"+
-			"- Активных подписок: %d\n"+
-			"- Новые подписки (7 дней): %d\n\n"+
-			"💰 *Доходы:*\n"+
-			"- Общий доход: %.2f руб.\n"+
-			"- Доход за 30 дней: %.2f руб.\n\n"+
-			"🖥 *Серверы:*\n"+
-			"- Активных серверов: %d\n"+
-			"- Подключено клиентов: %d\n"+
-			"- Общая вместимость: %d\n"+
-			"- Загрузка серверов: %.1f%%",
+	text := h.t(chatID, "admin_stats.system",
 		stats.TotalUsers,
 		stats.NewUsers7Days,
 		stats.ActiveSubscriptions,
@@ -891,7 +925,7 @@ func (h *BotHandler) showSystemStats(chatID int64) {
 	// Добавляем кнопку возврата
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("Назад", "admin_menu:stats"),
+			h.button(chatID, h.t(chatID, "admin.back"), "admin_menu:stats"),
 		),
 	)
 
@@ -899,14 +933,12 @@ func (h *BotHandler) showSystemStats(chatID int64) {
 	msg.ParseMode = "HTML"
 	msg.ReplyMarkup = keyboard
 
-	h.bot.Send(msg)
+	h.sender.Send(msg)
 }
 
-// showRevenueStats отображает статистику доходов
-func (h *BotHandler) showRevenueStats(chatID int64) {
-	// TODO: Реализовать более подробную статистику доходов
-	// Пока просто перенаправляем на общую статистику
-	h.showSystemStats(chatID)
+// showRevenueStats отображает статистику доходов за указанное количество дней
+func (h *BotHandler) showRevenueStats(chatID int64, days int) {
+	h.showRevenuePage(chatID, days, 0)
 }
 
 // showServerStats отображает статистику по серверам
@@ -914,16 +946,16 @@ func (h *BotHandler) showServerStats(chatID int64) {
 	// Получаем список серверов
 	servers, err := h.db.GetAllServers()
 	if err != nil {
-		h.sendMessage(chatID, fmt.Sprintf("Ошибка при получении списка серверов: %v", err))
+		h.sendMessage(chatID, h.t(chatID, "admin_servers.list_error", err))
 		return
 	}
 
 	if len(servers) == 0 {
-		h.sendMessage(chatID, "Серверы не найдены.")
+		h.sendMessage(chatID, h.t(chatID, "admin_servers.not_found"))
 		return
 	}
 
-	text := "📊 *Статистика серверов*\n\n"
+	text := h.t(chatID, "admin_stats.servers_header")
 
 	for _, server := range servers {
 		var loadPercentage float64
@@ -936,10 +968,7 @@ func (h *BotHandler) showServerStats(chatID int64) {
 			statusEmoji = "❌"
 		}
 
-		text += fmt.Sprintf(
-			"🖥 *Сервер #%d* %s\n"+
-				"- IP: `%s`\n"+
-				"- Клиенты: %d/%d (%.1f%%)\n\n",
+		text += h.t(chatID, "admin_stats.server",
 			server.ID,
 			statusEmoji,
 			server.IP,
@@ -952,7 +981,7 @@ func (h *BotHandler) showServerStats(chatID int64) {
 	// Добавляем кнопку возврата
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("Назад", "admin_menu:stats"),
+			h.button(chatID, h.t(chatID, "admin.back"), "admin_menu:stats"),
 		),
 	)
 
@@ -960,7 +989,7 @@ func (h *BotHandler) showServerStats(chatID int64) {
 	msg.ParseMode = "HTML"
 	msg.ReplyMarkup = keyboard
 
-	h.bot.Send(msg)
+	h.sender.Send(msg)
 }
 
 // handleSuccessfulPayment обрабатывает успешный платеж через Telegram Stars
@@ -971,153 +1000,149 @@ func (h *BotHandler) handleSuccessfulPayment(message *tgbotapi.Message) {
 
 	log.Printf("Получен успешный платеж от пользователя %d: %+v", userID, payment)
 
-	// Извлекаем ID плана из InvoicePayload
+	// Извлекаем тип покупки и ID плана из InvoicePayload
 	parts := strings.Split(payment.InvoicePayload, ":")
-	if len(parts) != 2 || parts[0] != "plan" {
-		h.sendMessage(chatID, "Ошибка при обработке платежа: неверный формат данных.")
-		return
-	}
 
-	planID, err := strconv.Atoi(parts[1])
-	if err != nil {
-		h.sendMessage(chatID, "Ошибка при обработке платежа: неверный ID плана.")
+	// Доплата за смену плана: upgrade:<ID подписки>:<ID плана>
+	if len(parts) == 3 && parts[0] == "upgrade" {
+		subscriptionID, subErr := strconv.Atoi(parts[1])
+		planID, planErr := strconv.Atoi(parts[2])
+		if subErr != nil || planErr != nil {
+			h.sendMessage(chatID, h.t(chatID, "payment.invalid_data"))
+			return
+		}
+		h.handlePlanChangePayment(chatID, userID, subscriptionID, planID, payment)
 		return
 	}
 
-	// Получаем информацию о плане
-	plan, err := h.db.GetSubscriptionPlanByID(planID)
-	if err != nil {
-		h.sendMessage(chatID, fmt.Sprintf("Ошибка при получении информации о плане: %v", err))
+	if len(parts) != 2 || (parts[0] != "plan" && parts[0] != "gift" && parts[0] != "topup" && parts[0] != "renew") {
+		h.sendMessage(chatID, h.t(chatID, "payment.invalid_data"))
 		return
 	}
 
-	// Проверяем доступность серверов
-	servers, err := h.db.GetAllServers()
-	if err != nil {
-		h.sendMessage(chatID, "Ошибка при проверке доступности серверов. Пожалуйста, попробуйте позже.")
+	// Пополнение баланса не связано с планом подписки
+	if parts[0] == "topup" {
+		h.handleTopUpPayment(chatID, userID, payment)
 		return
 	}
 
-	var availableServer *models.Server
-	for _, server := range servers {
-		if server.IsActive && server.CurrentClients < server.MaxClients {
-			availableServer = &server
-			break
+	// Продление существующей подписки: renew:<ID подписки>
+	if parts[0] == "renew" {
+		subscriptionID, err := strconv.Atoi(parts[1])
+		if err != nil {
+			h.sendMessage(chatID, h.t(chatID, "payment.invalid_subscription"))
+			return
 		}
-	}
-
-	if availableServer == nil {
-		h.sendMessage(chatID, "К сожалению, в данный момент нет доступных серверов. Пожалуйста, попробуйте позже.")
+		h.handleRenewPayment(chatID, userID, subscriptionID, payment)
 		return
 	}
 
-	// Получаем пользователя
-	user, err := h.db.GetUserByTelegramID(userID)
+	planID, err := strconv.Atoi(parts[1])
 	if err != nil {
-		h.sendMessage(chatID, "Ошибка при получении информации о пользователе. Пожалуйста, попробуйте позже.")
+		h.sendMessage(chatID, h.t(chatID, "payment.invalid_plan"))
 		return
 	}
 
-	// Создаем подписку
-	startDate := time.Now()
-	endDate := startDate.AddDate(0, 0, plan.Duration) // Используем длительность из плана
-
-	subscription := &models.Subscription{
-		UserID:    user.ID,
-		ServerID:  availableServer.ID,
-		PlanID:    planID,
-		StartDate: startDate,
-		EndDate:   endDate,
-		Status:    "active",
+	// Подарок оформляется в виде кода, подписка создается при его активации
+	if parts[0] == "gift" {
+		h.handleGiftPayment(chatID, userID, planID, payment)
+		return
 	}
 
-	// Проверяем, что сервер правильно настроен
-	err = h.vpnManager.SetupServer(availableServer)
+	// Деньги уже списаны: если подписку выдать не удалось, платеж сохраняется и зачисляется на баланс
+	const unappliedDescription = "Оплата подписки, которую не удалось выдать"
+
+	// Получаем информацию о плане
+	plan, err := h.db.GetSubscriptionPlanByID(planID)
 	if err != nil {
-		h.sendMessage(chatID, fmt.Sprintf("Ошибка при настройке сервера VPN: %v", err))
+		h.creditUnappliedPayment(chatID, userID, payment, h.t(chatID, "common.plan_error", err), unappliedDescription)
 		return
 	}
 
-	// Генерируем конфигурационный файл
-	configPath, err := h.vpnManager.CreateClientConfig(availableServer, fmt.Sprintf("user_%d", user.ID))
+	// Получаем пользователя
+	user, err := h.db.GetUserByTelegramID(userID)
 	if err != nil {
-		h.sendMessage(chatID, fmt.Sprintf("Ошибка при создании конфигурации VPN: %v", err))
+		log.Printf("Ошибка при получении пользователя %d для оплаты подписки: %v", userID, err)
+		h.creditUnappliedPayment(chatID, userID, payment, "", unappliedDescription)
 		return
 	}
 
-	subscription.ConfigFilePath = configPath
+	// Платеж сохраняется вместе с активацией подписки
+	paymentRecord := telegramPayment(user.ID, payment)
 
-	// Сохраняем подписку в базу данных
-	err = h.db.AddSubscription(subscription)
+	// Создаем подписку и конфигурацию VPN. Если свободных мест нет, Provision вернет ошибку, и оплата зачислится на баланс.
+	subscription, err := h.provisionSubscription(user, plan, fmt.Sprintf("user_%d", user.ID), false, paymentRecord)
 	if err != nil {
-		h.sendMessage(chatID, fmt.Sprintf("Ошибка при создании подписки: %v", err))
+		h.creditUnappliedPayment(chatID, userID, payment, err.Error(), unappliedDescription)
 		return
 	}
 
-	// Создаем запись о платеже
-	paymentRecord := &models.Payment{
-		UserID:         user.ID,
-		SubscriptionID: subscription.ID,
-		Amount:         float64(payment.TotalAmount) / 100.0, // Переводим из копеек в рубли
-		PaymentMethod:  "telegram_stars",
-		PaymentID:      payment.TelegramPaymentChargeID,
-		Status:         "completed",
+	// Отправляем файл конфигурации и инструкцию
+	if err := h.sendConfigWithInstructions(chatID, subscription.ConfigFilePath); err != nil {
+		return
 	}
 
-	err = h.db.AddPayment(paymentRecord)
-	if err != nil {
-		log.Printf("Ошибка при сохранении платежа в базу данных: %v", err)
+	// Отправляем сообщение о успешной покупке
+	successMsg := h.t(chatID, "purchase.success",
+		plan.Name,
+		plan.Duration,
+		subscription.StartDate.Format("02.01.2006"),
+		subscription.EndDate.Format("02.01.2006"),
+	)
+
+	msg := tgbotapi.NewMessage(chatID, successMsg)
+	msg.ParseMode = "Markdown"
+	h.sender.Send(msg)
+}
+
+// provisionSubscription выдает пользователю новую подписку через сервис подписок.
+// Платеж (если он передан) сохраняется вместе с активацией подписки.
+// Возвращаемая ошибка содержит текст, пригодный для отправки пользователю.
+func (h *BotHandler) provisionSubscription(user *models.User, plan *models.SubscriptionPlan, clientName string, isTrial bool, payment *models.Payment) (*models.Subscription, error) {
+	subscription, err := h.subscriptions.Provision(service.ProvisionRequest{
+		User:       user,
+		Plan:       plan,
+		ClientName: clientName,
+		IsTrial:    isTrial,
+		Payment:    payment,
+	})
+	if err == nil {
+		return subscription, nil
+	}
+
+	lang := userLang(user)
+	var provisionErr *service.ProvisionError
+	switch {
+	case errors.Is(err, service.ErrNoAvailableServer):
+		return nil, errors.New(i18n.T(lang, "common.no_servers"))
+	case errors.As(err, &provisionErr) && provisionErr.Op == service.OpServerSetup:
+		return nil, errors.New(i18n.T(lang, "provision.setup_error", provisionErr.Err))
+	case errors.As(err, &provisionErr):
+		return nil, errors.New(i18n.T(lang, "provision.config_error", provisionErr.Err))
+	default:
+		return nil, errors.New(i18n.T(lang, "provision.save_error", err))
 	}
+}
 
-	// Отправляем файл конфигурации пользователю
+// sendConfigWithInstructions отправляет пользователю файл конфигурации и инструкцию по настройке
+func (h *BotHandler) sendConfigWithInstructions(chatID int64, configPath string) error {
 	configFile := tgbotapi.NewDocument(chatID, tgbotapi.FilePath(configPath))
-	configFile.Caption = "Вот ваш файл конфигурации VPN. Инструкция по установке в следующем сообщении."
+	configFile.Caption = h.t(chatID, "config.caption")
 
-	_, err = h.bot.Send(configFile)
+	_, err := h.sender.Send(configFile)
 	if err != nil {
-		h.sendMessage(chatID, fmt.Sprintf("Ошибка при отправке файла конфигурации: %v", err))
-		return
+		h.sendMessage(chatID, h.t(chatID, "config.send_error", err))
+		return err
 	}
 
 	// Отправляем инструкцию
-	instructions := `
-*Инструкция по настройке VPN:*
-
-1. Скачайте и установите клиент AmneziaVPN:
-   - для Windows: https://github.com/amnezia-vpn/amnezia-client/releases/download/4.8.3.1/AmneziaVPN_4.8.3.1_x64.exe
-   - для MacOS: https://github.com/amnezia-vpn/amnezia-client/releases/download/4.8.3.1/AmneziaVPN_4.8.3.1_macos.dmg
-   - для iOS: https://apps.apple.com/us/app/amneziavpn/id1600529900
-   - для Android: https://play.google.com/store/apps/details?id=org.amnezia.vpn
-
-2. Откройте клиент AmneziaVPN
-3. Импортируйте полученный файл конфигурации
-4. Активируйте подключение
-
-Готово! Теперь ваш трафик защищен VPN.
-`
+	instructions := h.t(chatID, "config.instructions")
 
 	instrMsg := tgbotapi.NewMessage(chatID, instructions)
 	instrMsg.ParseMode = "Markdown"
 
-	h.bot.Send(instrMsg)
-
-	// Отправляем сообщение о успешной покупке
-	successMsg := fmt.Sprintf(
-		"✅ *Подписка успешно оформлена!*\n\n"+
-			"План: %s\n"+
-			"Срок действия: %d дней\n"+
-			"Дата начала: %s\n"+
-			"Дата окончания: %s\n\n"+
-			"Спасибо за покупку!",
-		plan.Name,
-		plan.Duration,
-		startDate.Format("02.01.2006"),
-		endDate.Format("02.01.2006"),
-	)
-
-	msg := tgbotapi.NewMessage(chatID, successMsg)
-	msg.ParseMode = "Markdown"
-	h.bot.Send(msg)
+	h.sender.Send(instrMsg)
+	return nil
 }
 
 // handleMenuButtonPress обрабатывает нажатия на кнопки основного меню
@@ -1126,62 +1151,45 @@ func (h *BotHandler) handleMenuButtonPress(message *tgbotapi.Message) bool {
 	chatID := message.Chat.ID
 	userID := message.From.ID
 
-	switch text {
-	case "💰 Купить подписку":
+	// Кнопки клавиатуры приходят обычным текстом на языке, на котором было отправлено меню
+	key, ok := i18n.Match(text, "menu.buy", "menu.my", "menu.balance", "menu.help", "menu.support", "menu.admin")
+	if !ok {
+		return false
+	}
+
+	switch key {
+	case "menu.buy":
 		h.handleBuyCommand(message)
-		return true
 
-	case "🔑 Мои подписки":
+	case "menu.my":
 		h.handleMySubscriptionsCommand(message)
-		return true
-
-	case "ℹ️ Помощь":
-		h.handleHelpCommand(message)
-		return true
-
-	case "📞 Поддержка":
-		supportMsg := `
-*Поддержка VPN-сервиса*
 
-Если у вас возникли вопросы или проблемы с использованием нашего VPN:
+	case "menu.balance":
+		h.handleBalanceCommand(chatID, userID)
 
-1. Опишите вашу проблему подробно
-2. Укажите, какую подписку вы используете
-3. По возможности, приложите скриншоты ошибок
-
-Наша команда поддержки ответит вам в кратчайшие сроки!
-`
-		keyboard := tgbotapi.NewInlineKeyboardMarkup(
-			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonURL("📞 Написать в поддержку", "https://t.me/Demokrat_repablick"),
-			),
-		)
+	case "menu.help":
+		h.handleHelpCommand(message)
 
-		msg := tgbotapi.NewMessage(chatID, supportMsg)
-		msg.ParseMode = "Markdown"
-		msg.ReplyMarkup = keyboard
-		h.bot.Send(msg)
-		return true
+	case "menu.support":
+		h.showSupportMenu(chatID, userID)
 
-	case "⚙️ Админ-панель":
+	case "menu.admin":
 		// Проверяем, является ли пользователь администратором
 		if h.IsAdmin(userID) {
 			h.showAdminMenu(chatID)
-			return true
 		} else {
-			h.sendMessage(chatID, "У вас нет прав администратора.")
-			return true
+			h.sendMessage(chatID, h.t(chatID, "common.no_admin_rights"))
 		}
 	}
 
-	return false
+	return true
 }
 
 // sendMessage отправляет сообщение пользователю
 func (h *BotHandler) sendMessage(chatID int64, text string) {
 	msg := tgbotapi.NewMessage(chatID, text)
 	msg.ParseMode = "Markdown"
-	_, err := h.bot.Send(msg)
+	_, err := h.sender.Send(msg)
 	if err != nil {
 		log.Printf("Ошибка при отправке сообщения: %v", err)
 	}
@@ -1203,137 +1211,129 @@ func formatBytes(bytes int64) string {
 
 // listAvailableSubscriptionPlans отображает список доступных планов подписки для покупки
 func (h *BotHandler) listAvailableSubscriptionPlans(chatID int64) {
+	lang := h.lang(chatID)
+
 	// Получаем список активных планов подписки
 	plans, err := h.db.GetAllSubscriptionPlans()
 	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Ошибка при получении списка планов: %v", err))
-		h.bot.Send(msg)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, "common.plans_error", err))
+		h.sender.Send(msg)
 		return
 	}
 
 	// Если нет доступных планов
 	if len(plans) == 0 {
-		msg := tgbotapi.NewMessage(chatID, "В настоящее время нет доступных планов подписки. Пожалуйста, попробуйте позже.")
-		h.bot.Send(msg)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, "plans.none"))
+		h.sender.Send(msg)
 		return
 	}
 
 	// Отправляем сообщение с заголовком
-	headerMsg := `
-*💰 Выберите план подписки*
-
-Ниже представлены доступные тарифные планы для VPN-подключения.
-Выберите подходящий вариант и нажмите на кнопку для оформления подписки.
-`
+	headerMsg := i18n.T(lang, "plans.header")
 	msg := tgbotapi.NewMessage(chatID, headerMsg)
 	msg.ParseMode = "Markdown"
-	h.bot.Send(msg)
+	h.sender.Send(msg)
+
+	// Предлагаем пробный период, если пользователь его еще не использовал
+	h.offerTrial(chatID)
 
 	// Отправляем карточку для каждого плана
 	for _, plan := range plans {
-		// Пропускаем неактивные планы
-		if !plan.IsActive {
+		// Пропускаем неактивные и пробные планы
+		if !plan.IsActive || plan.IsTrial {
 			continue
 		}
 
-		// Создаем красивое сообщение с описанием плана
-		planMsg := fmt.Sprintf(
-			"*%s*\n\n"+
-				"%s\n\n"+
-				"💰 *Цена:* %.2f руб.\n"+
-				"⏳ *Длительность:* %d дней\n"+
-				"💵 *Цена за день:* %.2f руб.",
+		// Создаем красивое сообщение с описанием плана (цена указывается в валюте счета)
+		amount, currency := h.planInvoicePrice(&plan)
+		planMsg := i18n.T(lang, "plans.card",
 			plan.Name,
 			plan.Description,
-			plan.Price,
+			money.Format(amount, currency),
 			plan.Duration,
-			plan.Price/float64(plan.Duration),
+			money.Format(money.Prorate(amount, 1, int64(plan.Duration)), currency),
 		)
 
-		// Создаем инлайн-кнопку для покупки
+		// Создаем инлайн-кнопки для покупки себе и в подарок
 		keyboard := tgbotapi.NewInlineKeyboardMarkup(
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("💳 Купить", fmt.Sprintf("buy_plan:%d", plan.ID)),
+				h.button(chatID, i18n.T(lang, "button.buy"), fmt.Sprintf("buy_plan:%d", plan.ID)),
+				h.button(chatID, i18n.T(lang, "button.gift"), fmt.Sprintf("gift_plan:%d", plan.ID)),
 			),
 		)
 
+		// Оплата с баланса доступна, если у плана есть цена в валюте баланса
+		if _, ok := plan.PriceIn(models.LedgerCurrency); ok {
+			keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+				h.button(chatID, i18n.T(lang, "button.balance_pay"), fmt.Sprintf("balance_pay:%d", plan.ID)),
+			))
+		}
+
 		planMsgConfig := tgbotapi.NewMessage(chatID, planMsg)
 		planMsgConfig.ParseMode = "Markdown"
 		planMsgConfig.ReplyMarkup = keyboard
 
-		h.bot.Send(planMsgConfig)
+		h.sender.Send(planMsgConfig)
 	}
 
 	// Добавляем кнопку для возврата в меню
-	footerMsg := "*Остались вопросы?*\nСвяжитесь с нашей технической поддержкой."
+	footerMsg := i18n.T(lang, "plans.footer")
 	footerMsgConfig := tgbotapi.NewMessage(chatID, footerMsg)
 	footerMsgConfig.ParseMode = "Markdown"
 
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonURL("📞 Поддержка", "https://t.me/Demokrat_repablick"),
+			h.button(chatID, i18n.T(lang, "menu.support"), "support_menu"),
 		),
 	)
 	footerMsgConfig.ReplyMarkup = keyboard
 
-	h.bot.Send(footerMsgConfig)
+	h.sender.Send(footerMsgConfig)
 }
 
 // handleBuyPlan обрабатывает покупку выбранного плана подписки
 func (h *BotHandler) handleBuyPlan(chatID int64, userID int64, planID int) {
+	lang := h.lang(chatID)
+
 	// Получаем информацию о плане
 	plan, err := h.db.GetSubscriptionPlanByID(planID)
 	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Ошибка при получении информации о плане: %v", err))
-		h.bot.Send(msg)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, "common.plan_error", err))
+		h.sender.Send(msg)
 		return
 	}
 
-	// Проверяем, что план активен
-	if !plan.IsActive {
-		msg := tgbotapi.NewMessage(chatID, "Выбранный план недоступен для покупки.")
-		h.bot.Send(msg)
+	// Проверяем, что план активен и не является пробным
+	if !plan.IsActive || plan.IsTrial {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, "common.plan_unavailable"))
+		h.sender.Send(msg)
 		return
 	}
 
 	// Проверяем доступность серверов перед оформлением платежа
-	servers, err := h.db.GetAllServers()
+	availableServer, err := h.subscriptions.AvailableServer()
 	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, "Ошибка при проверке доступности серверов. Пожалуйста, попробуйте позже.")
-		h.bot.Send(msg)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, "common.servers_check_error"))
+		h.sender.Send(msg)
 		return
 	}
 
-	var availableServer *models.Server
-	for _, server := range servers {
-		if server.IsActive && server.CurrentClients < server.MaxClients {
-			availableServer = &server
-			break
-		}
-	}
-
 	if availableServer == nil {
-		msg := tgbotapi.NewMessage(chatID, "К сожалению, в данный момент нет доступных серверов. Пожалуйста, попробуйте позже.")
-		h.bot.Send(msg)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, "common.no_servers"))
+		h.sender.Send(msg)
 		return
 	}
 
-	// Создаем платежный инвойс
-	priceInPennies := int(plan.Price * 100) // Переводим в копейки
-	invoice := tgbotapi.NewInvoice(
+	// Создаем платежный инвойс в валюте из настроек платежей (или в основной валюте плана)
+	amount, currency := h.planInvoicePrice(plan)
+	invoice := h.newInvoice(
 		chatID,
-		fmt.Sprintf("VPN-подписка: %s", plan.Name),
-		fmt.Sprintf("Подписка на VPN-сервис длительностью %d дней", plan.Duration),
+		i18n.T(lang, "purchase.invoice_title", plan.Name),
+		i18n.T(lang, "purchase.invoice_desc", plan.Duration),
 		fmt.Sprintf("plan:%d", planID), // Payload для идентификации плана
-		h.config.Payments.Provider,
-		"RUB", // Валюта
-		"RUB", // Валюта параметра провайдера
-		[]tgbotapi.LabeledPrice{
-			{
-				Label:  plan.Name,
-				Amount: priceInPennies,
-			},
-		},
+		plan.Name,
+		amount,
+		currency,
 	)
 
 	// Настраиваем дополнительные параметры инвойса
@@ -1345,53 +1345,68 @@ func (h *BotHandler) handleBuyPlan(chatID int64, userID int64, planID int) {
 	invoice.DisableNotification = false
 
 	// Отправляем запрос на оплату
-	_, err = h.bot.Send(invoice)
+	_, err = h.sender.Send(invoice)
 	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Ошибка при создании счета для оплаты: %v", err))
-		h.bot.Send(msg)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, "common.invoice_error", err))
+		h.sender.Send(msg)
 		return
 	}
 
 	// Сообщение с инструкцией по оплате
-	paymentInstructions := `
-*Инструкция по оплате:*
-
-1. Нажмите кнопку "Оплатить" в отправленном счете
-2. Выберите способ оплаты
-3. Следуйте инструкциям для завершения оплаты
-4. После успешной оплаты вы получите конфигурационный файл и инструкции по настройке VPN
-
-В случае возникновения проблем с оплатой, обратитесь в службу поддержки.
-`
+	paymentInstructions := i18n.T(lang, "purchase.instructions")
 	instructionMsg := tgbotapi.NewMessage(chatID, paymentInstructions)
 	instructionMsg.ParseMode = "Markdown"
-	h.bot.Send(instructionMsg)
+	h.sender.Send(instructionMsg)
 }
 
 // showAdminMenu отображает меню администратора
 func (h *BotHandler) showAdminMenu(chatID int64) {
-	text := "🔧 *Меню администратора*\n\nВыберите действие:"
-
-	keyboard := tgbotapi.NewInlineKeyboardMarkup(
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🖥️ Управление серверами", "admin_menu:servers"),
-		),
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("📑 Управление планами", "admin_menu:plans"),
-		),
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("👥 Управление пользователями", "admin_menu:users"),
-		),
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("📊 Статистика", "admin_menu:stats"),
-		),
-	)
+	role := h.role(chatID)
+	text := h.t(chatID, "admin.menu", roleName(h.lang(chatID), role))
+
+	// Показываем только разделы, доступные роли сотрудника
+	keyboard := tgbotapi.NewInlineKeyboardMarkup()
+	if roles.Can(role, roles.PermServers) {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "admin.menu_servers"), "admin_menu:servers"),
+		))
+	}
+	if roles.Can(role, roles.PermPlans) {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "admin.menu_plans"), "admin_menu:plans"),
+		))
+	}
+	if roles.CanAny(role, roles.PermUsers, roles.PermRefunds) {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "admin.menu_users"), "admin_menu:users"),
+		))
+	}
+	if roles.Can(role, roles.PermStats) {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "admin.menu_stats"), "admin_menu:stats"),
+		))
+	}
+	if roles.Can(role, roles.PermBroadcasts) {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "admin.menu_broadcast"), "admin_menu:broadcast"),
+		))
+	}
+	if roles.Can(role, roles.PermRoles) {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "admin.menu_roles"), "admin_menu:roles"),
+		))
+	}
+	if roles.Can(role, roles.PermAudit) {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "admin.menu_audit"), "admin_menu:audit"),
+		))
+	}
 
 	msg := tgbotapi.NewMessage(chatID, text)
 	msg.ParseMode = "Markdown"
 	msg.ReplyMarkup = keyboard
 
-	h.bot.Send(msg)
+	h.sender.Send(msg)
 }
 
 // handleAdminMenuSelection обрабатывает выбор в меню администратора
@@ -1401,48 +1416,53 @@ func (h *BotHandler) handleAdminMenuSelection(chatID int64, selection string) {
 		// Возвращаемся в главное меню администратора
 		h.showAdminMenu(chatID)
 
+	case "roles":
+		h.showRolesMenu(chatID)
+
+	case "audit":
+		h.showAuditLog(chatID, 0)
+
+	case "broadcast":
+		h.startBroadcast(chatID)
+
 	case "servers":
 		// Показываем список серверов
 		servers, err := h.db.GetAllServers()
 		if err != nil {
-			msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Ошибка при получении списка серверов: %v", err))
-			h.bot.Send(msg)
+			msg := tgbotapi.NewMessage(chatID, h.t(chatID, "admin_servers.list_error", err))
+			h.sender.Send(msg)
 			return
 		}
 
 		if len(servers) == 0 {
 			keyboard := tgbotapi.NewInlineKeyboardMarkup(
 				tgbotapi.NewInlineKeyboardRow(
-					tgbotapi.NewInlineKeyboardButtonData("➕ Добавить сервер", "server_action:add:0"),
+					h.button(chatID, h.t(chatID, "admin_servers.add"), "server_action:add:0"),
 				),
 				tgbotapi.NewInlineKeyboardRow(
-					tgbotapi.NewInlineKeyboardButtonData("🔙 Назад", "admin_menu:main"),
+					h.button(chatID, h.t(chatID, "admin.back_button"), "admin_menu:main"),
 				),
 			)
 
-			msg := tgbotapi.NewMessage(chatID, "Серверы не найдены. Добавьте новый сервер.")
+			msg := tgbotapi.NewMessage(chatID, h.t(chatID, "admin_servers.empty"))
 			msg.ReplyMarkup = keyboard
-			h.bot.Send(msg)
+			h.sender.Send(msg)
 			return
 		}
 
 		// Отправляем заголовок
-		headerMsg := tgbotapi.NewMessage(chatID, "*Список серверов*\n\nВыберите сервер для управления:")
+		headerMsg := tgbotapi.NewMessage(chatID, h.t(chatID, "admin_servers.list_header"))
 		headerMsg.ParseMode = "Markdown"
-		h.bot.Send(headerMsg)
+		h.sender.Send(headerMsg)
 
 		// Отправляем информацию о каждом сервере
 		for _, server := range servers {
-			status := "🟢 Активен"
+			status := h.t(chatID, "admin.status_active")
 			if !server.IsActive {
-				status = "🔴 Неактивен"
+				status = h.t(chatID, "admin.status_inactive")
 			}
 
-			serverMsg := fmt.Sprintf(
-				"*Сервер #%d*\n"+
-					"IP: `%s:%d`\n"+
-					"Клиенты: %d / %d\n"+
-					"Статус: %s",
+			serverMsg := h.t(chatID, "admin_servers.list_item",
 				server.ID,
 				server.IP,
 				server.Port,
@@ -1453,162 +1473,41 @@ func (h *BotHandler) handleAdminMenuSelection(chatID int64, selection string) {
 
 			keyboard := tgbotapi.NewInlineKeyboardMarkup(
 				tgbotapi.NewInlineKeyboardRow(
-					tgbotapi.NewInlineKeyboardButtonData("📝 Редактировать", fmt.Sprintf("server_action:edit:%d", server.ID)),
-					tgbotapi.NewInlineKeyboardButtonData("🔍 Детали", fmt.Sprintf("server_action:view:%d", server.ID)),
+					h.button(chatID, h.t(chatID, "admin.edit"), fmt.Sprintf("server_action:edit:%d", server.ID)),
+					h.button(chatID, h.t(chatID, "admin.details"), fmt.Sprintf("server_action:view:%d", server.ID)),
 				),
 				tgbotapi.NewInlineKeyboardRow(
-					tgbotapi.NewInlineKeyboardButtonData("❌ Удалить", fmt.Sprintf("server_action:delete:%d", server.ID)),
+					h.button(chatID, h.t(chatID, "admin.delete"), fmt.Sprintf("server_action:delete:%d", server.ID)),
 				),
 			)
 
 			msg := tgbotapi.NewMessage(chatID, serverMsg)
 			msg.ParseMode = "Markdown"
 			msg.ReplyMarkup = keyboard
-			h.bot.Send(msg)
+			h.sender.Send(msg)
 		}
 
 		// Добавляем кнопки для создания нового сервера и возврата в меню
 		footerKeyboard := tgbotapi.NewInlineKeyboardMarkup(
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("➕ Добавить сервер", "server_action:add:0"),
+				h.button(chatID, h.t(chatID, "admin_servers.add"), "server_action:add:0"),
 			),
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("🔙 Назад", "admin_menu:main"),
+				h.button(chatID, h.t(chatID, "admin.back_button"), "admin_menu:main"),
 			),
 		)
 
-		footerMsg := tgbotapi.NewMessage(chatID, "Действия с серверами:")
+		footerMsg := tgbotapi.NewMessage(chatID, h.t(chatID, "admin_servers.actions"))
 		footerMsg.ReplyMarkup = footerKeyboard
-		h.bot.Send(footerMsg)
+		h.sender.Send(footerMsg)
 
 	case "plans":
 		// Показываем список планов подписки
 		h.listSubscriptionPlans(chatID)
 
 	case "users":
-		// Показываем список пользователей
-		users, err := h.db.GetAllUsers()
-		if err != nil {
-			msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Ошибка при получении списка пользователей: %v", err))
-			h.bot.Send(msg)
-			return
-		}
-
-		// Добавляем отладочный вывод
-		log.Printf("Найдено пользователей в базе: %d", len(users))
-		for i, user := range users {
-			log.Printf("Пользователь %d: ID=%d, TelegramID=%d, Username=%s, IsAdmin=%v",
-				i+1, user.ID, user.TelegramID, user.Username, user.IsAdmin)
-		}
-
-		if len(users) == 0 {
-			keyboard := tgbotapi.NewInlineKeyboardMarkup(
-				tgbotapi.NewInlineKeyboardRow(
-					tgbotapi.NewInlineKeyboardButtonData("🔙 Назад", "admin_menu:main"),
-				),
-			)
-			msg := tgbotapi.NewMessage(chatID, "Список пользователей пуст")
-			msg.ReplyMarkup = keyboard
-			h.bot.Send(msg)
-			return
-		}
-		headerMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("*Список пользователей (%d всего)*\n\nНиже будут показаны все пользователи. Пожалуйста, дождитесь загрузки всех сообщений:", len(users)))
-		headerMsg.ParseMode = "Markdown"
-		h.bot.Send(headerMsg)
-
-		// Отправляем информацию о каждом пользователе (ограничиваем вывод 10 пользователями)
-		count := 0
-		for _, user := range users {
-			// Получаем статистику пользователя
-			stats, err := h.db.GetUserStats(user.ID)
-			if err != nil {
-				log.Printf("Ошибка при получении статистики пользователя #%d: %v", user.ID, err)
-				continue
-			}
-
-			admin := ""
-			if user.IsAdmin {
-				admin = "👑 Администратор"
-			}
-
-			name := user.Username
-			if name == "" {
-				name = fmt.Sprintf("%s %s", user.FirstName, user.LastName)
-			}
-
-			// Добавляем порядковый номер в сообщение для лучшей видимости
-			userMsg := fmt.Sprintf(
-				"*Пользователь #%d (№%d из %d)*\n"+
-					"Имя: `%s`\n"+
-					"Telegram ID: `%d`\n"+
-					"Дата регистрации: `%s`\n"+
-					"Активных подписок: `%d`\n"+
-					"Всего подписок: `%d`\n"+
-					"Использовано данных: `%.2f GB`\n"+
-					"Сумма платежей: `%.2f ₽`\n"+
-					"%s",
-				user.ID, count+1, len(users), name, user.TelegramID,
-				user.CreatedAt.Format("02.01.2006"),
-				stats.ActiveSubscriptionsCount,
-				stats.SubscriptionsCount,
-				float64(stats.TotalDataUsage)/(1024*1024*1024), // Конвертируем байты в GB
-				stats.TotalPayments,
-				admin)
-			fmt.Println(user.ID, count)
-			var keyboard tgbotapi.InlineKeyboardMarkup
-			if user.IsAdmin {
-				keyboard = tgbotapi.NewInlineKeyboardMarkup(
-					tgbotapi.NewInlineKeyboardRow(
-						tgbotapi.NewInlineKeyboardButtonData("🔍 Подписки", fmt.Sprintf("user_action:subscriptions:%d", user.ID)),
-					),
-					tgbotapi.NewInlineKeyboardRow(
-						tgbotapi.NewInlineKeyboardButtonData("❌ Снять админа", fmt.Sprintf("user_action:remove_admin:%d", user.ID)),
-					),
-				)
-			} else {
-				keyboard = tgbotapi.NewInlineKeyboardMarkup(
-					tgbotapi.NewInlineKeyboardRow(
-						tgbotapi.NewInlineKeyboardButtonData("🔍 Подписки", fmt.Sprintf("user_action:subscriptions:%d", user.ID)),
-					),
-					tgbotapi.NewInlineKeyboardRow(
-						tgbotapi.NewInlineKeyboardButtonData("👑 Сделать админом", fmt.Sprintf("user_action:make_admin:%d", user.ID)),
-					),
-				)
-			}
-
-			msg := tgbotapi.NewMessage(chatID, userMsg)
-			msg.ParseMode = "Markdown"
-			msg.ReplyMarkup = keyboard
-
-			// Добавляем задержку перед отправкой следующего сообщения (1 секунда)
-			time.Sleep(1000 * time.Millisecond)
-
-			// Перехватываем возможные ошибки при отправке сообщений
-			sentMsg, err := h.bot.Send(msg)
-			if err != nil {
-				log.Printf("ОШИБКА при отправке сообщения для пользователя %s (ID=%d): %v",
-					name, user.ID, err)
-				continue
-			}
-
-			// Дополнительный отладочный вывод после отправки сообщения
-			log.Printf("Отправлено сообщение для пользователя %s (ID=%d), IsAdmin=%v, MessageID=%d",
-				name, user.ID, user.IsAdmin, sentMsg.MessageID)
-
-			count++
-		}
-
-		// Добавляем кнопку для возврата в меню
-		footerKeyboard := tgbotapi.NewInlineKeyboardMarkup(
-			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("🔙 Назад", "admin_menu:main"),
-			),
-		)
-
-		footerMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("✅ *Список завершен*\nВсего показано пользователей: *%d*", count))
-		footerMsg.ParseMode = "Markdown"
-		footerMsg.ReplyMarkup = footerKeyboard
-		h.bot.Send(footerMsg)
+		// Показываем первую страницу списка пользователей
+		h.showUserList(chatID, 0, defaultUserListView)
 
 	case "stats":
 		// Показываем меню статистики
@@ -1621,81 +1520,79 @@ func (h *BotHandler) listSubscriptionPlans(chatID int64) {
 	// Получаем все планы подписки
 	plans, err := h.db.GetAllSubscriptionPlans()
 	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Ошибка при получении списка планов: %v", err))
-		h.bot.Send(msg)
+		msg := tgbotapi.NewMessage(chatID, h.t(chatID, "common.plans_error", err))
+		h.sender.Send(msg)
 		return
 	}
 
 	if len(plans) == 0 {
 		keyboard := tgbotapi.NewInlineKeyboardMarkup(
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("➕ Добавить план", "plan_action:add:0"),
+				h.button(chatID, h.t(chatID, "admin_plans.add"), "plan_action:add:0"),
 			),
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("🔙 Назад", "admin_menu:main"),
+				h.button(chatID, h.t(chatID, "admin.back_button"), "admin_menu:main"),
 			),
 		)
 
-		msg := tgbotapi.NewMessage(chatID, "Планы подписки не найдены. Добавьте новый план.")
+		msg := tgbotapi.NewMessage(chatID, h.t(chatID, "admin_plans.empty"))
 		msg.ReplyMarkup = keyboard
-		h.bot.Send(msg)
+		h.sender.Send(msg)
 		return
 	}
 
 	// Отправляем заголовок
-	headerMsg := tgbotapi.NewMessage(chatID, "*Список планов подписки*\n\nВыберите план для управления:")
+	headerMsg := tgbotapi.NewMessage(chatID, h.t(chatID, "admin_plans.list_header"))
 	headerMsg.ParseMode = "Markdown"
-	h.bot.Send(headerMsg)
+	h.sender.Send(headerMsg)
 
 	// Отправляем информацию о каждом плане
 	for _, plan := range plans {
-		status := "🟢 Активен"
+		status := h.t(chatID, "admin.status_active")
 		if !plan.IsActive {
-			status = "🔴 Неактивен"
+			status = h.t(chatID, "admin.status_inactive")
 		}
 
-		planMsg := fmt.Sprintf(
-			"*%s*\n"+
-				"%s\n"+
-				"Цена: %.2f руб.\n"+
-				"Длительность: %d дней\n"+
-				"Статус: %s",
+		planMsg := h.t(chatID, "admin_plans.list_item",
 			plan.Name,
 			plan.Description,
-			plan.Price,
+			formatPlanPrices(&plan),
 			plan.Duration,
 			status,
 		)
+		if plan.IsTrial {
+			planMsg += h.t(chatID, "admin_plans.trial_mark")
+		}
 
 		keyboard := tgbotapi.NewInlineKeyboardMarkup(
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("📝 Редактировать", fmt.Sprintf("plan_action:edit:%d", plan.ID)),
-				tgbotapi.NewInlineKeyboardButtonData("🔍 Детали", fmt.Sprintf("plan_action:view:%d", plan.ID)),
+				h.button(chatID, h.t(chatID, "admin.edit"), fmt.Sprintf("plan_action:edit:%d", plan.ID)),
+				h.button(chatID, h.t(chatID, "admin.details"), fmt.Sprintf("plan_action:view:%d", plan.ID)),
 			),
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("❌ Удалить", fmt.Sprintf("plan_action:delete:%d", plan.ID)),
+				h.button(chatID, h.t(chatID, "admin.delete"), fmt.Sprintf("plan_action:delete:%d", plan.ID)),
 			),
 		)
 
 		msg := tgbotapi.NewMessage(chatID, planMsg)
 		msg.ParseMode = "Markdown"
 		msg.ReplyMarkup = keyboard
-		h.bot.Send(msg)
+		h.sender.Send(msg)
 	}
 
 	// Добавляем кнопки для создания нового плана и возврата в меню
 	footerKeyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("➕ Добавить план", "plan_action:add:0"),
+			h.button(chatID, h.t(chatID, "admin_plans.add"), "plan_action:add:0"),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🔙 Назад", "admin_menu:main"),
+			h.button(chatID, h.t(chatID, "admin.back_button"), "admin_menu:main"),
 		),
 	)
 
-	footerMsg := tgbotapi.NewMessage(chatID, "Действия с планами:")
+	footerMsg := tgbotapi.NewMessage(chatID, h.t(chatID, "admin_plans.actions"))
 	footerMsg.ReplyMarkup = footerKeyboard
-	h.bot.Send(footerMsg)
+	h.sender.Send(footerMsg)
 }
 
 // viewPlanDetails отображает подробную информацию о плане подписки
@@ -1703,49 +1600,48 @@ func (h *BotHandler) viewPlanDetails(chatID int64, planID int) {
 	// Получаем информацию о плане
 	plan, err := h.db.GetSubscriptionPlanByID(planID)
 	if err != nil {
-		h.sendMessage(chatID, fmt.Sprintf("Ошибка при получении информации о плане: %v", err))
+		h.sendMessage(chatID, h.t(chatID, "common.plan_error", err))
 		return
 	}
 
 	if plan == nil {
-		h.sendMessage(chatID, "План подписки не найден.")
+		h.sendMessage(chatID, h.t(chatID, "admin_plans.not_found"))
 		return
 	}
 
 	// Получаем количество активных подписок на этот план
 	// Предполагаем, что у нас нет метода GetActiveSubscriptionCountByPlanID,
 	// поэтому будем просто показывать "Недоступно"
-	activeSubscriptions := "Недоступно"
+	activeSubscriptions := h.t(chatID, "admin_plans.unavailable")
 
 	// Получаем общее количество подписок на этот план
 	// Предполагаем, что у нас нет метода GetTotalSubscriptionCountByPlanID,
 	// поэтому будем просто показывать "Недоступно"
-	totalSubscriptions := "Недоступно"
+	totalSubscriptions := h.t(chatID, "admin_plans.unavailable")
 
-	status := "🟢 Активен"
+	status := h.t(chatID, "admin.status_active")
 	if !plan.IsActive {
-		status = "🔴 Неактивен"
+		status = h.t(chatID, "admin.status_inactive")
+	}
+
+	trialText := h.t(chatID, "admin.no")
+	trialButton := h.t(chatID, "admin_plans.make_trial")
+	if plan.IsTrial {
+		trialText = h.t(chatID, "admin.yes")
+		trialButton = h.t(chatID, "admin_plans.make_paid")
 	}
 
 	// Формируем сообщение с подробной информацией
-	planMsg := fmt.Sprintf(
-		"*Детали плана подписки*\n\n"+
-			"*ID:* `%d`\n"+
-			"*Название:* %s\n"+
-			"*Описание:* %s\n"+
-			"*Цена:* %.2f руб.\n"+
-			"*Длительность:* %d дней\n"+
-			"*Статус:* %s\n"+
-			"*Активных подписок:* %s\n"+
-			"*Всего подписок:* %s\n"+
-			"*Создан:* %s\n"+
-			"*Обновлен:* %s",
+	planMsg := h.t(chatID, "admin_plans.details",
 		plan.ID,
 		plan.Name,
 		plan.Description,
-		plan.Price,
+		formatPlanPrices(plan),
 		plan.Duration,
 		status,
+		trialText,
+		plan.MaxPauseDays,
+		plan.GraceDays,
 		activeSubscriptions,
 		totalSubscriptions,
 		plan.CreatedAt.Format("02.01.2006 15:04:05"),
@@ -1755,24 +1651,34 @@ func (h *BotHandler) viewPlanDetails(chatID int64, planID int) {
 	// Кнопки для управления планом
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("📝 Редактировать", fmt.Sprintf("plan_action:edit:%d", plan.ID)),
+			h.button(chatID, h.t(chatID, "admin.edit"), fmt.Sprintf("plan_action:edit:%d", plan.ID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, trialButton, fmt.Sprintf("plan_action:toggle_trial:%d", plan.ID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "admin_plans.max_pause"), fmt.Sprintf("plan_action:max_pause:%d", plan.ID)),
+			h.button(chatID, h.t(chatID, "admin_plans.grace_days"), fmt.Sprintf("plan_action:grace_days:%d", plan.ID)),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("❌ Удалить", fmt.Sprintf("plan_action:delete:%d", plan.ID)),
+			h.button(chatID, h.t(chatID, "admin_plans.prices"), fmt.Sprintf("plan_action:prices:%d", plan.ID)),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🔙 К списку планов", "admin_menu:plans"),
+			h.button(chatID, h.t(chatID, "admin.delete"), fmt.Sprintf("plan_action:delete:%d", plan.ID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "admin_plans.back_to_list"), "admin_menu:plans"),
 		),
 	)
 
 	msg := tgbotapi.NewMessage(chatID, planMsg)
 	msg.ParseMode = "Markdown"
 	msg.ReplyMarkup = keyboard
-	h.bot.Send(msg)
+	h.sender.Send(msg)
 }
 
 // handlePlanAction обрабатывает действия с планами подписки
-func (h *BotHandler) handlePlanAction(chatID int64, action string, planID int) {
+func (h *BotHandler) handlePlanAction(chatID int64, actorTelegramID int64, action string, planID int) {
 	switch action {
 	case "view":
 		// Показываем детали плана
@@ -1782,12 +1688,12 @@ func (h *BotHandler) handlePlanAction(chatID int64, action string, planID int) {
 		// Получаем план из базы данных
 		plan, err := h.db.GetSubscriptionPlanByID(planID)
 		if err != nil {
-			h.sendMessage(chatID, fmt.Sprintf("Ошибка при получении плана: %v", err))
+			h.sendMessage(chatID, h.t(chatID, "common.plan_error", err))
 			return
 		}
 
 		if plan == nil {
-			h.sendMessage(chatID, "План подписки не найден.")
+			h.sendMessage(chatID, h.t(chatID, "admin_plans.not_found"))
 			return
 		}
 
@@ -1798,84 +1704,113 @@ func (h *BotHandler) handlePlanAction(chatID int64, action string, planID int) {
 				"plan_id":     strconv.Itoa(plan.ID),
 				"name":        plan.Name,
 				"description": plan.Description,
-				"price":       fmt.Sprintf("%.2f", plan.Price),
+				"price":       money.Decimal(plan.Price, plan.Currency),
+				"currency":    plan.Currency,
 				"duration":    strconv.Itoa(plan.Duration),
 				"is_active":   strconv.FormatBool(plan.IsActive),
 			},
 		}
-		h.userStates[chatID] = userState
+		h.setState(chatID, userState)
 
 		// Отправляем сообщение с текущими значениями плана
-		msg := fmt.Sprintf("📝 *Редактирование плана подписки*\n\n"+
-			"*Текущие данные:*\n"+
-			"*Название:* %s\n"+
-			"*Описание:* %s\n"+
-			"*Цена:* %.2f руб.\n"+
-			"*Длительность:* %d дней\n"+
-			"*Статус:* %s %s\n\n"+
-			"Введите новое название плана (или отправьте точку '.' чтобы оставить текущее название):",
+		msg := h.t(chatID, "admin_plans.edit_start",
 			plan.Name,
 			plan.Description,
-			plan.Price,
+			money.Format(plan.Price, plan.Currency),
 			plan.Duration,
 			getStatusEmoji(plan.IsActive),
-			getStatusText(plan.IsActive))
+			h.activeText(chatID, plan.IsActive))
 
 		// Добавляем кнопку отмены
 		keyboard := tgbotapi.NewInlineKeyboardMarkup(
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("❌ Отменить редактирование", "plan:view:"+strconv.Itoa(planID)),
+				h.button(chatID, h.t(chatID, "admin_plans.cancel_edit"), "plan:view:"+strconv.Itoa(planID)),
 			),
 		)
 
 		msgConfig := tgbotapi.NewMessage(chatID, msg)
 		msgConfig.ParseMode = "Markdown"
 		msgConfig.ReplyMarkup = keyboard
-		h.bot.Send(msgConfig)
+		h.sender.Send(msgConfig)
 
 	case "delete":
 		// Запрашиваем подтверждение удаления плана
 		plan, err := h.db.GetSubscriptionPlanByID(planID)
 		if err != nil {
-			msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Ошибка при получении информации о плане: %v", err))
-			h.bot.Send(msg)
+			msg := tgbotapi.NewMessage(chatID, h.t(chatID, "common.plan_error", err))
+			h.sender.Send(msg)
 			return
 		}
 
 		if plan == nil {
-			msg := tgbotapi.NewMessage(chatID, "План подписки не найден.")
-			h.bot.Send(msg)
+			msg := tgbotapi.NewMessage(chatID, h.t(chatID, "admin_plans.not_found"))
+			h.sender.Send(msg)
 			return
 		}
 
-		confirmMsg := fmt.Sprintf(
-			"Вы действительно хотите удалить план *%s*?\n\n"+
-				"⚠️ Внимание: Это действие не повлияет на существующие подписки, но сделает план недоступным для покупки новым пользователям.",
-			plan.Name,
-		)
+		confirmMsg := h.t(chatID, "admin_plans.confirm_delete", plan.Name)
 
 		keyboard := tgbotapi.NewInlineKeyboardMarkup(
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("✅ Да, удалить", fmt.Sprintf("plan_action:confirm_delete:%d", planID)),
-				tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", "admin_menu:plans"),
+				h.button(chatID, h.t(chatID, "admin.confirm_delete"), fmt.Sprintf("plan_action:confirm_delete:%d", planID)),
+				h.button(chatID, h.t(chatID, "admin.cancel"), "admin_menu:plans"),
 			),
 		)
 
 		msg := tgbotapi.NewMessage(chatID, confirmMsg)
 		msg.ParseMode = "Markdown"
 		msg.ReplyMarkup = keyboard
-		h.bot.Send(msg)
+		h.sender.Send(msg)
+
+	case "toggle_trial":
+		// Переключаем признак пробного плана
+		plan, err := h.db.GetSubscriptionPlanByID(planID)
+		if err != nil {
+			h.sendMessage(chatID, h.t(chatID, "common.plan_error", err))
+			return
+		}
+
+		if err := h.db.SetSubscriptionPlanTrial(planID, !plan.IsTrial); err != nil {
+			h.sendMessage(chatID, h.t(chatID, "admin_plans.update_error", err))
+			return
+		}
+
+		if after, err := h.db.GetSubscriptionPlanByID(planID); err == nil {
+			h.audit(actorTelegramID, models.AuditPlanEdit, models.AuditTargetPlan, planID, plan, after)
+		}
+
+		h.viewPlanDetails(chatID, planID)
+
+	case "max_pause":
+		// Запрашиваем максимальную длительность паузы
+		h.startPlanLimitEdit(chatID, planID, planLimitMaxPause)
+
+	case "grace_days":
+		// Запрашиваем длительность льготного периода
+		h.startPlanLimitEdit(chatID, planID, planLimitGraceDays)
+
+	case "prices":
+		// Показываем цены плана в других валютах
+		h.showPlanPrices(chatID, planID)
 
 	case "confirm_delete":
 		// Удаляем план подписки
+		plan, err := h.db.GetSubscriptionPlanByID(planID)
+		if err != nil {
+			h.sendMessage(chatID, h.t(chatID, "common.plan_error", err))
+			return
+		}
+
 		if err := h.db.DeleteSubscriptionPlan(planID); err != nil {
-			msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Ошибка при удалении плана: %v", err))
-			h.bot.Send(msg)
+			msg := tgbotapi.NewMessage(chatID, h.t(chatID, "admin_plans.delete_error", err))
+			h.sender.Send(msg)
 			return
 		}
 
-		msg := tgbotapi.NewMessage(chatID, "✅ План подписки успешно удален.")
-		h.bot.Send(msg)
+		h.audit(actorTelegramID, models.AuditPlanDelete, models.AuditTargetPlan, planID, plan, nil)
+
+		msg := tgbotapi.NewMessage(chatID, h.t(chatID, "admin_plans.deleted"))
+		h.sender.Send(msg)
 
 		// Возвращаемся к списку планов
 		h.listSubscriptionPlans(chatID)
@@ -1886,23 +1821,23 @@ func (h *BotHandler) handlePlanAction(chatID int64, action string, planID int) {
 			State: "add_plan_name",
 			Data:  make(map[string]string),
 		}
-		h.userStates[chatID] = userState
+		h.setState(chatID, userState)
 
 		// Добавляем кнопку отмены
 		keyboard := tgbotapi.NewInlineKeyboardMarkup(
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("❌ Отменить", "admin_menu:plans"),
+				h.button(chatID, h.t(chatID, "admin.cancel"), "admin_menu:plans"),
 			),
 		)
 
-		msgConfig := tgbotapi.NewMessage(chatID, "➕ *Добавление нового плана подписки*\n\nВведите название плана:")
+		msgConfig := tgbotapi.NewMessage(chatID, h.t(chatID, "admin_plans.add_start"))
 		msgConfig.ParseMode = "Markdown"
 		msgConfig.ReplyMarkup = keyboard
-		h.bot.Send(msgConfig)
+		h.sender.Send(msgConfig)
 
 	default:
-		msg := tgbotapi.NewMessage(chatID, "Неизвестное действие с планом.")
-		h.bot.Send(msg)
+		msg := tgbotapi.NewMessage(chatID, h.t(chatID, "admin_plans.unknown_action"))
+		h.sender.Send(msg)
 	}
 }
 
@@ -1922,39 +1857,41 @@ func (h *BotHandler) handleServerAction(chatID int64, action string, serverID in
 		// Получаем детальную информацию о сервере
 		server, err := h.db.GetServerByID(serverID)
 		if err != nil {
-			responseText = fmt.Sprintf("Ошибка: не удалось найти сервер #%d", serverID)
+			responseText = h.t(chatID, "admin_servers.not_found_id", serverID)
 			break
 		}
 
 		// Формируем сообщение с информацией о сервере
-		responseText = fmt.Sprintf("🖥️ *Информация о сервере #%d*\n\n", server.ID)
-		responseText += fmt.Sprintf("IP: `%s`\n", server.IP)
-		responseText += fmt.Sprintf("Порт: `%d`\n", server.Port)
-		responseText += fmt.Sprintf("SSH пользователь: `%s`\n", server.SSHUser)
-		responseText += fmt.Sprintf("Максимум клиентов: `%d`\n", server.MaxClients)
-		responseText += fmt.Sprintf("Текущих клиентов: `%d`\n", server.CurrentClients)
-		responseText += fmt.Sprintf("Статус: %s\n", getStatusEmoji(server.IsActive))
-		responseText += fmt.Sprintf("Создан: `%s`\n", server.CreatedAt.Format("02.01.2006 15:04:05"))
-		responseText += fmt.Sprintf("Обновлен: `%s`\n", server.UpdatedAt.Format("02.01.2006 15:04:05"))
+		responseText = h.t(chatID, "admin_servers.details",
+			server.ID,
+			server.IP,
+			server.Port,
+			server.SSHUser,
+			server.MaxClients,
+			server.CurrentClients,
+			getStatusEmoji(server.IsActive),
+			server.CreatedAt.Format("02.01.2006 15:04:05"),
+			server.UpdatedAt.Format("02.01.2006 15:04:05"),
+		)
 
 		// Создаем клавиатуру с кнопками действий
 		keyboard := tgbotapi.NewInlineKeyboardMarkup(
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("🔍 Проверить доступность", fmt.Sprintf("server_action:check:%d", server.ID)),
+				h.button(chatID, h.t(chatID, "admin_servers.check"), fmt.Sprintf("server_action:check:%d", server.ID)),
 			),
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("📝 Редактировать", fmt.Sprintf("server_action:edit:%d", server.ID)),
-				tgbotapi.NewInlineKeyboardButtonData("❌ Удалить", fmt.Sprintf("server_action:delete:%d", server.ID)),
+				h.button(chatID, h.t(chatID, "admin.edit"), fmt.Sprintf("server_action:edit:%d", server.ID)),
+				h.button(chatID, h.t(chatID, "admin.delete"), fmt.Sprintf("server_action:delete:%d", server.ID)),
 			),
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("◀️ Назад к списку серверов", "admin_menu:servers"),
+				h.button(chatID, h.t(chatID, "admin_servers.back_to_list"), "admin_menu:servers"),
 			),
 		)
 
 		msg := tgbotapi.NewMessage(chatID, responseText)
 		msg.ParseMode = "Markdown"
 		msg.ReplyMarkup = keyboard
-		h.bot.Send(msg)
+		h.sender.Send(msg)
 		return
 
 	case "check":
@@ -1963,61 +1900,15 @@ func (h *BotHandler) handleServerAction(chatID int64, action string, serverID in
 		return
 
 	case "edit":
-		// Получаем информацию о сервере для редактирования
-		server, err := h.db.GetServerByID(serverID)
-		if err != nil {
-			responseText = fmt.Sprintf("Ошибка: не удалось найти сервер #%d", serverID)
-			break
-		}
-
-		// Сохраняем ID сервера в сессии пользователя
-		h.userStates[chatID] = UserState{
-			State: "editing_server",
-			Data: map[string]string{
-				"server_id": strconv.Itoa(serverID),
-			},
-		}
-
-		// Формируем сообщение для редактирования
-		responseText = fmt.Sprintf("📝 *Редактирование сервера #%d*\n\n", server.ID)
-		responseText += "Выберите, что хотите изменить:\n\n"
-		responseText += fmt.Sprintf("1. IP: `%s`\n", server.IP)
-		responseText += fmt.Sprintf("2. Порт: `%d`\n", server.Port)
-		responseText += fmt.Sprintf("3. SSH пользователь: `%s`\n", server.SSHUser)
-		responseText += fmt.Sprintf("4. SSH пароль: `%s`\n", maskPassword(server.SSHPassword))
-		responseText += fmt.Sprintf("5. Максимум клиентов: `%d`\n", server.MaxClients)
-		responseText += fmt.Sprintf("6. Статус: %s\n", getStatusEmoji(server.IsActive))
-
-		// Создаем клавиатуру с кнопками для редактирования
-		keyboard := tgbotapi.NewInlineKeyboardMarkup(
-			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("1️⃣ IP", fmt.Sprintf("server_edit:ip:%d", server.ID)),
-				tgbotapi.NewInlineKeyboardButtonData("2️⃣ Порт", fmt.Sprintf("server_edit:port:%d", server.ID)),
-			),
-			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("3️⃣ SSH пользователь", fmt.Sprintf("server_edit:user:%d", server.ID)),
-				tgbotapi.NewInlineKeyboardButtonData("4️⃣ SSH пароль", fmt.Sprintf("server_edit:pass:%d", server.ID)),
-			),
-			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("5️⃣ Макс. клиентов", fmt.Sprintf("server_edit:max:%d", server.ID)),
-				tgbotapi.NewInlineKeyboardButtonData("6️⃣ Статус", fmt.Sprintf("server_edit:status:%d", server.ID)),
-			),
-			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("◀️ Назад к серверу", fmt.Sprintf("server_action:view:%d", server.ID)),
-			),
-		)
-
-		msg := tgbotapi.NewMessage(chatID, responseText)
-		msg.ParseMode = "Markdown"
-		msg.ReplyMarkup = keyboard
-		h.bot.Send(msg)
+		// Показываем параметры сервера для редактирования
+		h.showServerEditMenu(chatID, serverID)
 		return
 
 	case "delete":
 		// Получаем информацию о сервере для удаления
 		server, err := h.db.GetServerByID(serverID)
 		if err != nil {
-			responseText = fmt.Sprintf("Ошибка: не удалось найти сервер #%d", serverID)
+			responseText = h.t(chatID, "admin_servers.not_found_id", serverID)
 			break
 		}
 
@@ -2026,95 +1917,91 @@ func (h *BotHandler) handleServerAction(chatID int64, action string, serverID in
 		err = h.db.DB.Get(&subscriptionsCount, "SELECT COUNT(*) FROM subscriptions WHERE server_id = $1 AND status = 'active'", serverID)
 		if err != nil {
 			log.Printf("Ошибка при проверке подписок сервера: %v", err)
-			responseText = "Ошибка при проверке подписок сервера"
+			responseText = h.t(chatID, "admin_servers.subscriptions_error")
 			break
 		}
 
 		if subscriptionsCount > 0 {
-			responseText = fmt.Sprintf("❌ Невозможно удалить сервер #%d, так как на нем есть %d активных подписок.\n\nСначала переместите или отмените все подписки на этом сервере.", serverID, subscriptionsCount)
+			responseText = h.t(chatID, "admin_servers.has_subscriptions", serverID, subscriptionsCount)
 
 			// Добавляем кнопку для возврата
 			keyboard := tgbotapi.NewInlineKeyboardMarkup(
 				tgbotapi.NewInlineKeyboardRow(
-					tgbotapi.NewInlineKeyboardButtonData("◀️ Назад к серверу", fmt.Sprintf("server_action:view:%d", server.ID)),
+					h.button(chatID, h.t(chatID, "admin_servers.back_to_server"), fmt.Sprintf("server_action:view:%d", server.ID)),
 				),
 			)
 
 			msg := tgbotapi.NewMessage(chatID, responseText)
 			msg.ReplyMarkup = keyboard
-			h.bot.Send(msg)
+			h.sender.Send(msg)
 			return
 		}
 
 		// Запрашиваем подтверждение удаления
-		responseText = fmt.Sprintf("❓ Вы действительно хотите удалить сервер #%d (%s)?\n\nЭто действие нельзя отменить.", serverID, server.IP)
+		responseText = h.t(chatID, "admin_servers.confirm_delete", serverID, server.IP)
 
 		// Добавляем кнопки подтверждения
 		keyboard := tgbotapi.NewInlineKeyboardMarkup(
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("✅ Да, удалить", fmt.Sprintf("server_confirm_delete:%d", server.ID)),
-				tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", fmt.Sprintf("server_action:view:%d", server.ID)),
+				h.button(chatID, h.t(chatID, "admin.confirm_delete"), fmt.Sprintf("server_confirm_delete:%d", server.ID)),
+				h.button(chatID, h.t(chatID, "admin.cancel"), fmt.Sprintf("server_action:view:%d", server.ID)),
 			),
 		)
 
 		msg := tgbotapi.NewMessage(chatID, responseText)
 		msg.ReplyMarkup = keyboard
-		h.bot.Send(msg)
+		h.sender.Send(msg)
 		return
 
 	default:
-		responseText = fmt.Sprintf("Неизвестное действие '%s' для сервера #%d", action, serverID)
+		responseText = h.t(chatID, "admin_servers.unknown_action", action, serverID)
 	}
 
 	// Отправляем ответ пользователю
 	msg := tgbotapi.NewMessage(chatID, responseText)
-	h.bot.Send(msg)
+	h.sender.Send(msg)
 }
 
 // startServerAddition начинает процесс добавления нового сервера
 func (h *BotHandler) startServerAddition(chatID int64) {
 	// Сохраняем состояние пользователя
-	h.userStates[chatID] = UserState{
+	h.setState(chatID, UserState{
 		State: "add_server_ip",
 		Data: map[string]string{
 			"port":        "22",
 			"max_clients": "10",
 			"is_active":   "true",
 		},
-	}
+	})
 
 	// Отправляем сообщение пользователю
-	responseText := "🖥️ *Добавление нового сервера*\n\n"
-	responseText += "Введите IP-адрес сервера:\n"
-	responseText += "_(например, 123.45.67.89)_"
-
-	msg := tgbotapi.NewMessage(chatID, responseText)
+	msg := tgbotapi.NewMessage(chatID, h.t(chatID, "admin_servers.add_start"))
 	msg.ParseMode = "Markdown"
 
 	// Добавляем кнопку отмены
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", "admin_menu:servers"),
+			h.button(chatID, h.t(chatID, "admin.cancel"), "admin_menu:servers"),
 		),
 	)
 	msg.ReplyMarkup = keyboard
 
-	h.bot.Send(msg)
+	h.sender.Send(msg)
 }
 
 // handleServerConfirmDelete обрабатывает подтверждение удаления сервера
-func (h *BotHandler) handleServerConfirmDelete(chatID int64, serverID int) {
+func (h *BotHandler) handleServerConfirmDelete(chatID int64, actorTelegramID int64, serverID int) {
 	// Получаем информацию о сервере
 	server, err := h.db.GetServerByID(serverID)
 	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Ошибка: не удалось найти сервер #%d", serverID))
-		h.bot.Send(msg)
+		msg := tgbotapi.NewMessage(chatID, h.t(chatID, "admin_servers.not_found_id", serverID))
+		h.sender.Send(msg)
 		return
 	}
 
 	// Отправляем сообщение о начале удаления
-	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("🗑️ Удаление сервера #%d (%s)...", serverID, server.IP))
-	sentMsg, _ := h.bot.Send(msg)
+	msg := tgbotapi.NewMessage(chatID, h.t(chatID, "admin_servers.deleting", serverID, server.IP))
+	sentMsg, _ := h.sender.Send(msg)
 
 	// Удаляем сервер из базы данных
 	err = h.db.DeleteServer(serverID)
@@ -2122,27 +2009,29 @@ func (h *BotHandler) handleServerConfirmDelete(chatID int64, serverID int) {
 		editMsg := tgbotapi.NewEditMessageText(
 			chatID,
 			sentMsg.MessageID,
-			fmt.Sprintf("❌ Ошибка при удалении сервера #%d: %v", serverID, err),
+			h.t(chatID, "admin_servers.delete_error", serverID, err),
 		)
-		h.bot.Send(editMsg)
+		h.sender.Send(editMsg)
 		return
 	}
 
+	h.audit(actorTelegramID, models.AuditServerDelete, models.AuditTargetServer, serverID, server, nil)
+
 	// Добавляем кнопку возврата к списку серверов
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("◀️ Назад к списку серверов", "admin_menu:servers"),
+			h.button(chatID, h.t(chatID, "admin_servers.back_to_list"), "admin_menu:servers"),
 		),
 	)
 
 	editMsgWithKeyboard := tgbotapi.NewEditMessageTextAndMarkup(
 		chatID,
 		sentMsg.MessageID,
-		fmt.Sprintf("✅ Сервер #%d (%s) успешно удален", serverID, server.IP),
+		h.t(chatID, "admin_servers.deleted", serverID, server.IP),
 		keyboard,
 	)
 
-	h.bot.Send(editMsgWithKeyboard)
+	h.sender.Send(editMsgWithKeyboard)
 }
 
 // maskPassword маскирует пароль, оставляя видимыми только первый и последний символы
@@ -2162,22 +2051,22 @@ func getStatusEmoji(isActive bool) string {
 	return "🔴"
 }
 
-// getStatusText преобразует булево значение активности в текст статуса
-func getStatusText(isActive bool) string {
+// activeText преобразует булево значение активности в текст статуса на языке пользователя chatID
+func (h *BotHandler) activeText(chatID int64, isActive bool) string {
 	if isActive {
-		return "Активен"
+		return h.t(chatID, "admin.active")
 	}
-	return "Неактивен"
+	return h.t(chatID, "admin.inactive")
 }
 
 // handleSubscriptionAction обрабатывает действия с подписками
-func (h *BotHandler) handleSubscriptionAction(chatID int64, action string, subscriptionID int) {
+func (h *BotHandler) handleSubscriptionAction(chatID int64, actorTelegramID int64, action string, subscriptionID int) {
 	// Получаем информацию о подписке
 	subscription, err := h.db.GetSubscriptionByID(subscriptionID)
 	if err != nil {
 		log.Printf("Ошибка при получении информации о подписке #%d: %v", subscriptionID, err)
-		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Ошибка: не удалось найти подписку #%d", subscriptionID))
-		h.bot.Send(msg)
+		msg := tgbotapi.NewMessage(chatID, h.t(chatID, "admin_subs.not_found", subscriptionID))
+		h.sender.Send(msg)
 		return
 	}
 
@@ -2185,8 +2074,8 @@ func (h *BotHandler) handleSubscriptionAction(chatID int64, action string, subsc
 	user, err := h.db.GetUserByID(subscription.UserID)
 	if err != nil {
 		log.Printf("Ошибка при получении информации о пользователе #%d: %v", subscription.UserID, err)
-		msg := tgbotapi.NewMessage(chatID, "Ошибка: не удалось найти пользователя подписки")
-		h.bot.Send(msg)
+		msg := tgbotapi.NewMessage(chatID, h.t(chatID, "admin_subs.user_not_found"))
+		h.sender.Send(msg)
 		return
 	}
 
@@ -2194,168 +2083,97 @@ func (h *BotHandler) handleSubscriptionAction(chatID int64, action string, subsc
 	plan, err := h.db.GetSubscriptionPlanByID(subscription.PlanID)
 	if err != nil {
 		log.Printf("Ошибка при получении информации о плане #%d: %v", subscription.PlanID, err)
-		msg := tgbotapi.NewMessage(chatID, "Ошибка: не удалось найти план подписки")
-		h.bot.Send(msg)
-		return
-	}
-
-	// Получаем сервер
-	server, err := h.db.GetServerByID(subscription.ServerID)
-	if err != nil {
-		log.Printf("Ошибка при получении информации о сервере #%d: %v", subscription.ServerID, err)
-		msg := tgbotapi.NewMessage(chatID, "Ошибка: не удалось найти сервер подписки")
-		h.bot.Send(msg)
+		msg := tgbotapi.NewMessage(chatID, h.t(chatID, "admin_subs.plan_not_found"))
+		h.sender.Send(msg)
 		return
 	}
 
 	// Отправляем сообщение о том, что начали обработку
-	processingMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("⏳ Выполняется операция с подпиской #%d пользователя %s...",
-		subscriptionID, user.Username))
-	sentMsg, _ := h.bot.Send(processingMsg)
+	processingMsg := tgbotapi.NewMessage(chatID, h.t(chatID, "admin_subs.processing", subscriptionID, user.Username))
+	sentMsg, _ := h.sender.Send(processingMsg)
 
 	var responseText string
 
 	switch action {
 	case "block":
-		// Проверяем, заблокирована ли уже подписка (с таймаутом)
 		log.Printf("Отправка команды блокировки для подписки #%d", subscriptionID)
+		err := h.subscriptions.Block(subscription)
+		switch {
+		case errors.Is(err, service.ErrPeerTimeout):
+			responseText = h.t(chatID, "admin_subs.block_timeout", subscriptionID)
+		case err != nil:
+			responseText = h.t(chatID, "admin_subs.block_error", subscriptionID)
+		default:
+			log.Printf("Подписка #%d успешно заблокирована", subscriptionID)
+			h.audit(actorTelegramID, models.AuditSubscriptionBlock, models.AuditTargetSubscription, subscriptionID,
+				map[string]interface{}{"status": subscription.Status, "blocked": false},
+				map[string]interface{}{"status": subscription.Status, "blocked": true})
+			responseText = h.t(chatID, "admin_subs.blocked", subscriptionID, user.Username)
 
-		// Создаем канал для обработки таймаута
-		done := make(chan bool, 1)
-		var blockErr error
-
-		// Запускаем операцию в отдельной горутине
-		go func() {
-			err := h.vpnManager.BlockClient(server, subscription.ConfigFilePath)
-			if err != nil {
-				blockErr = err
-			}
-			done <- true
-		}()
-
-		// Устанавливаем таймаут 10 секунд
-		select {
-		case <-done:
-			if blockErr != nil {
-				log.Printf("Ошибка при блокировке подписки #%d: %v", subscriptionID, blockErr)
-				responseText = fmt.Sprintf("❌ Ошибка при блокировке подписки #%d: не удалось подключиться к серверу VPN.\n\nВозможно, сервер временно недоступен. Пожалуйста, повторите попытку позже.", subscriptionID)
-			} else {
-				log.Printf("Подписка #%d успешно заблокирована", subscriptionID)
-				responseText = fmt.Sprintf("✅ Подписка #%d пользователя %s успешно заблокирована", subscriptionID, user.Username)
-
-				// Отправляем уведомление пользователю о блокировке
-				userMsg := fmt.Sprintf("❗ Ваша подписка #%d (%s) была заблокирована администратором", subscriptionID, plan.Name)
-				notificationMsg := tgbotapi.NewMessage(user.TelegramID, userMsg)
-				h.bot.Send(notificationMsg)
-			}
-		case <-time.After(10 * time.Second):
-			log.Printf("Таймаут при блокировке подписки #%d", subscriptionID)
-			responseText = fmt.Sprintf("⚠️ Превышено время ожидания при попытке заблокировать подписку #%d.\n\nСервер VPN не отвечает. Попробуйте повторить операцию позже.", subscriptionID)
+			// Отправляем уведомление пользователю о блокировке
+			userMsg := i18n.T(userLang(user), "subscription.blocked_notice", subscriptionID, plan.Name)
+			h.sender.Send(tgbotapi.NewMessage(user.TelegramID, userMsg))
 		}
 
 	case "unblock":
 		log.Printf("Отправка команды разблокировки для подписки #%d", subscriptionID)
+		err := h.subscriptions.Unblock(subscription)
+		switch {
+		case errors.Is(err, service.ErrPeerTimeout):
+			responseText = h.t(chatID, "admin_subs.unblock_timeout", subscriptionID)
+		case err != nil:
+			responseText = h.t(chatID, "admin_subs.unblock_error", subscriptionID)
+		default:
+			log.Printf("Подписка #%d успешно разблокирована", subscriptionID)
+			h.audit(actorTelegramID, models.AuditSubscriptionUnblock, models.AuditTargetSubscription, subscriptionID,
+				map[string]interface{}{"status": subscription.Status, "blocked": true},
+				map[string]interface{}{"status": subscription.Status, "blocked": false})
+			responseText = h.t(chatID, "admin_subs.unblocked", subscriptionID, user.Username)
 
-		// Создаем канал для обработки таймаута
-		done := make(chan bool, 1)
-		var unblockErr error
-
-		// Запускаем операцию в отдельной горутине
-		go func() {
-			err := h.vpnManager.UnblockClient(server, subscription.ConfigFilePath)
-			if err != nil {
-				unblockErr = err
-			}
-			done <- true
-		}()
-
-		// Устанавливаем таймаут 10 секунд
-		select {
-		case <-done:
-			if unblockErr != nil {
-				log.Printf("Ошибка при разблокировке подписки #%d: %v", subscriptionID, unblockErr)
-				responseText = fmt.Sprintf("❌ Ошибка при разблокировке подписки #%d: не удалось подключиться к серверу VPN.\n\nВозможно, сервер временно недоступен. Пожалуйста, повторите попытку позже.", subscriptionID)
-			} else {
-				log.Printf("Подписка #%d успешно разблокирована", subscriptionID)
-				responseText = fmt.Sprintf("✅ Подписка #%d пользователя %s успешно разблокирована", subscriptionID, user.Username)
-
-				// Отправляем уведомление пользователю о разблокировке
-				userMsg := fmt.Sprintf("✅ Ваша подписка #%d (%s) была разблокирована администратором", subscriptionID, plan.Name)
-				notificationMsg := tgbotapi.NewMessage(user.TelegramID, userMsg)
-				h.bot.Send(notificationMsg)
-			}
-		case <-time.After(10 * time.Second):
-			log.Printf("Таймаут при разблокировке подписки #%d", subscriptionID)
-			responseText = fmt.Sprintf("⚠️ Превышено время ожидания при попытке разблокировать подписку #%d.\n\nСервер VPN не отвечает. Попробуйте повторить операцию позже.", subscriptionID)
+			// Отправляем уведомление пользователю о разблокировке
+			userMsg := i18n.T(userLang(user), "subscription.unblocked_notice", subscriptionID, plan.Name)
+			h.sender.Send(tgbotapi.NewMessage(user.TelegramID, userMsg))
 		}
 
 	case "delete":
-		log.Printf("Отзыв конфигурации для клиента %s (файл: %s)",
-			subscription.ConfigFilePath, subscription.ConfigFilePath)
+		log.Printf("Отзыв конфигурации подписки #%d (файл: %s)", subscriptionID, subscription.ConfigFilePath)
+		oldStatus := subscription.Status
+
+		// Подписка помечается отозванной только после удаления пира на сервере VPN
+		err := h.subscriptions.Revoke(subscription, "Отозвана администратором")
+		switch {
+		case errors.Is(err, service.ErrInvalidTransition):
+			responseText = h.t(chatID, "admin_subs.revoke_invalid", subscriptionID, oldStatus)
+		case errors.Is(err, service.ErrPeerTimeout):
+			responseText = h.t(chatID, "admin_subs.revoke_timeout", subscriptionID, user.Username)
+		case errors.Is(err, service.ErrPeerUnavailable):
+			responseText = h.t(chatID, "admin_subs.revoke_peer_unavailable", subscriptionID, user.Username)
+		case err != nil:
+			log.Printf("Ошибка при обновлении статуса подписки #%d: %v", subscriptionID, err)
+			responseText = h.t(chatID, "admin_subs.status_error", subscriptionID)
+		default:
+			responseText = h.t(chatID, "admin_subs.revoked", subscriptionID, user.Username)
 
-		// Создаем канал для обработки таймаута
-		done := make(chan bool, 1)
-		var revokeErr error
+			// Отправляем уведомление пользователю
+			userMsg := i18n.T(userLang(user), "subscription.revoked_notice", subscriptionID, plan.Name)
+			notificationMsg := tgbotapi.NewMessage(user.TelegramID, userMsg)
+			h.sender.Send(notificationMsg)
+		}
 
-		// Запускаем операцию в отдельной горутине
-		go func() {
-			err := h.vpnManager.RevokeClientConfig(server, subscription.ConfigFilePath)
-			if err != nil {
-				revokeErr = err
-			}
-			done <- true
-		}()
-
-		// Устанавливаем таймаут 10 секунд
-		select {
-		case <-done:
-			if revokeErr != nil {
-				log.Printf("Ошибка при отзыве конфигурации VPN для подписки #%d: %v", subscriptionID, revokeErr)
-				// Всё равно меняем статус подписки на отозванный
-				subscription.Status = "revoked"
-				err = h.db.UpdateSubscription(subscription)
-				if err != nil {
-					log.Printf("Ошибка при обновлении статуса подписки #%d: %v", subscriptionID, err)
-					responseText = fmt.Sprintf("❌ Ошибка при обновлении статуса подписки. Сервер VPN недоступен.")
-				} else {
-					responseText = fmt.Sprintf("⚠️ Подписка #%d пользователя %s помечена как отозванная, но сервер VPN недоступен. Конфигурация клиента будет отозвана автоматически, когда сервер станет доступен.", subscriptionID, user.Username)
-				}
-			} else {
-				// Обновляем статус подписки на отозванный
-				subscription.Status = "revoked"
-				err = h.db.UpdateSubscription(subscription)
-				if err != nil {
-					log.Printf("Ошибка при обновлении статуса подписки #%d: %v", subscriptionID, err)
-					responseText = fmt.Sprintf("❌ Подписка отозвана на сервере, но произошла ошибка при обновлении статуса в базе данных")
-				} else {
-					responseText = fmt.Sprintf("✅ Подписка #%d пользователя %s успешно отозвана", subscriptionID, user.Username)
-
-					// Отправляем уведомление пользователю
-					userMsg := fmt.Sprintf("❗ Ваша подписка #%d (%s) была отозвана администратором", subscriptionID, plan.Name)
-					notificationMsg := tgbotapi.NewMessage(user.TelegramID, userMsg)
-					h.bot.Send(notificationMsg)
-				}
-			}
-		case <-time.After(10 * time.Second):
-			log.Printf("Таймаут при отзыве подписки #%d", subscriptionID)
-			// Всё равно меняем статус подписки на отозванный
-			subscription.Status = "revoked"
-			err = h.db.UpdateSubscription(subscription)
-			if err != nil {
-				log.Printf("Ошибка при обновлении статуса подписки #%d: %v", subscriptionID, err)
-				responseText = fmt.Sprintf("❌ Ошибка при обновлении статуса подписки. Сервер VPN не отвечает.")
-			} else {
-				responseText = fmt.Sprintf("⚠️ Превышено время ожидания при отзыве подписки #%d, но она помечена как отозванная в базе данных. Конфигурация клиента будет отозвана автоматически, когда сервер станет доступен.", subscriptionID)
-			}
+		// Записываем отзыв в журнал, если статус подписки удалось обновить
+		if subscription.Status == service.StatusRevoked {
+			h.audit(actorTelegramID, models.AuditSubscriptionRevoke, models.AuditTargetSubscription, subscriptionID,
+				map[string]interface{}{"status": oldStatus},
+				map[string]interface{}{"status": subscription.Status})
 		}
 
 	default:
-		responseText = fmt.Sprintf("Неизвестное действие '%s' для подписки #%d", action, subscriptionID)
+		responseText = h.t(chatID, "admin_subs.unknown_action", action, subscriptionID)
 	}
 
 	// Отправляем ответ администратору (редактируем предыдущее сообщение)
 	editMsg := tgbotapi.NewEditMessageText(chatID, sentMsg.MessageID, responseText)
-	h.bot.Send(editMsg)
+	h.sender.Send(editMsg)
 }
 
 // handleUserAction обрабатывает действия с пользователями
@@ -2366,8 +2184,8 @@ func (h *BotHandler) handleUserAction(chatID int64, action string, userID int) {
 	user, err := h.db.GetUserByID(userID)
 	if err != nil {
 		log.Printf("Ошибка при получении информации о пользователе #%d: %v", userID, err)
-		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Ошибка: не удалось найти пользователя #%d", userID))
-		h.bot.Send(msg)
+		msg := tgbotapi.NewMessage(chatID, h.t(chatID, "admin_users.not_found", userID))
+		h.sender.Send(msg)
 		return
 	}
 
@@ -2380,8 +2198,8 @@ func (h *BotHandler) handleUserAction(chatID int64, action string, userID int) {
 		subscriptions, err := h.db.GetSubscriptionsByUserID(userID)
 		if err != nil {
 			log.Printf("Ошибка при получении подписок пользователя #%d: %v", userID, err)
-			msg := tgbotapi.NewMessage(chatID, "Ошибка при получении подписок пользователя")
-			h.bot.Send(msg)
+			msg := tgbotapi.NewMessage(chatID, h.t(chatID, "admin_users.subscriptions_error"))
+			h.sender.Send(msg)
 			return
 		}
 
@@ -2389,13 +2207,13 @@ func (h *BotHandler) handleUserAction(chatID int64, action string, userID int) {
 
 		if len(subscriptions) == 0 {
 			log.Printf("У пользователя #%d нет подписок", userID)
-			msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("У пользователя %s нет подписок", user.Username))
-			h.bot.Send(msg)
+			msg := tgbotapi.NewMessage(chatID, h.t(chatID, "admin_users.no_subscriptions", user.Username))
+			h.sender.Send(msg)
 			return
 		}
 
 		// Формируем сообщение с подписками
-		messageText := fmt.Sprintf("📋 Подписки пользователя %s:\n\n", user.Username)
+		messageText := h.t(chatID, "admin_users.subscriptions_header", user.Username)
 
 		for i, subscription := range subscriptions {
 			// Получаем план подписки
@@ -2422,14 +2240,14 @@ func (h *BotHandler) handleUserAction(chatID int64, action string, userID int) {
 			// Просто добавляем заметку, что статус может быть неточным
 			blockedStatus := ""
 			if subscription.Status == "active" {
-				blockedStatus = " [статус блокировки: неизвестен]"
+				blockedStatus = h.t(chatID, "admin_users.block_status_unknown")
 			}
 
 			// Форматируем дату
 			endDateStr := subscription.EndDate.Format("02.01.2006")
 
 			// Добавляем информацию о подписке
-			messageText += fmt.Sprintf("%d. #%d - %s %s%s\n   План: %s\n   Дата окончания: %s\n\n",
+			messageText += h.t(chatID, "admin_users.subscription_item",
 				i+1, subscription.ID, statusEmoji, subscription.Status, blockedStatus, plan.Name, endDateStr)
 		}
 
@@ -2440,12 +2258,12 @@ func (h *BotHandler) handleUserAction(chatID int64, action string, userID int) {
 			// Если подписка активна, добавляем кнопки для блокировки/разблокировки и удаления
 			if subscription.Status == "active" {
 				// Не проверяем статус блокировки - предлагаем обе кнопки
-				blockButton := tgbotapi.NewInlineKeyboardButtonData(
-					fmt.Sprintf("🔒 Заблокировать #%d", subscription.ID),
+				blockButton := h.button(chatID,
+					h.t(chatID, "admin_users.block_subscription", subscription.ID),
 					fmt.Sprintf("subscription_action:block:%d", subscription.ID),
 				)
-				unblockButton := tgbotapi.NewInlineKeyboardButtonData(
-					fmt.Sprintf("🔓 Разблокировать #%d", subscription.ID),
+				unblockButton := h.button(chatID,
+					h.t(chatID, "admin_users.unblock_subscription", subscription.ID),
 					fmt.Sprintf("subscription_action:unblock:%d", subscription.ID),
 				)
 
@@ -2454,8 +2272,8 @@ func (h *BotHandler) handleUserAction(chatID int64, action string, userID int) {
 				keyboardButtons = append(keyboardButtons, []tgbotapi.InlineKeyboardButton{unblockButton})
 
 				// Добавляем кнопку удаления
-				deleteButton := tgbotapi.NewInlineKeyboardButtonData(
-					fmt.Sprintf("❌ Удалить #%d", subscription.ID),
+				deleteButton := h.button(chatID,
+					h.t(chatID, "admin_users.delete_subscription", subscription.ID),
 					fmt.Sprintf("subscription_action:delete:%d", subscription.ID),
 				)
 				keyboardButtons = append(keyboardButtons, []tgbotapi.InlineKeyboardButton{deleteButton})
@@ -2463,7 +2281,7 @@ func (h *BotHandler) handleUserAction(chatID int64, action string, userID int) {
 		}
 
 		// Добавляем кнопку "Назад"
-		backButton := tgbotapi.NewInlineKeyboardButtonData("🔙 Назад", "admin_menu:users")
+		backButton := h.button(chatID, h.t(chatID, "admin.back_button"), fmt.Sprintf("user_card:%d", user.ID))
 		keyboardButtons = append(keyboardButtons, []tgbotapi.InlineKeyboardButton{backButton})
 
 		// Создаем клавиатуру
@@ -2474,95 +2292,69 @@ func (h *BotHandler) handleUserAction(chatID int64, action string, userID int) {
 		msg.ReplyMarkup = keyboard
 
 		log.Printf("Отправка сообщения с подписками для пользователя #%d. Длина сообщения: %d символов", userID, len(messageText))
-		sentMsg, err := h.bot.Send(msg)
+		sentMsg, err := h.sender.Send(msg)
 		if err != nil {
 			log.Printf("Ошибка при отправке сообщения с подписками: %v", err)
 		} else {
 			log.Printf("Сообщение с подписками успешно отправлено, message_id=%d", sentMsg.MessageID)
 		}
 
-	case "make_admin":
-		// Назначаем пользователя администратором
-		err = h.db.SetUserAdmin(userID, true)
-		if err != nil {
-			log.Printf("Ошибка при назначении пользователя #%d администратором: %v", userID, err)
-			msg := tgbotapi.NewMessage(chatID, "Ошибка при назначении пользователя администратором")
-			h.bot.Send(msg)
-			return
-		}
-
-		// Отправляем уведомление пользователю
-		userMsg := "✅ Вам были предоставлены права администратора в боте. Теперь вы имеете доступ к дополнительным функциям. Используйте команду /admin для доступа к панели администратора."
-		notificationMsg := tgbotapi.NewMessage(user.TelegramID, userMsg)
-		h.bot.Send(notificationMsg)
-
-		// Отправляем ответ администратору
-		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("✅ Пользователь %s успешно назначен администратором", user.Username))
-		h.bot.Send(msg)
+	case "balance":
+		h.showUserBalanceForAdmin(chatID, user)
 
-	case "remove_admin":
-		// Снимаем права администратора
-		err = h.db.SetUserAdmin(userID, false)
-		if err != nil {
-			log.Printf("Ошибка при снятии прав администратора у пользователя #%d: %v", userID, err)
-			msg := tgbotapi.NewMessage(chatID, "Ошибка при снятии прав администратора")
-			h.bot.Send(msg)
-			return
-		}
+	case "adjust_balance":
+		h.startBalanceAdjustment(chatID, user, models.LedgerAdjustment)
 
-		// Отправляем уведомление пользователю
-		userMsg := "❗ Ваши права администратора в боте были отозваны."
-		notificationMsg := tgbotapi.NewMessage(user.TelegramID, userMsg)
-		h.bot.Send(notificationMsg)
+	case "bonus_balance":
+		h.startBalanceAdjustment(chatID, user, models.LedgerBonus)
 
-		// Отправляем ответ администратору
-		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("✅ Права администратора успешно сняты с пользователя %s", user.Username))
-		h.bot.Send(msg)
+	case "role":
+		h.showUserRoleOptions(chatID, userID)
 
 	default:
-		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Неизвестное действие для пользователя #%d", userID))
-		h.bot.Send(msg)
+		msg := tgbotapi.NewMessage(chatID, h.t(chatID, "admin_users.unknown_action", userID))
+		h.sender.Send(msg)
 	}
 }
 
 // checkServerAvailability проверяет доступность сервера и отправляет результат пользователю
 func (h *BotHandler) checkServerAvailability(chatID int64, serverID int) {
 	// Отправляем сообщение о начале проверки
-	msg := tgbotapi.NewMessage(chatID, "🔄 Проверка доступности сервера...")
-	sentMsg, _ := h.bot.Send(msg)
+	msg := tgbotapi.NewMessage(chatID, h.t(chatID, "admin_check.started"))
+	sentMsg, _ := h.sender.Send(msg)
 
 	// Создаем обновляемое сообщение
-	msgText := "🔍 Проверка доступности сервера:\n\n"
+	msgText := h.t(chatID, "admin_check.header")
 
 	// Получаем информацию о сервере из БД
 	server, err := h.db.GetServerByID(serverID)
 	if err != nil {
-		msgText += "❌ Ошибка: сервер не найден в базе данных"
+		msgText += h.t(chatID, "admin_check.not_found")
 		editMsg := tgbotapi.NewEditMessageText(chatID, sentMsg.MessageID, msgText)
-		h.bot.Send(editMsg)
+		h.sender.Send(editMsg)
 		return
 	}
 
-	msgText += fmt.Sprintf("🖥️ Сервер: %s (ID: %d)\n", server.IP, server.ID)
+	msgText += h.t(chatID, "admin_check.server", server.IP, server.ID)
 	editMsg := tgbotapi.NewEditMessageText(chatID, sentMsg.MessageID, msgText)
-	h.bot.Send(editMsg)
+	h.sender.Send(editMsg)
 
 	// Проверяем TCP-соединение
-	msgText += "🔄 Проверка TCP-соединения...\n"
+	msgText += h.t(chatID, "admin_check.tcp")
 	editMsg = tgbotapi.NewEditMessageText(chatID, sentMsg.MessageID, msgText)
-	h.bot.Send(editMsg)
+	h.sender.Send(editMsg)
 
 	timeout := 5 * time.Second
 	conn, err := net.DialTimeout("tcp", fmt.Sprintf("%s:%d", server.IP, server.Port), timeout)
 	if err != nil {
-		msgText += fmt.Sprintf("❌ TCP-соединение: Ошибка - %v\n", err)
+		msgText += h.t(chatID, "admin_check.tcp_error", err)
 		editMsg = tgbotapi.NewEditMessageText(chatID, sentMsg.MessageID, msgText)
-		h.bot.Send(editMsg)
+		h.sender.Send(editMsg)
 
 		// Добавляем кнопку для возврата к списку серверов
 		keyboard := tgbotapi.NewInlineKeyboardMarkup(
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("◀️ Назад к списку серверов", "admin_menu:servers"),
+				h.button(chatID, h.t(chatID, "admin_servers.back_to_list"), "admin_menu:servers"),
 			),
 		)
 		editMsgWithKeyboard := tgbotapi.NewEditMessageTextAndMarkup(
@@ -2571,19 +2363,19 @@ func (h *BotHandler) checkServerAvailability(chatID int64, serverID int) {
 			msgText,
 			keyboard,
 		)
-		h.bot.Send(editMsgWithKeyboard)
+		h.sender.Send(editMsgWithKeyboard)
 		return
 	}
 
 	conn.Close()
-	msgText += "✅ TCP-соединение: Установлено\n"
+	msgText += h.t(chatID, "admin_check.tcp_ok")
 	editMsg = tgbotapi.NewEditMessageText(chatID, sentMsg.MessageID, msgText)
-	h.bot.Send(editMsg)
+	h.sender.Send(editMsg)
 
 	// Проверяем SSH-соединение
-	msgText += "🔄 Проверка SSH-соединения...\n"
+	msgText += h.t(chatID, "admin_check.ssh")
 	editMsg = tgbotapi.NewEditMessageText(chatID, sentMsg.MessageID, msgText)
-	h.bot.Send(editMsg)
+	h.sender.Send(editMsg)
 
 	// Создаем клиента SSH
 	sshConfig := &ssh.ClientConfig{
@@ -2599,14 +2391,14 @@ func (h *BotHandler) checkServerAvailability(chatID int64, serverID int) {
 	addr := fmt.Sprintf("%s:%d", server.IP, server.Port)
 	sshClient, err := ssh.Dial("tcp", addr, sshConfig)
 	if err != nil {
-		msgText += fmt.Sprintf("❌ SSH-соединение: Ошибка - %v\n", err)
+		msgText += h.t(chatID, "admin_check.ssh_error", err)
 		editMsg = tgbotapi.NewEditMessageText(chatID, sentMsg.MessageID, msgText)
-		h.bot.Send(editMsg)
+		h.sender.Send(editMsg)
 
 		// Добавляем кнопку для возврата к списку серверов
 		keyboard := tgbotapi.NewInlineKeyboardMarkup(
 			tgbotapi.NewInlineKeyboardRow(
-				tgbotapi.NewInlineKeyboardButtonData("◀️ Назад к списку серверов", "admin_menu:servers"),
+				h.button(chatID, h.t(chatID, "admin_servers.back_to_list"), "admin_menu:servers"),
 			),
 		)
 		editMsgWithKeyboard := tgbotapi.NewEditMessageTextAndMarkup(
@@ -2615,23 +2407,23 @@ func (h *BotHandler) checkServerAvailability(chatID int64, serverID int) {
 			msgText,
 			keyboard,
 		)
-		h.bot.Send(editMsgWithKeyboard)
+		h.sender.Send(editMsgWithKeyboard)
 		return
 	}
 
 	defer sshClient.Close()
-	msgText += "✅ SSH-соединение: Установлено\n"
+	msgText += h.t(chatID, "admin_check.ssh_ok")
 	editMsg = tgbotapi.NewEditMessageText(chatID, sentMsg.MessageID, msgText)
-	h.bot.Send(editMsg)
+	h.sender.Send(editMsg)
 
 	// Проверяем наличие Wireguard
-	msgText += "🔄 Проверка Wireguard...\n"
+	msgText += h.t(chatID, "admin_check.wireguard")
 	editMsg = tgbotapi.NewEditMessageText(chatID, sentMsg.MessageID, msgText)
-	h.bot.Send(editMsg)
+	h.sender.Send(editMsg)
 
 	session, err := sshClient.NewSession()
 	if err != nil {
-		msgText += fmt.Sprintf("❌ Создание SSH-сессии: Ошибка - %v\n", err)
+		msgText += h.t(chatID, "admin_check.session_error", err)
 	} else {
 		defer session.Close()
 
@@ -2639,21 +2431,21 @@ func (h *BotHandler) checkServerAvailability(chatID int64, serverID int) {
 		session.Stdout = &stdout
 
 		if err := session.Run("which wg"); err != nil {
-			msgText += "❌ Wireguard: Не установлен\n"
+			msgText += h.t(chatID, "admin_check.wireguard_missing")
 		} else {
-			msgText += "✅ Wireguard: Установлен\n"
+			msgText += h.t(chatID, "admin_check.wireguard_ok")
 		}
 	}
 
 	// Проверяем конфигурацию Wireguard
-	msgText += "🔄 Проверка конфигурации Wireguard...\n"
+	msgText += h.t(chatID, "admin_check.config")
 	editMsg = tgbotapi.NewEditMessageText(chatID, sentMsg.MessageID, msgText)
-	h.bot.Send(editMsg)
+	h.sender.Send(editMsg)
 
 	// Создаем новую сессию
 	session, err = sshClient.NewSession()
 	if err != nil {
-		msgText += fmt.Sprintf("❌ Создание SSH-сессии: Ошибка - %v\n", err)
+		msgText += h.t(chatID, "admin_check.session_error", err)
 	} else {
 		defer session.Close()
 
@@ -2661,11 +2453,11 @@ func (h *BotHandler) checkServerAvailability(chatID int64, serverID int) {
 		session.Stdout = &stdout
 
 		if err := session.Run("sudo cat /etc/wireguard/wg0.conf 2>/dev/null | grep -c '\\[Interface\\]' || echo '0'"); err != nil {
-			msgText += "❌ Конфигурация Wireguard: Не найдена\n"
+			msgText += h.t(chatID, "admin_check.config_missing")
 		} else {
 			count := strings.TrimSpace(stdout.String())
 			if count != "0" {
-				msgText += "✅ Конфигурация Wireguard: Найдена\n"
+				msgText += h.t(chatID, "admin_check.config_ok")
 
 				// Проверяем количество клиентов
 				session, err = sshClient.NewSession()
@@ -2688,29 +2480,29 @@ func (h *BotHandler) checkServerAvailability(chatID int64, serverID int) {
 							}
 						}
 
-						msgText += fmt.Sprintf("👥 Активных клиентов: %s\n", peerCount)
+						msgText += h.t(chatID, "admin_check.peers", peerCount)
 					}
 				}
 			} else {
-				msgText += "❌ Конфигурация Wireguard: Не найдена\n"
+				msgText += h.t(chatID, "admin_check.config_missing")
 			}
 		}
 	}
 
 	// Добавляем статус успешной проверки и время
-	msgText += fmt.Sprintf("\n✅ Проверка завершена успешно!\n⏱️ Время: %s", time.Now().Format("02.01.2006 15:04:05"))
+	msgText += h.t(chatID, "admin_check.done", time.Now().Format("02.01.2006 15:04:05"))
 
 	// Добавляем кнопки для действий с сервером
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🔄 Повторить проверку", fmt.Sprintf("server_action:check:%d", server.ID)),
+			h.button(chatID, h.t(chatID, "admin_check.retry"), fmt.Sprintf("server_action:check:%d", server.ID)),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("📝 Редактировать", fmt.Sprintf("server_action:edit:%d", server.ID)),
-			tgbotapi.NewInlineKeyboardButtonData("❌ Удалить", fmt.Sprintf("server_action:delete:%d", server.ID)),
+			h.button(chatID, h.t(chatID, "admin.edit"), fmt.Sprintf("server_action:edit:%d", server.ID)),
+			h.button(chatID, h.t(chatID, "admin.delete"), fmt.Sprintf("server_action:delete:%d", server.ID)),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("◀️ Назад к списку серверов", "admin_menu:servers"),
+			h.button(chatID, h.t(chatID, "admin_servers.back_to_list"), "admin_menu:servers"),
 		),
 	)
 
@@ -2720,5 +2512,5 @@ func (h *BotHandler) checkServerAvailability(chatID int64, serverID int) {
 		msgText,
 		keyboard,
 	)
-	h.bot.Send(editMsgWithKeyboard)
+	h.sender.Send(editMsgWithKeyboard)
 }
@@ -0,0 +1,476 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/ilokitv/botVPN/internal/models"
+	"github.com/ilokitv/botVPN/internal/sender"
+)
+
+// broadcastProgressInterval - как часто (в сообщениях) сохранять счетчики доставки во время рассылки
+const broadcastProgressInterval = 100
+
+// maxBroadcastButtons - максимальное количество кнопок-ссылок под сообщением рассылки
+const maxBroadcastButtons = 10
+
+// startBroadcast начинает составление рассылки
+func (h *BotHandler) startBroadcast(chatID int64) {
+	h.setState(chatID, UserState{
+		State: "broadcast_message",
+		Data:  make(map[string]string),
+	})
+	h.sendMessage(chatID, h.t(chatID, "broadcast.start"))
+}
+
+// handleBroadcastMessageInput сохраняет сообщение, которое будет разослано
+func (h *BotHandler) handleBroadcastMessageInput(message *tgbotapi.Message, userState UserState) {
+	chatID := message.Chat.ID
+	if message.Text == "" && len(message.Photo) == 0 {
+		h.sendMessage(chatID, h.t(chatID, "broadcast.unsupported_message"))
+		return
+	}
+
+	userState.Data["message_id"] = strconv.Itoa(message.MessageID)
+	userState.State = "broadcast_buttons"
+	h.setState(chatID, userState)
+
+	h.sender.Send(tgbotapi.NewMessage(chatID, h.t(chatID, "broadcast.buttons_prompt")))
+}
+
+// parseBroadcastButtons разбирает кнопки-ссылки в формате "Текст - URL", по одной на строке
+func parseBroadcastButtons(input string) ([]models.BroadcastButton, error) {
+	buttons := []models.BroadcastButton{}
+	input = strings.TrimSpace(input)
+	if input == "-" {
+		return buttons, nil
+	}
+
+	for _, line := range strings.Split(input, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		separator := strings.LastIndex(line, " - ")
+		if separator < 0 {
+			return nil, &inputError{key: "broadcast.invalid_button_line", args: []interface{}{line}}
+		}
+		text := strings.TrimSpace(line[:separator])
+		link := strings.TrimSpace(line[separator+3:])
+
+		parsed, err := url.Parse(link)
+		if text == "" || err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http" && parsed.Scheme != "tg") {
+			return nil, &inputError{key: "broadcast.invalid_button_line", args: []interface{}{line}}
+		}
+		buttons = append(buttons, models.BroadcastButton{Text: text, URL: link})
+	}
+
+	if len(buttons) > maxBroadcastButtons {
+		return nil, &inputError{key: "broadcast.too_many_buttons", args: []interface{}{maxBroadcastButtons}}
+	}
+	return buttons, nil
+}
+
+// broadcastKeyboard формирует клавиатуру из кнопок-ссылок рассылки
+func broadcastKeyboard(buttons []models.BroadcastButton) *tgbotapi.InlineKeyboardMarkup {
+	if len(buttons) == 0 {
+		return nil
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup()
+	for _, button := range buttons {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonURL(button.Text, button.URL),
+		))
+	}
+	return &keyboard
+}
+
+// handleBroadcastButtonsInput сохраняет кнопки рассылки и предлагает выбрать получателей
+func (h *BotHandler) handleBroadcastButtonsInput(message *tgbotapi.Message, userState UserState) {
+	chatID := message.Chat.ID
+	buttons, err := parseBroadcastButtons(message.Text)
+	if err != nil {
+		h.sender.Send(tgbotapi.NewMessage(chatID, h.t(chatID, "broadcast.buttons_error", h.errorText(chatID, err))))
+		return
+	}
+
+	data, err := json.Marshal(buttons)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "broadcast.buttons_save_error"))
+		return
+	}
+	userState.Data["buttons"] = string(data)
+	userState.State = "broadcast_segment"
+	h.setState(chatID, userState)
+
+	h.showBroadcastSegments(chatID)
+}
+
+// showBroadcastSegments предлагает выбрать получателей рассылки
+func (h *BotHandler) showBroadcastSegments(chatID int64) {
+	msg := tgbotapi.NewMessage(chatID, h.t(chatID, "broadcast.choose_segment"))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "broadcast.segment_all_button"), "broadcast_segment:"+models.BroadcastSegmentAll),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "broadcast.segment_active_button"), "broadcast_segment:"+models.BroadcastSegmentActive),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "broadcast.segment_expired_button"), "broadcast_segment:"+models.BroadcastSegmentExpired),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "broadcast.segment_plan_button"), "broadcast_segment:"+models.BroadcastSegmentPlan),
+			h.button(chatID, h.t(chatID, "broadcast.segment_server_button"), "broadcast_segment:"+models.BroadcastSegmentServer),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "admin.cancel"), "broadcast_cancel"),
+		),
+	)
+	h.sender.Send(msg)
+}
+
+// broadcastDraft возвращает составляемую рассылку, если сообщение и кнопки уже выбраны
+func (h *BotHandler) broadcastDraft(chatID int64) (UserState, bool) {
+	userState, exists := h.getState(chatID)
+	if !exists || userState.Data["buttons"] == "" || !strings.HasPrefix(userState.State, "broadcast_") {
+		h.sendMessage(chatID, h.t(chatID, "broadcast.not_found"))
+		return UserState{}, false
+	}
+	return userState, true
+}
+
+// handleBroadcastSegment обрабатывает выбор сегмента получателей
+func (h *BotHandler) handleBroadcastSegment(chatID int64, segment string) {
+	userState, ok := h.broadcastDraft(chatID)
+	if !ok {
+		return
+	}
+
+	switch segment {
+	case models.BroadcastSegmentAll, models.BroadcastSegmentActive:
+		h.showBroadcastPreview(chatID, userState, segment, 0)
+
+	case models.BroadcastSegmentExpired:
+		userState.State = "broadcast_days"
+		h.setState(chatID, userState)
+		h.sendMessage(chatID, h.t(chatID, "broadcast.days_prompt"))
+
+	case models.BroadcastSegmentPlan:
+		plans, err := h.db.GetAllSubscriptionPlans()
+		if err != nil {
+			h.sendMessage(chatID, h.t(chatID, "common.plans_error", err))
+			return
+		}
+		keyboard := tgbotapi.NewInlineKeyboardMarkup()
+		for _, plan := range plans {
+			keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+				h.button(chatID, plan.Name, fmt.Sprintf("broadcast_target:%s:%d", segment, plan.ID)),
+			))
+		}
+		msg := tgbotapi.NewMessage(chatID, h.t(chatID, "broadcast.choose_plan"))
+		msg.ReplyMarkup = keyboard
+		h.sender.Send(msg)
+
+	case models.BroadcastSegmentServer:
+		servers, err := h.db.GetAllServers()
+		if err != nil {
+			h.sendMessage(chatID, h.t(chatID, "admin_servers.list_error", err))
+			return
+		}
+		keyboard := tgbotapi.NewInlineKeyboardMarkup()
+		for _, server := range servers {
+			keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+				h.button(chatID, fmt.Sprintf("#%d %s", server.ID, server.IP), fmt.Sprintf("broadcast_target:%s:%d", segment, server.ID)),
+			))
+		}
+		msg := tgbotapi.NewMessage(chatID, h.t(chatID, "broadcast.choose_server"))
+		msg.ReplyMarkup = keyboard
+		h.sender.Send(msg)
+
+	default:
+		h.sendMessage(chatID, h.t(chatID, "broadcast.unknown_segment"))
+	}
+}
+
+// handleBroadcastTarget обрабатывает выбор плана или сервера для рассылки
+func (h *BotHandler) handleBroadcastTarget(chatID int64, segment string, value int) {
+	userState, ok := h.broadcastDraft(chatID)
+	if !ok {
+		return
+	}
+	if segment != models.BroadcastSegmentPlan && segment != models.BroadcastSegmentServer {
+		h.sendMessage(chatID, h.t(chatID, "broadcast.unknown_segment"))
+		return
+	}
+	h.showBroadcastPreview(chatID, userState, segment, value)
+}
+
+// handleBroadcastDaysInput обрабатывает количество дней для сегмента истекших подписок
+func (h *BotHandler) handleBroadcastDaysInput(message *tgbotapi.Message, userState UserState) {
+	chatID := message.Chat.ID
+	days, err := strconv.Atoi(strings.TrimSpace(message.Text))
+	if err != nil || days <= 0 {
+		h.sendMessage(chatID, h.t(chatID, "broadcast.invalid_days"))
+		return
+	}
+	h.showBroadcastPreview(chatID, userState, models.BroadcastSegmentExpired, days)
+}
+
+// broadcastSegmentName возвращает описание сегмента получателей для панели управления
+func (h *BotHandler) broadcastSegmentName(chatID int64, segment string, value int) string {
+	switch segment {
+	case models.BroadcastSegmentAll:
+		return h.t(chatID, "broadcast.segment_all")
+	case models.BroadcastSegmentActive:
+		return h.t(chatID, "broadcast.segment_active")
+	case models.BroadcastSegmentExpired:
+		return h.t(chatID, "broadcast.segment_expired", value)
+	case models.BroadcastSegmentPlan:
+		if plan, err := h.db.GetSubscriptionPlanByID(value); err == nil {
+			return h.t(chatID, "broadcast.segment_plan", plan.Name)
+		}
+		return h.t(chatID, "broadcast.segment_plan_id", value)
+	case models.BroadcastSegmentServer:
+		if server, err := h.db.GetServerByID(value); err == nil {
+			return h.t(chatID, "broadcast.segment_server", server.ID, server.IP)
+		}
+		return h.t(chatID, "broadcast.segment_server_id", value)
+	}
+	return segment
+}
+
+// showBroadcastPreview показывает сообщение так, как его увидят получатели, и просит подтвердить рассылку
+func (h *BotHandler) showBroadcastPreview(chatID int64, userState UserState, segment string, value int) {
+	audience, err := h.db.GetBroadcastAudience(segment, value)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "broadcast.audience_error", err))
+		return
+	}
+
+	var buttons []models.BroadcastButton
+	if err := json.Unmarshal([]byte(userState.Data["buttons"]), &buttons); err != nil {
+		log.Printf("Ошибка при чтении кнопок рассылки: %v", err)
+		h.clearState(chatID)
+		h.sendMessage(chatID, h.t(chatID, "broadcast.buttons_read_error"))
+		return
+	}
+
+	userState.Data["segment"] = segment
+	userState.Data["segment_value"] = strconv.Itoa(value)
+	userState.State = "broadcast_confirm"
+	h.setState(chatID, userState)
+
+	messageID, _ := strconv.Atoi(userState.Data["message_id"])
+
+	preview := tgbotapi.NewCopyMessage(chatID, chatID, messageID)
+	if keyboard := broadcastKeyboard(buttons); keyboard != nil {
+		preview.ReplyMarkup = keyboard
+	}
+	if _, err := h.sender.Request(preview); err != nil {
+		h.clearState(chatID)
+		h.sendMessage(chatID, h.t(chatID, "broadcast.preview_error", err))
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, h.t(chatID, "broadcast.preview", h.broadcastSegmentName(chatID, segment, value), len(audience)))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "broadcast.send"), "broadcast_confirm"),
+			h.button(chatID, h.t(chatID, "admin.cancel"), "broadcast_cancel"),
+		),
+	)
+	h.sender.Send(msg)
+}
+
+// handleBroadcastConfirm запускает подтвержденную рассылку в фоне
+func (h *BotHandler) handleBroadcastConfirm(chatID int64, actorTelegramID int64) {
+	userState, ok := h.broadcastDraft(chatID)
+	if !ok {
+		return
+	}
+	if userState.State != "broadcast_confirm" {
+		h.sendMessage(chatID, h.t(chatID, "broadcast.choose_segment_first"))
+		return
+	}
+	h.clearState(chatID)
+
+	segment := userState.Data["segment"]
+	value, _ := strconv.Atoi(userState.Data["segment_value"])
+	messageID, _ := strconv.Atoi(userState.Data["message_id"])
+
+	audience, err := h.db.GetBroadcastAudience(segment, value)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "broadcast.audience_error", err))
+		return
+	}
+	if len(audience) == 0 {
+		h.sendMessage(chatID, h.t(chatID, "broadcast.no_audience"))
+		return
+	}
+
+	broadcast := &models.Broadcast{
+		CreatedBy:       actorTelegramID,
+		SourceChatID:    chatID,
+		SourceMessageID: messageID,
+		Buttons:         userState.Data["buttons"],
+		Segment:         segment,
+		SegmentValue:    value,
+		Status:          models.BroadcastRunning,
+		Total:           len(audience),
+	}
+	if err := h.db.AddBroadcast(broadcast); err != nil {
+		log.Printf("Ошибка при сохранении рассылки: %v", err)
+		h.sendMessage(chatID, h.t(chatID, "broadcast.save_error"))
+		return
+	}
+
+	h.audit(actorTelegramID, models.AuditBroadcast, models.AuditTargetBroadcast, broadcast.ID, nil,
+		map[string]interface{}{"segment": segment, "segment_value": value, "total": broadcast.Total})
+
+	// Скорость рассылки ограничивает sender: общий лимит бота делится между рассылкой и ответами пользователям
+	rate := h.config.Sender.GetGlobalRatePerSecond()
+	h.sendMessage(chatID, h.t(chatID, "broadcast.started",
+		broadcast.ID, broadcast.Total, (time.Duration(broadcast.Total/rate+1)*time.Second).String()))
+
+	h.startBroadcastWorker(broadcast, audience)
+}
+
+// handleBroadcastCancel отменяет составление рассылки
+func (h *BotHandler) handleBroadcastCancel(chatID int64) {
+	h.clearState(chatID)
+	h.sendMessage(chatID, h.t(chatID, "broadcast.cancelled"))
+}
+
+// startBroadcastWorker запускает доставку рассылки в фоне. Wait дожидается, пока рассылка сохранит прогресс при остановке бота.
+func (h *BotHandler) startBroadcastWorker(broadcast *models.Broadcast, audience []models.User) {
+	h.background.Add(1)
+	go func() {
+		defer h.background.Done()
+		h.runBroadcast(broadcast, audience)
+	}()
+}
+
+// runBroadcast доставляет рассылку получателям и сообщает итоговую статистику.
+// Получатели до LastUserID пропускаются: им сообщение было отправлено до перезапуска бота.
+// При отмене контекста приложения рассылка сохраняется прерванной и продолжается после запуска.
+func (h *BotHandler) runBroadcast(broadcast *models.Broadcast, audience []models.User) {
+	var buttons []models.BroadcastButton
+	if err := json.Unmarshal([]byte(broadcast.Buttons), &buttons); err != nil {
+		log.Printf("Ошибка при чтении кнопок рассылки #%d: %v", broadcast.ID, err)
+		h.finishBroadcast(broadcast, models.BroadcastFailed)
+		h.sender.Send(tgbotapi.NewMessage(broadcast.CreatedBy, h.t(broadcast.CreatedBy, "broadcast.buttons_corrupted", broadcast.ID)))
+		return
+	}
+	keyboard := broadcastKeyboard(buttons)
+
+	processed := 0
+	for _, user := range audience {
+		if user.ID <= broadcast.LastUserID {
+			continue
+		}
+		if h.ctx.Err() != nil {
+			broadcast.Status = models.BroadcastInterrupted
+			if err := h.db.UpdateBroadcastStats(broadcast); err != nil {
+				log.Printf("Ошибка при сохранении прерванной рассылки #%d: %v", broadcast.ID, err)
+			}
+			log.Printf("Рассылка #%d прервана остановкой бота и продолжится после запуска: отправлено %d из %d",
+				broadcast.ID, broadcast.Sent, broadcast.Total)
+			return
+		}
+
+		copyMsg := tgbotapi.NewCopyMessage(user.TelegramID, broadcast.SourceChatID, broadcast.SourceMessageID)
+		if keyboard != nil {
+			copyMsg.ReplyMarkup = keyboard
+		}
+
+		// Ограничение скорости, повторы, отметку заблокировавших бота и сохранение недоставленных сообщений выполняет sender
+		_, err := h.sender.Request(copyMsg)
+		switch {
+		case err == nil:
+			broadcast.Sent++
+		case sender.IsBotBlocked(err):
+			broadcast.Blocked++
+		default:
+			broadcast.Failed++
+		}
+		broadcast.LastUserID = user.ID
+
+		processed++
+		if processed%broadcastProgressInterval == 0 {
+			if err := h.db.UpdateBroadcastStats(broadcast); err != nil {
+				log.Printf("Ошибка при сохранении прогресса рассылки #%d: %v", broadcast.ID, err)
+			}
+		}
+	}
+
+	h.finishBroadcast(broadcast, models.BroadcastFinished)
+	log.Printf("Рассылка #%d завершена: отправлено %d, ошибок %d, заблокировали бота %d из %d",
+		broadcast.ID, broadcast.Sent, broadcast.Failed, broadcast.Blocked, broadcast.Total)
+	h.sender.Send(tgbotapi.NewMessage(broadcast.CreatedBy, h.t(broadcast.CreatedBy, "broadcast.finished",
+		broadcast.ID, broadcast.FinishedAt.Sub(broadcast.CreatedAt).Round(time.Second), broadcast.Total,
+		broadcast.Sent, broadcast.Blocked, broadcast.Failed)))
+}
+
+// finishBroadcast сохраняет итоговый статус рассылки
+func (h *BotHandler) finishBroadcast(broadcast *models.Broadcast, status string) {
+	now := time.Now()
+	broadcast.Status = status
+	broadcast.FinishedAt = &now
+	if err := h.db.UpdateBroadcastStats(broadcast); err != nil {
+		log.Printf("Ошибка при сохранении итогов рассылки #%d: %v", broadcast.ID, err)
+	}
+}
+
+// ResumeBroadcasts продолжает рассылки, прерванные остановкой бота. Рассылки, которые остались запущенными
+// после аварийной остановки, не продолжаются: последний обработанный получатель сохраняется не после каждого
+// сообщения, и части получателей сообщение пришло бы дважды.
+func (h *BotHandler) ResumeBroadcasts() {
+	broadcasts, err := h.db.GetUnfinishedBroadcasts()
+	if err != nil {
+		log.Printf("Ошибка при получении незавершенных рассылок: %v", err)
+		return
+	}
+
+	for i := range broadcasts {
+		broadcast := &broadcasts[i]
+		if broadcast.Status == models.BroadcastRunning {
+			h.finishBroadcast(broadcast, models.BroadcastFailed)
+			log.Printf("Рассылка #%d прервана аварийной остановкой бота: отправлено %d из %d", broadcast.ID, broadcast.Sent, broadcast.Total)
+			h.sender.Send(tgbotapi.NewMessage(broadcast.CreatedBy, h.t(broadcast.CreatedBy, "broadcast.crashed",
+				broadcast.ID, broadcast.Total, broadcast.Sent, broadcast.Blocked, broadcast.Failed)))
+			continue
+		}
+
+		// Рассылка остается прерванной, если получателей не удалось получить, и будет продолжена при следующем запуске
+		audience, err := h.db.GetBroadcastAudience(broadcast.Segment, broadcast.SegmentValue)
+		if err != nil {
+			log.Printf("Ошибка при получении получателей рассылки #%d: %v", broadcast.ID, err)
+			continue
+		}
+		remaining := 0
+		for _, user := range audience {
+			if user.ID > broadcast.LastUserID {
+				remaining++
+			}
+		}
+
+		broadcast.Status = models.BroadcastRunning
+		if err := h.db.UpdateBroadcastStats(broadcast); err != nil {
+			log.Printf("Ошибка при возобновлении рассылки #%d: %v", broadcast.ID, err)
+			continue
+		}
+		log.Printf("Рассылка #%d продолжена после перезапуска: осталось получателей %d", broadcast.ID, remaining)
+		h.sender.Send(tgbotapi.NewMessage(broadcast.CreatedBy, h.t(broadcast.CreatedBy, "broadcast.resumed", broadcast.ID, remaining)))
+		h.startBroadcastWorker(broadcast, audience)
+	}
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseBroadcastButtons(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    int
+		wantKey string
+	}{
+		{name: "no buttons", input: " - ", want: 0},
+		{name: "two buttons", input: "Сайт - https://example.com\n\nКанал - tg://resolve?domain=example", want: 2},
+		{name: "dash in text", input: "Вопрос - ответ - https://example.com/faq", want: 1},
+		{name: "no separator", input: "https://example.com", wantKey: "broadcast.invalid_button_line"},
+		{name: "unsupported scheme", input: "Файл - ftp://example.com", wantKey: "broadcast.invalid_button_line"},
+		{name: "too many buttons", input: strings.Repeat("Сайт - https://example.com\n", maxBroadcastButtons+1), wantKey: "broadcast.too_many_buttons"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buttons, err := parseBroadcastButtons(tt.input)
+			if tt.wantKey == "" {
+				if err != nil {
+					t.Fatalf("parseBroadcastButtons() error = %v", err)
+				}
+				if len(buttons) != tt.want {
+					t.Errorf("got %d buttons, want %d", len(buttons), tt.want)
+				}
+				return
+			}
+
+			var inputErr *inputError
+			if !errors.As(err, &inputErr) || inputErr.key != tt.wantKey {
+				t.Errorf("parseBroadcastButtons() error = %v, want key %q", err, tt.wantKey)
+			}
+		})
+	}
+}
@@ -0,0 +1,347 @@
+package handlers
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/ilokitv/botVPN/internal/callbackdata"
+	"github.com/ilokitv/botVPN/internal/i18n"
+	"github.com/ilokitv/botVPN/internal/roles"
+)
+
+// callbackOwnership определяет, какой объект из данных кнопки должен принадлежать пользователю
+type callbackOwnership int
+
+const (
+	ownsNothing      callbackOwnership = iota
+	ownsSubscription                   // Первый аргумент - ID подписки пользователя (сотрудникам с правом users доступны любые подписки)
+)
+
+// rejectedCallbackReportInterval - как часто сообщать администраторам об отклоненных кнопках одного пользователя
+const rejectedCallbackReportInterval = 10 * time.Minute
+
+// callbackContext содержит разобранные данные нажатой инлайн-кнопки
+type callbackContext struct {
+	query  *tgbotapi.CallbackQuery
+	chatID int64
+	userID int64
+	args   []string // Аргументы после действия
+}
+
+// intArg возвращает аргумент с индексом i как число (0, если аргумент не число)
+func (c *callbackContext) intArg(i int) int {
+	value, _ := strconv.Atoi(c.args[i])
+	return value
+}
+
+// callbackRoute описывает обработчик действия инлайн-кнопки и требования к пользователю
+type callbackRoute struct {
+	permissions []roles.Permission // Достаточно любого из прав; пусто - доступно всем пользователям
+	ownership   callbackOwnership
+	args        int // Минимальное количество аргументов
+	handle      func(h *BotHandler, c *callbackContext)
+}
+
+// callbackRoutes - обработчики инлайн-кнопок. Ключ - действие ("renew") или действие с
+// подтипом ("subscription_action:block"); маршрут с подтипом имеет приоритет.
+var callbackRoutes = map[string]callbackRoute{
+	// Администрирование
+	"admin_menu": {permissions: perms(roles.PermPanel), args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleAdminMenuSelection(c.chatID, c.args[0])
+	}},
+	"admin_menu:servers": {permissions: perms(roles.PermServers), handle: func(h *BotHandler, c *callbackContext) {
+		h.handleAdminMenuSelection(c.chatID, "servers")
+	}},
+	"admin_menu:plans": {permissions: perms(roles.PermPlans), handle: func(h *BotHandler, c *callbackContext) {
+		h.handleAdminMenuSelection(c.chatID, "plans")
+	}},
+	"admin_menu:users": {permissions: perms(roles.PermUsers, roles.PermRefunds), handle: func(h *BotHandler, c *callbackContext) {
+		h.handleAdminMenuSelection(c.chatID, "users")
+	}},
+	"admin_menu:stats": {permissions: perms(roles.PermStats), handle: func(h *BotHandler, c *callbackContext) {
+		h.handleAdminMenuSelection(c.chatID, "stats")
+	}},
+	"admin_menu:roles": {permissions: perms(roles.PermRoles), handle: func(h *BotHandler, c *callbackContext) {
+		h.handleAdminMenuSelection(c.chatID, "roles")
+	}},
+	"admin_menu:audit": {permissions: perms(roles.PermAudit), handle: func(h *BotHandler, c *callbackContext) {
+		h.handleAdminMenuSelection(c.chatID, "audit")
+	}},
+	"admin_menu:broadcast": {permissions: perms(roles.PermBroadcasts), handle: func(h *BotHandler, c *callbackContext) {
+		h.handleAdminMenuSelection(c.chatID, "broadcast")
+	}},
+	"server_action": {permissions: perms(roles.PermServers), args: 2, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleServerAction(c.chatID, c.args[0], c.intArg(1))
+	}},
+	"server_edit": {permissions: perms(roles.PermServers), args: 2, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleServerEdit(c.chatID, c.userID, c.args[0], c.intArg(1))
+	}},
+	"server_reissue": {permissions: perms(roles.PermServers), args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleConfigReissue(c.chatID, c.userID, c.intArg(0))
+	}},
+	"server_confirm_delete": {permissions: perms(roles.PermServers), args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleServerConfirmDelete(c.chatID, c.userID, c.intArg(0))
+	}},
+	"plan_action": {permissions: perms(roles.PermPlans), args: 2, handle: func(h *BotHandler, c *callbackContext) {
+		h.handlePlanAction(c.chatID, c.userID, c.args[0], c.intArg(1))
+	}},
+	"plan_price": {permissions: perms(roles.PermPlans), args: 2, handle: func(h *BotHandler, c *callbackContext) {
+		h.startPlanPriceEdit(c.chatID, c.intArg(0), c.args[1])
+	}},
+	"plan_status": {permissions: perms(roles.PermPlans), args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handlePlanStatusSelect(c.query, c.args[0])
+	}},
+	"users_page": {permissions: perms(roles.PermUsers, roles.PermRefunds), args: 3, handle: func(h *BotHandler, c *callbackContext) {
+		h.showUserList(c.chatID, c.query.Message.MessageID, parseUserListView(c.args))
+	}},
+	"user_card": {permissions: perms(roles.PermUsers, roles.PermRefunds), args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.showUserCard(c.chatID, c.query.Message.MessageID, c.intArg(0), parseUserListView(c.args[1:]))
+	}},
+	"user_search": {permissions: perms(roles.PermUsers, roles.PermRefunds), handle: func(h *BotHandler, c *callbackContext) {
+		h.startUserSearch(c.chatID)
+	}},
+	"user_action": {permissions: perms(roles.PermUsers), args: 2, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleUserAction(c.chatID, c.args[0], c.intArg(1))
+	}},
+	"user_action:balance": {permissions: perms(roles.PermUsers, roles.PermRefunds), args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleUserAction(c.chatID, "balance", c.intArg(0))
+	}},
+	"user_action:adjust_balance": {permissions: perms(roles.PermRefunds), args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleUserAction(c.chatID, "adjust_balance", c.intArg(0))
+	}},
+	"user_action:bonus_balance": {permissions: perms(roles.PermRefunds), args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleUserAction(c.chatID, "bonus_balance", c.intArg(0))
+	}},
+	"user_action:role": {permissions: perms(roles.PermRoles), args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleUserAction(c.chatID, "role", c.intArg(0))
+	}},
+	"user_role": {permissions: perms(roles.PermRoles), args: 2, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleSetUserRole(c.chatID, c.userID, c.intArg(0), c.args[1])
+	}},
+	"stats_action": {permissions: perms(roles.PermStats), args: 2, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleStatsAction(c.chatID, c.args[0], c.intArg(1))
+	}},
+	"stats_action:revenue": {permissions: perms(roles.PermPayments), args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleStatsAction(c.chatID, "revenue", c.intArg(0))
+	}},
+	"stats_action:cohorts": {permissions: perms(roles.PermPayments), args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleStatsAction(c.chatID, "cohorts", c.intArg(0))
+	}},
+	"subscription_action:block": {permissions: perms(roles.PermUsers), args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleSubscriptionAction(c.chatID, c.userID, "block", c.intArg(0))
+	}},
+	"subscription_action:unblock": {permissions: perms(roles.PermUsers), args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleSubscriptionAction(c.chatID, c.userID, "unblock", c.intArg(0))
+	}},
+	"subscription_action:delete": {permissions: perms(roles.PermUsers), args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleSubscriptionAction(c.chatID, c.userID, "delete", c.intArg(0))
+	}},
+	"revenue_page": {permissions: perms(roles.PermPayments), args: 2, handle: func(h *BotHandler, c *callbackContext) {
+		h.showRevenuePage(c.chatID, c.intArg(0), c.intArg(1))
+	}},
+	"revenue_csv": {permissions: perms(roles.PermPayments), args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.exportRevenueCSV(c.chatID, c.intArg(0))
+	}},
+	"cohorts_csv": {permissions: perms(roles.PermPayments), handle: func(h *BotHandler, c *callbackContext) {
+		h.exportCohortsCSV(c.chatID)
+	}},
+	"audit_page": {permissions: perms(roles.PermAudit), args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.showAuditLog(c.chatID, c.intArg(0))
+	}},
+	"audit_csv": {permissions: perms(roles.PermAudit), handle: func(h *BotHandler, c *callbackContext) {
+		h.exportAuditCSV(c.chatID)
+	}},
+	"support_staff:close": {permissions: perms(roles.PermUsers), args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleSupportStaffClose(c.chatID, c.userID, c.intArg(0))
+	}},
+	"support_staff:history": {permissions: perms(roles.PermUsers), args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.showSupportHistory(c.chatID, c.intArg(0))
+	}},
+	"broadcast_segment": {permissions: perms(roles.PermBroadcasts), args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleBroadcastSegment(c.chatID, c.args[0])
+	}},
+	"broadcast_target": {permissions: perms(roles.PermBroadcasts), args: 2, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleBroadcastTarget(c.chatID, c.args[0], c.intArg(1))
+	}},
+	"broadcast_confirm": {permissions: perms(roles.PermBroadcasts), handle: func(h *BotHandler, c *callbackContext) {
+		h.handleBroadcastConfirm(c.chatID, c.userID)
+	}},
+	"broadcast_cancel": {permissions: perms(roles.PermBroadcasts), handle: func(h *BotHandler, c *callbackContext) {
+		h.handleBroadcastCancel(c.chatID)
+	}},
+
+	// Подписки пользователя
+	"subscription_action:config": {ownership: ownsSubscription, args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleSubscriptionAction(c.chatID, c.userID, "config", c.intArg(0))
+	}},
+	"subscription_action:stats": {ownership: ownsSubscription, args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleSubscriptionAction(c.chatID, c.userID, "stats", c.intArg(0))
+	}},
+	"change_plan": {ownership: ownsSubscription, args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.showPlanChangeOptions(c.chatID, c.userID, c.intArg(0))
+	}},
+	"change_plan_to": {ownership: ownsSubscription, args: 2, handle: func(h *BotHandler, c *callbackContext) {
+		h.handlePlanChange(c.chatID, c.userID, c.intArg(0), c.intArg(1))
+	}},
+	"renew": {ownership: ownsSubscription, args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleRenewSubscription(c.chatID, c.userID, c.intArg(0))
+	}},
+	"pause_subscription": {ownership: ownsSubscription, args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handlePauseSubscription(c.chatID, c.userID, c.intArg(0))
+	}},
+	"resume_subscription": {ownership: ownsSubscription, args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleResumeSubscription(c.chatID, c.userID, c.intArg(0))
+	}},
+
+	// Поддержка
+	"support_menu": {handle: func(h *BotHandler, c *callbackContext) {
+		h.showSupportMenu(c.chatID, c.userID)
+	}},
+	"support_new": {handle: func(h *BotHandler, c *callbackContext) {
+		h.handleSupportNew(c.chatID, c.userID)
+	}},
+	"support_sub": {ownership: ownsSubscription, args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.startSupportTicket(c.chatID, c.intArg(0))
+	}},
+	"support_nosub": {handle: func(h *BotHandler, c *callbackContext) {
+		h.startSupportTicket(c.chatID, 0)
+	}},
+	"support_write": {args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleSupportWrite(c.chatID, c.userID, c.intArg(0))
+	}},
+	"support_close": {args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleSupportClose(c.chatID, c.userID, c.intArg(0))
+	}},
+
+	// Покупка, подарки и баланс
+	"show_buy_plans": {handle: func(h *BotHandler, c *callbackContext) {
+		h.listAvailableSubscriptionPlans(c.chatID)
+	}},
+	"buy_plan": {args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleBuyPlan(c.chatID, c.userID, c.intArg(0))
+	}},
+	"trial:activate": {handle: func(h *BotHandler, c *callbackContext) {
+		h.handleTrialActivation(c.chatID, c.userID)
+	}},
+	"gift_plan": {args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleGiftPlan(c.chatID, c.intArg(0))
+	}},
+	"balance_pay": {args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleBalancePay(c.chatID, c.userID, c.intArg(0))
+	}},
+	"balance_topup": {args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleBalanceTopUp(c.chatID, c.intArg(0))
+	}},
+	"balance_action": {handle: func(h *BotHandler, c *callbackContext) {
+		h.handleBalanceCommand(c.chatID, c.userID)
+	}},
+	"language": {args: 1, handle: func(h *BotHandler, c *callbackContext) {
+		h.handleLanguageSelect(c.chatID, c.userID, c.args[0])
+	}},
+}
+
+// perms возвращает список прав для маршрута
+func perms(permissions ...roles.Permission) []roles.Permission {
+	return permissions
+}
+
+// findCallbackRoute находит обработчик для данных кнопки и возвращает его аргументы
+func findCallbackRoute(payload string) (callbackRoute, []string, bool) {
+	parts := strings.Split(payload, ":")
+	if len(parts) >= 2 {
+		if route, ok := callbackRoutes[parts[0]+":"+parts[1]]; ok {
+			return route, parts[2:], true
+		}
+	}
+	route, ok := callbackRoutes[parts[0]]
+	return route, parts[1:], ok
+}
+
+// button создает инлайн-кнопку, данные которой подписаны для чата chatID
+func (h *BotHandler) button(chatID int64, text, payload string) tgbotapi.InlineKeyboardButton {
+	data := h.callbacks.Sign(chatID, payload)
+	if len(data) > callbackdata.MaxLength {
+		log.Printf("Данные кнопки %q длиннее %d байт и будут отклонены Telegram", payload, callbackdata.MaxLength)
+	}
+	return tgbotapi.NewInlineKeyboardButtonData(text, data)
+}
+
+// handleCallbackQuery проверяет подпись и права пользователя и передает нажатие кнопки обработчику
+func (h *BotHandler) handleCallbackQuery(query *tgbotapi.CallbackQuery) {
+	if query.Message == nil {
+		// Бот не отправляет инлайн-сообщений, такие колбэки не обрабатываются
+		h.sender.Request(tgbotapi.NewCallback(query.ID, ""))
+		return
+	}
+
+	chatID := query.Message.Chat.ID
+	log.Printf("Получен callback: data=%s, от пользователя ID=%d", query.Data, query.From.ID)
+
+	payload, ok := h.callbacks.Verify(chatID, query.Data)
+	if !ok {
+		if !callbackdata.IsSigned(query.Data) {
+			// Кнопка из сообщения, отправленного до включения подписи
+			log.Printf("Колбэк без подписи от пользователя %d: %q", query.From.ID, query.Data)
+			h.sender.Request(tgbotapi.NewCallbackWithAlert(query.ID, h.t(query.From.ID, "callback.outdated")))
+			return
+		}
+		h.rejectCallback(query, "неверная подпись", "callback.outdated")
+		return
+	}
+
+	route, args, ok := findCallbackRoute(payload)
+	if !ok || len(args) < route.args {
+		log.Printf("Неизвестный или некорректный колбэк: %s", payload)
+		h.sender.Request(tgbotapi.NewCallback(query.ID, ""))
+		return
+	}
+
+	c := &callbackContext{query: query, chatID: chatID, userID: query.From.ID, args: args}
+
+	role := h.role(c.userID)
+	if len(route.permissions) > 0 && !roles.CanAny(role, route.permissions...) {
+		h.rejectCallback(query, "недостаточно прав: "+payload, "callback.forbidden")
+		return
+	}
+	if route.ownership == ownsSubscription && !roles.Can(role, roles.PermUsers) && !h.ownsSubscription(c.userID, c.intArg(0)) {
+		h.rejectCallback(query, "чужая подписка: "+payload, "callback.forbidden")
+		return
+	}
+
+	// Отвечаем на запрос обратного вызова
+	h.sender.Request(tgbotapi.NewCallback(query.ID, ""))
+
+	route.handle(h, c)
+}
+
+// ownsSubscription проверяет, что подписка принадлежит пользователю Telegram
+func (h *BotHandler) ownsSubscription(telegramID int64, subscriptionID int) bool {
+	_, _, errText := h.getOwnedSubscription(telegramID, subscriptionID)
+	return errText == ""
+}
+
+// rejectCallback отклоняет нажатие кнопки: показывает пользователю сообщение, пишет в лог
+// и сообщает администраторам (не чаще rejectedCallbackReportInterval для одного пользователя)
+func (h *BotHandler) rejectCallback(query *tgbotapi.CallbackQuery, reason, messageKey string) {
+	log.Printf("Отклонен колбэк от пользователя %d (%s): %s, data=%q",
+		query.From.ID, query.From.UserName, reason, query.Data)
+
+	h.sender.Request(tgbotapi.NewCallbackWithAlert(query.ID, h.t(query.From.ID, messageKey)))
+
+	h.rejectedReportsMu.Lock()
+	lastReport, reported := h.rejectedReports[query.From.ID]
+	if reported && time.Since(lastReport) < rejectedCallbackReportInterval {
+		h.rejectedReportsMu.Unlock()
+		return
+	}
+	h.rejectedReports[query.From.ID] = time.Now()
+	h.rejectedReportsMu.Unlock()
+
+	report := i18n.T(i18n.DefaultLanguage, "callback.rejected_report",
+		query.From.UserName, query.From.ID, reason, query.Data)
+	h.notifyStaff(roles.PermUsers, report, query.From.ID)
+}
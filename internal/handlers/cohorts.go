@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/ilokitv/botVPN/internal/analytics"
+)
+
+// cohortWeeks - количество недельных когорт в отчете
+const cohortWeeks = 16
+
+// buildCohortReport строит когорты за последние cohortWeeks недель
+func (h *BotHandler) buildCohortReport() ([]analytics.Cohort, error) {
+	now := time.Now()
+	since := analytics.WeekStart(now).AddDate(0, 0, -7*(cohortWeeks-1))
+
+	users, subscriptions, payments, err := h.db.GetCohortData(since)
+	if err != nil {
+		return nil, err
+	}
+
+	return analytics.BuildCohorts(users, subscriptions, payments, now), nil
+}
+
+// showCohortStats отображает когортный отчет по удержанию и LTV
+func (h *BotHandler) showCohortStats(chatID int64) {
+	cohorts, err := h.buildCohortReport()
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "cohorts.error", err))
+		return
+	}
+
+	text := h.t(chatID, "cohorts.header", cohortWeeks)
+	if len(cohorts) == 0 {
+		text += h.t(chatID, "cohorts.empty")
+	} else {
+		text += h.t(chatID, "cohorts.legend") +
+			"```\n" + analytics.FormatCohortTable(h.lang(chatID), cohorts) + "```"
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "revenue.export_csv"), "cohorts_csv:0"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "admin.back"), "admin_menu:stats"),
+		),
+	)
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+	h.sender.Send(msg)
+}
+
+// exportCohortsCSV отправляет администратору CSV-выгрузку когортного отчета
+func (h *BotHandler) exportCohortsCSV(chatID int64) {
+	cohorts, err := h.buildCohortReport()
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "cohorts.error", err))
+		return
+	}
+
+	data, err := analytics.CohortsCSV(cohorts)
+	if err != nil {
+		log.Printf("Ошибка при формировании CSV-выгрузки когорт: %v", err)
+		h.sendMessage(chatID, h.t(chatID, "revenue.export_error"))
+		return
+	}
+
+	document := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{
+		Name:  fmt.Sprintf("cohorts_%s.csv", time.Now().Format("20060102")),
+		Bytes: data,
+	})
+	document.Caption = h.t(chatID, "cohorts.export_caption")
+
+	if _, err := h.sender.Send(document); err != nil {
+		h.sendMessage(chatID, h.t(chatID, "revenue.export_send_error", err))
+	}
+}
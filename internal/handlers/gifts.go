@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/ilokitv/botVPN/internal/i18n"
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+const (
+	// giftCodeAlphabet не содержит похожих символов (0/O, 1/I), чтобы код было удобно вводить вручную
+	giftCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	giftCodeLength   = 10
+	// giftDeepLinkPrefix - префикс параметра /start для ссылок на подарок
+	giftDeepLinkPrefix = "gift_"
+	// defaultGiftExpirationDays - срок действия кода, если он не задан в конфигурации
+	defaultGiftExpirationDays = 90
+)
+
+// generateGiftCode генерирует случайный подарочный код
+func generateGiftCode() (string, error) {
+	var sb strings.Builder
+	max := big.NewInt(int64(len(giftCodeAlphabet)))
+	for i := 0; i < giftCodeLength; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(giftCodeAlphabet[n.Int64()])
+	}
+	return sb.String(), nil
+}
+
+// giftExpirationDays возвращает срок действия подарочного кода
+func (h *BotHandler) giftExpirationDays() int {
+	if h.config.Gifts.ExpirationDays > 0 {
+		return h.config.Gifts.ExpirationDays
+	}
+	return defaultGiftExpirationDays
+}
+
+// giftDeepLink возвращает ссылку, открыв которую получатель активирует подарок
+func (h *BotHandler) giftDeepLink(code string) string {
+	return fmt.Sprintf("https://t.me/%s?start=%s%s", h.bot.Self.UserName, giftDeepLinkPrefix, code)
+}
+
+// handleGiftPlan выставляет счет на покупку плана в подарок
+func (h *BotHandler) handleGiftPlan(chatID int64, planID int) {
+	plan, err := h.db.GetSubscriptionPlanByID(planID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.plan_error", err))
+		return
+	}
+
+	if !plan.IsActive || plan.IsTrial {
+		h.sendMessage(chatID, h.t(chatID, "common.plan_unavailable"))
+		return
+	}
+
+	amount, currency := h.planInvoicePrice(plan)
+	invoice := h.newInvoice(
+		chatID,
+		h.t(chatID, "gift.invoice_title", plan.Name),
+		h.t(chatID, "gift.invoice_desc", plan.Duration, h.giftExpirationDays()),
+		fmt.Sprintf("gift:%d", planID), // Payload для идентификации подарка
+		plan.Name,
+		amount,
+		currency,
+	)
+	invoice.NeedName = true
+	invoice.NeedEmail = true
+	invoice.SendEmailToProvider = true
+
+	_, err = h.sender.Send(invoice)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.invoice_error", err))
+		return
+	}
+
+	h.sendMessage(chatID, h.t(chatID, "gift.after_payment"))
+}
+
+// handleGiftPayment создает подарочный код после успешной оплаты
+func (h *BotHandler) handleGiftPayment(chatID int64, telegramID int64, planID int, payment *tgbotapi.SuccessfulPayment) {
+	plan, err := h.db.GetSubscriptionPlanByID(planID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.plan_error", err))
+		return
+	}
+
+	buyer, err := h.db.GetUserByTelegramID(telegramID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.user_error_support"))
+		return
+	}
+
+	// Сохраняем платеж за покупателем, подписка будет создана при активации кода
+	paymentRecord := telegramPayment(buyer.ID, payment)
+	err = h.db.AddPayment(paymentRecord)
+	if err != nil {
+		log.Printf("Ошибка при сохранении платежа за подарок в базу данных: %v", err)
+	}
+
+	gift := &models.GiftCode{
+		PlanID:      plan.ID,
+		BuyerUserID: buyer.ID,
+		Status:      "active",
+		ExpiresAt:   time.Now().AddDate(0, 0, h.giftExpirationDays()),
+	}
+	if paymentRecord.ID != 0 {
+		gift.PaymentID = &paymentRecord.ID
+	}
+
+	// Повторяем генерацию на случай совпадения кода с уже существующим
+	for attempt := 0; attempt < 3; attempt++ {
+		gift.Code, err = generateGiftCode()
+		if err != nil {
+			break
+		}
+		if err = h.db.AddGiftCode(gift); err == nil {
+			break
+		}
+	}
+
+	if err != nil {
+		log.Printf("Ошибка при создании подарочного кода для пользователя #%d: %v", buyer.ID, err)
+		h.sendMessage(chatID, h.t(chatID, "gift.create_error"))
+		return
+	}
+
+	log.Printf("Пользователь #%d купил подарочный код #%d на план #%d", buyer.ID, gift.ID, plan.ID)
+
+	giftMsg := h.t(chatID, "gift.created",
+		plan.Name,
+		plan.Duration,
+		gift.Code,
+		gift.ExpiresAt.Format("02.01.2006"),
+		h.giftDeepLink(gift.Code),
+		gift.Code,
+	)
+
+	msg := tgbotapi.NewMessage(chatID, giftMsg)
+	msg.ParseMode = "Markdown"
+	msg.DisableWebPagePreview = true
+	h.sender.Send(msg)
+}
+
+// handleGiftRedeem активирует подарочный код и создает подписку для получателя
+func (h *BotHandler) handleGiftRedeem(chatID int64, telegramID int64, code string) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		h.sendMessage(chatID, h.t(chatID, "gift.code_required"))
+		return
+	}
+
+	user, err := h.db.GetUserByTelegramID(telegramID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.user_error"))
+		return
+	}
+
+	// Проверяем доступность серверов до того, как погасить код
+	availableServer, err := h.subscriptions.AvailableServer()
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.servers_check_error"))
+		return
+	}
+
+	if availableServer == nil {
+		h.sendMessage(chatID, h.t(chatID, "gift.no_servers"))
+		return
+	}
+
+	gift, err := h.db.ClaimGiftCode(code, user.ID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Ошибка при активации подарочного кода %s: %v", code, err)
+			h.sendMessage(chatID, h.t(chatID, "gift.redeem_error"))
+			return
+		}
+		h.sendMessage(chatID, h.giftUnavailableReason(h.lang(chatID), code))
+		return
+	}
+
+	plan, err := h.db.GetSubscriptionPlanByID(gift.PlanID)
+	if err != nil {
+		h.releaseGiftCode(gift.ID)
+		h.sendMessage(chatID, h.t(chatID, "common.plan_error", err))
+		return
+	}
+
+	h.sendMessage(chatID, h.t(chatID, "gift.activating"))
+
+	subscription, err := h.provisionSubscription(user, plan, fmt.Sprintf("gift_%d", gift.ID), false, nil)
+	if err != nil {
+		h.releaseGiftCode(gift.ID)
+		h.sendMessage(chatID, err.Error())
+		return
+	}
+
+	if err := h.db.SetGiftCodeSubscription(gift.ID, subscription.ID); err != nil {
+		log.Printf("Ошибка при связывании подарочного кода #%d с подпиской #%d: %v", gift.ID, subscription.ID, err)
+	}
+
+	log.Printf("Пользователь #%d активировал подарочный код #%d, подписка #%d", user.ID, gift.ID, subscription.ID)
+
+	if err := h.sendConfigWithInstructions(chatID, subscription.ConfigFilePath); err != nil {
+		return
+	}
+
+	successMsg := h.t(chatID, "gift.redeemed",
+		plan.Name,
+		plan.Duration,
+		subscription.EndDate.Format("02.01.2006"),
+	)
+
+	msg := tgbotapi.NewMessage(chatID, successMsg)
+	msg.ParseMode = "Markdown"
+	h.sender.Send(msg)
+
+	// Сообщаем покупателю, что подарок активирован
+	if buyer, err := h.db.GetUserByID(gift.BuyerUserID); err == nil && buyer.ID != user.ID {
+		recipient := user.Username
+		if recipient == "" {
+			recipient = strings.TrimSpace(user.FirstName + " " + user.LastName)
+		}
+		h.sendMessage(buyer.TelegramID, i18n.T(userLang(buyer), "gift.buyer_notice",
+			gift.Code, tgbotapi.EscapeText(tgbotapi.ModeMarkdown, recipient)))
+	}
+}
+
+// releaseGiftCode возвращает код в активное состояние после неудачной активации
+func (h *BotHandler) releaseGiftCode(giftID int) {
+	if err := h.db.ReleaseGiftCode(giftID); err != nil {
+		log.Printf("Ошибка при возврате подарочного кода #%d: %v", giftID, err)
+	}
+}
+
+// giftUnavailableReason объясняет на языке lang, почему код не удалось активировать
+func (h *BotHandler) giftUnavailableReason(lang, code string) string {
+	gift, err := h.db.GetGiftCodeByCode(code)
+	if err != nil {
+		return i18n.T(lang, "gift.not_found")
+	}
+
+	switch {
+	case gift.Status == "redeemed":
+		return i18n.T(lang, "gift.already_redeemed")
+	case gift.Status == "expired" || !gift.ExpiresAt.After(time.Now()):
+		return i18n.T(lang, "gift.expired", gift.ExpiresAt.Format("02.01.2006"))
+	default:
+		return i18n.T(lang, "gift.unavailable")
+	}
+}
+
+// handleMyGiftsCommand показывает покупателю статус купленных подарков
+func (h *BotHandler) handleMyGiftsCommand(chatID int64, telegramID int64) {
+	user, err := h.db.GetUserByTelegramID(telegramID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.user_error"))
+		return
+	}
+
+	gifts, err := h.db.GetGiftCodesByBuyerID(user.ID)
+	if err != nil {
+		log.Printf("Ошибка при получении подарков пользователя #%d: %v", user.ID, err)
+		h.sendMessage(chatID, h.t(chatID, "gift.list_error"))
+		return
+	}
+
+	if len(gifts) == 0 {
+		h.sendMessage(chatID, h.t(chatID, "gift.list_empty"))
+		return
+	}
+
+	lang := h.lang(chatID)
+	text := i18n.T(lang, "gift.list_header", len(gifts))
+	for _, gift := range gifts {
+		planName := i18n.T(lang, "gift.plan_fallback", gift.PlanID)
+		if plan, err := h.db.GetSubscriptionPlanByID(gift.PlanID); err == nil {
+			planName = plan.Name
+		}
+
+		var status string
+		switch {
+		case gift.Status == "redeemed" && gift.RedeemedAt != nil:
+			status = i18n.T(lang, "gift.status_redeemed", gift.RedeemedAt.Format("02.01.2006"))
+		case gift.Status == "expired" || !gift.ExpiresAt.After(time.Now()):
+			status = i18n.T(lang, "gift.status_expired")
+		default:
+			status = i18n.T(lang, "gift.status_pending", gift.ExpiresAt.Format("02.01.2006"))
+		}
+
+		text += fmt.Sprintf("`%s` - %s\n%s\n\n", gift.Code, planName, status)
+	}
+
+	h.sendMessage(chatID, text)
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/ilokitv/botVPN/internal/i18n"
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+// languageAuto - значение колбэка для возврата к языку из настроек Telegram
+const languageAuto = "auto"
+
+// setLanguage запоминает язык интерфейса пользователя, чтобы не обращаться к базе данных при каждом сообщении
+func (h *BotHandler) setLanguage(telegramID int64, lang string) {
+	h.languagesMu.Lock()
+	h.languages[telegramID] = lang
+	h.languagesMu.Unlock()
+}
+
+// lang возвращает язык интерфейса пользователя Telegram
+func (h *BotHandler) lang(telegramID int64) string {
+	h.languagesMu.RLock()
+	lang, ok := h.languages[telegramID]
+	h.languagesMu.RUnlock()
+	if ok {
+		return lang
+	}
+
+	user, err := h.db.GetUserByTelegramID(telegramID)
+	if err != nil {
+		return i18n.DefaultLanguage
+	}
+
+	lang = userLang(user)
+	h.setLanguage(telegramID, lang)
+	return lang
+}
+
+// userLang возвращает язык интерфейса пользователя по его настройкам
+func userLang(user *models.User) string {
+	return i18n.Resolve(user.Language, user.LanguageCode)
+}
+
+// inputError - ошибка проверки введенного значения с текстом из каталога
+type inputError struct {
+	key  string
+	args []interface{}
+}
+
+func (e *inputError) Error() string {
+	return i18n.T(i18n.DefaultLanguage, e.key, e.args...)
+}
+
+// errorText возвращает текст ошибки для пользователя: ошибки проверки ввода - на его языке, остальные - как есть
+func (h *BotHandler) errorText(chatID int64, err error) string {
+	var inputErr *inputError
+	if errors.As(err, &inputErr) {
+		return h.t(chatID, inputErr.key, inputErr.args...)
+	}
+	return err.Error()
+}
+
+// t возвращает сообщение из каталога на языке пользователя с указанным chatID
+func (h *BotHandler) t(chatID int64, key string, args ...interface{}) string {
+	return i18n.T(h.lang(chatID), key, args...)
+}
+
+// handleLanguageCommand показывает пользователю выбор языка интерфейса
+func (h *BotHandler) handleLanguageCommand(chatID int64) {
+	lang := h.lang(chatID)
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup()
+	for _, code := range i18n.Languages() {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, i18n.Name(code), "language:"+code),
+		))
+	}
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+		h.button(chatID, i18n.T(lang, "language.auto"), "language:"+languageAuto),
+	))
+
+	msg := tgbotapi.NewMessage(chatID, i18n.T(lang, "language.choose", i18n.Name(lang)))
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+	h.sender.Send(msg)
+}
+
+// handleLanguageSelect сохраняет выбранный пользователем язык и обновляет главное меню
+func (h *BotHandler) handleLanguageSelect(chatID int64, telegramID int64, choice string) {
+	language := choice
+	if choice == languageAuto {
+		language = ""
+	} else if !i18n.IsSupported(choice) {
+		return
+	}
+
+	if err := h.db.SetUserLanguage(telegramID, language); err != nil {
+		log.Printf("Ошибка при сохранении языка пользователя %d: %v", telegramID, err)
+		h.sendMessage(chatID, h.t(chatID, "language.error"))
+		return
+	}
+
+	// Сбрасываем запомненный язык, чтобы при возврате к языку Telegram он был определен заново
+	h.languagesMu.Lock()
+	delete(h.languages, telegramID)
+	h.languagesMu.Unlock()
+
+	lang := h.lang(telegramID)
+	h.sendMainMenu(chatID, i18n.T(lang, "language.changed", i18n.Name(lang)), telegramID)
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/ilokitv/botVPN/internal/service"
+)
+
+// handlePauseSubscription приостанавливает подписку пользователя: пир блокируется, срок подписки не расходуется
+func (h *BotHandler) handlePauseSubscription(chatID int64, telegramID int64, subscriptionID int) {
+	user, subscription, errText := h.getOwnedSubscription(telegramID, subscriptionID)
+	if errText != "" {
+		h.sendMessage(chatID, errText)
+		return
+	}
+
+	if subscription.Status != "active" {
+		h.sendMessage(chatID, h.t(chatID, "pause.only_active"))
+		return
+	}
+
+	plan, err := h.db.GetSubscriptionPlanByID(subscription.PlanID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.plan_error", err))
+		return
+	}
+
+	if subscription.IsTrial || plan.MaxPauseDays <= 0 {
+		h.sendMessage(chatID, h.t(chatID, "pause.unavailable"))
+		return
+	}
+
+	if !subscription.EndDate.After(time.Now()) {
+		h.sendMessage(chatID, h.t(chatID, "pause.expired"))
+		return
+	}
+
+	err = h.subscriptions.Pause(subscription, "Приостановлена пользователем")
+	if errors.Is(err, service.ErrPeerUnavailable) {
+		log.Printf("Ошибка при блокировке пира подписки #%d для паузы: %v", subscription.ID, err)
+		h.sendMessage(chatID, h.t(chatID, "pause.server_unavailable"))
+		return
+	}
+	if err != nil {
+		log.Printf("Ошибка при приостановке подписки #%d: %v", subscription.ID, err)
+		h.sendMessage(chatID, h.t(chatID, "pause.failed"))
+		return
+	}
+
+	log.Printf("Пользователь #%d приостановил подписку #%d", user.ID, subscription.ID)
+
+	text := h.t(chatID, "pause.success",
+		subscription.ID,
+		plan.MaxPauseDays,
+		subscription.PausedAt.AddDate(0, 0, plan.MaxPauseDays).Format("02.01.2006 15:04"),
+	)
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "button.resume"), fmt.Sprintf("resume_subscription:%d", subscription.ID)),
+		),
+	)
+	h.sender.Send(msg)
+}
+
+// handleResumeSubscription возобновляет приостановленную подписку и продлевает ее на время паузы
+func (h *BotHandler) handleResumeSubscription(chatID int64, telegramID int64, subscriptionID int) {
+	user, subscription, errText := h.getOwnedSubscription(telegramID, subscriptionID)
+	if errText != "" {
+		h.sendMessage(chatID, errText)
+		return
+	}
+
+	if subscription.Status != "paused" {
+		h.sendMessage(chatID, h.t(chatID, "resume.not_paused"))
+		return
+	}
+
+	resumed, err := h.subscriptions.Resume(subscription, time.Now(), "Возобновлена пользователем")
+	if errors.Is(err, service.ErrPeerUnavailable) {
+		log.Printf("Ошибка при разблокировке пира подписки #%d после паузы: %v", subscription.ID, err)
+		h.sendMessage(chatID, h.t(chatID, "resume.server_unavailable"))
+		return
+	}
+	if err != nil {
+		log.Printf("Ошибка при возобновлении подписки #%d: %v", subscription.ID, err)
+		h.sendMessage(chatID, h.t(chatID, "resume.error"))
+		return
+	}
+
+	log.Printf("Пользователь #%d возобновил подписку #%d", user.ID, subscription.ID)
+
+	msg := tgbotapi.NewMessage(chatID, h.t(chatID, "resume.success",
+		resumed.ID,
+		resumed.EndDate.Format("02.01.2006"),
+	))
+	msg.ParseMode = "Markdown"
+	h.sender.Send(msg)
+}
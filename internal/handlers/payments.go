@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"log"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/ilokitv/botVPN/internal/models"
+	"github.com/ilokitv/botVPN/internal/money"
+)
+
+// invoiceCurrency возвращает валюту счетов за подписки из настроек платежей
+func (h *BotHandler) invoiceCurrency() string {
+	currency, err := h.config.Payments.GetCurrency()
+	if err != nil {
+		log.Printf("Ошибка в настройках платежей, счета выставляются в рублях: %v", err)
+		return money.RUB
+	}
+	return currency
+}
+
+// planInvoicePrice возвращает цену плана для счета: в валюте из настроек платежей, если для плана задана
+// такая цена, иначе основную цену плана
+func (h *BotHandler) planInvoicePrice(plan *models.SubscriptionPlan) (int64, string) {
+	currency := h.invoiceCurrency()
+	if amount, ok := plan.PriceIn(currency); ok {
+		return amount, currency
+	}
+	return plan.Price, plan.Currency
+}
+
+// newInvoice создает счет на сумму amount в минимальных единицах валюты currency.
+// Счета в Telegram Stars выставляются без токена платежного провайдера.
+func (h *BotHandler) newInvoice(chatID int64, title, description, payload, label string, amount int64, currency string) tgbotapi.InvoiceConfig {
+	provider := h.config.Payments.Provider
+	if currency == money.XTR {
+		provider = ""
+	}
+
+	return tgbotapi.NewInvoice(
+		chatID,
+		title,
+		description,
+		payload,
+		provider,
+		currency,
+		currency,
+		[]tgbotapi.LabeledPrice{
+			{
+				Label:  label,
+				Amount: int(amount),
+			},
+		},
+	)
+}
+
+// telegramPayment создает запись о платеже через Telegram.
+// Telegram передает сумму в минимальных единицах валюты платежа, поэтому она сохраняется без пересчета.
+// Способ оплаты определяется валютой: звезды списываются самим Telegram, остальные валюты - платежным провайдером.
+func telegramPayment(userID int, payment *tgbotapi.SuccessfulPayment) *models.Payment {
+	method := models.PaymentMethodTelegramProvider
+	if payment.Currency == money.XTR {
+		method = models.PaymentMethodTelegramStars
+	}
+
+	return &models.Payment{
+		UserID:        userID,
+		Amount:        int64(payment.TotalAmount),
+		Currency:      payment.Currency,
+		PaymentMethod: method,
+		PaymentID:     payment.TelegramPaymentChargeID,
+		Status:        "completed",
+	}
+}
+
+// formatBalance возвращает сумму по балансу пользователя с обозначением валюты
+func formatBalance(amount int64) string {
+	return money.Format(amount, models.LedgerCurrency)
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/ilokitv/botVPN/internal/models"
+	"github.com/ilokitv/botVPN/internal/money"
+)
+
+func TestTelegramPaymentMethod(t *testing.T) {
+	tests := []struct {
+		currency string
+		want     string
+	}{
+		{money.XTR, models.PaymentMethodTelegramStars},
+		{money.RUB, models.PaymentMethodTelegramProvider},
+		{money.USD, models.PaymentMethodTelegramProvider},
+	}
+
+	for _, tt := range tests {
+		payment := telegramPayment(1, &tgbotapi.SuccessfulPayment{Currency: tt.currency, TotalAmount: 500, TelegramPaymentChargeID: "charge"})
+		if payment.PaymentMethod != tt.want || payment.Currency != tt.currency || payment.Amount != 500 {
+			t.Errorf("telegramPayment(%s) = %+v, want method %s", tt.currency, payment, tt.want)
+		}
+	}
+}
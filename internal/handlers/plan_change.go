@@ -0,0 +1,352 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/ilokitv/botVPN/internal/i18n"
+	"github.com/ilokitv/botVPN/internal/models"
+	"github.com/ilokitv/botVPN/internal/money"
+	"github.com/ilokitv/botVPN/internal/service"
+)
+
+// planChangeQuote содержит расчет перехода подписки на другой план. Суммы указаны в копейках.
+type planChangeQuote struct {
+	Credit    int64     // Стоимость неиспользованных дней текущего плана
+	Charge    int64     // Сумма к доплате
+	Surplus   int64     // Излишек кредита, возвращаемый на баланс
+	StartDate time.Time // Начало нового периода
+	EndDate   time.Time // Окончание нового периода
+}
+
+// calculatePlanChange рассчитывает перерасчет при смене плана подписки.
+// Неиспользованная часть текущего периода засчитывается пропорционально оставшемуся времени,
+// новый план начинает действовать с момента now на полный срок. Пробные подписки кредита не дают.
+// Расчет ведется в валюте баланса: если у одного из планов нет цены в этой валюте, смена плана невозможна.
+func calculatePlanChange(subscription *models.Subscription, currentPlan, targetPlan *models.SubscriptionPlan, now time.Time) (planChangeQuote, bool) {
+	currentPrice, ok := currentPlan.PriceIn(models.LedgerCurrency)
+	if !ok && !subscription.IsTrial {
+		return planChangeQuote{}, false
+	}
+	targetPrice, ok := targetPlan.PriceIn(models.LedgerCurrency)
+	if !ok {
+		return planChangeQuote{}, false
+	}
+
+	quote := planChangeQuote{
+		StartDate: now,
+		EndDate:   now.AddDate(0, 0, targetPlan.Duration),
+	}
+
+	total := subscription.EndDate.Sub(subscription.StartDate)
+	remaining := subscription.EndDate.Sub(now)
+	if remaining > total {
+		remaining = total
+	}
+
+	if !subscription.IsTrial && total > 0 && remaining > 0 {
+		quote.Credit = money.Prorate(currentPrice, int64(remaining), int64(total))
+	}
+
+	difference := targetPrice - quote.Credit
+	if difference > 0 {
+		quote.Charge = difference
+	} else {
+		quote.Surplus = -difference
+	}
+
+	return quote, true
+}
+
+// getOwnedSubscription возвращает пользователя и принадлежащую ему подписку.
+// Если подписка не найдена или принадлежит другому пользователю, возвращается текст ошибки для пользователя.
+func (h *BotHandler) getOwnedSubscription(telegramID int64, subscriptionID int) (*models.User, *models.Subscription, string) {
+	user, err := h.db.GetUserByTelegramID(telegramID)
+	if err != nil {
+		return nil, nil, h.t(telegramID, "common.user_error")
+	}
+
+	subscription, err := h.db.GetSubscriptionByID(subscriptionID)
+	if err != nil || subscription.UserID != user.ID {
+		return nil, nil, h.t(telegramID, "common.subscription_not_found")
+	}
+
+	return user, subscription, ""
+}
+
+// getOwnedActiveSubscription возвращает пользователя и его активную подписку.
+// Если подписку нельзя изменить, возвращается текст ошибки для пользователя.
+func (h *BotHandler) getOwnedActiveSubscription(telegramID int64, subscriptionID int) (*models.User, *models.Subscription, string) {
+	user, subscription, errText := h.getOwnedSubscription(telegramID, subscriptionID)
+	if errText != "" {
+		return nil, nil, errText
+	}
+
+	if subscription.Status != "active" {
+		return nil, nil, h.t(telegramID, "plan_change.only_active")
+	}
+
+	return user, subscription, ""
+}
+
+// showPlanChangeOptions показывает пользователю планы, на которые можно перейти, с учетом перерасчета
+func (h *BotHandler) showPlanChangeOptions(chatID int64, telegramID int64, subscriptionID int) {
+	_, subscription, errText := h.getOwnedActiveSubscription(telegramID, subscriptionID)
+	if errText != "" {
+		h.sendMessage(chatID, errText)
+		return
+	}
+
+	currentPlan, err := h.db.GetSubscriptionPlanByID(subscription.PlanID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.plan_error", err))
+		return
+	}
+
+	plans, err := h.db.GetAllSubscriptionPlans()
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.plans_error", err))
+		return
+	}
+
+	now := time.Now()
+	lang := h.lang(chatID)
+	text := i18n.T(lang, "plan_change.header",
+		subscription.ID,
+		currentPlan.Name,
+		subscription.EndDate.Format("02.01.2006"),
+	)
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup()
+	for i := range plans {
+		plan := &plans[i]
+		if !plan.IsActive || plan.IsTrial || plan.ID == currentPlan.ID {
+			continue
+		}
+
+		quote, ok := calculatePlanChange(subscription, currentPlan, plan, now)
+		if !ok {
+			continue
+		}
+
+		var priceText string
+		if quote.Charge > 0 {
+			priceText = i18n.T(lang, "plan_change.charge", formatBalance(quote.Charge))
+		} else {
+			priceText = i18n.T(lang, "plan_change.surplus", formatBalance(quote.Surplus))
+		}
+
+		targetPrice, _ := plan.PriceIn(models.LedgerCurrency)
+		text += i18n.T(lang, "plan_change.option", plan.Name, plan.Duration, formatBalance(targetPrice), priceText)
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, plan.Name, fmt.Sprintf("change_plan_to:%d:%d", subscription.ID, plan.ID)),
+		))
+	}
+
+	if len(keyboard.InlineKeyboard) == 0 {
+		h.sendMessage(chatID, h.t(chatID, "plan_change.no_plans"))
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+	h.sender.Send(msg)
+}
+
+// handlePlanChange переводит подписку на выбранный план или выставляет счет на доплату
+func (h *BotHandler) handlePlanChange(chatID int64, telegramID int64, subscriptionID, planID int) {
+	user, subscription, errText := h.getOwnedActiveSubscription(telegramID, subscriptionID)
+	if errText != "" {
+		h.sendMessage(chatID, errText)
+		return
+	}
+
+	currentPlan, targetPlan, ok := h.getPlanChangePlans(chatID, subscription, planID)
+	if !ok {
+		return
+	}
+
+	quote, ok := calculatePlanChange(subscription, currentPlan, targetPlan, time.Now())
+	if !ok {
+		h.sendMessage(chatID, h.t(chatID, "plan_change.unavailable"))
+		return
+	}
+	if quote.Charge <= 0 {
+		if err := h.applyPlanChange(chatID, user, subscription, targetPlan, quote, nil); err != nil {
+			h.sendMessage(chatID, h.t(chatID, "plan_change.error"))
+		}
+		return
+	}
+
+	// Доплата рассчитана в валюте баланса, поэтому и счет выставляется в ней
+	invoice := h.newInvoice(
+		chatID,
+		h.t(chatID, "plan_change.invoice_title", targetPlan.Name),
+		h.t(chatID, "plan_change.invoice_desc", subscription.ID, targetPlan.Name, formatBalance(quote.Credit)),
+		fmt.Sprintf("upgrade:%d:%d", subscription.ID, targetPlan.ID), // Payload для идентификации смены плана
+		h.t(chatID, "plan_change.invoice_label", targetPlan.Name),
+		quote.Charge,
+		models.LedgerCurrency,
+	)
+
+	_, err := h.sender.Send(invoice)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.invoice_error", err))
+	}
+}
+
+// getPlanChangePlans возвращает текущий и целевой планы подписки и проверяет, что переход возможен
+func (h *BotHandler) getPlanChangePlans(chatID int64, subscription *models.Subscription, planID int) (*models.SubscriptionPlan, *models.SubscriptionPlan, bool) {
+	currentPlan, err := h.db.GetSubscriptionPlanByID(subscription.PlanID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.plan_error", err))
+		return nil, nil, false
+	}
+
+	targetPlan, err := h.db.GetSubscriptionPlanByID(planID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.plan_error", err))
+		return nil, nil, false
+	}
+
+	if !targetPlan.IsActive || targetPlan.IsTrial || targetPlan.ID == currentPlan.ID {
+		h.sendMessage(chatID, h.t(chatID, "plan_change.unavailable"))
+		return nil, nil, false
+	}
+
+	return currentPlan, targetPlan, true
+}
+
+// settlePlanChange учитывает в расчете смены плана фактически оплаченную сумму paid.
+// Если оплачено больше доплаты, разница возвращается на баланс; если меньше - счет устарел и смена плана невозможна.
+func settlePlanChange(quote planChangeQuote, paid int64) (planChangeQuote, bool) {
+	if paid < quote.Charge {
+		return planChangeQuote{}, false
+	}
+
+	quote.Surplus += paid - quote.Charge
+	quote.Charge = 0
+	return quote, true
+}
+
+// handlePlanChangePayment завершает смену плана после оплаты доплаты.
+// Перерасчет выполняется заново на момент оплаты: за время между выставлением счета и оплатой
+// неиспользованных дней становится меньше, а цены планов могут измениться.
+func (h *BotHandler) handlePlanChangePayment(chatID int64, telegramID int64, subscriptionID, planID int, payment *tgbotapi.SuccessfulPayment) {
+	user, subscription, errText := h.getOwnedActiveSubscription(telegramID, subscriptionID)
+	if errText != "" {
+		h.creditUnappliedPayment(chatID, telegramID, payment, errText, "Оплата смены плана, которую не удалось выполнить")
+		return
+	}
+
+	currentPlan, targetPlan, ok := h.getPlanChangePlans(chatID, subscription, planID)
+	if !ok {
+		h.creditUnappliedPayment(chatID, telegramID, payment, "", "Оплата смены плана, которую не удалось выполнить")
+		return
+	}
+
+	// Счет на доплату выставляется в валюте баланса, оплата в другой валюте не может быть учтена в расчете
+	quote, ok := calculatePlanChange(subscription, currentPlan, targetPlan, time.Now())
+	ok = ok && payment.Currency == models.LedgerCurrency
+	if ok {
+		quote, ok = settlePlanChange(quote, int64(payment.TotalAmount))
+	}
+	if !ok {
+		h.creditUnappliedPayment(chatID, telegramID, payment, h.t(chatID, "plan_change.stale_invoice"), "Оплата устаревшего счета за смену плана")
+		return
+	}
+
+	// Платеж сохраняется вместе со сменой плана: если план сменить не удалось, оплата зачисляется на баланс
+	if err := h.applyPlanChange(chatID, user, subscription, targetPlan, quote, telegramPayment(user.ID, payment)); err != nil {
+		h.creditUnappliedPayment(chatID, telegramID, payment, h.t(chatID, "plan_change.not_applied"), "Оплата смены плана, которую не удалось выполнить")
+	}
+}
+
+// creditUnappliedPayment зачисляет оплату на баланс, если выполнить оплаченное действие не удалось.
+// Оплата в другой валюте, чем баланс, только сохраняется, а пользователь направляется в поддержку.
+func (h *BotHandler) creditUnappliedPayment(chatID int64, telegramID int64, payment *tgbotapi.SuccessfulPayment, reason, description string) {
+	user, err := h.db.GetUserByTelegramID(telegramID)
+	if err != nil {
+		// Без пользователя платеж сохранить нельзя: данные для ручного зачисления остаются в журнале
+		log.Printf("Платеж %s пользователя Telegram %d (%d %s) не сохранен: %v",
+			payment.TelegramPaymentChargeID, telegramID, payment.TotalAmount, payment.Currency, err)
+		h.sendMessage(chatID, h.t(chatID, "payment.processing_error"))
+		return
+	}
+
+	paymentRecord := telegramPayment(user.ID, payment)
+	if err := h.db.AddPayment(paymentRecord); err != nil {
+		log.Printf("Ошибка при сохранении платежа: %v", err)
+	}
+
+	if paymentRecord.Currency != models.LedgerCurrency {
+		log.Printf("Оплата пользователя #%d в валюте %s не зачислена на баланс: %s", user.ID, paymentRecord.Currency, description)
+		text := h.t(chatID, "payment.not_credited", money.Format(paymentRecord.Amount, paymentRecord.Currency))
+		if reason != "" {
+			text = reason + "\n" + text
+		}
+		h.sendMessage(chatID, text)
+		return
+	}
+
+	transaction := &models.LedgerTransaction{
+		UserID:      user.ID,
+		Type:        models.LedgerTopUp,
+		Amount:      paymentRecord.Amount,
+		Description: description,
+	}
+	if paymentRecord.ID != 0 {
+		transaction.PaymentID = &paymentRecord.ID
+	}
+
+	if err := h.db.PostLedgerTransaction(transaction); err != nil {
+		log.Printf("Ошибка при зачислении оплаты на баланс пользователя #%d: %v", user.ID, err)
+		h.sendMessage(chatID, h.t(chatID, "payment.processing_error"))
+		return
+	}
+
+	text := h.t(chatID, "payment.credited", formatBalance(paymentRecord.Amount))
+	if reason != "" {
+		text = reason + "\n" + text
+	}
+	h.sendMessage(chatID, text)
+}
+
+// applyPlanChange переводит подписку на новый план на том же пире, сохраняя платеж за доплату (nil - без платежа)
+// и возврат излишка на баланс в одной транзакции. При ошибке ничего не сохраняется, а сообщение об ошибке
+// пользователю отправляет вызывающий.
+func (h *BotHandler) applyPlanChange(chatID int64, user *models.User, subscription *models.Subscription, targetPlan *models.SubscriptionPlan, quote planChangeQuote, payment *models.Payment) error {
+	oldPlanID := subscription.PlanID
+	err := h.subscriptions.ChangePlan(service.PlanChangeRequest{
+		Subscription: subscription,
+		Plan:         targetPlan,
+		StartDate:    quote.StartDate,
+		EndDate:      quote.EndDate,
+		Payment:      payment,
+		Refund:       quote.Surplus,
+	})
+	if err != nil {
+		log.Printf("Ошибка при смене плана подписки #%d: %v", subscription.ID, err)
+		return err
+	}
+
+	log.Printf("Подписка #%d пользователя #%d переведена с плана #%d на план #%d",
+		subscription.ID, user.ID, oldPlanID, targetPlan.ID)
+
+	successMsg := h.t(chatID, "plan_change.success",
+		targetPlan.Name,
+		quote.EndDate.Format("02.01.2006"),
+	)
+	if quote.Surplus > 0 {
+		successMsg += h.t(chatID, "plan_change.refunded", formatBalance(quote.Surplus))
+	}
+
+	msg := tgbotapi.NewMessage(chatID, successMsg)
+	msg.ParseMode = "Markdown"
+	h.sender.Send(msg)
+	return nil
+}
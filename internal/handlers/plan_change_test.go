@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ilokitv/botVPN/internal/models"
+	"github.com/ilokitv/botVPN/internal/money"
+)
+
+func rubPlan(id int, price int64, duration int) *models.SubscriptionPlan {
+	return &models.SubscriptionPlan{ID: id, Price: price, Currency: money.RUB, Duration: duration, IsActive: true}
+}
+
+func TestCalculatePlanChange(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	// Подписка на 30 дней, из которых осталось 20
+	active := &models.Subscription{ID: 1, PlanID: 1, Status: "active", StartDate: now.AddDate(0, 0, -10), EndDate: now.AddDate(0, 0, 20)}
+	current := rubPlan(1, 30000, 30)
+
+	tests := []struct {
+		name         string
+		subscription *models.Subscription
+		current      *models.SubscriptionPlan
+		target       *models.SubscriptionPlan
+		wantOK       bool
+		want         planChangeQuote
+	}{
+		{
+			name:         "upgrade charges difference",
+			subscription: active,
+			current:      current,
+			target:       rubPlan(2, 60000, 30),
+			wantOK:       true,
+			want:         planChangeQuote{Credit: 20000, Charge: 40000},
+		},
+		{
+			name:         "downgrade returns surplus",
+			subscription: active,
+			current:      current,
+			target:       rubPlan(3, 15000, 30),
+			wantOK:       true,
+			want:         planChangeQuote{Credit: 20000, Surplus: 5000},
+		},
+		{
+			name:         "same price charges used days",
+			subscription: active,
+			current:      current,
+			target:       rubPlan(4, 30000, 30),
+			wantOK:       true,
+			want:         planChangeQuote{Credit: 20000, Charge: 10000},
+		},
+		{
+			name:         "credit equals target price",
+			subscription: active,
+			current:      current,
+			target:       rubPlan(5, 20000, 30),
+			wantOK:       true,
+			want:         planChangeQuote{Credit: 20000},
+		},
+		{
+			name:         "expired subscription gives no credit",
+			subscription: &models.Subscription{ID: 2, PlanID: 1, StartDate: now.AddDate(0, 0, -31), EndDate: now.AddDate(0, 0, -1)},
+			current:      current,
+			target:       rubPlan(2, 60000, 30),
+			wantOK:       true,
+			want:         planChangeQuote{Charge: 60000},
+		},
+		{
+			name:         "not started subscription credits full price",
+			subscription: &models.Subscription{ID: 3, PlanID: 1, StartDate: now.AddDate(0, 0, 1), EndDate: now.AddDate(0, 0, 31)},
+			current:      current,
+			target:       rubPlan(2, 60000, 30),
+			wantOK:       true,
+			want:         planChangeQuote{Credit: 30000, Charge: 30000},
+		},
+		{
+			name:         "trial gives no credit",
+			subscription: &models.Subscription{ID: 4, PlanID: 1, IsTrial: true, StartDate: active.StartDate, EndDate: active.EndDate},
+			current:      &models.SubscriptionPlan{ID: 1, Currency: money.XTR, Duration: 30, IsTrial: true},
+			target:       rubPlan(2, 60000, 30),
+			wantOK:       true,
+			want:         planChangeQuote{Charge: 60000},
+		},
+		{
+			name:         "rounds credit to kopecks",
+			subscription: &models.Subscription{ID: 5, PlanID: 1, StartDate: now.AddDate(0, 0, -1), EndDate: now.AddDate(0, 0, 2)},
+			current:      rubPlan(1, 10000, 3),
+			target:       rubPlan(2, 10000, 3),
+			wantOK:       true,
+			want:         planChangeQuote{Credit: 6667, Charge: 3333},
+		},
+		{
+			name:         "target without ledger price",
+			subscription: active,
+			current:      current,
+			target:       &models.SubscriptionPlan{ID: 6, Price: 500, Currency: money.USD, Duration: 30},
+			wantOK:       false,
+		},
+		{
+			name:         "current without ledger price",
+			subscription: active,
+			current:      &models.SubscriptionPlan{ID: 1, Price: 300, Currency: money.XTR, Duration: 30},
+			target:       rubPlan(2, 60000, 30),
+			wantOK:       false,
+		},
+		{
+			name:         "target with additional ledger price",
+			subscription: active,
+			current:      current,
+			target: &models.SubscriptionPlan{ID: 7, Price: 500, Currency: money.USD, Duration: 30,
+				Prices: []models.PlanPrice{{PlanID: 7, Currency: money.RUB, Amount: 45000}}},
+			wantOK: true,
+			want:   planChangeQuote{Credit: 20000, Charge: 25000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := calculatePlanChange(tt.subscription, tt.current, tt.target, now)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+
+			tt.want.StartDate = now
+			tt.want.EndDate = now.AddDate(0, 0, tt.target.Duration)
+			if got != tt.want {
+				t.Errorf("quote = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSettlePlanChange(t *testing.T) {
+	tests := []struct {
+		name   string
+		quote  planChangeQuote
+		paid   int64
+		wantOK bool
+		want   planChangeQuote
+	}{
+		{
+			name:   "exact payment",
+			quote:  planChangeQuote{Credit: 20000, Charge: 40000},
+			paid:   40000,
+			wantOK: true,
+			want:   planChangeQuote{Credit: 20000},
+		},
+		{
+			name:   "overpayment after credit grew is returned",
+			quote:  planChangeQuote{Credit: 21000, Charge: 39000},
+			paid:   40000,
+			wantOK: true,
+			want:   planChangeQuote{Credit: 21000, Surplus: 1000},
+		},
+		{
+			name:   "payment when change became free",
+			quote:  planChangeQuote{Credit: 35000, Surplus: 5000},
+			paid:   1000,
+			wantOK: true,
+			want:   planChangeQuote{Credit: 35000, Surplus: 6000},
+		},
+		{
+			name:   "stale invoice for lower amount",
+			quote:  planChangeQuote{Credit: 15000, Charge: 45000},
+			paid:   40000,
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := settlePlanChange(tt.quote, tt.paid)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && got != tt.want {
+				t.Errorf("quote = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
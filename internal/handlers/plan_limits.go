@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+// maxPlanLimitDays - верхняя граница лимитов плана в днях, которую может задать администратор
+const maxPlanLimitDays = 365
+
+// Лимиты плана подписки, задаваемые в днях
+const (
+	planLimitMaxPause  = "max_pause"  // Максимальная длительность паузы
+	planLimitGraceDays = "grace_days" // Длительность льготного периода
+)
+
+// startPlanLimitEdit запрашивает у администратора новое значение лимита плана в днях
+func (h *BotHandler) startPlanLimitEdit(chatID int64, planID int, limit string) {
+	plan, err := h.db.GetSubscriptionPlanByID(planID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.plan_error", err))
+		return
+	}
+
+	var prompt string
+	switch limit {
+	case planLimitMaxPause:
+		prompt = h.t(chatID, "plan_limits.max_pause_prompt", plan.Name, plan.MaxPauseDays)
+	case planLimitGraceDays:
+		prompt = h.t(chatID, "plan_limits.grace_prompt", plan.Name, plan.GraceDays)
+	default:
+		h.sendMessage(chatID, h.t(chatID, "plan_limits.unknown"))
+		return
+	}
+
+	h.setState(chatID, UserState{
+		State: "edit_plan_limit",
+		Data: map[string]string{
+			"plan_id": strconv.Itoa(plan.ID),
+			"limit":   limit,
+		},
+	})
+
+	h.sendMessage(chatID, prompt)
+}
+
+// handlePlanLimitInput сохраняет введенное администратором значение лимита плана
+func (h *BotHandler) handlePlanLimitInput(message *tgbotapi.Message, userState UserState) {
+	chatID := message.Chat.ID
+
+	days, err := strconv.Atoi(strings.TrimSpace(message.Text))
+	if err != nil || days < 0 || days > maxPlanLimitDays {
+		h.sendMessage(chatID, h.t(chatID, "plan_limits.invalid_days", maxPlanLimitDays))
+		return
+	}
+
+	h.clearState(chatID)
+
+	planID, _ := strconv.Atoi(userState.Data["plan_id"])
+	before, err := h.db.GetSubscriptionPlanByID(planID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.plan_error", err))
+		return
+	}
+
+	switch userState.Data["limit"] {
+	case planLimitMaxPause:
+		err = h.db.SetSubscriptionPlanMaxPause(planID, days)
+	case planLimitGraceDays:
+		err = h.db.SetSubscriptionPlanGraceDays(planID, days)
+	default:
+		err = fmt.Errorf("unknown plan limit: %s", userState.Data["limit"])
+	}
+
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "plan_limits.save_error", err))
+		return
+	}
+
+	if after, err := h.db.GetSubscriptionPlanByID(planID); err == nil {
+		h.audit(message.From.ID, models.AuditPlanEdit, models.AuditTargetPlan, planID, before, after)
+	}
+
+	h.viewPlanDetails(chatID, planID)
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/ilokitv/botVPN/internal/models"
+	"github.com/ilokitv/botVPN/internal/money"
+)
+
+// formatPlanPrices возвращает основную цену плана и цены в дополнительных валютах через запятую
+func formatPlanPrices(plan *models.SubscriptionPlan) string {
+	prices := []string{money.Format(plan.Price, plan.Currency)}
+	for _, price := range plan.Prices {
+		prices = append(prices, money.Format(price.Amount, price.Currency))
+	}
+	return strings.Join(prices, ", ")
+}
+
+// showPlanPrices показывает администратору цены плана во всех поддерживаемых валютах
+func (h *BotHandler) showPlanPrices(chatID int64, planID int) {
+	plan, err := h.db.GetSubscriptionPlanByID(planID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.plan_error", err))
+		return
+	}
+
+	var text strings.Builder
+	text.WriteString(h.t(chatID, "plan_prices.title", plan.Name))
+	text.WriteString(h.t(chatID, "plan_prices.base", money.Format(plan.Price, plan.Currency)))
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup()
+	for _, currency := range money.Currencies() {
+		if currency == plan.Currency {
+			continue
+		}
+
+		if amount, ok := plan.PriceIn(currency); ok {
+			fmt.Fprintf(&text, "%s: %s\n", currency, money.Format(amount, currency))
+		} else {
+			fmt.Fprintf(&text, "%s: %s\n", currency, h.t(chatID, "plan_prices.not_set"))
+		}
+
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "plan_prices.edit_button", currency), fmt.Sprintf("plan_price:%d:%s", plan.ID, currency)),
+		))
+	}
+
+	text.WriteString(h.t(chatID, "plan_prices.note"))
+
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+		h.button(chatID, h.t(chatID, "plan_prices.back"), fmt.Sprintf("plan_action:view:%d", plan.ID)),
+	))
+
+	msg := tgbotapi.NewMessage(chatID, text.String())
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+	h.sender.Send(msg)
+}
+
+// startPlanPriceEdit запрашивает у администратора цену плана в дополнительной валюте
+func (h *BotHandler) startPlanPriceEdit(chatID int64, planID int, currency string) {
+	plan, err := h.db.GetSubscriptionPlanByID(planID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.plan_error", err))
+		return
+	}
+
+	currency, err = money.ParseCurrency(currency)
+	if err != nil || currency == plan.Currency {
+		h.sendMessage(chatID, h.t(chatID, "plan_prices.unknown_currency"))
+		return
+	}
+
+	current := h.t(chatID, "plan_prices.not_set")
+	if amount, ok := plan.PriceIn(currency); ok {
+		current = money.Format(amount, currency)
+	}
+
+	h.setState(chatID, UserState{
+		State: "edit_plan_price_point",
+		Data: map[string]string{
+			"plan_id":  strconv.Itoa(plan.ID),
+			"currency": currency,
+		},
+	})
+
+	h.sendMessage(chatID, h.t(chatID, "plan_prices.prompt", plan.Name, currency, current, currency))
+}
+
+// handlePlanPriceInput сохраняет введенную администратором цену плана в дополнительной валюте
+func (h *BotHandler) handlePlanPriceInput(message *tgbotapi.Message, userState UserState) {
+	chatID := message.Chat.ID
+	currency := userState.Data["currency"]
+
+	remove := strings.TrimSpace(message.Text) == "-"
+	var amount int64
+	if !remove {
+		var err error
+		amount, err = money.Parse(message.Text, currency)
+		if err != nil || amount <= 0 {
+			h.sendMessage(chatID, h.t(chatID, "plan_prices.invalid_amount", currency))
+			return
+		}
+	}
+
+	h.clearState(chatID)
+
+	planID, _ := strconv.Atoi(userState.Data["plan_id"])
+	before, err := h.db.GetSubscriptionPlanByID(planID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.plan_error", err))
+		return
+	}
+
+	if remove {
+		err = h.db.DeletePlanPrice(planID, currency)
+	} else {
+		err = h.db.SetPlanPrice(planID, currency, amount)
+	}
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "plan_prices.save_error", err))
+		return
+	}
+
+	if after, err := h.db.GetSubscriptionPlanByID(planID); err == nil {
+		h.audit(message.From.ID, models.AuditPlanEdit, models.AuditTargetPlan, planID, before, after)
+	}
+
+	h.showPlanPrices(chatID, planID)
+}
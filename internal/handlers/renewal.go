@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/ilokitv/botVPN/internal/models"
+	"github.com/ilokitv/botVPN/internal/service"
+)
+
+// getRenewableSubscription возвращает подписку пользователя и ее план, если подписку можно продлить.
+// Если продление невозможно, возвращается текст ошибки для пользователя.
+func (h *BotHandler) getRenewableSubscription(telegramID int64, subscriptionID int) (*models.User, *models.Subscription, *models.SubscriptionPlan, string) {
+	user, subscription, errText := h.getOwnedSubscription(telegramID, subscriptionID)
+	if errText != "" {
+		return nil, nil, nil, errText
+	}
+
+	if subscription.Status != "active" && subscription.Status != "grace" {
+		return nil, nil, nil, h.t(telegramID, "renew.not_allowed")
+	}
+
+	if subscription.IsTrial {
+		return nil, nil, nil, h.t(telegramID, "renew.trial")
+	}
+
+	plan, err := h.db.GetSubscriptionPlanByID(subscription.PlanID)
+	if err != nil {
+		return nil, nil, nil, h.t(telegramID, "renew.plan_error")
+	}
+
+	if !plan.IsActive || plan.IsTrial {
+		return nil, nil, nil, h.t(telegramID, "renew.plan_unavailable")
+	}
+
+	return user, subscription, plan, ""
+}
+
+// handleRenewSubscription выставляет счет на продление подписки с сохранением текущей конфигурации
+func (h *BotHandler) handleRenewSubscription(chatID int64, telegramID int64, subscriptionID int) {
+	_, subscription, plan, errText := h.getRenewableSubscription(telegramID, subscriptionID)
+	if errText != "" {
+		h.sendMessage(chatID, errText)
+		return
+	}
+
+	amount, currency := h.planInvoicePrice(plan)
+	invoice := h.newInvoice(
+		chatID,
+		h.t(chatID, "renew.invoice_title", subscription.ID),
+		h.t(chatID, "renew.invoice_desc", plan.Name, plan.Duration),
+		fmt.Sprintf("renew:%d", subscription.ID), // Payload для идентификации продления
+		h.t(chatID, "renew.invoice_label", plan.Name),
+		amount,
+		currency,
+	)
+
+	_, err := h.sender.Send(invoice)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.invoice_error", err))
+	}
+}
+
+// handleRenewPayment продлевает подписку после оплаты и восстанавливает доступ, если подписка была в льготном периоде
+func (h *BotHandler) handleRenewPayment(chatID int64, telegramID int64, subscriptionID int, payment *tgbotapi.SuccessfulPayment) {
+	user, subscription, plan, errText := h.getRenewableSubscription(telegramID, subscriptionID)
+	if errText != "" {
+		h.creditUnappliedPayment(chatID, telegramID, payment, errText,
+			fmt.Sprintf("Оплата продления подписки #%d, которое не удалось выполнить", subscriptionID))
+		return
+	}
+
+	// Платеж сохраняется вместе с продлением
+	paymentRecord := telegramPayment(user.ID, payment)
+
+	renewed, oldStatus, err := h.subscriptions.Renew(subscription.ID, plan.Duration, paymentRecord)
+	if errors.Is(err, service.ErrPeerUnavailable) {
+		// Подписка продлена, но пир льготного периода не удалось разблокировать
+		log.Printf("Ошибка при разблокировке пира продленной подписки #%d: %v", subscription.ID, err)
+		h.sendMessage(chatID, h.t(chatID, "renew.unblock_failed",
+			renewed.ID, renewed.EndDate.Format("02.01.2006")))
+		return
+	}
+	if err != nil {
+		log.Printf("Ошибка при продлении подписки #%d: %v", subscription.ID, err)
+		h.creditUnappliedPayment(chatID, telegramID, payment, h.t(chatID, "renew.failed"),
+			fmt.Sprintf("Оплата продления подписки #%d, которое не удалось выполнить", subscription.ID))
+		return
+	}
+
+	log.Printf("Подписка #%d пользователя #%d продлена до %s (статус до продления: %s)",
+		renewed.ID, user.ID, renewed.EndDate.Format("02.01.2006"), oldStatus)
+
+	successMsg := h.t(chatID, "renew.success",
+		renewed.ID,
+		plan.Name,
+		renewed.EndDate.Format("02.01.2006"),
+	)
+
+	msg := tgbotapi.NewMessage(chatID, successMsg)
+	msg.ParseMode = "Markdown"
+	h.sender.Send(msg)
+}
@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/ilokitv/botVPN/internal/i18n"
+	"github.com/ilokitv/botVPN/internal/models"
+	"github.com/ilokitv/botVPN/internal/money"
+)
+
+// revenuePeriods - периоды (в днях), доступные для выбора на экране доходов
+var revenuePeriods = []int{7, 30, 90, 365}
+
+// defaultRevenuePeriodDays - период, который показывается по умолчанию
+const defaultRevenuePeriodDays = 30
+
+// maxRevenueRows - максимальное количество строк в разбивке на одной странице, полные данные доступны в CSV
+const maxRevenueRows = 31
+
+// revenuePages - ключи каталога с названиями страниц экрана доходов
+var revenuePages = []string{"revenue.page_summary", "revenue.page_plans", "revenue.page_days", "revenue.page_weeks", "revenue.page_months"}
+
+// revenueGranularityByPage - интервал группировки для страниц с разбивкой по времени
+var revenueGranularityByPage = map[int]string{
+	2: "day",
+	3: "week",
+	4: "month",
+}
+
+// normalizeRevenuePeriod возвращает допустимый период в днях
+func normalizeRevenuePeriod(days int) int {
+	for _, period := range revenuePeriods {
+		if period == days {
+			return days
+		}
+	}
+	return defaultRevenuePeriodDays
+}
+
+// revenueRange возвращает границы периода отчета
+func revenueRange(days int) (time.Time, time.Time) {
+	to := time.Now()
+	return to.AddDate(0, 0, -days), to
+}
+
+// formatRevenueRows формирует строки разбивки поступлений по времени (по строке на интервал и валюту)
+func formatRevenueRows(lang string, points []models.RevenuePoint, granularity string) string {
+	if len(points) == 0 {
+		return i18n.T(lang, "revenue.no_payments")
+	}
+
+	layout := "02.01.2006"
+	if granularity == "month" {
+		layout = "01.2006"
+	}
+
+	// Показываем последние интервалы, полная разбивка доступна в CSV
+	hidden := 0
+	if len(points) > maxRevenueRows {
+		hidden = len(points) - maxRevenueRows
+		points = points[hidden:]
+	}
+
+	var sb strings.Builder
+	for _, point := range points {
+		label := point.Period.Format(layout)
+		if granularity == "week" {
+			label = i18n.T(lang, "revenue.week_of", label)
+		}
+		sb.WriteString(fmt.Sprintf("%s: %s (%d)\n", label, money.Format(point.Revenue, point.Currency), point.Payments))
+	}
+	if hidden > 0 {
+		sb.WriteString(i18n.T(lang, "revenue.hidden_rows", hidden))
+	}
+	return sb.String()
+}
+
+// showRevenuePage отображает страницу экрана доходов за выбранный период
+func (h *BotHandler) showRevenuePage(chatID int64, days int, page int) {
+	days = normalizeRevenuePeriod(days)
+	if page < 0 || page >= len(revenuePages) {
+		page = 0
+	}
+
+	from, to := revenueRange(days)
+
+	text := h.t(chatID, "revenue.header", days, h.t(chatID, revenuePages[page]))
+
+	switch page {
+	case 0:
+		stats, err := h.db.GetRevenueStats(from, to)
+		if err != nil {
+			h.sendMessage(chatID, h.t(chatID, "revenue.stats_error", err))
+			return
+		}
+
+		// Суммы в разных валютах не складываются, поэтому денежные показатели выводятся по каждой валюте
+		if len(stats.Currencies) == 0 {
+			text += h.t(chatID, "revenue.no_payments") + "\n"
+		}
+		for _, revenue := range stats.Currencies {
+			text += h.t(chatID, "revenue.currency_summary",
+				revenue.Currency,
+				money.Format(revenue.Revenue, revenue.Currency), revenue.PaymentsCount,
+				revenue.OrdersCount, money.Format(revenue.OrdersRevenue, revenue.Currency),
+				money.Format(revenue.AverageOrderValue, revenue.Currency),
+				money.Format(revenue.MRR, revenue.Currency),
+			)
+		}
+
+		text += h.t(chatID, "revenue.summary",
+			stats.ActivePaidSubscriptions,
+			stats.Renewals,
+			stats.Expirations,
+			stats.RenewalRate,
+			stats.ChurnRate,
+		)
+
+	case 1:
+		plans, err := h.db.GetRevenueByPlan(from, to)
+		if err != nil {
+			h.sendMessage(chatID, h.t(chatID, "revenue.plans_error", err))
+			return
+		}
+
+		if len(plans) == 0 {
+			text += h.t(chatID, "revenue.no_orders")
+		}
+		for _, plan := range plans {
+			text += h.t(chatID, "revenue.plan_row", plan.PlanName, plan.Orders, money.Format(plan.Revenue, plan.Currency))
+		}
+
+	default:
+		granularity := revenueGranularityByPage[page]
+		points, err := h.db.GetRevenueByPeriod(granularity, from, to)
+		if err != nil {
+			h.sendMessage(chatID, h.t(chatID, "revenue.payments_error", err))
+			return
+		}
+		text += formatRevenueRows(h.lang(chatID), points, granularity)
+	}
+
+	// Выбор периода
+	var periodRow []tgbotapi.InlineKeyboardButton
+	for _, period := range revenuePeriods {
+		label := h.t(chatID, "revenue.period", period)
+		if period == days {
+			label = "✅ " + label
+		}
+		periodRow = append(periodRow, h.button(chatID, label,
+			fmt.Sprintf("revenue_page:%d:%d", period, page)))
+	}
+
+	// Переключение страниц
+	var navRow []tgbotapi.InlineKeyboardButton
+	if page > 0 {
+		navRow = append(navRow, h.button(chatID, "◀️ "+h.t(chatID, revenuePages[page-1]),
+			fmt.Sprintf("revenue_page:%d:%d", days, page-1)))
+	}
+	if page < len(revenuePages)-1 {
+		navRow = append(navRow, h.button(chatID, h.t(chatID, revenuePages[page+1])+" ▶️",
+			fmt.Sprintf("revenue_page:%d:%d", days, page+1)))
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		periodRow,
+		navRow,
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "revenue.export_csv"), fmt.Sprintf("revenue_csv:%d", days)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "admin.back"), "admin_menu:stats"),
+		),
+	)
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+	h.sender.Send(msg)
+}
+
+// buildRevenueCSV формирует CSV-выгрузку статистики доходов.
+// Суммы выводятся в основных единицах валюты, указанной в столбце currency.
+func buildRevenueCSV(stats *models.RevenueStats, plans []models.PlanRevenue, series map[string][]models.RevenuePoint) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	rows := [][]string{
+		{"section", "key", "currency", "value", "count"},
+		{"summary", "period_start", "", stats.PeriodStart.Format(time.RFC3339), ""},
+		{"summary", "period_end", "", stats.PeriodEnd.Format(time.RFC3339), ""},
+	}
+
+	for _, revenue := range stats.Currencies {
+		currency := revenue.Currency
+		rows = append(rows,
+			[]string{"summary", "revenue", currency, money.Decimal(revenue.Revenue, currency), strconv.Itoa(revenue.PaymentsCount)},
+			[]string{"summary", "orders_revenue", currency, money.Decimal(revenue.OrdersRevenue, currency), strconv.Itoa(revenue.OrdersCount)},
+			[]string{"summary", "average_order_value", currency, money.Decimal(revenue.AverageOrderValue, currency), ""},
+			[]string{"summary", "mrr", currency, money.Decimal(revenue.MRR, currency), ""},
+		)
+	}
+
+	rows = append(rows,
+		[]string{"summary", "active_paid_subscriptions", "", "", strconv.Itoa(stats.ActivePaidSubscriptions)},
+		[]string{"summary", "renewals", "", "", strconv.Itoa(stats.Renewals)},
+		[]string{"summary", "expirations", "", "", strconv.Itoa(stats.Expirations)},
+		[]string{"summary", "renewal_rate_percent", "", strconv.FormatFloat(stats.RenewalRate, 'f', 1, 64), ""},
+		[]string{"summary", "churn_rate_percent", "", strconv.FormatFloat(stats.ChurnRate, 'f', 1, 64), ""},
+	)
+
+	for _, plan := range plans {
+		rows = append(rows, []string{"plan", plan.PlanName, plan.Currency,
+			money.Decimal(plan.Revenue, plan.Currency), strconv.Itoa(plan.Orders)})
+	}
+
+	for _, granularity := range []string{"day", "week", "month"} {
+		for _, point := range series[granularity] {
+			rows = append(rows, []string{granularity, point.Period.Format("2006-01-02"), point.Currency,
+				money.Decimal(point.Revenue, point.Currency), strconv.Itoa(point.Payments)})
+		}
+	}
+
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// exportRevenueCSV отправляет администратору CSV-выгрузку статистики доходов за период
+func (h *BotHandler) exportRevenueCSV(chatID int64, days int) {
+	days = normalizeRevenuePeriod(days)
+	from, to := revenueRange(days)
+
+	stats, err := h.db.GetRevenueStats(from, to)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "revenue.stats_error", err))
+		return
+	}
+
+	plans, err := h.db.GetRevenueByPlan(from, to)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "revenue.plans_error", err))
+		return
+	}
+
+	series := make(map[string][]models.RevenuePoint)
+	for _, granularity := range []string{"day", "week", "month"} {
+		points, err := h.db.GetRevenueByPeriod(granularity, from, to)
+		if err != nil {
+			h.sendMessage(chatID, h.t(chatID, "revenue.payments_error", err))
+			return
+		}
+		series[granularity] = points
+	}
+
+	data, err := buildRevenueCSV(stats, plans, series)
+	if err != nil {
+		log.Printf("Ошибка при формировании CSV-выгрузки доходов: %v", err)
+		h.sendMessage(chatID, h.t(chatID, "revenue.export_error"))
+		return
+	}
+
+	document := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{
+		Name:  fmt.Sprintf("revenue_%dd_%s.csv", days, to.Format("20060102")),
+		Bytes: data,
+	})
+	document.Caption = h.t(chatID, "revenue.export_caption", days)
+
+	if _, err := h.sender.Send(document); err != nil {
+		h.sendMessage(chatID, h.t(chatID, "revenue.export_send_error", err))
+	}
+}
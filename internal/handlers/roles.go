@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/ilokitv/botVPN/internal/i18n"
+	"github.com/ilokitv/botVPN/internal/models"
+	"github.com/ilokitv/botVPN/internal/roles"
+)
+
+// roleNone - значение кнопки для снятия роли
+const roleNone = "none"
+
+// role возвращает роль сотрудника для пользователя Telegram (пустая строка - обычный пользователь)
+func (h *BotHandler) role(telegramID int64) string {
+	user, err := h.db.GetUserByTelegramID(telegramID)
+	if err != nil {
+		return ""
+	}
+	return user.Role
+}
+
+// can проверяет, есть ли у пользователя Telegram хотя бы одно из прав
+func (h *BotHandler) can(telegramID int64, permissions ...roles.Permission) bool {
+	return roles.CanAny(h.role(telegramID), permissions...)
+}
+
+// isBootstrapOwner проверяет, указан ли пользователь в admin_ids конфигурации.
+// Такие пользователи получают роль владельца; дальше роли управляются из бота.
+func (h *BotHandler) isBootstrapOwner(telegramID int64) bool {
+	for _, adminID := range h.config.Bot.AdminIDs {
+		if adminID == telegramID {
+			return true
+		}
+	}
+	return false
+}
+
+// notifyStaff отправляет сообщение всем сотрудникам с указанным правом, кроме exceptTelegramID
+func (h *BotHandler) notifyStaff(permission roles.Permission, text string, exceptTelegramID int64) {
+	staff, err := h.db.GetAllAdmins()
+	if err != nil {
+		log.Printf("Ошибка при получении списка сотрудников: %v", err)
+		return
+	}
+
+	for _, member := range staff {
+		if member.TelegramID == exceptTelegramID || !roles.Can(member.Role, permission) {
+			continue
+		}
+		h.sender.Send(tgbotapi.NewMessage(member.TelegramID, text))
+	}
+}
+
+// showRolesMenu показывает список сотрудников и их роли
+func (h *BotHandler) showRolesMenu(chatID int64) {
+	staff, err := h.db.GetAllAdmins()
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "roles.list_error", err))
+		return
+	}
+
+	lang := h.lang(chatID)
+	var text strings.Builder
+	text.WriteString(i18n.T(lang, "roles.title"))
+	if len(staff) == 0 {
+		text.WriteString(i18n.T(lang, "roles.empty"))
+	}
+	for _, member := range staff {
+		fmt.Fprintf(&text, "%s — %s (ID %d)\n", roleName(lang, member.Role), displayName(member.Username, member.FirstName, member.LastName), member.TelegramID)
+	}
+	text.WriteString(i18n.T(lang, "roles.hint"))
+	text.WriteString(i18n.T(lang, "roles.permissions"))
+
+	msg := tgbotapi.NewMessage(chatID, text.String())
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, i18n.T(lang, "admin.back_button"), "admin_menu:main"),
+		),
+	)
+	h.sender.Send(msg)
+}
+
+// showUserRoleOptions предлагает выбрать роль для пользователя
+func (h *BotHandler) showUserRoleOptions(chatID int64, userID int) {
+	user, err := h.db.GetUserByID(userID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "admin_users.not_found", userID))
+		return
+	}
+
+	lang := h.lang(chatID)
+	keyboard := tgbotapi.NewInlineKeyboardMarkup()
+	for _, role := range roles.All() {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, roleName(lang, role), fmt.Sprintf("user_role:%d:%s", user.ID, role)),
+		))
+	}
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+		h.button(chatID, i18n.T(lang, "roles.remove_button"), fmt.Sprintf("user_role:%d:%s", user.ID, roleNone)),
+	))
+
+	msg := tgbotapi.NewMessage(chatID, i18n.T(lang, "roles.choose",
+		displayName(user.Username, user.FirstName, user.LastName), roleName(lang, user.Role)))
+	msg.ReplyMarkup = keyboard
+	h.sender.Send(msg)
+}
+
+// handleSetUserRole назначает пользователю роль, выбранную владельцем
+func (h *BotHandler) handleSetUserRole(chatID int64, actorTelegramID int64, userID int, role string) {
+	if role == roleNone {
+		role = ""
+	} else if !roles.IsValid(role) {
+		h.sendMessage(chatID, h.t(chatID, "roles.unknown"))
+		return
+	}
+
+	user, err := h.db.GetUserByID(userID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "admin_users.not_found", userID))
+		return
+	}
+
+	// Не даем владельцу случайно лишить себя доступа к управлению ролями
+	if user.TelegramID == actorTelegramID {
+		h.sendMessage(chatID, h.t(chatID, "roles.own_role"))
+		return
+	}
+
+	if err := h.db.SetUserRole(user.ID, role); err != nil {
+		log.Printf("Ошибка при изменении роли пользователя #%d: %v", user.ID, err)
+		h.sendMessage(chatID, h.t(chatID, "roles.save_error"))
+		return
+	}
+
+	log.Printf("Сотрудник %d изменил роль пользователя #%d: %q -> %q", actorTelegramID, user.ID, user.Role, role)
+	h.audit(actorTelegramID, models.AuditRoleChange, models.AuditTargetUser, user.ID,
+		map[string]string{"role": user.Role}, map[string]string{"role": role})
+
+	lang := userLang(user)
+	if role == "" {
+		h.sender.Send(tgbotapi.NewMessage(user.TelegramID, i18n.T(lang, "role.revoked")))
+	} else {
+		h.sender.Send(tgbotapi.NewMessage(user.TelegramID, i18n.T(lang, "role.granted", i18n.T(lang, "role."+role))))
+	}
+
+	h.sendMessage(chatID, h.t(chatID, "roles.changed",
+		displayName(user.Username, user.FirstName, user.LastName), roleName(h.lang(chatID), role)))
+}
+
+// roleName возвращает название роли для панели управления на языке lang
+func roleName(lang, role string) string {
+	if !roles.IsValid(role) {
+		return i18n.T(lang, "roles.name_user")
+	}
+	return i18n.T(lang, "roles.name_"+role)
+}
+
+// displayName возвращает имя пользователя для панели управления
+func displayName(username, firstName, lastName string) string {
+	if username != "" {
+		return "@" + username
+	}
+	return strings.TrimSpace(firstName + " " + lastName)
+}
@@ -0,0 +1,340 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/ilokitv/botVPN/internal/i18n"
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+// Параметры сервера, которые можно изменить в мастере редактирования
+const (
+	serverFieldIP         = "ip"
+	serverFieldPort       = "port"
+	serverFieldUser       = "user"
+	serverFieldPassword   = "pass"
+	serverFieldMaxClients = "max"
+	serverFieldStatus     = "status"
+)
+
+// serverFieldPrompts - ключи каталога с запросами нового значения для параметров сервера
+var serverFieldPrompts = map[string]string{
+	serverFieldIP:         "server_edit.prompt_ip",
+	serverFieldPort:       "server_edit.prompt_port",
+	serverFieldUser:       "server_edit.prompt_user",
+	serverFieldPassword:   "server_edit.prompt_password",
+	serverFieldMaxClients: "server_edit.prompt_max_clients",
+}
+
+// reissueStatuses - статусы подписок, которым после смены адреса сервера отправляется новая конфигурация
+var reissueStatuses = []string{"active", "grace", "paused"}
+
+// serverAuditSnapshot - состояние сервера для журнала действий. Сам SSH-пароль в журнал не попадает,
+// поэтому при его смене записывается только отметка об изменении.
+type serverAuditSnapshot struct {
+	*models.Server
+	SSHPassword string `json:"ssh_password,omitempty"`
+}
+
+// serverAuditValues возвращает состояния сервера до и после изменения для журнала действий
+func serverAuditValues(before, after *models.Server) (serverAuditSnapshot, serverAuditSnapshot) {
+	afterSnapshot := serverAuditSnapshot{Server: after}
+	if before.SSHPassword != after.SSHPassword {
+		afterSnapshot.SSHPassword = "changed"
+	}
+	return serverAuditSnapshot{Server: before}, afterSnapshot
+}
+
+// hostnamePattern - допустимое доменное имя сервера
+var hostnamePattern = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?\.)+[a-zA-Z]{2,63}$`)
+
+// serverFieldValue возвращает текущее значение параметра сервера для показа администратору
+func serverFieldValue(server *models.Server, field string) string {
+	switch field {
+	case serverFieldIP:
+		return server.IP
+	case serverFieldPort:
+		return strconv.Itoa(server.Port)
+	case serverFieldUser:
+		return server.SSHUser
+	case serverFieldPassword:
+		return maskPassword(server.SSHPassword)
+	case serverFieldMaxClients:
+		return strconv.Itoa(server.MaxClients)
+	default:
+		return ""
+	}
+}
+
+// applyServerField проверяет новое значение параметра и записывает его в server
+func applyServerField(server *models.Server, field, value string) error {
+	switch field {
+	case serverFieldIP:
+		if net.ParseIP(value) == nil && !hostnamePattern.MatchString(value) {
+			return &inputError{key: "server_edit.invalid_ip"}
+		}
+		server.IP = value
+
+	case serverFieldPort:
+		port, err := strconv.Atoi(value)
+		if err != nil || port < 1 || port > 65535 {
+			return &inputError{key: "server_edit.invalid_port"}
+		}
+		server.Port = port
+
+	case serverFieldUser:
+		if value == "" || strings.ContainsAny(value, " \t") {
+			return &inputError{key: "server_edit.invalid_user"}
+		}
+		server.SSHUser = value
+
+	case serverFieldPassword:
+		if value == "" {
+			return &inputError{key: "server_edit.invalid_password"}
+		}
+		server.SSHPassword = value
+
+	case serverFieldMaxClients:
+		maxClients, err := strconv.Atoi(value)
+		if err != nil || maxClients < 1 {
+			return &inputError{key: "server_edit.invalid_max_clients"}
+		}
+		if maxClients < server.CurrentClients {
+			return &inputError{key: "server_edit.max_clients_below_current", args: []interface{}{server.CurrentClients}}
+		}
+		server.MaxClients = maxClients
+
+	default:
+		return &inputError{key: "server_edit.unknown_field_name", args: []interface{}{field}}
+	}
+	return nil
+}
+
+// showServerEditMenu показывает параметры сервера и кнопки для их изменения
+func (h *BotHandler) showServerEditMenu(chatID int64, serverID int) {
+	server, err := h.db.GetServerByID(serverID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "admin_servers.not_found_id", serverID))
+		return
+	}
+
+	// Формируем сообщение для редактирования
+	responseText := h.t(chatID, "server_edit.menu",
+		server.ID,
+		server.IP,
+		server.Port,
+		server.SSHUser,
+		maskPassword(server.SSHPassword),
+		server.MaxClients,
+		getStatusEmoji(server.IsActive),
+	)
+
+	// Создаем клавиатуру с кнопками для редактирования
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, "1️⃣ IP", fmt.Sprintf("server_edit:ip:%d", server.ID)),
+			h.button(chatID, h.t(chatID, "server_edit.button_port"), fmt.Sprintf("server_edit:port:%d", server.ID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "server_edit.button_user"), fmt.Sprintf("server_edit:user:%d", server.ID)),
+			h.button(chatID, h.t(chatID, "server_edit.button_password"), fmt.Sprintf("server_edit:pass:%d", server.ID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "server_edit.button_max_clients"), fmt.Sprintf("server_edit:max:%d", server.ID)),
+			h.button(chatID, h.t(chatID, "server_edit.button_status"), fmt.Sprintf("server_edit:status:%d", server.ID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "admin_servers.back_to_server"), fmt.Sprintf("server_action:view:%d", server.ID)),
+		),
+	)
+
+	msg := tgbotapi.NewMessage(chatID, responseText)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+	h.sender.Send(msg)
+}
+
+// handleServerEdit начинает изменение параметра сервера. Статус переключается сразу, для остальных параметров запрашивается новое значение.
+func (h *BotHandler) handleServerEdit(chatID int64, actorTelegramID int64, field string, serverID int) {
+	server, err := h.db.GetServerByID(serverID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "admin_servers.not_found_id", serverID))
+		return
+	}
+
+	if field == serverFieldStatus {
+		before := *server
+		server.IsActive = !server.IsActive
+		if err := h.db.UpdateServer(server); err != nil {
+			h.sendMessage(chatID, h.t(chatID, "server_edit.status_error", err))
+			return
+		}
+
+		h.audit(actorTelegramID, models.AuditServerEdit, models.AuditTargetServer, server.ID, before, server)
+		h.sendMessage(chatID, h.t(chatID, "server_edit.status_changed", server.ID, h.activeText(chatID, server.IsActive)))
+		h.showServerEditMenu(chatID, server.ID)
+		return
+	}
+
+	prompt, ok := serverFieldPrompts[field]
+	if !ok {
+		h.sendMessage(chatID, h.t(chatID, "server_edit.unknown_field"))
+		return
+	}
+
+	h.setState(chatID, UserState{
+		State: "edit_server_value",
+		Data: map[string]string{
+			"server_id": strconv.Itoa(server.ID),
+			"field":     field,
+		},
+	})
+
+	h.sendMessage(chatID, h.t(chatID, prompt, serverFieldValue(server, field))+h.t(chatID, "server_edit.cancel_hint"))
+}
+
+// handleServerEditInput проверяет и сохраняет новое значение параметра сервера
+func (h *BotHandler) handleServerEditInput(message *tgbotapi.Message, userState UserState) {
+	chatID := message.Chat.ID
+	field := userState.Data["field"]
+	value := strings.TrimSpace(message.Text)
+
+	// Не оставляем пароль в истории чата
+	if field == serverFieldPassword {
+		if _, err := h.sender.Request(tgbotapi.NewDeleteMessage(chatID, message.MessageID)); err != nil {
+			log.Printf("Не удалось удалить сообщение с паролем сервера: %v", err)
+		}
+	}
+
+	serverID, _ := strconv.Atoi(userState.Data["server_id"])
+	server, err := h.db.GetServerByID(serverID)
+	if err != nil {
+		h.clearState(chatID)
+		h.sendMessage(chatID, h.t(chatID, "admin_servers.not_found_id", serverID))
+		return
+	}
+
+	before := *server
+	if err := applyServerField(server, field, value); err != nil {
+		// Оставляем состояние, чтобы администратор мог ввести значение еще раз
+		h.sendMessage(chatID, h.t(chatID, "server_edit.invalid_value", h.errorText(chatID, err)))
+		return
+	}
+
+	h.clearState(chatID)
+
+	// Изменение адреса или учетных данных сохраняется только после успешной проверки подключения
+	if field != serverFieldMaxClients {
+		h.sendMessage(chatID, h.t(chatID, "server_edit.checking"))
+		if err := h.vpnManager.CheckConnection(server); err != nil {
+			log.Printf("Проверка подключения к серверу #%d с новыми параметрами не прошла: %v", server.ID, err)
+			h.sendMessage(chatID, h.t(chatID, "server_edit.check_failed", err))
+			h.showServerEditMenu(chatID, server.ID)
+			return
+		}
+	}
+
+	if err := h.db.UpdateServer(server); err != nil {
+		h.sendMessage(chatID, h.t(chatID, "server_edit.save_error", err))
+		return
+	}
+
+	auditBefore, auditAfter := serverAuditValues(&before, server)
+	h.audit(message.From.ID, models.AuditServerEdit, models.AuditTargetServer, server.ID, auditBefore, auditAfter)
+	h.sendMessage(chatID, h.t(chatID, "server_edit.saved", server.ID))
+
+	if field == serverFieldIP && before.IP != server.IP {
+		h.offerConfigReissue(chatID, server)
+		return
+	}
+
+	h.showServerEditMenu(chatID, server.ID)
+}
+
+// offerConfigReissue предлагает отправить подписчикам сервера конфигурации с новым адресом
+func (h *BotHandler) offerConfigReissue(chatID int64, server *models.Server) {
+	subscriptions, err := h.db.GetSubscriptionsByServerID(server.ID, reissueStatuses)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "server_edit.subscriptions_error", err))
+		return
+	}
+
+	if len(subscriptions) == 0 {
+		h.sendMessage(chatID, h.t(chatID, "server_edit.reissue_not_needed"))
+		h.showServerEditMenu(chatID, server.ID)
+		return
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "server_edit.reissue", len(subscriptions)), fmt.Sprintf("server_reissue:%d", server.ID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "server_edit.reissue_later"), fmt.Sprintf("server_action:edit:%d", server.ID)),
+		),
+	)
+
+	msg := tgbotapi.NewMessage(chatID, h.t(chatID, "server_edit.reissue_offer", server.IP, len(subscriptions)))
+	msg.ReplyMarkup = keyboard
+	h.sender.Send(msg)
+}
+
+// handleConfigReissue обновляет адрес сервера в конфигурациях подписчиков и отправляет им новые файлы
+func (h *BotHandler) handleConfigReissue(chatID int64, actorTelegramID int64, serverID int) {
+	server, err := h.db.GetServerByID(serverID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "admin_servers.not_found_id", serverID))
+		return
+	}
+
+	subscriptions, err := h.db.GetSubscriptionsByServerID(server.ID, reissueStatuses)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "server_edit.subscriptions_error", err))
+		return
+	}
+
+	h.sendMessage(chatID, h.t(chatID, "server_edit.reissuing", len(subscriptions)))
+
+	updated, failed := 0, 0
+	for _, subscription := range subscriptions {
+		if err := h.vpnManager.UpdateClientEndpoint(subscription.ConfigFilePath, server.IP); err != nil {
+			log.Printf("Ошибка при перевыпуске конфигурации подписки #%d: %v", subscription.ID, err)
+			failed++
+			continue
+		}
+
+		user, err := h.db.GetUserByID(subscription.UserID)
+		if err != nil {
+			log.Printf("Ошибка при получении пользователя подписки #%d: %v", subscription.ID, err)
+			failed++
+			continue
+		}
+
+		document := tgbotapi.NewDocument(user.TelegramID, tgbotapi.FilePath(subscription.ConfigFilePath))
+		document.Caption = i18n.T(userLang(user), "config.reissued", subscription.ID)
+		if _, err := h.sender.Send(document); err != nil {
+			log.Printf("Ошибка при отправке новой конфигурации подписки #%d: %v", subscription.ID, err)
+			failed++
+			continue
+		}
+
+		updated++
+	}
+
+	h.audit(actorTelegramID, models.AuditServerReissue, models.AuditTargetServer, server.ID, nil,
+		map[string]interface{}{"endpoint": server.IP, "updated": updated, "failed": failed})
+
+	text := h.t(chatID, "server_edit.reissued", updated)
+	if failed > 0 {
+		text += h.t(chatID, "server_edit.reissue_failed", failed)
+	}
+	h.sendMessage(chatID, text)
+	h.showServerEditMenu(chatID, server.ID)
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+func TestServerAuditValuesRecordPasswordChange(t *testing.T) {
+	before := models.Server{ID: 1, IP: "10.0.0.1", Port: 22, SSHUser: "root", SSHPassword: "old-secret"}
+	after := before
+	after.SSHPassword = "new-secret"
+
+	auditBefore, auditAfter := serverAuditValues(&before, &after)
+	beforeJSON, afterJSON := auditValue(auditBefore), auditValue(auditAfter)
+
+	if beforeJSON == afterJSON {
+		t.Fatalf("audit snapshots are identical after a password change: %s", afterJSON)
+	}
+	for _, snapshot := range []string{beforeJSON, afterJSON} {
+		if strings.Contains(snapshot, "secret") {
+			t.Errorf("audit snapshot contains the password: %s", snapshot)
+		}
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(afterJSON), &fields); err != nil {
+		t.Fatalf("unmarshal audit snapshot: %v", err)
+	}
+	if fields["ssh_password"] != "changed" || fields["ip"] != "10.0.0.1" {
+		t.Errorf("unexpected audit snapshot: %s", afterJSON)
+	}
+}
+
+func TestServerAuditValuesWithoutPasswordChange(t *testing.T) {
+	before := models.Server{ID: 1, IP: "10.0.0.1", Port: 22, SSHUser: "root", SSHPassword: "secret"}
+	after := before
+	after.Port = 2222
+
+	_, auditAfter := serverAuditValues(&before, &after)
+	if snapshot := auditValue(auditAfter); strings.Contains(snapshot, "ssh_password") {
+		t.Errorf("audit snapshot marks an unchanged password: %s", snapshot)
+	}
+}
+
+func TestApplyServerField(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   string
+		value   string
+		wantKey string
+	}{
+		{name: "ip address", field: serverFieldIP, value: "192.168.1.10"},
+		{name: "hostname", field: serverFieldIP, value: "vpn.example.com"},
+		{name: "invalid host", field: serverFieldIP, value: "not a host", wantKey: "server_edit.invalid_ip"},
+		{name: "port", field: serverFieldPort, value: "2222"},
+		{name: "port out of range", field: serverFieldPort, value: "70000", wantKey: "server_edit.invalid_port"},
+		{name: "user with spaces", field: serverFieldUser, value: "ro ot", wantKey: "server_edit.invalid_user"},
+		{name: "empty password", field: serverFieldPassword, value: "", wantKey: "server_edit.invalid_password"},
+		{name: "max clients below current", field: serverFieldMaxClients, value: "3", wantKey: "server_edit.max_clients_below_current"},
+		{name: "unknown field", field: "dns", value: "1.1.1.1", wantKey: "server_edit.unknown_field_name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &models.Server{IP: "10.0.0.1", Port: 22, SSHUser: "root", MaxClients: 10, CurrentClients: 5}
+			err := applyServerField(server, tt.field, tt.value)
+			if tt.wantKey == "" {
+				if err != nil {
+					t.Fatalf("applyServerField() error = %v", err)
+				}
+				if got := serverFieldValue(server, tt.field); got != tt.value {
+					t.Errorf("field %s = %q, want %q", tt.field, got, tt.value)
+				}
+				return
+			}
+
+			var fieldErr *inputError
+			if !errors.As(err, &fieldErr) || fieldErr.key != tt.wantKey {
+				t.Errorf("applyServerField() error = %v, want key %q", err, tt.wantKey)
+			}
+		})
+	}
+}
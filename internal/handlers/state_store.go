@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ilokitv/botVPN/internal/database"
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+// StateStore хранит состояния диалогов с ботом. Ключом всегда служит ID чата.
+// Состояние, не обновлявшееся дольше времени жизни хранилища, считается отсутствующим.
+type StateStore interface {
+	// Get возвращает состояние диалога; второе значение false, если состояния нет или оно истекло
+	Get(chatID int64) (UserState, bool, error)
+	// Set сохраняет состояние диалога и продлевает срок его жизни
+	Set(chatID int64, state UserState) error
+	// Delete удаляет состояние диалога
+	Delete(chatID int64) error
+}
+
+// copyStateData возвращает копию данных состояния, чтобы изменения вызывающего кода не попадали в хранилище без Set
+func copyStateData(data map[string]string) map[string]string {
+	result := make(map[string]string, len(data))
+	for key, value := range data {
+		result[key] = value
+	}
+	return result
+}
+
+// memoryStateEntry - состояние диалога в памяти вместе со сроком его жизни
+type memoryStateEntry struct {
+	state     UserState
+	expiresAt time.Time
+}
+
+// MemoryStateStore хранит состояния диалогов в памяти процесса. Состояния теряются при перезапуске.
+type MemoryStateStore struct {
+	ttl    time.Duration
+	mu     sync.Mutex
+	states map[int64]memoryStateEntry
+}
+
+// NewMemoryStateStore создает хранилище состояний диалогов в памяти
+func NewMemoryStateStore(ttl time.Duration) *MemoryStateStore {
+	return &MemoryStateStore{
+		ttl:    ttl,
+		states: make(map[int64]memoryStateEntry),
+	}
+}
+
+// Get возвращает состояние диалога из памяти
+func (s *MemoryStateStore) Get(chatID int64) (UserState, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.states[chatID]
+	if !ok {
+		return UserState{}, false, nil
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(s.states, chatID)
+		return UserState{}, false, nil
+	}
+
+	state := entry.state
+	state.Data = copyStateData(entry.state.Data)
+	return state, true, nil
+}
+
+// Set сохраняет состояние диалога в памяти и удаляет истекшие состояния других чатов
+func (s *MemoryStateStore) Set(chatID int64, state UserState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for id, entry := range s.states {
+		if now.After(entry.expiresAt) {
+			delete(s.states, id)
+		}
+	}
+
+	state.Data = copyStateData(state.Data)
+	s.states[chatID] = memoryStateEntry{state: state, expiresAt: now.Add(s.ttl)}
+	return nil
+}
+
+// Delete удаляет состояние диалога из памяти
+func (s *MemoryStateStore) Delete(chatID int64) error {
+	s.mu.Lock()
+	delete(s.states, chatID)
+	s.mu.Unlock()
+	return nil
+}
+
+// DBStateStore хранит состояния диалогов в базе данных бота (PostgreSQL или SQLite), поэтому они сохраняются при перезапуске бота
+type DBStateStore struct {
+	db  *database.DB
+	ttl time.Duration
+}
+
+// NewDBStateStore создает хранилище состояний диалогов в базе данных
+func NewDBStateStore(db *database.DB, ttl time.Duration) *DBStateStore {
+	return &DBStateStore{db: db, ttl: ttl}
+}
+
+// Get возвращает состояние диалога из базы данных
+func (s *DBStateStore) Get(chatID int64) (UserState, bool, error) {
+	stored, err := s.db.GetConversationState(chatID, time.Now())
+	if errors.Is(err, sql.ErrNoRows) {
+		return UserState{}, false, nil
+	}
+	if err != nil {
+		return UserState{}, false, err
+	}
+
+	state := UserState{
+		State:         stored.State,
+		PreviousState: stored.PreviousState,
+		Data:          make(map[string]string),
+	}
+	if err := json.Unmarshal([]byte(stored.Data), &state.Data); err != nil {
+		return UserState{}, false, fmt.Errorf("failed to decode conversation state data: %w", err)
+	}
+	return state, true, nil
+}
+
+// Set сохраняет состояние диалога в базе данных
+func (s *DBStateStore) Set(chatID int64, state UserState) error {
+	data, err := json.Marshal(copyStateData(state.Data))
+	if err != nil {
+		return fmt.Errorf("failed to encode conversation state data: %w", err)
+	}
+
+	return s.db.SaveConversationState(&models.ConversationState{
+		ChatID:        chatID,
+		State:         state.State,
+		PreviousState: state.PreviousState,
+		Data:          string(data),
+	}, time.Now().Add(s.ttl))
+}
+
+// Delete удаляет состояние диалога из базы данных
+func (s *DBStateStore) Delete(chatID int64) error {
+	return s.db.DeleteConversationState(chatID)
+}
+
+// getState возвращает текущее состояние диалога в чате
+func (h *BotHandler) getState(chatID int64) (UserState, bool) {
+	state, ok, err := h.states.Get(chatID)
+	if err != nil {
+		log.Printf("Ошибка при получении состояния диалога в чате %d: %v", chatID, err)
+		return UserState{}, false
+	}
+	return state, ok
+}
+
+// setState сохраняет состояние диалога в чате
+func (h *BotHandler) setState(chatID int64, state UserState) {
+	if err := h.states.Set(chatID, state); err != nil {
+		log.Printf("Ошибка при сохранении состояния диалога в чате %d: %v", chatID, err)
+	}
+}
+
+// clearState сбрасывает состояние диалога в чате
+func (h *BotHandler) clearState(chatID int64) {
+	if err := h.states.Delete(chatID); err != nil {
+		log.Printf("Ошибка при удалении состояния диалога в чате %d: %v", chatID, err)
+	}
+}
+
+// handleCancelCommand обрабатывает команду /cancel и прерывает текущий диалог
+func (h *BotHandler) handleCancelCommand(chatID int64) {
+	if _, ok := h.getState(chatID); !ok {
+		h.sendMessage(chatID, h.t(chatID, "cancel.nothing"))
+		return
+	}
+
+	h.clearState(chatID)
+	h.sendMessage(chatID, h.t(chatID, "cancel.done"))
+}
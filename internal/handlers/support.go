@@ -0,0 +1,573 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/ilokitv/botVPN/internal/i18n"
+	"github.com/ilokitv/botVPN/internal/models"
+	"github.com/ilokitv/botVPN/internal/roles"
+)
+
+// supportContextSubscriptions - сколько последних подписок пользователя показывать сотрудникам в обращении
+const supportContextSubscriptions = 5
+
+// supportHistoryLimit - сколько последних сообщений обращения показывать в истории
+const supportHistoryLimit = 30
+
+// maxSupportHistoryText - максимальная длина одного сообщения в истории обращения
+const maxSupportHistoryText = 200
+
+// maxCaptionLength - ограничение Telegram на длину подписи к фото
+const maxCaptionLength = 1024
+
+// isSupportGroup проверяет, является ли чат группой поддержки из конфигурации
+func (h *BotHandler) isSupportGroup(chatID int64) bool {
+	return h.config.Support.GroupID != 0 && chatID == h.config.Support.GroupID
+}
+
+// supportChats возвращает чаты, куда пересылаются обращения: группа поддержки,
+// а если она не настроена - личные чаты сотрудников с правом users
+func (h *BotHandler) supportChats() []int64 {
+	if h.config.Support.GroupID != 0 {
+		return []int64{h.config.Support.GroupID}
+	}
+
+	staff, err := h.db.GetAllAdmins()
+	if err != nil {
+		log.Printf("Ошибка при получении списка сотрудников: %v", err)
+		return nil
+	}
+
+	var chats []int64
+	for _, member := range staff {
+		if roles.Can(member.Role, roles.PermUsers) {
+			chats = append(chats, member.TelegramID)
+		}
+	}
+	return chats
+}
+
+// truncateCaption сокращает подпись к фото до ограничения Telegram
+func truncateCaption(text string) string {
+	if len([]rune(text)) <= maxCaptionLength {
+		return text
+	}
+	return string([]rune(text)[:maxCaptionLength-1]) + "…"
+}
+
+// supportMessageContent возвращает текст и скриншот сообщения для обращения
+func supportMessageContent(message *tgbotapi.Message) (string, string) {
+	if len(message.Photo) > 0 {
+		// Последний размер фото - самый большой
+		return message.Caption, message.Photo[len(message.Photo)-1].FileID
+	}
+	return message.Text, ""
+}
+
+// sendSupportMessage отправляет текст или фото с подписью и возвращает ID отправленного сообщения
+func (h *BotHandler) sendSupportMessage(chatID int64, text, photoFileID string, keyboard *tgbotapi.InlineKeyboardMarkup) (int, error) {
+	var config tgbotapi.Chattable
+	if photoFileID != "" {
+		photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileID(photoFileID))
+		photo.Caption = truncateCaption(text)
+		if keyboard != nil {
+			photo.ReplyMarkup = keyboard
+		}
+		config = photo
+	} else {
+		msg := tgbotapi.NewMessage(chatID, text)
+		if keyboard != nil {
+			msg.ReplyMarkup = keyboard
+		}
+		config = msg
+	}
+
+	sent, err := h.sender.Send(config)
+	if err != nil {
+		return 0, err
+	}
+	return sent.MessageID, nil
+}
+
+// relayToStaff пересылает сообщение обращения сотрудникам и запоминает его, чтобы ответ сотрудника попал в обращение
+func (h *BotHandler) relayToStaff(ticketID int, text, photoFileID string, withActions bool) {
+	for _, chatID := range h.supportChats() {
+		var keyboard *tgbotapi.InlineKeyboardMarkup
+		if withActions {
+			actions := tgbotapi.NewInlineKeyboardMarkup(
+				tgbotapi.NewInlineKeyboardRow(
+					h.button(chatID, h.t(chatID, "support_staff.history_button"), fmt.Sprintf("support_staff:history:%d", ticketID)),
+					h.button(chatID, h.t(chatID, "support_staff.close_button"), fmt.Sprintf("support_staff:close:%d", ticketID)),
+				),
+			)
+			keyboard = &actions
+		}
+
+		messageID, err := h.sendSupportMessage(chatID, text, photoFileID, keyboard)
+		if err != nil {
+			log.Printf("Ошибка при пересылке обращения #%d в чат %d: %v", ticketID, chatID, err)
+			continue
+		}
+		if err := h.db.AddSupportRelay(chatID, messageID, ticketID); err != nil {
+			log.Printf("Ошибка при сохранении связи сообщения с обращением #%d: %v", ticketID, err)
+		}
+	}
+}
+
+// supportTicketContext формирует для сотрудников описание обращения: пользователь, его подписки,
+// серверы и время последнего рукопожатия Wireguard. Описание пересылается во все чаты поддержки,
+// поэтому оно на языке по умолчанию.
+func (h *BotHandler) supportTicketContext(user *models.User, ticket *models.SupportTicket) string {
+	lang := i18n.DefaultLanguage
+	var text strings.Builder
+	text.WriteString(i18n.T(lang, "support_staff.ticket", ticket.ID))
+	text.WriteString(i18n.T(lang, "support_staff.user",
+		displayName(user.Username, user.FirstName, user.LastName), user.TelegramID, user.ID))
+	if ticket.SubscriptionID != nil {
+		text.WriteString(i18n.T(lang, "support_staff.ticket_subscription", *ticket.SubscriptionID))
+	} else {
+		text.WriteString(i18n.T(lang, "support_staff.no_ticket_subscription"))
+	}
+
+	subscriptions, err := h.db.GetSubscriptionsByUserID(user.ID)
+	if err != nil {
+		log.Printf("Ошибка при получении подписок пользователя #%d: %v", user.ID, err)
+		return text.String()
+	}
+	if len(subscriptions) == 0 {
+		text.WriteString(i18n.T(lang, "support_staff.no_subscriptions"))
+	} else {
+		text.WriteString(i18n.T(lang, "support_staff.subscriptions"))
+	}
+	if len(subscriptions) > supportContextSubscriptions {
+		subscriptions = subscriptions[:supportContextSubscriptions]
+	}
+
+	for _, subscription := range subscriptions {
+		planName := i18n.T(lang, "support_staff.plan_id", subscription.PlanID)
+		if plan, err := h.db.GetSubscriptionPlanByID(subscription.PlanID); err == nil {
+			planName = plan.Name
+		}
+		text.WriteString(i18n.T(lang, "support_staff.subscription", subscription.ID, planName,
+			i18n.T(lang, "status."+subscription.Status), subscription.EndDate.Format("02.01.2006")))
+
+		server, err := h.db.GetServerByID(subscription.ServerID)
+		if err != nil {
+			text.WriteString(i18n.T(lang, "support_staff.server_not_found", subscription.ServerID))
+			continue
+		}
+		text.WriteString(i18n.T(lang, "support_staff.server", server.ID, server.IP))
+
+		// Рукопожатие проверяется только для подписок, у которых пир должен работать
+		isTicketSubscription := ticket.SubscriptionID != nil && *ticket.SubscriptionID == subscription.ID
+		if subscription.Status != "active" && !isTicketSubscription {
+			continue
+		}
+		handshake, err := h.vpnManager.GetLastHandshake(server, subscription.ConfigFilePath)
+		switch {
+		case err != nil:
+			log.Printf("Ошибка при получении рукопожатия подписки #%d: %v", subscription.ID, err)
+			text.WriteString(i18n.T(lang, "support_staff.handshake_error"))
+		case handshake == nil:
+			text.WriteString(i18n.T(lang, "support_staff.handshake_never"))
+		default:
+			text.WriteString(i18n.T(lang, "support_staff.handshake",
+				handshake.Format("02.01.2006 15:04"), time.Since(*handshake).Round(time.Second)))
+		}
+	}
+
+	text.WriteString(i18n.T(lang, "support_staff.reply_hint"))
+	return text.String()
+}
+
+// showSupportMenu показывает описание поддержки и незакрытые обращения пользователя
+func (h *BotHandler) showSupportMenu(chatID int64, telegramID int64) {
+	lang := h.lang(chatID)
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, i18n.T(lang, "support.new"), "support_new"),
+		),
+	)
+
+	if user, err := h.db.GetUserByTelegramID(telegramID); err == nil {
+		tickets, err := h.db.GetOpenSupportTicketsByUserID(user.ID)
+		if err != nil {
+			log.Printf("Ошибка при получении обращений пользователя #%d: %v", user.ID, err)
+		}
+		for _, ticket := range tickets {
+			keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+				h.button(chatID, i18n.T(lang, "support.ticket_write", ticket.ID, i18n.T(lang, "support.status."+ticket.Status)),
+					fmt.Sprintf("support_write:%d", ticket.ID)),
+				h.button(chatID, i18n.T(lang, "support.ticket_close", ticket.ID), fmt.Sprintf("support_close:%d", ticket.ID)),
+			))
+		}
+	}
+
+	msg := tgbotapi.NewMessage(chatID, i18n.T(lang, "support.text"))
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+	h.sender.Send(msg)
+}
+
+// handleSupportNew начинает создание обращения: предлагает выбрать подписку, если они есть
+func (h *BotHandler) handleSupportNew(chatID int64, telegramID int64) {
+	lang := h.lang(chatID)
+	user, err := h.db.GetUserByTelegramID(telegramID)
+	if err != nil {
+		h.sendMessage(chatID, i18n.T(lang, "common.user_error"))
+		return
+	}
+
+	subscriptions, err := h.db.GetSubscriptionsByUserID(user.ID)
+	if err != nil || len(subscriptions) == 0 {
+		h.startSupportTicket(chatID, 0)
+		return
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup()
+	for _, subscription := range subscriptions {
+		planName := ""
+		if plan, err := h.db.GetSubscriptionPlanByID(subscription.PlanID); err == nil {
+			planName = plan.Name
+		}
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, i18n.T(lang, "support.subscription", subscription.ID, planName, i18n.T(lang, "status."+subscription.Status)),
+				fmt.Sprintf("support_sub:%d", subscription.ID)),
+		))
+	}
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+		h.button(chatID, i18n.T(lang, "support.no_subscription"), "support_nosub"),
+	))
+
+	msg := tgbotapi.NewMessage(chatID, i18n.T(lang, "support.choose_subscription"))
+	msg.ReplyMarkup = keyboard
+	h.sender.Send(msg)
+}
+
+// startSupportTicket ждет от пользователя описание проблемы для нового обращения
+func (h *BotHandler) startSupportTicket(chatID int64, subscriptionID int) {
+	h.setState(chatID, UserState{
+		State: "support_message",
+		Data:  map[string]string{"subscription_id": strconv.Itoa(subscriptionID)},
+	})
+	h.sender.Send(tgbotapi.NewMessage(chatID, h.t(chatID, "support.describe")))
+}
+
+// getOwnedTicket возвращает обращение пользователя Telegram или текст ошибки для него
+func (h *BotHandler) getOwnedTicket(telegramID int64, ticketID int) (*models.User, *models.SupportTicket, string) {
+	user, err := h.db.GetUserByTelegramID(telegramID)
+	if err != nil {
+		return nil, nil, h.t(telegramID, "common.user_error")
+	}
+
+	ticket, err := h.db.GetSupportTicketByID(ticketID)
+	if err != nil || ticket.UserID != user.ID {
+		return nil, nil, h.t(telegramID, "support.not_found")
+	}
+
+	return user, ticket, ""
+}
+
+// handleSupportWrite продолжает переписку в незакрытом обращении
+func (h *BotHandler) handleSupportWrite(chatID int64, telegramID int64, ticketID int) {
+	_, ticket, errText := h.getOwnedTicket(telegramID, ticketID)
+	if errText != "" {
+		h.sendMessage(chatID, errText)
+		return
+	}
+	if ticket.Status == models.TicketClosed {
+		h.sendMessage(chatID, h.t(chatID, "support.ticket_closed", ticket.ID))
+		return
+	}
+
+	h.setState(chatID, UserState{
+		State: "support_message",
+		Data:  map[string]string{"ticket_id": strconv.Itoa(ticket.ID)},
+	})
+	h.sender.Send(tgbotapi.NewMessage(chatID, h.t(chatID, "support.describe")))
+}
+
+// handleSupportMessageInput создает обращение из первого сообщения пользователя или добавляет сообщение в обращение
+func (h *BotHandler) handleSupportMessageInput(message *tgbotapi.Message, userState UserState) {
+	chatID := message.Chat.ID
+	text, photoFileID := supportMessageContent(message)
+	if text == "" && photoFileID == "" {
+		h.sender.Send(tgbotapi.NewMessage(chatID, h.t(chatID, "support.text_or_photo")))
+		return
+	}
+
+	user, err := h.db.GetUserByTelegramID(message.From.ID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.user_error"))
+		return
+	}
+
+	if ticketID, _ := strconv.Atoi(userState.Data["ticket_id"]); ticketID != 0 {
+		ticket, err := h.db.GetSupportTicketByID(ticketID)
+		if err != nil || ticket.UserID != user.ID {
+			h.clearState(chatID)
+			h.sendMessage(chatID, h.t(chatID, "support.not_found"))
+			return
+		}
+		if ticket.Status == models.TicketClosed {
+			h.clearState(chatID)
+			h.sendMessage(chatID, h.t(chatID, "support.ticket_closed", ticket.ID))
+			return
+		}
+		h.addUserSupportMessage(user, ticket, text, photoFileID)
+		h.sender.Send(tgbotapi.NewMessage(chatID, h.t(chatID, "support.added", ticket.ID)))
+		return
+	}
+
+	ticket := &models.SupportTicket{UserID: user.ID, Status: models.TicketOpen}
+	if subscriptionID, _ := strconv.Atoi(userState.Data["subscription_id"]); subscriptionID != 0 {
+		ticket.SubscriptionID = &subscriptionID
+	}
+	if err := h.db.AddSupportTicket(ticket); err != nil {
+		log.Printf("Ошибка при создании обращения пользователя #%d: %v", user.ID, err)
+		h.sendMessage(chatID, h.t(chatID, "support.error"))
+		return
+	}
+
+	// Следующие сообщения и скриншоты пользователя попадают в это же обращение
+	userState.Data["ticket_id"] = strconv.Itoa(ticket.ID)
+	h.setState(chatID, userState)
+
+	h.relayToStaff(ticket.ID, h.supportTicketContext(user, ticket), "", true)
+	h.addUserSupportMessage(user, ticket, text, photoFileID)
+	h.sender.Send(tgbotapi.NewMessage(chatID, h.t(chatID, "support.created", ticket.ID)))
+}
+
+// addUserSupportMessage сохраняет сообщение пользователя в обращении и пересылает его сотрудникам
+func (h *BotHandler) addUserSupportMessage(user *models.User, ticket *models.SupportTicket, text, photoFileID string) {
+	err := h.db.AddSupportMessage(&models.SupportMessage{
+		TicketID:         ticket.ID,
+		SenderTelegramID: user.TelegramID,
+		Text:             text,
+		PhotoFileID:      photoFileID,
+	})
+	if err != nil {
+		log.Printf("Ошибка при сохранении сообщения обращения #%d: %v", ticket.ID, err)
+	}
+
+	if ticket.Status != models.TicketOpen {
+		if err := h.db.SetSupportTicketStatus(ticket.ID, models.TicketOpen); err != nil {
+			log.Printf("Ошибка при изменении статуса обращения #%d: %v", ticket.ID, err)
+		}
+		ticket.Status = models.TicketOpen
+	}
+
+	header := i18n.T(i18n.DefaultLanguage, "support_staff.message_from", ticket.ID, displayName(user.Username, user.FirstName, user.LastName))
+	if text != "" {
+		header += "\n" + text
+	}
+	h.relayToStaff(ticket.ID, header, photoFileID, false)
+}
+
+// handleSupportReply обрабатывает ответ (reply) на сообщение обращения: ответ сотрудника
+// отправляется пользователю, ответ пользователя добавляется в обращение.
+// Возвращает false, если сообщение не относится к обращению.
+func (h *BotHandler) handleSupportReply(message *tgbotapi.Message) bool {
+	if message.ReplyToMessage == nil {
+		return false
+	}
+
+	chatID := message.Chat.ID
+	ticket, err := h.db.GetSupportTicketByRelay(chatID, message.ReplyToMessage.MessageID)
+	if err != nil {
+		return false
+	}
+
+	sender, err := h.db.GetUserByTelegramID(message.From.ID)
+	if err != nil {
+		log.Printf("Ошибка при получении отправителя ответа на обращение #%d: %v", ticket.ID, err)
+		return true
+	}
+
+	// Ответ автора обращения в чате с ботом
+	if sender.ID == ticket.UserID && !h.isSupportGroup(chatID) {
+		text, photoFileID := supportMessageContent(message)
+		if text == "" && photoFileID == "" {
+			h.sender.Send(tgbotapi.NewMessage(chatID, h.t(chatID, "support.text_or_photo")))
+			return true
+		}
+		if ticket.Status == models.TicketClosed {
+			h.sendMessage(chatID, h.t(chatID, "support.ticket_closed", ticket.ID))
+			return true
+		}
+		h.addUserSupportMessage(sender, ticket, text, photoFileID)
+		h.sender.Send(tgbotapi.NewMessage(chatID, h.t(chatID, "support.added", ticket.ID)))
+		return true
+	}
+
+	if !roles.Can(sender.Role, roles.PermUsers) {
+		log.Printf("Ответ на обращение #%d от пользователя %d без права users проигнорирован", ticket.ID, sender.TelegramID)
+		return true
+	}
+
+	if message.IsCommand() && message.Command() == "close" {
+		h.handleSupportStaffClose(chatID, sender.TelegramID, ticket.ID)
+		return true
+	}
+
+	h.handleSupportStaffReply(message, sender, ticket)
+	return true
+}
+
+// handleSupportStaffReply отправляет пользователю ответ сотрудника на обращение
+func (h *BotHandler) handleSupportStaffReply(message *tgbotapi.Message, staff *models.User, ticket *models.SupportTicket) {
+	chatID := message.Chat.ID
+	if ticket.Status == models.TicketClosed {
+		h.sender.Send(tgbotapi.NewMessage(chatID, h.t(chatID, "support_staff.closed_not_sent", ticket.ID)))
+		return
+	}
+
+	text, photoFileID := supportMessageContent(message)
+	if text == "" && photoFileID == "" {
+		h.sender.Send(tgbotapi.NewMessage(chatID, h.t(chatID, "support_staff.text_or_photo")))
+		return
+	}
+
+	user, err := h.db.GetUserByID(ticket.UserID)
+	if err != nil {
+		h.sender.Send(tgbotapi.NewMessage(chatID, h.t(chatID, "support_staff.author_not_found", ticket.ID)))
+		return
+	}
+
+	lang := userLang(user)
+	userText := i18n.T(lang, "support.reply", ticket.ID, text)
+	if photoFileID != "" {
+		userText = i18n.T(lang, "support.reply_photo", ticket.ID)
+		if text != "" {
+			userText += ":\n\n" + text
+		}
+	}
+
+	messageID, err := h.sendSupportMessage(user.TelegramID, userText, photoFileID, nil)
+	if err != nil {
+		log.Printf("Ошибка при отправке ответа на обращение #%d: %v", ticket.ID, err)
+		h.sender.Send(tgbotapi.NewMessage(chatID, h.t(chatID, "support_staff.reply_error", ticket.ID, err)))
+		return
+	}
+	if err := h.db.AddSupportRelay(user.TelegramID, messageID, ticket.ID); err != nil {
+		log.Printf("Ошибка при сохранении связи сообщения с обращением #%d: %v", ticket.ID, err)
+	}
+
+	err = h.db.AddSupportMessage(&models.SupportMessage{
+		TicketID:         ticket.ID,
+		SenderTelegramID: staff.TelegramID,
+		FromStaff:        true,
+		Text:             text,
+		PhotoFileID:      photoFileID,
+	})
+	if err != nil {
+		log.Printf("Ошибка при сохранении ответа на обращение #%d: %v", ticket.ID, err)
+	}
+	if err := h.db.SetSupportTicketStatus(ticket.ID, models.TicketPending); err != nil {
+		log.Printf("Ошибка при изменении статуса обращения #%d: %v", ticket.ID, err)
+	}
+
+	reply := tgbotapi.NewMessage(chatID, h.t(chatID, "support_staff.reply_sent", ticket.ID))
+	reply.ReplyToMessageID = message.MessageID
+	h.sender.Send(reply)
+}
+
+// handleSupportStaffClose закрывает обращение по решению сотрудника и сообщает об этом пользователю
+func (h *BotHandler) handleSupportStaffClose(chatID int64, actorTelegramID int64, ticketID int) {
+	ticket, err := h.db.GetSupportTicketByID(ticketID)
+	if err != nil {
+		h.sender.Send(tgbotapi.NewMessage(chatID, h.t(chatID, "support_staff.not_found", ticketID)))
+		return
+	}
+	if ticket.Status == models.TicketClosed {
+		h.sender.Send(tgbotapi.NewMessage(chatID, h.t(chatID, "support_staff.already_closed", ticket.ID)))
+		return
+	}
+
+	if err := h.db.SetSupportTicketStatus(ticket.ID, models.TicketClosed); err != nil {
+		log.Printf("Ошибка при закрытии обращения #%d: %v", ticket.ID, err)
+		h.sender.Send(tgbotapi.NewMessage(chatID, h.t(chatID, "support_staff.close_error")))
+		return
+	}
+
+	if user, err := h.db.GetUserByID(ticket.UserID); err == nil {
+		h.sender.Send(tgbotapi.NewMessage(user.TelegramID, i18n.T(userLang(user), "support.closed_by_staff", ticket.ID)))
+	}
+
+	actor := fmt.Sprintf("ID %d", actorTelegramID)
+	if staff, err := h.db.GetUserByTelegramID(actorTelegramID); err == nil {
+		actor = displayName(staff.Username, staff.FirstName, staff.LastName)
+	}
+	h.sender.Send(tgbotapi.NewMessage(chatID, h.t(chatID, "support_staff.closed", ticket.ID, actor)))
+}
+
+// handleSupportClose закрывает обращение по просьбе пользователя
+func (h *BotHandler) handleSupportClose(chatID int64, telegramID int64, ticketID int) {
+	user, ticket, errText := h.getOwnedTicket(telegramID, ticketID)
+	if errText != "" {
+		h.sendMessage(chatID, errText)
+		return
+	}
+	if ticket.Status == models.TicketClosed {
+		h.sendMessage(chatID, h.t(chatID, "support.ticket_closed", ticket.ID))
+		return
+	}
+
+	if err := h.db.SetSupportTicketStatus(ticket.ID, models.TicketClosed); err != nil {
+		log.Printf("Ошибка при закрытии обращения #%d: %v", ticket.ID, err)
+		h.sendMessage(chatID, h.t(chatID, "support.error"))
+		return
+	}
+
+	h.clearState(chatID)
+	h.sendMessage(chatID, h.t(chatID, "support.closed", ticket.ID))
+	h.relayToStaff(ticket.ID, i18n.T(i18n.DefaultLanguage, "support_staff.closed_by_user",
+		displayName(user.Username, user.FirstName, user.LastName), ticket.ID), "", false)
+}
+
+// showSupportHistory показывает сотруднику историю сообщений обращения
+func (h *BotHandler) showSupportHistory(chatID int64, ticketID int) {
+	ticket, err := h.db.GetSupportTicketByID(ticketID)
+	if err != nil {
+		h.sender.Send(tgbotapi.NewMessage(chatID, h.t(chatID, "support_staff.not_found", ticketID)))
+		return
+	}
+
+	messages, err := h.db.GetSupportMessages(ticket.ID)
+	if err != nil {
+		h.sender.Send(tgbotapi.NewMessage(chatID, h.t(chatID, "support_staff.history_error", err)))
+		return
+	}
+
+	lang := h.lang(chatID)
+	var text strings.Builder
+	text.WriteString(i18n.T(lang, "support_staff.history",
+		ticket.ID, i18n.T(lang, "support.status."+ticket.Status), ticket.CreatedAt.Format("02.01.2006 15:04")))
+	if len(messages) > supportHistoryLimit {
+		text.WriteString(i18n.T(lang, "support_staff.history_truncated", supportHistoryLimit, len(messages)))
+		messages = messages[len(messages)-supportHistoryLimit:]
+	}
+	for _, message := range messages {
+		author := i18n.T(lang, "support_staff.author_user")
+		if message.FromStaff {
+			author = i18n.T(lang, "support_staff.author_staff")
+		}
+		messageText := message.Text
+		if len([]rune(messageText)) > maxSupportHistoryText {
+			messageText = string([]rune(messageText)[:maxSupportHistoryText]) + "…"
+		}
+		fmt.Fprintf(&text, "[%s] %s: %s", message.CreatedAt.Format("02.01 15:04"), author, messageText)
+		if message.PhotoFileID != "" {
+			text.WriteString(i18n.T(lang, "support_staff.photo"))
+		}
+		text.WriteString("\n")
+	}
+
+	h.sender.Send(tgbotapi.NewMessage(chatID, text.String()))
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/ilokitv/botVPN/internal/i18n"
+)
+
+// offerTrial предлагает пользователю пробный период, если он доступен и еще не использован
+func (h *BotHandler) offerTrial(chatID int64) {
+	user, err := h.db.GetUserByTelegramID(chatID)
+	if err != nil || user.TrialUsedAt != nil {
+		return
+	}
+
+	plan, err := h.db.GetTrialPlan()
+	if err != nil {
+		return
+	}
+
+	lang := userLang(user)
+	trialMsg := i18n.T(lang, "trial.offer", plan.Duration)
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, i18n.T(lang, "button.trial"), "trial:activate"),
+		),
+	)
+
+	msg := tgbotapi.NewMessage(chatID, trialMsg)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+	h.sender.Send(msg)
+}
+
+// handleTrialActivation выдает пользователю бесплатную пробную подписку
+func (h *BotHandler) handleTrialActivation(chatID int64, telegramID int64) {
+	user, err := h.db.GetUserByTelegramID(telegramID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.user_error"))
+		return
+	}
+
+	if user.TrialUsedAt != nil {
+		h.sendMessage(chatID, h.t(chatID, "trial.already_used"))
+		return
+	}
+
+	// Получаем пробный план
+	plan, err := h.db.GetTrialPlan()
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Ошибка при получении пробного плана: %v", err)
+		}
+		h.sendMessage(chatID, h.t(chatID, "trial.unavailable"))
+		return
+	}
+
+	// Проверяем доступность серверов
+	availableServer, err := h.subscriptions.AvailableServer()
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "common.servers_check_error"))
+		return
+	}
+
+	if availableServer == nil {
+		h.sendMessage(chatID, h.t(chatID, "common.no_servers"))
+		return
+	}
+
+	// Помечаем пробный период как использованный до выдачи конфигурации,
+	// чтобы повторное нажатие кнопки не выдало вторую подписку
+	claimed, err := h.db.ClaimTrial(user.ID)
+	if err != nil {
+		log.Printf("Ошибка при активации пробного периода пользователя #%d: %v", user.ID, err)
+		h.sendMessage(chatID, h.t(chatID, "trial.activation_error"))
+		return
+	}
+
+	if !claimed {
+		h.sendMessage(chatID, h.t(chatID, "trial.already_used"))
+		return
+	}
+
+	h.sendMessage(chatID, h.t(chatID, "trial.activating"))
+
+	subscription, err := h.provisionSubscription(user, plan, fmt.Sprintf("trial_%d", user.ID), true, nil)
+	if err != nil {
+		// Возвращаем возможность активировать пробный период
+		if releaseErr := h.db.ReleaseTrial(user.ID); releaseErr != nil {
+			log.Printf("Ошибка при возврате пробного периода пользователю #%d: %v", user.ID, releaseErr)
+		}
+		h.sendMessage(chatID, err.Error())
+		return
+	}
+
+	log.Printf("Пользователь #%d активировал пробный период, подписка #%d", user.ID, subscription.ID)
+
+	if err := h.sendConfigWithInstructions(chatID, subscription.ConfigFilePath); err != nil {
+		return
+	}
+
+	successMsg := h.t(chatID, "trial.success",
+		plan.Duration,
+		subscription.EndDate.Format("02.01.2006"),
+	)
+
+	msg := tgbotapi.NewMessage(chatID, successMsg)
+	msg.ParseMode = "Markdown"
+	h.sender.Send(msg)
+}
+
+// showTrialStats отображает статистику конверсии пробного периода
+func (h *BotHandler) showTrialStats(chatID int64) {
+	stats, err := h.db.GetTrialStats()
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "admin_stats.trial_error", err))
+		return
+	}
+
+	text := h.t(chatID, "admin_stats.trial_text",
+		stats.TrialUsers,
+		stats.ActiveTrials,
+		stats.ConvertedUsers,
+		stats.ConversionRate,
+	)
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, h.t(chatID, "admin.back_button"), "admin_menu:stats"),
+		),
+	)
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+
+	h.sender.Send(msg)
+}
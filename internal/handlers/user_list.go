@@ -0,0 +1,371 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/ilokitv/botVPN/internal/i18n"
+	"github.com/ilokitv/botVPN/internal/models"
+	"github.com/ilokitv/botVPN/internal/money"
+	"github.com/ilokitv/botVPN/internal/roles"
+)
+
+// userListPageSize - количество пользователей на одной странице списка
+const userListPageSize = 10
+
+// userSearchLimit - максимальное количество пользователей в результатах поиска
+const userSearchLimit = 20
+
+// userListFilters - фильтры списка пользователей в порядке показа
+var userListFilters = []string{
+	models.UserFilterAll,
+	models.UserFilterActive,
+	models.UserFilterExpired,
+	models.UserFilterStaff,
+	models.UserFilterNew,
+}
+
+// userListFilterNames - ключи каталога с названиями фильтров списка пользователей
+var userListFilterNames = map[string]string{
+	models.UserFilterAll:     "users.filter_all",
+	models.UserFilterActive:  "users.filter_active",
+	models.UserFilterExpired: "users.filter_expired",
+	models.UserFilterStaff:   "users.filter_staff",
+	models.UserFilterNew:     "users.filter_new",
+}
+
+// userListSorts - сортировки списка пользователей в порядке показа
+var userListSorts = []string{
+	models.UserSortNewest,
+	models.UserSortPayments,
+	models.UserSortName,
+}
+
+// userListSortNames - ключи каталога с названиями сортировок списка пользователей
+var userListSortNames = map[string]string{
+	models.UserSortNewest:   "users.sort_newest",
+	models.UserSortPayments: "users.sort_payments",
+	models.UserSortName:     "users.sort_name",
+}
+
+// userListView - фильтр, сортировка и страница списка пользователей.
+// Передается в данных кнопок, чтобы из карточки пользователя можно было вернуться на ту же страницу.
+type userListView struct {
+	filter string
+	sort   string
+	page   int
+}
+
+// defaultUserListView - список пользователей, который открывается из меню администратора
+var defaultUserListView = userListView{filter: models.UserFilterAll, sort: models.UserSortNewest}
+
+// parseUserListView восстанавливает параметры списка из аргументов кнопки
+func parseUserListView(args []string) userListView {
+	view := defaultUserListView
+	if len(args) >= 3 {
+		if _, ok := userListFilterNames[args[0]]; ok {
+			view.filter = args[0]
+		}
+		if _, ok := userListSortNames[args[1]]; ok {
+			view.sort = args[1]
+		}
+		if page, err := strconv.Atoi(args[2]); err == nil && page > 0 {
+			view.page = page
+		}
+	}
+	return view
+}
+
+// payload возвращает параметры списка для данных кнопки
+func (v userListView) payload() string {
+	return fmt.Sprintf("%s:%s:%d", v.filter, v.sort, v.page)
+}
+
+// sendOrEdit редактирует сообщение messageID или отправляет новое, если messageID равен 0
+func (h *BotHandler) sendOrEdit(chatID int64, messageID int, text string, keyboard tgbotapi.InlineKeyboardMarkup) {
+	if messageID == 0 {
+		msg := tgbotapi.NewMessage(chatID, text)
+		msg.ReplyMarkup = keyboard
+		h.sender.Send(msg)
+		return
+	}
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(chatID, messageID, text, keyboard)
+	if _, err := h.sender.Send(edit); err != nil && !strings.Contains(err.Error(), "message is not modified") {
+		log.Printf("Ошибка при обновлении сообщения %d в чате %d: %v", messageID, chatID, err)
+	}
+}
+
+// userListLine формирует строку пользователя для списка на языке lang
+func userListLine(lang string, user models.UserListItem) string {
+	line := fmt.Sprintf("#%d %s (ID %d)", user.ID,
+		displayName(user.Username, user.FirstName, user.LastName), user.TelegramID)
+	if user.Role != "" {
+		line += " — " + roleName(lang, user.Role)
+	}
+	return i18n.T(lang, "users.list_line",
+		line, user.ActiveSubscriptions, user.TotalPayments, user.CreatedAt.Format("02.01.2006"))
+}
+
+// userButtonText формирует текст кнопки пользователя
+func userButtonText(user models.UserListItem) string {
+	name := displayName(user.Username, user.FirstName, user.LastName)
+	if name == "" {
+		name = fmt.Sprintf("ID %d", user.TelegramID)
+	}
+	return fmt.Sprintf("👤 #%d %s", user.ID, name)
+}
+
+// showUserList отображает страницу списка пользователей в одном сообщении.
+// При переключении страниц, фильтров и сортировок сообщение messageID редактируется.
+func (h *BotHandler) showUserList(chatID int64, messageID int, view userListView) {
+	query := models.UserListQuery{
+		Filter: view.filter,
+		Sort:   view.sort,
+		Limit:  userListPageSize,
+		Offset: view.page * userListPageSize,
+	}
+
+	users, total, err := h.db.GetUserList(query)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "users.list_error", err))
+		return
+	}
+
+	pages := (total + userListPageSize - 1) / userListPageSize
+	if pages == 0 {
+		pages = 1
+	}
+	if view.page >= pages {
+		// Список мог сократиться с момента показа страницы
+		view.page = pages - 1
+		query.Offset = view.page * userListPageSize
+		users, total, err = h.db.GetUserList(query)
+		if err != nil {
+			h.sendMessage(chatID, h.t(chatID, "users.list_error", err))
+			return
+		}
+	}
+
+	lang := h.lang(chatID)
+	var text strings.Builder
+	text.WriteString(i18n.T(lang, "users.list_header", total, i18n.T(lang, userListFilterNames[view.filter]),
+		i18n.T(lang, userListSortNames[view.sort]), view.page+1, pages))
+	if len(users) == 0 {
+		text.WriteString(i18n.T(lang, "users.not_found"))
+	}
+	for _, user := range users {
+		text.WriteString(userListLine(lang, user) + "\n")
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup()
+	for _, user := range users {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, userButtonText(user), fmt.Sprintf("user_card:%d:%s", user.ID, view.payload())),
+		))
+	}
+
+	// Переключение страниц
+	var navRow []tgbotapi.InlineKeyboardButton
+	if view.page > 0 {
+		prev := view
+		prev.page--
+		navRow = append(navRow, h.button(chatID, "◀️", "users_page:"+prev.payload()))
+	}
+	if view.page < pages-1 {
+		next := view
+		next.page++
+		navRow = append(navRow, h.button(chatID, "▶️", "users_page:"+next.payload()))
+	}
+	if len(navRow) > 0 {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, navRow)
+	}
+
+	// Фильтры (при смене фильтра список открывается с первой страницы)
+	var filterRows [][]tgbotapi.InlineKeyboardButton
+	for i, filter := range userListFilters {
+		label := i18n.T(lang, userListFilterNames[filter])
+		if filter == view.filter {
+			label = "✅ " + label
+		}
+		button := h.button(chatID, label, "users_page:"+userListView{filter: filter, sort: view.sort}.payload())
+		if i%3 == 0 {
+			filterRows = append(filterRows, nil)
+		}
+		filterRows[len(filterRows)-1] = append(filterRows[len(filterRows)-1], button)
+	}
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, filterRows...)
+
+	// Сортировки
+	var sortRow []tgbotapi.InlineKeyboardButton
+	for _, sort := range userListSorts {
+		label := "↕️ " + i18n.T(lang, userListSortNames[sort])
+		if sort == view.sort {
+			label = "✅ " + i18n.T(lang, userListSortNames[sort])
+		}
+		sortRow = append(sortRow, h.button(chatID, label, "users_page:"+userListView{filter: view.filter, sort: sort}.payload()))
+	}
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, sortRow,
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, i18n.T(lang, "users.search"), "user_search"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, i18n.T(lang, "admin.back_button"), "admin_menu:main"),
+		),
+	)
+
+	h.sendOrEdit(chatID, messageID, text.String(), keyboard)
+}
+
+// startUserSearch запрашивает у сотрудника строку поиска пользователя
+func (h *BotHandler) startUserSearch(chatID int64) {
+	h.setState(chatID, UserState{
+		State: "user_search",
+		Data:  make(map[string]string),
+	})
+	h.sendMessage(chatID, h.t(chatID, "users.search_prompt"))
+}
+
+// handleUserSearchInput ищет пользователей по введенной строке
+func (h *BotHandler) handleUserSearchInput(message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	search := strings.TrimSpace(message.Text)
+	if search == "" {
+		h.sendMessage(chatID, h.t(chatID, "users.search_empty"))
+		return
+	}
+
+	h.clearState(chatID)
+
+	users, total, err := h.db.GetUserList(models.UserListQuery{
+		Filter: models.UserFilterAll,
+		Sort:   models.UserSortNewest,
+		Search: search,
+		Limit:  userSearchLimit,
+	})
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "users.search_error", err))
+		return
+	}
+
+	lang := h.lang(chatID)
+	var text strings.Builder
+	text.WriteString(i18n.T(lang, "users.search_header", search, total))
+	if total == 0 {
+		text.WriteString(i18n.T(lang, "users.not_found"))
+	}
+	for _, user := range users {
+		text.WriteString(userListLine(lang, user) + "\n")
+	}
+	if total > len(users) {
+		text.WriteString(i18n.T(lang, "users.search_truncated", len(users)))
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup()
+	for _, user := range users {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, userButtonText(user), fmt.Sprintf("user_card:%d", user.ID)),
+		))
+	}
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard,
+		tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, i18n.T(lang, "users.search_again"), "user_search"),
+			h.button(chatID, i18n.T(lang, "users.all"), "users_page:"+defaultUserListView.payload()),
+		),
+	)
+
+	h.sendOrEdit(chatID, 0, text.String(), keyboard)
+}
+
+// showUserCard отображает карточку пользователя с подписками, платежами и действиями
+func (h *BotHandler) showUserCard(chatID int64, messageID int, userID int, view userListView) {
+	user, err := h.db.GetUserByID(userID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "admin_users.not_found", userID))
+		return
+	}
+
+	stats, err := h.db.GetUserStats(user.ID)
+	if err != nil {
+		h.sendMessage(chatID, h.t(chatID, "users.stats_error", err))
+		return
+	}
+
+	lang := h.lang(chatID)
+	var text strings.Builder
+	text.WriteString(i18n.T(lang, "users.card_header", user.ID))
+	text.WriteString(i18n.T(lang, "users.card_name", strings.TrimSpace(user.FirstName+" "+user.LastName)))
+	if user.Username != "" {
+		fmt.Fprintf(&text, "Username: @%s\n", user.Username)
+	}
+	fmt.Fprintf(&text, "Telegram ID: %d\n", user.TelegramID)
+	text.WriteString(i18n.T(lang, "users.card_role", roleName(lang, user.Role)))
+	text.WriteString(i18n.T(lang, "users.card_language", userLang(user)))
+	text.WriteString(i18n.T(lang, "users.card_registered", user.CreatedAt.Format("02.01.2006 15:04")))
+	if balance, err := h.db.GetUserBalance(user.ID); err == nil {
+		text.WriteString(i18n.T(lang, "users.card_balance", formatBalance(balance)))
+	}
+	text.WriteString(i18n.T(lang, "users.card_subscriptions", stats.SubscriptionsCount, stats.ActiveSubscriptionsCount))
+	text.WriteString(i18n.T(lang, "users.card_data_usage", float64(stats.TotalDataUsage)/(1024*1024*1024)))
+	text.WriteString(i18n.T(lang, "users.card_payments_total", stats.TotalPayments))
+
+	// Последние подписки
+	subscriptions, err := h.db.GetSubscriptionsByUserID(user.ID)
+	if err != nil {
+		log.Printf("Ошибка при получении подписок пользователя #%d: %v", user.ID, err)
+	}
+	text.WriteString(i18n.T(lang, "users.card_subscriptions_header"))
+	if len(subscriptions) == 0 {
+		text.WriteString(i18n.T(lang, "users.card_none"))
+	}
+	for i, subscription := range subscriptions {
+		if i == 5 {
+			text.WriteString(i18n.T(lang, "users.card_more", len(subscriptions)-i))
+			break
+		}
+		planName := i18n.T(lang, "users.card_plan_id", subscription.PlanID)
+		if plan, err := h.db.GetSubscriptionPlanByID(subscription.PlanID); err == nil {
+			planName = plan.Name
+		}
+		text.WriteString(i18n.T(lang, "users.card_subscription", subscription.ID, planName, subscription.Status,
+			subscription.EndDate.Format("02.01.2006")))
+	}
+
+	// Последние платежи
+	payments, err := h.db.GetPaymentsByUserID(user.ID, 5)
+	if err != nil {
+		log.Printf("Ошибка при получении платежей пользователя #%d: %v", user.ID, err)
+	}
+	text.WriteString(i18n.T(lang, "users.card_payments_header"))
+	if len(payments) == 0 {
+		text.WriteString(i18n.T(lang, "users.card_none"))
+	}
+	for _, payment := range payments {
+		fmt.Fprintf(&text, "%s — %s (%s, %s)\n", payment.CreatedAt.Format("02.01.2006"),
+			money.Format(payment.Amount, payment.Currency), payment.PaymentMethod, payment.Status)
+	}
+
+	// Действия, доступные роли сотрудника
+	role := h.role(chatID)
+	keyboard := tgbotapi.NewInlineKeyboardMarkup()
+	var actionRow []tgbotapi.InlineKeyboardButton
+	if roles.Can(role, roles.PermUsers) {
+		actionRow = append(actionRow, h.button(chatID, i18n.T(lang, "users.subscriptions"), fmt.Sprintf("user_action:subscriptions:%d", user.ID)))
+	}
+	actionRow = append(actionRow, h.button(chatID, i18n.T(lang, "users.balance"), fmt.Sprintf("user_action:balance:%d", user.ID)))
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, actionRow)
+	if roles.Can(role, roles.PermRoles) {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			h.button(chatID, i18n.T(lang, "users.role"), fmt.Sprintf("user_action:role:%d", user.ID)),
+		))
+	}
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+		h.button(chatID, i18n.T(lang, "users.back_to_list"), "users_page:"+view.payload()),
+	))
+
+	h.sendOrEdit(chatID, messageID, text.String(), keyboard)
+}
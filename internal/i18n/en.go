@@ -0,0 +1,847 @@
+package i18n
+
+// en - каталог сообщений на английском языке
+var en = map[string]string{
+	// Общие сообщения
+	"common.user_error":             "Failed to load your account. Please try again later.",
+	"common.user_error_support":     "Failed to load your account. Please contact support.",
+	"common.plan_error":             "Failed to load the plan: %v",
+	"common.plans_error":            "Failed to load the list of plans: %v",
+	"common.server_error":           "Failed to load the server. Please try again later.",
+	"common.servers_check_error":    "Failed to check server availability. Please try again later.",
+	"common.no_servers":             "Unfortunately, no servers are available right now. Please try again later.",
+	"common.invoice_error":          "Failed to create the invoice: %v",
+	"common.plan_unavailable":       "The selected plan is not available for purchase.",
+	"common.subscription_not_found": "Subscription not found.",
+	"common.no_admin_rights":        "You do not have administrator rights.",
+	"common.unknown_command":        "Unknown command. Use /help to see the list of commands.",
+
+	// Главное меню
+	"menu.buy":     "💰 Buy subscription",
+	"menu.my":      "🔑 My subscriptions",
+	"menu.balance": "💼 Balance",
+	"menu.help":    "ℹ️ Help",
+	"menu.support": "📞 Support",
+	"menu.admin":   "⚙️ Admin panel",
+
+	"start.welcome": `
+🔒 *Welcome to the VPN bot!*
+
+This bot helps you buy and manage VPN subscriptions.
+Use the menu buttons for quick access to all features.
+`,
+	"start.admin": "\nYou have administrator rights!",
+
+	"help.text": `
+*VPN bot help*
+
+*Main menu buttons:*
+• 💰 *Buy subscription* - browse and buy available plans
+• 🔑 *My subscriptions* - manage your active subscriptions
+• 💼 *Balance* - balance, top-ups and transaction history
+• ℹ️ *Help* - show this help
+• 📞 *Support* - contact the support team
+
+*Available commands:*
+• /start - show the main menu
+• /help - show this help
+• /buy - buy a VPN subscription
+• /my - view your active subscriptions
+• /trial - activate the free trial
+• /redeem - redeem a gift code
+• /gifts - status of gifts you bought
+• /balance - balance and transaction history
+• /language - choose the interface language
+• /cancel - cancel the current action
+`,
+	"help.admin": `
+*Administrator commands:*
+• ⚙️ *Admin panel* - bot management menu
+• /admin - open the admin panel
+`,
+
+	"support.text": `
+*VPN service support*
+
+If you have questions or problems using our VPN, open a ticket:
+
+1. Choose the subscription you have a problem with
+2. Describe your problem in detail
+3. Attach screenshots of any errors if possible
+
+Our support team will reply to you here, in the chat with the bot.
+`,
+	"support.new":                 "✉️ New ticket",
+	"support.ticket_write":        "✍️ Write to #%d (%s)",
+	"support.ticket_close":        "✅ Close #%d",
+	"support.status.open":         "awaiting reply",
+	"support.status.pending":      "replied",
+	"support.status.closed":       "closed",
+	"support.choose_subscription": "Which subscription is your ticket about?",
+	"support.subscription":        "#%d %s (%s)",
+	"support.no_subscription":     "No subscription",
+	"support.describe":            "Describe your problem in one or more messages. You can attach screenshots.",
+	"support.text_or_photo":       "Only text and photos are supported. Describe your problem or send a screenshot.",
+	"support.created":             "✅ Ticket #%d has been created. You can send more messages or screenshots - they will be added to this ticket. We will reply here, in the chat with the bot.",
+	"support.added":               "📎 Added to ticket #%d.",
+	"support.reply":               "💬 Support reply to ticket #%d:\n\n%s\n\nTo answer, use «Reply» on this message.",
+	"support.reply_photo":         "💬 Support reply to ticket #%d",
+	"support.closed":              "✅ Ticket #%d has been closed. If the problem happens again, open a new ticket.",
+	"support.closed_by_staff":     "✅ Support has closed ticket #%d. If the problem is not solved, open a new ticket.",
+	"support.ticket_closed":       "Ticket #%d is already closed. If the problem is not solved, open a new ticket.",
+	"support.not_found":           "Ticket not found.",
+	"support.error":               "Failed to send your ticket. Please try again later.",
+
+	// Выбор языка
+	"language.choose":  "🌐 *Interface language*\n\nCurrent language: %s\nChoose the bot language:",
+	"language.auto":    "🌐 Same as Telegram",
+	"language.changed": "✅ Interface language changed: %s",
+	"language.error":   "Failed to change the language. Please try again later.",
+
+	// Отмена диалога
+	"cancel.done":    "❌ The current action has been cancelled.",
+	"cancel.nothing": "There is no active action to cancel.",
+
+	// Кнопки устарели или недоступны
+	"callback.outdated":  "This button is out of date. Open the menu again with /start.",
+	"callback.forbidden": "⛔️ This action is not available to you.",
+
+	// Staff roles
+	"role.granted": "✅ You have been given the «%s» role. Open the control panel with /admin.",
+	"role.revoked": "Your staff role has been removed, the control panel is no longer available.",
+	"role.owner":   "Owner",
+	"role.admin":   "Administrator",
+	"role.support": "Support",
+	"role.finance": "Finance",
+
+	// Кнопки
+	"button.config":        "📄 Configuration",
+	"button.stats":         "📊 Statistics",
+	"button.change_plan":   "🔄 Change plan",
+	"button.pause":         "⏸️ Pause",
+	"button.resume":        "▶️ Resume",
+	"button.renew":         "🔁 Renew",
+	"button.renew_long":    "🔁 Renew subscription",
+	"button.block":         "🔒 Block",
+	"button.unblock":       "🔓 Unblock",
+	"button.buy":           "💳 Buy",
+	"button.gift":          "🎁 Gift",
+	"button.balance_pay":   "💼 Pay from balance",
+	"button.topup":         "💼 Top up balance",
+	"button.choose_plan":   "💰 Choose a plan",
+	"button.choose_tariff": "💰 Choose a plan",
+	"button.buy_more":      "💰 Buy another subscription",
+	"button.trial":         "🎁 Activate free trial",
+
+	// Статусы подписок
+	"status.pending": "Being set up",
+	"status.active":  "Active",
+	"status.blocked": "Blocked",
+	"status.paused":  "Paused",
+	"status.grace":   "Grace period",
+	"status.expired": "Expired",
+	"status.revoked": "Revoked",
+
+	// Мои подписки
+	"my.subscriptions_error": "❌ Failed to load your subscriptions. Please try again later.",
+	"my.empty": `
+*You have no active subscriptions yet* 🔎
+
+To start using the VPN service:
+1️⃣ Tap *"💰 Buy subscription"*
+2️⃣ Choose a plan that suits you
+3️⃣ Pay for the subscription in Telegram
+4️⃣ Get VPN access instantly!
+`,
+	"my.header":       "*🔑 Your VPN subscriptions (%d)*\n",
+	"my.default_plan": "Subscription plan",
+	"my.days_paused":  "🗓️ *Days left:* %d (paused since %s)\n",
+	"my.renew_until":  "🗓️ *Renew by:* %s to keep your configuration\n",
+	"my.days_left":    "🗓️ *Days left:* %d\n",
+	"my.overdue":      "🗓️ *Status:* Overdue\n",
+	"my.card": "*VPN subscription #%d*\n\n" +
+		"%s *Status:* %s\n" +
+		"📋 *Plan:* %s\n" +
+		"🌐 *Server:* %s\n" +
+		"📅 *Valid until:* %s\n" +
+		"%s" +
+		"📊 *Data used:* %s\n",
+	"my.last_connection": "🔄 *Last connection:* %s\n",
+	"my.buy_more":        "*Want to add another subscription?*",
+
+	// Планы и покупка
+	"plans.none": "There are no subscription plans available right now. Please try again later.",
+	"plans.header": `
+*💰 Choose a subscription plan*
+
+Below are the available VPN plans.
+Pick the one that suits you and tap the button to subscribe.
+`,
+	"plans.card": "*%s*\n\n" +
+		"%s\n\n" +
+		"💰 *Price:* %s\n" +
+		"⏳ *Duration:* %d days\n" +
+		"💵 *Price per day:* %s",
+	"plans.footer":           "*Any questions?*\nContact our support team.",
+	"purchase.invoice_title": "VPN subscription: %s",
+	"purchase.invoice_desc":  "VPN service subscription for %d days",
+	"purchase.instructions": `
+*How to pay:*
+
+1. Tap "Pay" in the invoice above
+2. Choose a payment method
+3. Follow the instructions to complete the payment
+4. After a successful payment you will receive the configuration file and VPN setup instructions
+
+If you have any problems with the payment, please contact support.
+`,
+	"purchase.success": "✅ *Subscription activated!*\n\n" +
+		"Plan: %s\n" +
+		"Duration: %d days\n" +
+		"Start date: %s\n" +
+		"End date: %s\n\n" +
+		"Thank you for your purchase!",
+
+	// Обработка платежей
+	"payment.invalid_data":         "Payment processing error: invalid data format.",
+	"payment.invalid_subscription": "Payment processing error: invalid subscription ID.",
+	"payment.invalid_plan":         "Payment processing error: invalid plan ID.",
+	"payment.processing_error":     "Payment processing error. Please contact support.",
+	"payment.credited":             "The payment of %s has been credited to your balance.",
+	"payment.not_credited":         "The payment of %s has been saved, but it cannot be credited to your balance, which is kept in rubles. Please contact support.",
+
+	// Выдача конфигурации
+	"provision.setup_error":  "Failed to set up the VPN server: %v",
+	"provision.config_error": "Failed to create the VPN configuration: %v",
+	"provision.save_error":   "Failed to create the subscription: %v",
+	"config.caption":         "Here is your VPN configuration file. Setup instructions follow in the next message.",
+	"config.send_error":      "Failed to send the configuration file: %v",
+	"config.reissued":        "🔄 The VPN server address has changed. Here is the updated configuration file for subscription #%d: import it into the WireGuard app in place of the old one.",
+	"config.instructions": `
+*VPN setup instructions:*
+
+1. Download and install the AmneziaVPN client:
+   - Windows: https://github.com/amnezia-vpn/amnezia-client/releases/download/4.8.3.1/AmneziaVPN_4.8.3.1_x64.exe
+   - MacOS: https://github.com/amnezia-vpn/amnezia-client/releases/download/4.8.3.1/AmneziaVPN_4.8.3.1_macos.dmg
+   - iOS: https://apps.apple.com/us/app/amneziavpn/id1600529900
+   - Android: https://play.google.com/store/apps/details?id=org.amnezia.vpn
+
+2. Open the AmneziaVPN client
+3. Import the configuration file you received
+4. Turn on the connection
+
+Done! Your traffic is now protected by VPN.
+`,
+
+	// Действия администратора с подпиской
+	"subscription.blocked_notice":   "❗ Your subscription #%d (%s) has been blocked by an administrator",
+	"subscription.unblocked_notice": "✅ Your subscription #%d (%s) has been unblocked by an administrator",
+	"subscription.revoked_notice":   "❗ Your subscription #%d (%s) has been revoked by an administrator",
+
+	// Пробный период
+	"trial.offer": "🎁 *Try it for free!*\n\n" +
+		"A %d-day trial with no payment. It can be activated only once.",
+	"trial.already_used":     "You have already used your trial. Choose a paid plan with the /buy command.",
+	"trial.unavailable":      "The trial is not available right now.",
+	"trial.activation_error": "Failed to activate the trial. Please try again later.",
+	"trial.activating":       "⏳ Activating your trial, this may take a moment...",
+	"trial.success": "🎁 *Trial activated!*\n\n" +
+		"Duration: %d days\n" +
+		"End date: %s\n\n" +
+		"Enjoying it? Subscribe to a paid plan before the trial ends to keep your access.",
+
+	// Подарки
+	"gift.invoice_title": "Gift VPN subscription: %s",
+	"gift.invoice_desc":  "Gift code for a %d-day VPN subscription. The code is valid for %d days.",
+	"gift.after_payment": "🎁 After the payment you will receive a gift code and a link you can forward to the recipient.",
+	"gift.create_error":  "❌ Payment received, but the gift code could not be created. Please contact support.",
+	"gift.created": "🎁 *Gift ready!*\n\n" +
+		"Plan: %s (%d days)\n" +
+		"Code: `%s`\n" +
+		"Valid until: %s\n\n" +
+		"Forward the link below to the recipient. Opening it gives them a VPN subscription right away:\n[🎁 Get the gift](%s)\n\n" +
+		"The code can also be redeemed with `/redeem %s`.\n" +
+		"Status of your gifts: /gifts",
+	"gift.code_required": "Please enter the gift code: `/redeem CODE`",
+	"gift.no_servers":    "Unfortunately, no servers are available right now. Your gift code is still valid, please try to redeem it later.",
+	"gift.redeem_error":  "Failed to redeem the gift code. Please try again later.",
+	"gift.activating":    "⏳ Activating your gift subscription, this may take a moment...",
+	"gift.redeemed": "🎁 *Gift redeemed!*\n\n" +
+		"Plan: %s\n" +
+		"Duration: %d days\n" +
+		"End date: %s",
+	"gift.buyer_notice":     "🎁 Your gift code `%s` has been redeemed by %s.",
+	"gift.not_found":        "❌ Gift code not found. Please check that it is entered correctly.",
+	"gift.already_redeemed": "❌ This gift code has already been redeemed.",
+	"gift.expired":          "❌ This gift code expired on %s.",
+	"gift.unavailable":      "❌ This gift code cannot be redeemed.",
+	"gift.list_error":       "Failed to load your gifts. Please try again later.",
+	"gift.list_empty":       "You have not bought any gifts yet. You can gift VPN from the list of plans: /buy",
+	"gift.list_header":      "🎁 *Your gifts (%d)*\n\n",
+	"gift.plan_fallback":    "Plan #%d",
+	"gift.status_redeemed":  "✅ Redeemed on %s",
+	"gift.status_expired":   "⏱️ Expired",
+	"gift.status_pending":   "🕓 Awaiting redemption until %s",
+
+	// Баланс
+	"ledger.topup":      "Top-up",
+	"ledger.purchase":   "Purchase",
+	"ledger.refund":     "Refund",
+	"ledger.bonus":      "Bonus",
+	"ledger.adjustment": "Adjustment",
+
+	"balance.no_transactions": "No transactions yet.\n",
+	"balance.transaction":     "%s · %s: %s",
+	"balance.error":           "Failed to load your balance. Please try again later.",
+	"balance.text": "💼 Your balance: %s\n\n" +
+		"Recent transactions:\n%s\n" +
+		"You can use the balance to pay for subscriptions. Choose a top-up amount:",
+	"balance.invalid_amount": "Invalid top-up amount.",
+	"balance.invoice_title":  "Balance top-up",
+	"balance.invoice_desc":   "VPN service balance top-up of %d RUB",
+	"balance.topup_error":    "❌ Payment received, but the funds could not be credited to your balance. Please contact support.",
+	"balance.topped_up":      "✅ Balance topped up by %s\nCurrent balance: %s",
+	"balance.insufficient":   "❌ Insufficient balance.\nPlan price: %s\nYour balance: %s",
+	"balance.pay_error":      "Failed to pay from balance. Please try again later.",
+	"balance.no_price":       "This plan cannot be paid from the balance: it has no price in rubles.",
+	"balance.provisioning":   "⏳ Setting up your subscription, this may take a moment...",
+	"balance.refunded":       "\nThe funds have been returned to your balance.",
+	"balance.purchase_success": "✅ *Subscription activated!*\n\n" +
+		"Plan: %s\n" +
+		"Duration: %d days\n" +
+		"End date: %s\n" +
+		"Remaining balance: %s",
+	"balance.changed_notice": "💼 %s: %s\nCurrent balance: %s",
+
+	// Смена плана
+	"plan_change.only_active": "Only an active subscription can change its plan.",
+	"plan_change.header": "🔄 *Change plan of subscription #%d*\n\n" +
+		"Current plan: %s\n" +
+		"Valid until: %s\n\n" +
+		"The value of the remaining days is credited towards the new plan, " +
+		"which starts immediately for its full duration. " +
+		"If the credit exceeds the price of the new plan, the difference is returned to your balance.\n\n",
+	"plan_change.charge":        "pay %s",
+	"plan_change.surplus":       "%s back to balance",
+	"plan_change.option":        "• *%s* (%d days, %s): %s\n",
+	"plan_change.no_plans":      "There are no other plans to switch to.",
+	"plan_change.invoice_title": "Switch to plan “%s”",
+	"plan_change.invoice_desc":  "Extra payment to switch subscription #%d to plan “%s”, with %s credited for unused days",
+	"plan_change.invoice_label": "Extra payment for plan “%s”",
+	"plan_change.unavailable":   "The selected plan is not available for switching.",
+	"plan_change.stale_invoice": "The price of switching changed after the invoice was issued, so the plan was not changed.",
+	"plan_change.error":         "Failed to change the plan. Please contact support.",
+	"plan_change.not_applied":   "Failed to change the subscription plan, so the plan was not changed.",
+	"plan_change.success": "✅ *Subscription plan changed!*\n\n" +
+		"New plan: %s\n" +
+		"End date: %s\n" +
+		"Your VPN configuration stays the same.",
+	"plan_change.refunded": "\n\n%s has been returned to your balance.",
+
+	// Пауза
+	"pause.only_active":        "Only an active subscription can be paused.",
+	"pause.unavailable":        "Pausing is not available for your subscription plan.",
+	"pause.expired":            "The subscription has already expired.",
+	"pause.failed":             "Failed to pause the subscription. It may already be paused.",
+	"pause.server_unavailable": "❌ Failed to pause the subscription: the VPN server is unavailable. Please try again later.",
+	"pause.success": "⏸️ *Subscription #%d paused*\n\n" +
+		"The VPN connection is disabled and the subscription time is not being used.\n" +
+		"A pause can last at most %d days, after which the subscription resumes automatically: %s.",
+	"resume.not_paused":         "The subscription is not paused.",
+	"resume.server_unavailable": "❌ Failed to resume the subscription: the VPN server is unavailable. Please try again later.",
+	"resume.error":              "Failed to resume the subscription. Please contact support.",
+	"resume.success": "▶️ *Subscription #%d resumed*\n\n" +
+		"The subscription has been extended by the length of the pause.\n" +
+		"New end date: %s",
+
+	// Продление
+	"renew.not_allowed":      "This subscription cannot be renewed. Buy a new subscription with the /buy command.",
+	"renew.trial":            "A trial cannot be renewed. Choose a paid plan with the /buy command.",
+	"renew.plan_error":       "Failed to load the plan. Please try again later.",
+	"renew.plan_unavailable": "The plan of this subscription is no longer available. Choose a new plan with the /buy command.",
+	"renew.invoice_title":    "Renewal of subscription #%d",
+	"renew.invoice_desc":     "Renewal of subscription “%s” for %d days, keeping your current VPN configuration",
+	"renew.invoice_label":    "Renewal of “%s”",
+	"renew.failed":           "Failed to renew the subscription.",
+	"renew.unblock_failed": "✅ Subscription #%d has been renewed until %s, but access could not be restored automatically. " +
+		"Please contact support.",
+	"renew.success": "✅ *Subscription renewed!*\n\n" +
+		"Subscription: #%d\n" +
+		"Plan: %s\n" +
+		"End date: %s\n\n" +
+		"Your current VPN configuration keeps working, there is no need to reinstall it.",
+
+	// Уведомления планировщика
+	"notify.resumed": "▶️ *Subscription #%d resumed*\n\n" +
+		"The maximum pause length has been reached, the VPN connection is available again.\n" +
+		"Subscription end date: %s",
+	"notify.trial_ended": "⌛️ *Your trial has ended*\n\n" +
+		"Your VPN connection has been disabled automatically.\n\n" +
+		"We hope you enjoyed it! To keep using the VPN without limits, " +
+		"choose a plan that suits you.",
+	"notify.expired": "❗️ *Your subscription has expired* ❗️\n\n" +
+		"Subscription: #%d\n" +
+		"Plan: %s\n" +
+		"Start date: %s\n" +
+		"End date: %s\n\n" +
+		"Your VPN connection has been disabled automatically.\n" +
+		"To keep using the VPN, please buy a new subscription with the /buy command.",
+	"notify.grace": "⌛ *Subscription #%d has expired*\n\n" +
+		"The VPN connection is suspended, but your configuration has been kept.\n" +
+		"Renew the subscription by %s and access will be restored right away, without reinstalling the configuration.",
+	"notify.trial_expiring": "⏳ *Your trial ends soon*\n\n" +
+		"End date: %s\n" +
+		"Time left: *%s*\n\n" +
+		"Subscribe to a paid plan to keep your VPN working without interruption.",
+	"notify.expiring": "⚠️ *Attention! Your subscription expires soon* ⚠️\n\n" +
+		"Subscription: #%d\n" +
+		"Plan: %s\n" +
+		"End date: %s\n\n" +
+		"Time left: *%s*\n\n" +
+		"To renew the subscription and keep your current configuration, tap the button below.\n" +
+		"If you do not renew, your VPN connection will be disabled automatically when the subscription expires.",
+
+	"time.days":           "%d d",
+	"time.hours":          "%d h",
+	"time.less_than_hour": "less than an hour",
+
+	"notify.admin_report":        "📊 *Expired subscriptions report*\n\nExpired subscriptions found and processed: %d\n\n*Processed subscriptions:*\n",
+	"notify.admin_report_line":   "%d. Subscription #%d - User: %s - Plan: %s - End date: %s\n",
+	"notify.admin_report_footer": "\nAll listed subscriptions were automatically marked as expired and their VPN configurations were revoked.",
+
+	// Панель администратора
+	"admin.status_active":   "🟢 Active",
+	"admin.status_inactive": "🔴 Inactive",
+	"admin.active":          "Active",
+	"admin.inactive":        "Inactive",
+
+	// Серверы в панели администратора
+	"admin_servers.enter_port":          "Enter the SSH port:",
+	"admin_servers.invalid_port":        "Please enter a valid port (a number):",
+	"admin_servers.enter_user":          "Enter the SSH user name:",
+	"admin_servers.enter_password":      "Enter the SSH password:",
+	"admin_servers.enter_max_clients":   "Enter the maximum number of clients for the server:",
+	"admin_servers.invalid_max_clients": "Please enter a valid number of clients:",
+	"admin_servers.setting_up":          "Setting up the server, this may take a while...",
+	"admin_servers.setup_error":         "Failed to set up the server: %v",
+	"admin_servers.add_error":           "Failed to save the server to the database: %v",
+	"admin_servers.added":               "Server added with ID: %d",
+
+	// Планы в панели администратора
+	"admin_plans.enter_description": "Enter the plan description:",
+	"admin_plans.enter_price":       "Enter the plan price in %s:",
+	"admin_plans.invalid_price":     "Please enter a valid price:",
+	"admin_plans.enter_duration":    "Enter the plan duration in days:",
+	"admin_plans.invalid_duration":  "Please enter a valid duration (whole number of days):",
+	"admin_plans.add_error":         "Failed to add the plan: %v",
+	"admin_plans.added":             "Plan added: %s",
+	"admin_plans.edit_description":  "Enter a new plan description (or send a dot '.' to keep the current one: \n\n%s)",
+	"admin_plans.edit_price":        "Enter a new plan price in %s (or send a dot '.' to keep the current price: %s):",
+	"admin_plans.edit_duration":     "Enter a new plan duration in days (or send a dot '.' to keep the current duration: %s days):",
+	"admin_plans.keep_status":       "Keep the current status",
+	"admin_plans.choose_status":     "Choose the plan status (current status: %s):",
+	"admin_plans.update_error":      "Failed to update the plan: %v",
+	"admin_plans.updated":           "✅ Plan updated: %s",
+	"admin_plans.saving":            "Plan status: %s\n\nSaving changes...",
+
+	// Статистика в панели администратора
+	"admin.back":                 "Back",
+	"admin.unknown_action":       "Unknown action. Please choose an action from the menu.",
+	"admin_stats.menu":           "Statistics menu. Choose an action:",
+	"admin_stats.overview":       "Overview",
+	"admin_stats.revenue":        "Revenue",
+	"admin_stats.servers":        "Servers",
+	"admin_stats.trial":          "Free trial",
+	"admin_stats.cohorts":        "Cohorts and retention",
+	"admin_stats.system_error":   "Failed to load system statistics: %v",
+	"admin_stats.system":         "📊 *System overview*\n\n👥 *Users:*\n- Total users: %d\n- New users (7 days): %d\n\n🔑 *Subscriptions:*\n- Active subscriptions: %d\n- New subscriptions (7 days): %d\n\n💰 *Revenue:*\n- Total revenue: %s\n- Revenue for 30 days: %s\n\n🖥 *Servers:*\n- Active servers: %d\n- Connected clients: %d\n- Total capacity: %d\n- Server load: %.1f%%",
+	"admin_stats.servers_header": "📊 *Server statistics*\n\n",
+	"admin_stats.server":         "🖥 *Server #%d* %s\n- IP: `%s`\n- Clients: %d/%d (%.1f%%)\n\n",
+	"admin_servers.list_error":   "Failed to load the list of servers: %v",
+	"admin_servers.not_found":    "No servers found.",
+
+	// Меню администратора, списки серверов и планов
+	"admin.menu":                "🔧 *Admin menu*\n\nYour role: %s\nChoose an action:",
+	"admin.menu_servers":        "🖥️ Servers",
+	"admin.menu_plans":          "📑 Plans",
+	"admin.menu_users":          "👥 Users",
+	"admin.menu_stats":          "📊 Statistics",
+	"admin.menu_broadcast":      "📢 Broadcast",
+	"admin.menu_roles":          "🛡 Staff roles",
+	"admin.menu_audit":          "📜 Audit log",
+	"admin.back_button":         "🔙 Back",
+	"admin.edit":                "📝 Edit",
+	"admin.details":             "🔍 Details",
+	"admin.delete":              "❌ Delete",
+	"admin.yes":                 "yes",
+	"admin.no":                  "no",
+	"admin_servers.add":         "➕ Add server",
+	"admin_servers.empty":       "No servers found. Add a new server.",
+	"admin_servers.list_header": "*Servers*\n\nChoose a server to manage:",
+	"admin_servers.list_item":   "*Server #%d*\nIP: `%s:%d`\nClients: %d / %d\nStatus: %s",
+	"admin_servers.actions":     "Server actions:",
+	"admin_plans.add":           "➕ Add plan",
+	"admin_plans.empty":         "No subscription plans found. Add a new plan.",
+	"admin_plans.list_header":   "*Subscription plans*\n\nChoose a plan to manage:",
+	"admin_plans.list_item":     "*%s*\n%s\nPrice: %s\nDuration: %d days\nStatus: %s",
+	"admin_plans.trial_mark":    "\n🎁 Trial plan",
+	"admin_plans.actions":       "Plan actions:",
+	"admin_plans.not_found":     "Subscription plan not found.",
+	"admin_plans.unavailable":   "Unavailable",
+	"admin_plans.make_trial":    "🎁 Make trial",
+	"admin_plans.make_paid":     "💳 Make paid",
+	"admin_plans.details":       "*Subscription plan details*\n\n*ID:* `%d`\n*Name:* %s\n*Description:* %s\n*Price:* %s\n*Duration:* %d days\n*Status:* %s\n*Trial:* %s\n*Max pause:* %d days\n*Grace period:* %d days\n*Active subscriptions:* %s\n*Total subscriptions:* %s\n*Created:* %s\n*Updated:* %s",
+	"admin_plans.max_pause":     "⏸️ Pause limit",
+	"admin_plans.grace_days":    "⌛ Grace period",
+	"admin_plans.prices":        "💱 Prices in other currencies",
+	"admin_plans.back_to_list":  "🔙 Back to plans",
+
+	// Редактирование и удаление планов и серверов
+	"admin.cancel":                      "❌ Cancel",
+	"admin.confirm_delete":              "✅ Yes, delete",
+	"admin_plans.edit_start":            "📝 *Editing subscription plan*\n\n*Current values:*\n*Name:* %s\n*Description:* %s\n*Price:* %s\n*Duration:* %d days\n*Status:* %s %s\n\nEnter a new plan name (or send a dot '.' to keep the current name):",
+	"admin_plans.cancel_edit":           "❌ Cancel editing",
+	"admin_plans.confirm_delete":        "Do you really want to delete the plan *%s*?\n\n⚠️ Note: existing subscriptions are not affected, but new users will no longer be able to buy the plan.",
+	"admin_plans.delete_error":          "Failed to delete the plan: %v",
+	"admin_plans.deleted":               "✅ Subscription plan deleted.",
+	"admin_plans.add_start":             "➕ *New subscription plan*\n\nEnter the plan name:",
+	"admin_plans.unknown_action":        "Unknown plan action.",
+	"admin_servers.not_found_id":        "Error: server #%d not found",
+	"admin_servers.details":             "🖥️ *Server #%d*\n\nIP: `%s`\nPort: `%d`\nSSH user: `%s`\nMax clients: `%d`\nCurrent clients: `%d`\nStatus: %s\nCreated: `%s`\nUpdated: `%s`\n",
+	"admin_servers.check":               "🔍 Check availability",
+	"admin_servers.back_to_list":        "◀️ Back to servers",
+	"admin_servers.back_to_server":      "◀️ Back to server",
+	"admin_servers.subscriptions_error": "Failed to check the server subscriptions",
+	"admin_servers.has_subscriptions":   "❌ Server #%d cannot be deleted because it has %d active subscriptions.\n\nMove or cancel all subscriptions on this server first.",
+	"admin_servers.confirm_delete":      "❓ Do you really want to delete server #%d (%s)?\n\nThis cannot be undone.",
+	"admin_servers.unknown_action":      "Unknown action '%s' for server #%d",
+	"admin_servers.add_start":           "🖥️ *New server*\n\nEnter the server IP address:\n_(for example, 123.45.67.89)_",
+	"admin_servers.deleting":            "🗑️ Deleting server #%d (%s)...",
+	"admin_servers.delete_error":        "❌ Failed to delete server #%d: %v",
+	"admin_servers.deleted":             "✅ Server #%d (%s) deleted",
+
+	// Подписки и пользователи в панели администратора
+	"admin_subs.not_found":               "Error: subscription #%d not found",
+	"admin_subs.user_not_found":          "Error: the subscription user was not found",
+	"admin_subs.plan_not_found":          "Error: the subscription plan was not found",
+	"admin_subs.processing":              "⏳ Processing subscription #%d of user %s...",
+	"admin_subs.block_error":             "❌ Failed to block subscription #%d: could not connect to the VPN server.\n\nThe server may be temporarily unavailable. Please try again later.",
+	"admin_subs.blocked":                 "✅ Subscription #%d of user %s blocked",
+	"admin_subs.block_timeout":           "⚠️ Timed out while blocking subscription #%d.\n\nThe VPN server is not responding. Please try again later.",
+	"admin_subs.unblock_error":           "❌ Failed to unblock subscription #%d: could not connect to the VPN server.\n\nThe server may be temporarily unavailable. Please try again later.",
+	"admin_subs.unblocked":               "✅ Subscription #%d of user %s unblocked",
+	"admin_subs.unblock_timeout":         "⚠️ Timed out while unblocking subscription #%d.\n\nThe VPN server is not responding. Please try again later.",
+	"admin_subs.revoke_invalid":          "❌ Subscription #%d with status %s cannot be revoked",
+	"admin_subs.revoke_peer_unavailable": "❌ Subscription #%d of user %s was not revoked: the VPN server is unavailable, so the client configuration is still on the server. The subscription status is unchanged, please try again later.",
+	"admin_subs.revoke_timeout":          "⏳ The VPN server did not respond in time to revoking subscription #%d of user %s. The command is still running: the subscription will be marked as revoked as soon as the server removes the client configuration.",
+	"admin_subs.status_error":            "❌ Failed to update the status of subscription #%d",
+	"admin_subs.revoked":                 "✅ Subscription #%d of user %s revoked",
+	"admin_subs.unknown_action":          "Unknown action '%s' for subscription #%d",
+	"admin_users.not_found":              "Error: user #%d not found",
+	"admin_users.subscriptions_error":    "Failed to load the user subscriptions",
+	"admin_users.no_subscriptions":       "User %s has no subscriptions",
+	"admin_users.subscriptions_header":   "📋 Subscriptions of user %s:\n\n",
+	"admin_users.block_status_unknown":   " [block status: unknown]",
+	"admin_users.subscription_item":      "%d. #%d - %s %s%s\n   Plan: %s\n   End date: %s\n\n",
+	"admin_users.block_subscription":     "🔒 Block #%d",
+	"admin_users.unblock_subscription":   "🔓 Unblock #%d",
+	"admin_users.delete_subscription":    "❌ Delete #%d",
+	"admin_users.unknown_action":         "Unknown action for user #%d",
+
+	// Проверка доступности сервера
+	"admin_check.started":           "🔄 Checking server availability...",
+	"admin_check.header":            "🔍 Server availability check:\n\n",
+	"admin_check.not_found":         "❌ Error: the server was not found in the database",
+	"admin_check.server":            "🖥️ Server: %s (ID: %d)\n",
+	"admin_check.tcp":               "🔄 Checking the TCP connection...\n",
+	"admin_check.tcp_error":         "❌ TCP connection: error - %v\n",
+	"admin_check.tcp_ok":            "✅ TCP connection: established\n",
+	"admin_check.ssh":               "🔄 Checking the SSH connection...\n",
+	"admin_check.ssh_error":         "❌ SSH connection: error - %v\n",
+	"admin_check.ssh_ok":            "✅ SSH connection: established\n",
+	"admin_check.wireguard":         "🔄 Checking Wireguard...\n",
+	"admin_check.session_error":     "❌ SSH session: error - %v\n",
+	"admin_check.wireguard_missing": "❌ Wireguard: not installed\n",
+	"admin_check.wireguard_ok":      "✅ Wireguard: installed\n",
+	"admin_check.config":            "🔄 Checking the Wireguard configuration...\n",
+	"admin_check.config_missing":    "❌ Wireguard configuration: not found\n",
+	"admin_check.config_ok":         "✅ Wireguard configuration: found\n",
+	"admin_check.peers":             "👥 Active clients: %s\n",
+	"admin_check.done":              "\n✅ Check completed!\n⏱️ Time: %s",
+	"admin_check.retry":             "🔄 Check again",
+
+	// Статистика доходов
+	"revenue.page_summary":      "Summary",
+	"revenue.page_plans":        "By plan",
+	"revenue.page_days":         "By day",
+	"revenue.page_weeks":        "By week",
+	"revenue.page_months":       "By month",
+	"revenue.no_payments":       "No payments in this period.\n",
+	"revenue.week_of":           "week of %s",
+	"revenue.hidden_rows":       "…and %d more rows in the CSV export\n",
+	"revenue.header":            "💰 *Revenue for %d days* (%s)\n\n",
+	"revenue.stats_error":       "Failed to load revenue statistics: %v",
+	"revenue.currency_summary":  "*%s*\n*Payments:* %s (%d payments)\n*Plan purchases:* %d totalling %s\n*Average order:* %s\n*MRR (estimate):* %s\n\n",
+	"revenue.summary":           "*Active paid subscriptions:* %d\n\n*Renewals:* %d\n*Expired subscriptions:* %d\n*Renewal rate:* %.1f%%\n*Churn:* %.1f%%",
+	"revenue.plans_error":       "Failed to load sales by plan: %v",
+	"revenue.no_orders":         "No purchases in this period.",
+	"revenue.plan_row":          "*%s:* %d purchases, %s\n",
+	"revenue.payments_error":    "Failed to load payments: %v",
+	"revenue.period":            "%d d",
+	"revenue.export_csv":        "📥 Export CSV",
+	"revenue.export_error":      "Failed to build the export.",
+	"revenue.export_caption":    "Revenue for %d days",
+	"revenue.export_send_error": "Failed to send the export: %v",
+
+	// Когорты и удержание
+	"cohorts.error":          "Failed to build the cohort report: %v",
+	"cohorts.header":         "📅 *Cohorts by signup week (%d weeks)*\n\n",
+	"cohorts.empty":          "No users in this period.",
+	"cohorts.legend":         "M1-M3 - share of users with a paid subscription 1-3 months after signup, LTV - cumulative revenue per user in each currency.\n\n",
+	"cohorts.export_caption": "Cohort report: retention and LTV",
+	"cohorts.column_week":    "Week",
+	"cohorts.column_users":   "Users",
+
+	// Журнал действий сотрудников
+	"audit.error":                       "Failed to load the audit log: %v",
+	"audit.header":                      "📜 Staff audit log (page %d of %d, total entries: %d)\n\n",
+	"audit.empty":                       "No entries yet.",
+	"audit.before":                      "Before: %s\n",
+	"audit.after":                       "After: %s\n",
+	"audit.newer":                       "◀️ Newer",
+	"audit.older":                       "Older ▶️",
+	"audit.export_caption":              "Staff audit log (%d entries)",
+	"audit.action_server_add":           "Server added",
+	"audit.action_server_edit":          "Server edited",
+	"audit.action_server_reissue":       "Configurations reissued",
+	"audit.action_server_delete":        "Server deleted",
+	"audit.action_plan_add":             "Plan created",
+	"audit.action_plan_edit":            "Plan edited",
+	"audit.action_plan_delete":          "Plan deleted",
+	"audit.action_subscription_block":   "Subscription blocked",
+	"audit.action_subscription_unblock": "Subscription unblocked",
+	"audit.action_subscription_revoke":  "Subscription revoked",
+	"audit.action_role_change":          "Role changed",
+	"audit.action_balance_change":       "Balance changed",
+	"audit.action_broadcast":            "Broadcast started",
+	"audit.target_server":               "server",
+	"audit.target_plan":                 "plan",
+	"audit.target_subscription":         "subscription",
+	"audit.target_user":                 "user",
+	"audit.target_broadcast":            "broadcast",
+
+	// Список и карточка пользователей
+	"users.filter_all":                "All",
+	"users.filter_active":             "Active",
+	"users.filter_expired":            "Expired",
+	"users.filter_staff":              "Staff",
+	"users.filter_new":                "New this week",
+	"users.sort_newest":               "Newest",
+	"users.sort_payments":             "Payments",
+	"users.sort_name":                 "Name",
+	"users.list_line":                 "%s\n   active subscriptions: %d, payments: %s, since %s",
+	"users.list_error":                "Failed to load the list of users: %v",
+	"users.list_header":               "👥 Users: %d\nFilter: %s, sort: %s\nPage %d of %d\n\n",
+	"users.not_found":                 "No users found.",
+	"users.search":                    "🔎 Search",
+	"users.search_prompt":             "🔎 Enter a username, Telegram ID or subscription ID:",
+	"users.search_empty":              "The search query cannot be empty. Enter a username, Telegram ID or subscription ID:",
+	"users.search_error":              "Failed to search users: %v",
+	"users.search_header":             "🔎 Search results for \"%s\": %d\n\n",
+	"users.search_truncated":          "\nShowing the first %d, please refine the query.",
+	"users.search_again":              "🔎 Search again",
+	"users.all":                       "👥 All users",
+	"users.stats_error":               "Failed to load user statistics: %v",
+	"users.card_header":               "👤 User #%d\n\n",
+	"users.card_name":                 "Name: %s\n",
+	"users.card_role":                 "Role: %s\n",
+	"users.card_language":             "Language: %s\n",
+	"users.card_registered":           "Registered: %s\n",
+	"users.card_balance":              "Balance: %s\n",
+	"users.card_subscriptions":        "Subscriptions: %d (active: %d)\n",
+	"users.card_data_usage":           "Data used: %.2f GB\n",
+	"users.card_payments_total":       "Total payments: %s\n",
+	"users.card_subscriptions_header": "\n📋 Subscriptions:\n",
+	"users.card_none":                 "none\n",
+	"users.card_more":                 "…and %d more\n",
+	"users.card_plan_id":              "plan #%d",
+	"users.card_subscription":         "#%d %s — %s, until %s\n",
+	"users.card_payments_header":      "\n💳 Recent payments:\n",
+	"users.subscriptions":             "🔍 Subscriptions",
+	"users.balance":                   "💼 Balance",
+	"users.role":                      "🛡 Role",
+	"users.back_to_list":              "🔙 Back to list",
+
+	// Редактирование сервера
+	"server_edit.prompt_ip":                 "Enter a new IP address or domain name of the server (current value: `%s`):",
+	"server_edit.prompt_port":               "Enter a new SSH port of the server (current value: `%s`):",
+	"server_edit.prompt_user":               "Enter a new SSH user (current value: `%s`):",
+	"server_edit.prompt_password":           "Enter a new SSH password (current value: `%s`).\nThe message with the password will be deleted from the chat.",
+	"server_edit.prompt_max_clients":        "Enter the maximum number of clients (current value: `%s`):",
+	"server_edit.cancel_hint":               "\n\nSend /cancel to cancel",
+	"server_edit.invalid_ip":                "enter a valid IP address or domain name",
+	"server_edit.invalid_port":              "the port must be a number from 1 to 65535",
+	"server_edit.invalid_user":              "the user name cannot be empty or contain spaces",
+	"server_edit.invalid_password":          "the password cannot be empty",
+	"server_edit.invalid_max_clients":       "enter a whole number greater than zero",
+	"server_edit.max_clients_below_current": "the value cannot be less than the current number of clients (%d)",
+	"server_edit.unknown_field_name":        "unknown server parameter: %s",
+	"server_edit.invalid_value":             "❌ %s. Try again or send /cancel:",
+	"server_edit.menu":                      "📝 *Editing server #%d*\n\nChoose what to change:\n\n1. IP: `%s`\n2. Port: `%d`\n3. SSH user: `%s`\n4. SSH password: `%s`\n5. Max clients: `%d`\n6. Status: %s\n\nThe SSH connection is checked before a new IP, port, user or password is saved.",
+	"server_edit.button_port":               "2️⃣ Port",
+	"server_edit.button_user":               "3️⃣ SSH user",
+	"server_edit.button_password":           "4️⃣ SSH password",
+	"server_edit.button_max_clients":        "5️⃣ Max clients",
+	"server_edit.button_status":             "6️⃣ Status",
+	"server_edit.status_error":              "Failed to change the server status: %v",
+	"server_edit.status_changed":            "✅ Server #%d status: %s",
+	"server_edit.unknown_field":             "Unknown server parameter.",
+	"server_edit.checking":                  "🔄 Checking the SSH connection with the new parameters...",
+	"server_edit.check_failed":              "❌ Could not connect to the server with the new parameters: %v\n\nThe changes were not saved.",
+	"server_edit.save_error":                "Failed to save the server: %v",
+	"server_edit.saved":                     "✅ Server #%d updated",
+	"server_edit.subscriptions_error":       "Failed to load the server subscriptions: %v",
+	"server_edit.reissue_not_needed":        "The server has no current subscriptions, no configurations need to be reissued.",
+	"server_edit.reissue":                   "🔄 Reissue (%d)",
+	"server_edit.reissue_later":             "Not now",
+	"server_edit.reissue_offer":             "The server address changed to %s. %d subscriptions still have the old address in their configuration.\n\nReissue the configurations with the new address and send them to the subscribers?",
+	"server_edit.reissuing":                 "⏳ Reissuing configurations for %d subscriptions...",
+	"server_edit.reissued":                  "✅ Configurations reissued: %d",
+	"server_edit.reissue_failed":            "\n❌ Errors: %d (see the logs for details)",
+
+	// Рассылки
+	"broadcast.start":                  "📢 *New broadcast*\n\nSend the message to broadcast: text with Telegram formatting (bold, italic, links) or a photo with a caption. It will be copied to the recipients unchanged.",
+	"broadcast.unsupported_message":    "Only text and photos are supported. Send the message to broadcast:",
+	"broadcast.buttons_prompt":         "Add link buttons under the message, one per line, in the format\nButton text - https://example.com\n\nSend \"-\" if no buttons are needed.",
+	"broadcast.invalid_button_line":    "line \"%s\" is not in the \"Text - URL\" format",
+	"broadcast.too_many_buttons":       "too many buttons, the maximum is %d",
+	"broadcast.buttons_error":          "Error: %s\n\nSend the buttons again or \"-\" if no buttons are needed:",
+	"broadcast.buttons_save_error":     "Failed to save the buttons.",
+	"broadcast.buttons_read_error":     "Failed to read the broadcast buttons. Start the broadcast again.",
+	"broadcast.choose_segment":         "👥 Who should receive the broadcast?",
+	"broadcast.segment_all_button":     "All users",
+	"broadcast.segment_active_button":  "Active subscribers",
+	"broadcast.segment_expired_button": "Expired in the last N days",
+	"broadcast.segment_plan_button":    "Plan subscribers",
+	"broadcast.segment_server_button":  "Server subscribers",
+	"broadcast.not_found":              "The broadcast was not found or has expired. Start again from the admin menu.",
+	"broadcast.days_prompt":            "Enter the number of days: the broadcast goes to users whose subscription ended within that time and was not renewed.",
+	"broadcast.choose_plan":            "Choose a plan: the broadcast goes to users with an active subscription to it.",
+	"broadcast.choose_server":          "Choose a server: the broadcast goes to users with an active subscription on it.",
+	"broadcast.unknown_segment":        "Unknown recipient segment.",
+	"broadcast.invalid_days":           "Please enter a positive number of days:",
+	"broadcast.segment_all":            "all users",
+	"broadcast.segment_active":         "active subscribers",
+	"broadcast.segment_expired":        "subscription expired in the last %d days",
+	"broadcast.segment_plan":           "subscribers of plan \"%s\"",
+	"broadcast.segment_plan_id":        "subscribers of plan #%d",
+	"broadcast.segment_server":         "subscribers of server #%d %s",
+	"broadcast.segment_server_id":      "subscribers of server #%d",
+	"broadcast.audience_error":         "Failed to get the recipients: %v",
+	"broadcast.preview_error":          "Failed to show the preview: %v\nStart the broadcast again.",
+	"broadcast.preview":                "👆 This is how the recipients will see the message.\n\nRecipients: %s\nCount: %d\n\nSend the broadcast?",
+	"broadcast.send":                   "✅ Send",
+	"broadcast.choose_segment_first":   "Choose the broadcast recipients first.",
+	"broadcast.no_audience":            "There are no recipients, the broadcast was not sent.",
+	"broadcast.save_error":             "Failed to save the broadcast.",
+	"broadcast.started":                "🚀 Broadcast #%d started: %d recipients, about %s. I will send the statistics when it finishes.",
+	"broadcast.cancelled":              "Broadcast cancelled.",
+	"broadcast.finished":               "📢 Broadcast #%d finished in %s\n\nRecipients: %d\n✅ Delivered: %d\n🚫 Blocked the bot: %d\n❌ Errors: %d",
+	"broadcast.resumed":                "▶️ Broadcast #%d resumed after the bot restart: %d recipients left.",
+	"broadcast.crashed":                "⚠️ Broadcast #%d was interrupted by a bot crash and will not be resumed, so that nobody gets the message twice.\n\nRecipients: %d\n✅ Delivered: %d\n🚫 Blocked the bot: %d\n❌ Errors: %d",
+	"broadcast.buttons_corrupted":      "⚠️ Broadcast #%d was not sent: failed to read its buttons.",
+
+	// Обращения в поддержку: сообщения сотрудникам
+	"support_staff.history_button":         "📜 History",
+	"support_staff.close_button":           "✅ Close",
+	"support_staff.ticket":                 "🆘 Ticket #%d\n\n",
+	"support_staff.user":                   "User: %s (Telegram ID %d, #%d)\n",
+	"support_staff.ticket_subscription":    "Ticket subscription: #%d\n",
+	"support_staff.no_ticket_subscription": "Ticket subscription: not specified\n",
+	"support_staff.no_subscriptions":       "\nNo subscriptions.\n",
+	"support_staff.subscriptions":          "\nSubscriptions:\n",
+	"support_staff.plan_id":                "plan #%d",
+	"support_staff.subscription":           "• #%d %s — %s until %s\n",
+	"support_staff.server_not_found":       "  Server #%d not found\n",
+	"support_staff.server":                 "  Server #%d %s\n",
+	"support_staff.handshake_error":        "  Last handshake: unavailable\n",
+	"support_staff.handshake_never":        "  Last handshake: never\n",
+	"support_staff.handshake":              "  Last handshake: %s (%s ago)\n",
+	"support_staff.reply_hint":             "\nReply to any message of the ticket to send the answer to the user.",
+	"support_staff.message_from":           "💬 #%d from %s:",
+	"support_staff.closed_not_sent":        "Ticket #%d is closed, the reply was not sent.",
+	"support_staff.text_or_photo":          "Only text or photos can be sent to the user.",
+	"support_staff.author_not_found":       "Error: the author of ticket #%d was not found",
+	"support_staff.reply_error":            "❌ Failed to send the reply to ticket #%d: %v",
+	"support_staff.reply_sent":             "✅ Reply to ticket #%d sent",
+	"support_staff.not_found":              "Error: ticket #%d was not found",
+	"support_staff.already_closed":         "Ticket #%d is already closed.",
+	"support_staff.close_error":            "Failed to close the ticket.",
+	"support_staff.closed":                 "✅ Ticket #%d closed (%s)",
+	"support_staff.closed_by_user":         "✅ %s closed ticket #%d",
+	"support_staff.history_error":          "Failed to get the ticket history: %v",
+	"support_staff.history":                "📜 Ticket #%d history (%s, created %s)\n\n",
+	"support_staff.history_truncated":      "Showing the last %d of %d messages.\n\n",
+	"support_staff.author_user":            "User",
+	"support_staff.author_staff":           "Support",
+	"support_staff.photo":                  " 📎 photo",
+
+	// Цены плана в дополнительных валютах
+	"plan_prices.title":            "💱 *%s plan prices*\n\n",
+	"plan_prices.base":             "Base price: %s\n",
+	"plan_prices.not_set":          "not set",
+	"plan_prices.edit_button":      "✏️ Price in %s",
+	"plan_prices.note":             "\nSubscription invoices use the currency from the payment settings if the plan has a price in it, otherwise the base currency. Paying from the balance and changing plans require a price in rubles.",
+	"plan_prices.back":             "🔙 Back to plan",
+	"plan_prices.unknown_currency": "Unknown plan price currency.",
+	"plan_prices.prompt":           "💱 Current price of the *%s* plan in %s: %s.\n\nEnter a new price in %s or send '-' to remove the price in this currency:",
+	"plan_prices.invalid_amount":   "Please enter a positive price in %s or '-':",
+	"plan_prices.save_error":       "Failed to change the plan price: %v",
+
+	// Роли сотрудников
+	"roles.name_owner":    "👑 Owner",
+	"roles.name_admin":    "🛠 Administrator",
+	"roles.name_support":  "🎧 Support",
+	"roles.name_finance":  "💰 Finance",
+	"roles.name_user":     "User",
+	"roles.list_error":    "Failed to load the staff list: %v",
+	"roles.title":         "🛡 Staff and roles\n\n",
+	"roles.empty":         "There are no staff members yet.\n",
+	"roles.hint":          "\nTo assign or remove a role, open the user in the «Users» section and press «🛡 Role».\n\n",
+	"roles.permissions":   "Role permissions:\n• Owner — all sections and role assignment\n• Administrator — all sections except role assignment\n• Support — users and their subscriptions, statistics\n• Finance — payments, analytics, balance adjustments, statistics",
+	"roles.remove_button": "❌ Remove role",
+	"roles.choose":        "🛡 Role of %s\n\nCurrent role: %s\nChoose a new role:",
+	"roles.unknown":       "Unknown role.",
+	"roles.own_role":      "You cannot change your own role.",
+	"roles.save_error":    "Failed to change the user role.",
+	"roles.changed":       "✅ Role of %s: %s",
+
+	// Лимиты плана в днях
+	"plan_limits.max_pause_prompt": "⏸️ Current pause limit of the *%s* plan: %d days.\n\nEnter the maximum pause length in days (0 - pausing is not allowed):",
+	"plan_limits.grace_prompt":     "⌛ Current grace period of the *%s* plan: %d days.\n\nEnter the grace period length in days. During it the peer is blocked, but the configuration is kept until renewal (0 - revoke right after the subscription ends):",
+	"plan_limits.unknown":          "Unknown plan setting.",
+	"plan_limits.invalid_days":     "Please enter a whole number from 0 to %d:",
+	"plan_limits.save_error":       "Failed to change the plan: %v",
+
+	// Статистика пробного периода
+	"admin_stats.trial_error": "Failed to load the trial statistics: %v",
+	"admin_stats.trial_text":  "🎁 *Trial statistics*\n\n- Activated the trial: %d\n- Active trial subscriptions: %d\n- Paid after the trial: %d\n- Conversion to paid subscriptions: %.1f%%",
+
+	// Баланс пользователя в панели администратора
+	"balance_admin.error":          "Failed to load the balance: %v",
+	"balance_admin.text":           "💼 Balance of %s (#%d): %s\n\nRecent transactions:\n%s",
+	"balance_admin.adjust_button":  "✏️ Adjustment",
+	"balance_admin.bonus_button":   "🎁 Bonus",
+	"balance_admin.adjust_prompt":  "Enter the adjustment amount in rubles (for example, 150 or -50):",
+	"balance_admin.bonus_prompt":   "Enter the bonus amount in rubles:",
+	"balance_admin.start":          "💼 Changing the balance of %s (#%d)\n\n%s",
+	"balance_admin.invalid_amount": "Please enter a valid amount (a non-zero number):",
+	"balance_admin.negative_bonus": "The bonus amount must be positive:",
+	"balance_admin.reason_prompt":  "Enter the reason for the balance change:",
+	"balance_admin.empty_reason":   "The reason cannot be empty. Enter the reason for the balance change:",
+	"balance_admin.save_error":     "❌ Failed to change the balance: %v",
+	"balance_admin.changed":        "✅ Balance of user #%d changed by %s. Current balance: %s",
+
+	// Отклоненные нажатия кнопок: сообщение сотрудникам
+	"callback.rejected_report": "🚨 Button press rejected\n\nUser: @%s (ID %d)\nReason: %s\nData: %s",
+}
@@ -0,0 +1,152 @@
+// Package i18n содержит каталоги сообщений бота и выбор языка интерфейса пользователя.
+package i18n
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultLanguage - язык, на котором написан основной каталог и который используется, если язык неизвестен
+	DefaultLanguage = "ru"
+	// ForeignLanguage - язык для пользователей, язык Telegram которых не поддерживается ботом
+	ForeignLanguage = "en"
+)
+
+// catalogs - каталоги сообщений по кодам языков
+var catalogs = map[string]map[string]string{
+	"ru": ru,
+	"en": en,
+}
+
+// languageNames - названия языков на самих этих языках
+var languageNames = map[string]string{
+	"ru": "🇷🇺 Русский",
+	"en": "🇬🇧 English",
+}
+
+// Languages возвращает коды поддерживаемых языков в порядке отображения
+func Languages() []string {
+	return []string{"ru", "en"}
+}
+
+// IsSupported проверяет, есть ли каталог для языка
+func IsSupported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Name возвращает название языка для отображения пользователю
+func Name(lang string) string {
+	if name, ok := languageNames[lang]; ok {
+		return name
+	}
+	return lang
+}
+
+// Resolve выбирает язык интерфейса: сначала язык, выбранный пользователем командой /language,
+// затем язык из настроек Telegram (language_code, например "en" или "pt-br").
+// Если язык Telegram неизвестен, используется DefaultLanguage, если не поддерживается - ForeignLanguage.
+func Resolve(override, languageCode string) string {
+	if IsSupported(override) {
+		return override
+	}
+
+	code := strings.ToLower(strings.TrimSpace(languageCode))
+	if code == "" {
+		return DefaultLanguage
+	}
+	if i := strings.IndexAny(code, "-_"); i >= 0 {
+		code = code[:i]
+	}
+	if IsSupported(code) {
+		return code
+	}
+	return ForeignLanguage
+}
+
+// T возвращает сообщение по ключу на языке lang, подставляя аргументы через fmt.Sprintf.
+// Если в каталоге языка нет ключа, используется основной каталог, если нет и там - сам ключ.
+func T(lang, key string, args ...interface{}) string {
+	text, ok := catalogs[lang][key]
+	if !ok {
+		text, ok = catalogs[DefaultLanguage][key]
+		if !ok {
+			log.Printf("i18n: отсутствует ключ %q", key)
+			return key
+		}
+		log.Printf("i18n: ключ %q отсутствует в каталоге %q", key, lang)
+	}
+
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// Match возвращает тот из ключей, перевод которого на любом из языков совпадает с text.
+// Используется для распознавания нажатий на кнопки клавиатуры, которые приходят обычным текстом.
+func Match(text string, keys ...string) (string, bool) {
+	for _, key := range keys {
+		for _, catalog := range catalogs {
+			if catalog[key] == text {
+				return key, true
+			}
+		}
+	}
+	return "", false
+}
+
+// countVerbs возвращает количество подстановок fmt в строке (без учета %%)
+func countVerbs(text string) int {
+	count := 0
+	for i := 0; i < len(text); i++ {
+		if text[i] != '%' {
+			continue
+		}
+		if i+1 < len(text) && text[i+1] == '%' {
+			i++
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+// Validate проверяет, что во всех каталогах есть одни и те же ключи и количество подстановок
+// в переводах совпадает с основным каталогом. Вызывается при запуске бота.
+func Validate() error {
+	base := catalogs[DefaultLanguage]
+	var problems []string
+
+	for lang, catalog := range catalogs {
+		if lang == DefaultLanguage {
+			continue
+		}
+
+		for key, text := range base {
+			translated, ok := catalog[key]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s: отсутствует ключ %q", lang, key))
+				continue
+			}
+			if countVerbs(translated) != countVerbs(text) {
+				problems = append(problems, fmt.Sprintf("%s: количество подстановок в %q не совпадает", lang, key))
+			}
+		}
+
+		for key := range catalog {
+			if _, ok := base[key]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: лишний ключ %q", lang, key))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("message catalogs are inconsistent:\n%s", strings.Join(problems, "\n"))
+	}
+	return nil
+}
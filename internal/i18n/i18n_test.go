@@ -0,0 +1,192 @@
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// keyUse - ключ каталога, найденный в исходном коде
+type keyUse struct {
+	key    string
+	prefix bool // Ключ составной ("status."+status): проверяется, что в каталоге есть ключи с таким началом
+	args   int  // Количество аргументов форматирования, -1 - не проверяется
+	pos    string
+}
+
+// keyArgs возвращает аргументы вызова, которые являются ключами каталога:
+// i18n.T(lang, key, ...), h.t(chatID, key, ...), T(lang, key, ...) внутри пакета и i18n.Match(text, keys...).
+// formatted сообщает, что остальные аргументы вызова подставляются в текст ключа
+func keyArgs(call *ast.CallExpr) (keys []ast.Expr, formatted bool) {
+	switch fun := call.Fun.(type) {
+	case *ast.SelectorExpr:
+		pkg, _ := fun.X.(*ast.Ident)
+		switch {
+		case pkg != nil && pkg.Name == "i18n" && fun.Sel.Name == "T", fun.Sel.Name == "t":
+			if len(call.Args) > 1 {
+				return call.Args[1:2], true
+			}
+		case pkg != nil && pkg.Name == "i18n" && fun.Sel.Name == "Match":
+			if len(call.Args) > 1 {
+				return call.Args[1:], false
+			}
+		}
+	case *ast.Ident:
+		if fun.Name == "T" && len(call.Args) > 1 {
+			return call.Args[1:2], true
+		}
+	}
+	return nil, false
+}
+
+// tableKeys возвращает ключи каталога, перечисленные в значениях составного литерала
+// (например, в таблице названий действий), - строки вида "раздел.ключ" с известным разделом
+func tableKeys(fset *token.FileSet, table *ast.CompositeLit) []keyUse {
+	var uses []keyUse
+	for _, element := range table.Elts {
+		if pair, ok := element.(*ast.KeyValueExpr); ok {
+			element = pair.Value
+		}
+		literal, ok := element.(*ast.BasicLit)
+		if !ok || literal.Kind != token.STRING {
+			continue
+		}
+		key, err := strconv.Unquote(literal.Value)
+		if err != nil || !isCatalogKey(key) {
+			continue
+		}
+		uses = append(uses, keyUse{key: key, args: -1, pos: fset.Position(literal.Pos()).String()})
+	}
+	return uses
+}
+
+// isCatalogKey сообщает, похожа ли строка на ключ одного из разделов основного каталога
+func isCatalogKey(text string) bool {
+	section, name, ok := strings.Cut(text, ".")
+	if !ok || name == "" || strings.ContainsAny(text, " %/") {
+		return false
+	}
+	return hasPrefix(catalogs[DefaultLanguage], section+".")
+}
+
+// collectKeys находит ключи каталога во всех файлах модуля, кроме тестов
+func collectKeys(t *testing.T, root string) []keyUse {
+	t.Helper()
+
+	var uses []keyUse
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+
+		ast.Inspect(file, func(node ast.Node) bool {
+			if table, ok := node.(*ast.CompositeLit); ok {
+				uses = append(uses, tableKeys(fset, table)...)
+				return true
+			}
+			call, ok := node.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			keys, formatted := keyArgs(call)
+			for _, arg := range keys {
+				use := keyUse{args: -1, pos: fset.Position(arg.Pos()).String()}
+				if formatted && !call.Ellipsis.IsValid() {
+					use.args = len(call.Args) - 2
+				}
+				if binary, ok := arg.(*ast.BinaryExpr); ok && binary.Op == token.ADD {
+					arg, use.prefix = binary.X, true
+				}
+				literal, ok := arg.(*ast.BasicLit)
+				if !ok || literal.Kind != token.STRING {
+					continue
+				}
+				use.key, err = strconv.Unquote(literal.Value)
+				if err != nil {
+					t.Fatalf("%s: %v", use.pos, err)
+				}
+				uses = append(uses, use)
+			}
+			return true
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("scan sources: %v", err)
+	}
+	return uses
+}
+
+func hasPrefix(catalog map[string]string, prefix string) bool {
+	for key := range catalog {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestKeysUsedInCodeExist(t *testing.T) {
+	uses := collectKeys(t, filepath.Join("..", ".."))
+	if len(uses) == 0 {
+		t.Fatal("no catalog keys found in the sources")
+	}
+
+	for _, use := range uses {
+		for lang, catalog := range catalogs {
+			if use.prefix {
+				if !hasPrefix(catalog, use.key) {
+					t.Errorf("%s: %s: no keys with prefix %q", use.pos, lang, use.key)
+				}
+				continue
+			}
+			text, ok := catalog[use.key]
+			if !ok {
+				t.Errorf("%s: %s: missing key %q", use.pos, lang, use.key)
+				continue
+			}
+			if use.args >= 0 && countVerbs(text) != use.args {
+				t.Errorf("%s: %s: key %q expects %d arguments, got %d", use.pos, lang, use.key, countVerbs(text), use.args)
+			}
+		}
+	}
+}
+
+func TestCatalogsAreConsistent(t *testing.T) {
+	if err := Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		override, code, want string
+	}{
+		{"", "", DefaultLanguage},
+		{"", "ru", "ru"},
+		{"", "en-US", "en"},
+		{"", "pt-br", ForeignLanguage},
+		{"en", "ru", "en"},
+		{"xx", "ru", "ru"},
+	}
+
+	for _, tt := range tests {
+		if got := Resolve(tt.override, tt.code); got != tt.want {
+			t.Errorf("Resolve(%q, %q) = %q, want %q", tt.override, tt.code, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,847 @@
+package i18n
+
+// ru - основной каталог сообщений
+var ru = map[string]string{
+	// Общие сообщения
+	"common.user_error":             "Ошибка при получении информации о пользователе. Пожалуйста, попробуйте позже.",
+	"common.user_error_support":     "Ошибка при получении информации о пользователе. Пожалуйста, обратитесь в поддержку.",
+	"common.plan_error":             "Ошибка при получении информации о плане: %v",
+	"common.plans_error":            "Ошибка при получении списка планов: %v",
+	"common.server_error":           "Ошибка при получении информации о сервере. Пожалуйста, попробуйте позже.",
+	"common.servers_check_error":    "Ошибка при проверке доступности серверов. Пожалуйста, попробуйте позже.",
+	"common.no_servers":             "К сожалению, в данный момент нет доступных серверов. Пожалуйста, попробуйте позже.",
+	"common.invoice_error":          "Ошибка при создании счета для оплаты: %v",
+	"common.plan_unavailable":       "Выбранный план недоступен для покупки.",
+	"common.subscription_not_found": "Подписка не найдена.",
+	"common.no_admin_rights":        "У вас нет прав администратора.",
+	"common.unknown_command":        "Неизвестная команда. Используйте /help для получения списка команд.",
+
+	// Главное меню
+	"menu.buy":     "💰 Купить подписку",
+	"menu.my":      "🔑 Мои подписки",
+	"menu.balance": "💼 Баланс",
+	"menu.help":    "ℹ️ Помощь",
+	"menu.support": "📞 Поддержка",
+	"menu.admin":   "⚙️ Админ-панель",
+
+	"start.welcome": `
+🔒 *Добро пожаловать в VPN бот!*
+
+Этот бот поможет вам приобрести и управлять подписками на VPN-сервис.
+Используйте кнопки меню для быстрого доступа к функциям.
+`,
+	"start.admin": "\nУ вас есть права администратора!",
+
+	"help.text": `
+*Справка по использованию VPN-бота*
+
+*Основные кнопки меню:*
+• 💰 *Купить подписку* - просмотр и покупка доступных тарифных планов
+• 🔑 *Мои подписки* - управление вашими активными подписками
+• 💼 *Баланс* - баланс, пополнение и история операций
+• ℹ️ *Помощь* - получение этой справки
+• 📞 *Поддержка* - связь с командой поддержки
+
+*Доступные команды:*
+• /start - отобразить главное меню бота
+• /help - показать эту справку
+• /buy - купить подписку на VPN
+• /my - просмотреть ваши активные подписки
+• /trial - активировать бесплатный пробный период
+• /redeem - активировать подарочный код
+• /gifts - статус купленных подарков
+• /balance - баланс и история операций
+• /language - выбрать язык интерфейса
+• /cancel - отменить текущее действие
+`,
+	"help.admin": `
+*Команды администратора:*
+• ⚙️ *Админ-панель* - меню управления ботом
+• /admin - открыть панель администратора
+`,
+
+	"support.text": `
+*Поддержка VPN-сервиса*
+
+Если у вас возникли вопросы или проблемы с использованием нашего VPN, создайте обращение:
+
+1. Выберите подписку, с которой возникла проблема
+2. Опишите проблему подробно
+3. По возможности приложите скриншоты ошибок
+
+Наша команда поддержки ответит вам здесь, в чате с ботом.
+`,
+	"support.new":                 "✉️ Новое обращение",
+	"support.ticket_write":        "✍️ Написать в #%d (%s)",
+	"support.ticket_close":        "✅ Закрыть #%d",
+	"support.status.open":         "ждет ответа",
+	"support.status.pending":      "есть ответ",
+	"support.status.closed":       "закрыто",
+	"support.choose_subscription": "С какой подпиской связано обращение?",
+	"support.subscription":        "#%d %s (%s)",
+	"support.no_subscription":     "Без подписки",
+	"support.describe":            "Опишите проблему одним или несколькими сообщениями. Можно прикрепить скриншоты.",
+	"support.text_or_photo":       "Поддерживаются только текст и фото. Опишите проблему или пришлите скриншот.",
+	"support.created":             "✅ Обращение #%d создано. Можете отправить еще сообщения или скриншоты - они попадут в это обращение. Мы ответим здесь, в чате с ботом.",
+	"support.added":               "📎 Добавлено в обращение #%d.",
+	"support.reply":               "💬 Ответ поддержки по обращению #%d:\n\n%s\n\nЧтобы ответить, используйте «Ответить» на этом сообщении.",
+	"support.reply_photo":         "💬 Ответ поддержки по обращению #%d",
+	"support.closed":              "✅ Обращение #%d закрыто. Если проблема повторится, создайте новое обращение.",
+	"support.closed_by_staff":     "✅ Поддержка закрыла обращение #%d. Если проблема не решена, создайте новое обращение.",
+	"support.ticket_closed":       "Обращение #%d уже закрыто. Если проблема не решена, создайте новое обращение.",
+	"support.not_found":           "Обращение не найдено.",
+	"support.error":               "Не удалось отправить обращение. Пожалуйста, попробуйте позже.",
+
+	// Выбор языка
+	"language.choose":  "🌐 *Язык интерфейса*\n\nТекущий язык: %s\nВыберите язык бота:",
+	"language.auto":    "🌐 Как в Telegram",
+	"language.changed": "✅ Язык интерфейса изменен: %s",
+	"language.error":   "Ошибка при изменении языка. Пожалуйста, попробуйте позже.",
+
+	// Отмена диалога
+	"cancel.done":    "❌ Текущее действие отменено.",
+	"cancel.nothing": "Нет активного действия для отмены.",
+
+	// Кнопки устарели или недоступны
+	"callback.outdated":  "Эта кнопка устарела. Откройте меню заново командой /start.",
+	"callback.forbidden": "⛔️ Это действие вам недоступно.",
+
+	// Роли сотрудников
+	"role.granted": "✅ Вам назначена роль «%s». Откройте панель управления командой /admin.",
+	"role.revoked": "Ваша роль сотрудника снята, панель управления больше недоступна.",
+	"role.owner":   "Владелец",
+	"role.admin":   "Администратор",
+	"role.support": "Поддержка",
+	"role.finance": "Финансы",
+
+	// Кнопки
+	"button.config":        "📄 Конфигурация",
+	"button.stats":         "📊 Статистика",
+	"button.change_plan":   "🔄 Сменить план",
+	"button.pause":         "⏸️ Приостановить",
+	"button.resume":        "▶️ Возобновить",
+	"button.renew":         "🔁 Продлить",
+	"button.renew_long":    "🔁 Продлить подписку",
+	"button.block":         "🔒 Блокировать",
+	"button.unblock":       "🔓 Разблокировать",
+	"button.buy":           "💳 Купить",
+	"button.gift":          "🎁 Подарить",
+	"button.balance_pay":   "💼 Оплатить с баланса",
+	"button.topup":         "💼 Пополнить баланс",
+	"button.choose_plan":   "💰 Выбрать план подписки",
+	"button.choose_tariff": "💰 Выбрать тарифный план",
+	"button.buy_more":      "💰 Купить еще подписку",
+	"button.trial":         "🎁 Активировать пробный период",
+
+	// Статусы подписок
+	"status.pending": "Оформляется",
+	"status.active":  "Активна",
+	"status.blocked": "Заблокирована",
+	"status.paused":  "Приостановлена",
+	"status.grace":   "Льготный период",
+	"status.expired": "Истекла",
+	"status.revoked": "Отозвана",
+
+	// Мои подписки
+	"my.subscriptions_error": "❌ Ошибка при получении информации о подписках. Пожалуйста, попробуйте позже.",
+	"my.empty": `
+*У вас пока нет активных подписок* 🔎
+
+Чтобы начать пользоваться VPN-сервисом:
+1️⃣ Нажмите на кнопку *"💰 Купить подписку"*
+2️⃣ Выберите подходящий тарифный план
+3️⃣ Оплатите подписку через Telegram
+4️⃣ Получите доступ к VPN мгновенно!
+`,
+	"my.header":       "*🔑 Ваши VPN-подписки (%d)*\n",
+	"my.default_plan": "План подписки",
+	"my.days_paused":  "🗓️ *Осталось дней:* %d (на паузе с %s)\n",
+	"my.renew_until":  "🗓️ *Продлите до:* %s, чтобы сохранить конфигурацию\n",
+	"my.days_left":    "🗓️ *Осталось дней:* %d\n",
+	"my.overdue":      "🗓️ *Статус:* Просрочена\n",
+	"my.card": "*VPN-подписка #%d*\n\n" +
+		"%s *Статус:* %s\n" +
+		"📋 *План:* %s\n" +
+		"🌐 *Сервер:* %s\n" +
+		"📅 *Действует до:* %s\n" +
+		"%s" +
+		"📊 *Использовано данных:* %s\n",
+	"my.last_connection": "🔄 *Последнее подключение:* %s\n",
+	"my.buy_more":        "*Хотите добавить еще одну подписку?*",
+
+	// Планы и покупка
+	"plans.none": "В настоящее время нет доступных планов подписки. Пожалуйста, попробуйте позже.",
+	"plans.header": `
+*💰 Выберите план подписки*
+
+Ниже представлены доступные тарифные планы для VPN-подключения.
+Выберите подходящий вариант и нажмите на кнопку для оформления подписки.
+`,
+	"plans.card": "*%s*\n\n" +
+		"%s\n\n" +
+		"💰 *Цена:* %s\n" +
+		"⏳ *Длительность:* %d дней\n" +
+		"💵 *Цена за день:* %s",
+	"plans.footer":           "*Остались вопросы?*\nСвяжитесь с нашей технической поддержкой.",
+	"purchase.invoice_title": "VPN-подписка: %s",
+	"purchase.invoice_desc":  "Подписка на VPN-сервис длительностью %d дней",
+	"purchase.instructions": `
+*Инструкция по оплате:*
+
+1. Нажмите кнопку "Оплатить" в отправленном счете
+2. Выберите способ оплаты
+3. Следуйте инструкциям для завершения оплаты
+4. После успешной оплаты вы получите конфигурационный файл и инструкции по настройке VPN
+
+В случае возникновения проблем с оплатой, обратитесь в службу поддержки.
+`,
+	"purchase.success": "✅ *Подписка успешно оформлена!*\n\n" +
+		"План: %s\n" +
+		"Срок действия: %d дней\n" +
+		"Дата начала: %s\n" +
+		"Дата окончания: %s\n\n" +
+		"Спасибо за покупку!",
+
+	// Обработка платежей
+	"payment.invalid_data":         "Ошибка при обработке платежа: неверный формат данных.",
+	"payment.invalid_subscription": "Ошибка при обработке платежа: неверный ID подписки.",
+	"payment.invalid_plan":         "Ошибка при обработке платежа: неверный ID плана.",
+	"payment.processing_error":     "Ошибка при обработке платежа. Пожалуйста, обратитесь в поддержку.",
+	"payment.credited":             "Оплата %s зачислена на ваш баланс.",
+	"payment.not_credited":         "Оплата %s сохранена, но ее нельзя зачислить на баланс: баланс ведется в рублях. Пожалуйста, обратитесь в поддержку.",
+
+	// Выдача конфигурации
+	"provision.setup_error":  "Ошибка при настройке сервера VPN: %v",
+	"provision.config_error": "Ошибка при создании конфигурации VPN: %v",
+	"provision.save_error":   "Ошибка при создании подписки: %v",
+	"config.caption":         "Вот ваш файл конфигурации VPN. Инструкция по установке в следующем сообщении.",
+	"config.send_error":      "Ошибка при отправке файла конфигурации: %v",
+	"config.reissued":        "🔄 Адрес VPN-сервера изменился. Это обновленный файл конфигурации для подписки #%d: импортируйте его в приложение WireGuard вместо старого.",
+	"config.instructions": `
+*Инструкция по настройке VPN:*
+
+1. Скачайте и установите клиент AmneziaVPN:
+   - для Windows: https://github.com/amnezia-vpn/amnezia-client/releases/download/4.8.3.1/AmneziaVPN_4.8.3.1_x64.exe
+   - для MacOS: https://github.com/amnezia-vpn/amnezia-client/releases/download/4.8.3.1/AmneziaVPN_4.8.3.1_macos.dmg
+   - для iOS: https://apps.apple.com/us/app/amneziavpn/id1600529900
+   - для Android: https://play.google.com/store/apps/details?id=org.amnezia.vpn
+
+2. Откройте клиент AmneziaVPN
+3. Импортируйте полученный файл конфигурации
+4. Активируйте подключение
+
+Готово! Теперь ваш трафик защищен VPN.
+`,
+
+	// Действия администратора с подпиской
+	"subscription.blocked_notice":   "❗ Ваша подписка #%d (%s) была заблокирована администратором",
+	"subscription.unblocked_notice": "✅ Ваша подписка #%d (%s) была разблокирована администратором",
+	"subscription.revoked_notice":   "❗ Ваша подписка #%d (%s) была отозвана администратором",
+
+	// Пробный период
+	"trial.offer": "🎁 *Попробуйте бесплатно!*\n\n" +
+		"Пробный период на %d дней без оплаты. Активировать его можно только один раз.",
+	"trial.already_used":     "Вы уже использовали пробный период. Выберите платный план с помощью команды /buy.",
+	"trial.unavailable":      "Пробный период сейчас недоступен.",
+	"trial.activation_error": "Ошибка при активации пробного периода. Пожалуйста, попробуйте позже.",
+	"trial.activating":       "⏳ Активирую пробный период, это может занять некоторое время...",
+	"trial.success": "🎁 *Пробный период активирован!*\n\n" +
+		"Срок действия: %d дней\n" +
+		"Дата окончания: %s\n\n" +
+		"Понравилось? Оформите платную подписку до окончания пробного периода, чтобы не потерять доступ.",
+
+	// Подарки
+	"gift.invoice_title": "Подарочная VPN-подписка: %s",
+	"gift.invoice_desc":  "Подарочный код на VPN-подписку длительностью %d дней. Код действует %d дней.",
+	"gift.after_payment": "🎁 После оплаты вы получите подарочный код и ссылку, которые можно переслать получателю.",
+	"gift.create_error":  "❌ Оплата получена, но не удалось создать подарочный код. Пожалуйста, обратитесь в поддержку.",
+	"gift.created": "🎁 *Подарок оформлен!*\n\n" +
+		"План: %s (%d дней)\n" +
+		"Код: `%s`\n" +
+		"Действует до: %s\n\n" +
+		"Перешлите получателю ссылку ниже. Открыв ее, он сразу получит VPN-подписку:\n[🎁 Получить подарок](%s)\n\n" +
+		"Также код можно активировать командой `/redeem %s`.\n" +
+		"Статус ваших подарков: /gifts",
+	"gift.code_required": "Укажите подарочный код: `/redeem КОД`",
+	"gift.no_servers":    "К сожалению, в данный момент нет доступных серверов. Подарочный код сохранен, попробуйте активировать его позже.",
+	"gift.redeem_error":  "Ошибка при активации подарочного кода. Пожалуйста, попробуйте позже.",
+	"gift.activating":    "⏳ Активирую подарочную подписку, это может занять некоторое время...",
+	"gift.redeemed": "🎁 *Подарок активирован!*\n\n" +
+		"План: %s\n" +
+		"Срок действия: %d дней\n" +
+		"Дата окончания: %s",
+	"gift.buyer_notice":     "🎁 Ваш подарочный код `%s` активирован пользователем %s.",
+	"gift.not_found":        "❌ Подарочный код не найден. Проверьте правильность ввода.",
+	"gift.already_redeemed": "❌ Этот подарочный код уже был активирован.",
+	"gift.expired":          "❌ Срок действия подарочного кода истек %s.",
+	"gift.unavailable":      "❌ Подарочный код недоступен для активации.",
+	"gift.list_error":       "Ошибка при получении списка подарков. Пожалуйста, попробуйте позже.",
+	"gift.list_empty":       "У вас пока нет купленных подарков. Подарить VPN можно из списка планов: /buy",
+	"gift.list_header":      "🎁 *Ваши подарки (%d)*\n\n",
+	"gift.plan_fallback":    "План #%d",
+	"gift.status_redeemed":  "✅ Активирован %s",
+	"gift.status_expired":   "⏱️ Истек",
+	"gift.status_pending":   "🕓 Ожидает активации до %s",
+
+	// Баланс
+	"ledger.topup":      "Пополнение",
+	"ledger.purchase":   "Покупка",
+	"ledger.refund":     "Возврат",
+	"ledger.bonus":      "Бонус",
+	"ledger.adjustment": "Корректировка",
+
+	"balance.no_transactions": "Операций пока нет.\n",
+	"balance.transaction":     "%s · %s: %s",
+	"balance.error":           "Ошибка при получении баланса. Пожалуйста, попробуйте позже.",
+	"balance.text": "💼 Ваш баланс: %s\n\n" +
+		"Последние операции:\n%s\n" +
+		"Баланс можно использовать для оплаты подписок. Выберите сумму пополнения:",
+	"balance.invalid_amount": "Некорректная сумма пополнения.",
+	"balance.invoice_title":  "Пополнение баланса",
+	"balance.invoice_desc":   "Пополнение баланса VPN-сервиса на %d руб.",
+	"balance.topup_error":    "❌ Оплата получена, но не удалось зачислить средства на баланс. Пожалуйста, обратитесь в поддержку.",
+	"balance.topped_up":      "✅ Баланс пополнен на %s\nТекущий баланс: %s",
+	"balance.insufficient":   "❌ Недостаточно средств на балансе.\nСтоимость плана: %s\nВаш баланс: %s",
+	"balance.pay_error":      "Ошибка при оплате с баланса. Пожалуйста, попробуйте позже.",
+	"balance.no_price":       "Этот план нельзя оплатить с баланса: для него не задана цена в рублях.",
+	"balance.provisioning":   "⏳ Оформляю подписку, это может занять некоторое время...",
+	"balance.refunded":       "\nСредства возвращены на баланс.",
+	"balance.purchase_success": "✅ *Подписка успешно оформлена!*\n\n" +
+		"План: %s\n" +
+		"Срок действия: %d дней\n" +
+		"Дата окончания: %s\n" +
+		"Остаток на балансе: %s",
+	"balance.changed_notice": "💼 %s: %s\nТекущий баланс: %s",
+
+	// Смена плана
+	"plan_change.only_active": "Сменить план можно только у активной подписки.",
+	"plan_change.header": "🔄 *Смена плана подписки #%d*\n\n" +
+		"Текущий план: %s\n" +
+		"Действует до: %s\n\n" +
+		"Стоимость оставшихся дней засчитывается в оплату нового плана, " +
+		"новый план действует с момента перехода на полный срок. " +
+		"Если зачтенная сумма больше цены нового плана, разница вернется на баланс.\n\n",
+	"plan_change.charge":        "доплата %s",
+	"plan_change.surplus":       "возврат на баланс %s",
+	"plan_change.option":        "• *%s* (%d дней, %s): %s\n",
+	"plan_change.no_plans":      "Нет других планов, на которые можно перейти.",
+	"plan_change.invoice_title": "Переход на план «%s»",
+	"plan_change.invoice_desc":  "Доплата за переход подписки #%d на план «%s» с учетом %s за неиспользованные дни",
+	"plan_change.invoice_label": "Доплата за план «%s»",
+	"plan_change.unavailable":   "Выбранный план недоступен для перехода.",
+	"plan_change.stale_invoice": "Стоимость перехода изменилась после выставления счета, план не изменен.",
+	"plan_change.error":         "Ошибка при смене плана. Пожалуйста, обратитесь в поддержку.",
+	"plan_change.not_applied":   "Не удалось сменить план подписки, план не изменен.",
+	"plan_change.success": "✅ *План подписки изменен!*\n\n" +
+		"Новый план: %s\n" +
+		"Дата окончания: %s\n" +
+		"Конфигурация VPN осталась прежней.",
+	"plan_change.refunded": "\n\nНа баланс возвращено %s",
+
+	// Пауза
+	"pause.only_active":        "Приостановить можно только активную подписку.",
+	"pause.unavailable":        "Приостановка недоступна для вашего плана подписки.",
+	"pause.expired":            "Срок действия подписки уже истек.",
+	"pause.failed":             "Не удалось приостановить подписку. Возможно, она уже приостановлена.",
+	"pause.server_unavailable": "❌ Не удалось приостановить подписку: сервер VPN недоступен. Пожалуйста, попробуйте позже.",
+	"pause.success": "⏸️ *Подписка #%d приостановлена*\n\n" +
+		"VPN-соединение отключено, срок подписки не расходуется.\n" +
+		"Пауза может длиться не более %d дней, после чего подписка возобновится автоматически: %s.",
+	"resume.not_paused":         "Подписка не приостановлена.",
+	"resume.server_unavailable": "❌ Не удалось возобновить подписку: сервер VPN недоступен. Пожалуйста, попробуйте позже.",
+	"resume.error":              "Ошибка при возобновлении подписки. Пожалуйста, обратитесь в поддержку.",
+	"resume.success": "▶️ *Подписка #%d возобновлена*\n\n" +
+		"Срок действия продлен на время паузы.\n" +
+		"Новая дата окончания: %s",
+
+	// Продление
+	"renew.not_allowed":      "Эту подписку нельзя продлить. Оформите новую подписку с помощью команды /buy.",
+	"renew.trial":            "Пробный период нельзя продлить. Выберите платный план с помощью команды /buy.",
+	"renew.plan_error":       "Ошибка при получении информации о плане. Пожалуйста, попробуйте позже.",
+	"renew.plan_unavailable": "План этой подписки больше недоступен. Выберите новый план с помощью команды /buy.",
+	"renew.invoice_title":    "Продление подписки #%d",
+	"renew.invoice_desc":     "Продление подписки «%s» на %d дней с сохранением текущей конфигурации VPN",
+	"renew.invoice_label":    "Продление «%s»",
+	"renew.failed":           "Не удалось продлить подписку.",
+	"renew.unblock_failed": "✅ Подписка #%d продлена до %s, но восстановить доступ автоматически не удалось. " +
+		"Пожалуйста, обратитесь в поддержку.",
+	"renew.success": "✅ *Подписка продлена!*\n\n" +
+		"Подписка: #%d\n" +
+		"План: %s\n" +
+		"Дата окончания: %s\n\n" +
+		"Текущая конфигурация VPN продолжает работать, переустанавливать ее не нужно.",
+
+	// Уведомления планировщика
+	"notify.resumed": "▶️ *Подписка #%d возобновлена*\n\n" +
+		"Максимальный срок паузы истек, VPN-соединение снова доступно.\n" +
+		"Дата окончания подписки: %s",
+	"notify.trial_ended": "⌛️ *Пробный период завершен*\n\n" +
+		"Ваше VPN-соединение было автоматически отключено.\n\n" +
+		"Надеемся, вам понравилось! Чтобы продолжить пользоваться VPN без ограничений, " +
+		"выберите подходящий тарифный план.",
+	"notify.expired": "❗️ *Срок действия вашей подписки истек* ❗️\n\n" +
+		"Подписка: #%d\n" +
+		"План: %s\n" +
+		"Дата начала: %s\n" +
+		"Дата окончания: %s\n\n" +
+		"Ваше VPN-соединение было автоматически отключено.\n" +
+		"Для продолжения использования VPN, пожалуйста, оформите новую подписку с помощью команды /buy.",
+	"notify.grace": "⌛ *Срок действия подписки #%d истек*\n\n" +
+		"VPN-соединение приостановлено, но ваша конфигурация сохранена.\n" +
+		"Продлите подписку до %s, и доступ восстановится сразу, без переустановки конфигурации.",
+	"notify.trial_expiring": "⏳ *Пробный период скоро закончится*\n\n" +
+		"Дата окончания: %s\n" +
+		"Осталось: *%s*\n\n" +
+		"Оформите платную подписку, чтобы VPN продолжил работать без перерыва.",
+	"notify.expiring": "⚠️ *Внимание! Ваша подписка скоро истечет* ⚠️\n\n" +
+		"Подписка: #%d\n" +
+		"План: %s\n" +
+		"Дата окончания: %s\n\n" +
+		"Осталось: *%s*\n\n" +
+		"Чтобы продлить подписку с сохранением текущей конфигурации, нажмите кнопку ниже.\n" +
+		"Если не продлить подписку, ваше VPN-соединение будет автоматически отключено по истечении срока.",
+
+	"time.days":           "%d дн.",
+	"time.hours":          "%d ч.",
+	"time.less_than_hour": "менее часа",
+
+	"notify.admin_report":        "📊 *Отчет о истекших подписках*\n\nОбнаружено и обработано истекших подписок: %d\n\n*Список обработанных подписок:*\n",
+	"notify.admin_report_line":   "%d. Подписка #%d - Пользователь: %s - План: %s - Дата окончания: %s\n",
+	"notify.admin_report_footer": "\nВсе указанные подписки были автоматически помечены как истекшие, и соответствующие VPN-конфигурации были отозваны.",
+
+	// Панель администратора
+	"admin.status_active":   "🟢 Активен",
+	"admin.status_inactive": "🔴 Неактивен",
+	"admin.active":          "Активен",
+	"admin.inactive":        "Неактивен",
+
+	// Серверы в панели администратора
+	"admin_servers.enter_port":          "Введите порт SSH:",
+	"admin_servers.invalid_port":        "Пожалуйста, введите корректный порт (число):",
+	"admin_servers.enter_user":          "Введите имя пользователя SSH:",
+	"admin_servers.enter_password":      "Введите пароль SSH:",
+	"admin_servers.enter_max_clients":   "Введите максимальное количество клиентов для сервера:",
+	"admin_servers.invalid_max_clients": "Пожалуйста, введите корректное число клиентов:",
+	"admin_servers.setting_up":          "Настраиваю сервер, это может занять некоторое время...",
+	"admin_servers.setup_error":         "Ошибка при настройке сервера: %v",
+	"admin_servers.add_error":           "Ошибка при добавлении сервера в базу данных: %v",
+	"admin_servers.added":               "Сервер успешно добавлен с ID: %d",
+
+	// Планы в панели администратора
+	"admin_plans.enter_description": "Введите описание плана подписки:",
+	"admin_plans.enter_price":       "Введите цену плана подписки в %s:",
+	"admin_plans.invalid_price":     "Пожалуйста, введите корректную цену:",
+	"admin_plans.enter_duration":    "Введите длительность плана в днях:",
+	"admin_plans.invalid_duration":  "Пожалуйста, введите корректную длительность (целое число дней):",
+	"admin_plans.add_error":         "Ошибка при добавлении плана подписки: %v",
+	"admin_plans.added":             "План подписки успешно добавлен: %s",
+	"admin_plans.edit_description":  "Введите новое описание плана (или отправьте точку '.' чтобы оставить текущее описание: \n\n%s)",
+	"admin_plans.edit_price":        "Введите новую цену плана в %s (или отправьте точку '.' чтобы оставить текущую цену: %s):",
+	"admin_plans.edit_duration":     "Введите новую длительность плана в днях (или отправьте точку '.' чтобы оставить текущую длительность: %s дней):",
+	"admin_plans.keep_status":       "Оставить текущий статус",
+	"admin_plans.choose_status":     "Выберите статус плана (текущий статус: %s):",
+	"admin_plans.update_error":      "Ошибка при обновлении плана подписки: %v",
+	"admin_plans.updated":           "✅ План подписки успешно обновлен: %s",
+	"admin_plans.saving":            "Статус плана: %s\n\nСохраняю изменения...",
+
+	// Статистика в панели администратора
+	"admin.back":                 "Назад",
+	"admin.unknown_action":       "Неизвестное действие. Пожалуйста, выберите действие из меню.",
+	"admin_stats.menu":           "Меню статистики. Выберите действие:",
+	"admin_stats.overview":       "Общая статистика",
+	"admin_stats.revenue":        "Статистика доходов",
+	"admin_stats.servers":        "Статистика серверов",
+	"admin_stats.trial":          "Пробный период",
+	"admin_stats.cohorts":        "Когорты и удержание",
+	"admin_stats.system_error":   "Ошибка при получении статистики системы: %v",
+	"admin_stats.system":         "📊 *Общая статистика системы*\n\n👥 *Пользователи:*\n- Всего пользователей: %d\n- Новые пользователи (7 дней): %d\n\n🔑 *Подписки:*\n- Активных подписок: %d\n- Новые подписки (7 дней): %d\n\n💰 *Доходы:*\n- Общий доход: %s\n- Доход за 30 дней: %s\n\n🖥 *Серверы:*\n- Активных серверов: %d\n- Подключено клиентов: %d\n- Общая вместимость: %d\n- Загрузка серверов: %.1f%%",
+	"admin_stats.servers_header": "📊 *Статистика серверов*\n\n",
+	"admin_stats.server":         "🖥 *Сервер #%d* %s\n- IP: `%s`\n- Клиенты: %d/%d (%.1f%%)\n\n",
+	"admin_servers.list_error":   "Ошибка при получении списка серверов: %v",
+	"admin_servers.not_found":    "Серверы не найдены.",
+
+	// Меню администратора, списки серверов и планов
+	"admin.menu":                "🔧 *Меню администратора*\n\nВаша роль: %s\nВыберите действие:",
+	"admin.menu_servers":        "🖥️ Управление серверами",
+	"admin.menu_plans":          "📑 Управление планами",
+	"admin.menu_users":          "👥 Управление пользователями",
+	"admin.menu_stats":          "📊 Статистика",
+	"admin.menu_broadcast":      "📢 Рассылка",
+	"admin.menu_roles":          "🛡 Роли сотрудников",
+	"admin.menu_audit":          "📜 Журнал действий",
+	"admin.back_button":         "🔙 Назад",
+	"admin.edit":                "📝 Редактировать",
+	"admin.details":             "🔍 Детали",
+	"admin.delete":              "❌ Удалить",
+	"admin.yes":                 "да",
+	"admin.no":                  "нет",
+	"admin_servers.add":         "➕ Добавить сервер",
+	"admin_servers.empty":       "Серверы не найдены. Добавьте новый сервер.",
+	"admin_servers.list_header": "*Список серверов*\n\nВыберите сервер для управления:",
+	"admin_servers.list_item":   "*Сервер #%d*\nIP: `%s:%d`\nКлиенты: %d / %d\nСтатус: %s",
+	"admin_servers.actions":     "Действия с серверами:",
+	"admin_plans.add":           "➕ Добавить план",
+	"admin_plans.empty":         "Планы подписки не найдены. Добавьте новый план.",
+	"admin_plans.list_header":   "*Список планов подписки*\n\nВыберите план для управления:",
+	"admin_plans.list_item":     "*%s*\n%s\nЦена: %s\nДлительность: %d дней\nСтатус: %s",
+	"admin_plans.trial_mark":    "\n🎁 Пробный план",
+	"admin_plans.actions":       "Действия с планами:",
+	"admin_plans.not_found":     "План подписки не найден.",
+	"admin_plans.unavailable":   "Недоступно",
+	"admin_plans.make_trial":    "🎁 Сделать пробным",
+	"admin_plans.make_paid":     "💳 Сделать платным",
+	"admin_plans.details":       "*Детали плана подписки*\n\n*ID:* `%d`\n*Название:* %s\n*Описание:* %s\n*Цена:* %s\n*Длительность:* %d дней\n*Статус:* %s\n*Пробный:* %s\n*Макс. пауза:* %d дней\n*Льготный период:* %d дней\n*Активных подписок:* %s\n*Всего подписок:* %s\n*Создан:* %s\n*Обновлен:* %s",
+	"admin_plans.max_pause":     "⏸️ Лимит паузы",
+	"admin_plans.grace_days":    "⌛ Льготный период",
+	"admin_plans.prices":        "💱 Цены в других валютах",
+	"admin_plans.back_to_list":  "🔙 К списку планов",
+
+	// Редактирование и удаление планов и серверов
+	"admin.cancel":                      "❌ Отмена",
+	"admin.confirm_delete":              "✅ Да, удалить",
+	"admin_plans.edit_start":            "📝 *Редактирование плана подписки*\n\n*Текущие данные:*\n*Название:* %s\n*Описание:* %s\n*Цена:* %s\n*Длительность:* %d дней\n*Статус:* %s %s\n\nВведите новое название плана (или отправьте точку '.' чтобы оставить текущее название):",
+	"admin_plans.cancel_edit":           "❌ Отменить редактирование",
+	"admin_plans.confirm_delete":        "Вы действительно хотите удалить план *%s*?\n\n⚠️ Внимание: Это действие не повлияет на существующие подписки, но сделает план недоступным для покупки новым пользователям.",
+	"admin_plans.delete_error":          "Ошибка при удалении плана: %v",
+	"admin_plans.deleted":               "✅ План подписки успешно удален.",
+	"admin_plans.add_start":             "➕ *Добавление нового плана подписки*\n\nВведите название плана:",
+	"admin_plans.unknown_action":        "Неизвестное действие с планом.",
+	"admin_servers.not_found_id":        "Ошибка: не удалось найти сервер #%d",
+	"admin_servers.details":             "🖥️ *Информация о сервере #%d*\n\nIP: `%s`\nПорт: `%d`\nSSH пользователь: `%s`\nМаксимум клиентов: `%d`\nТекущих клиентов: `%d`\nСтатус: %s\nСоздан: `%s`\nОбновлен: `%s`\n",
+	"admin_servers.check":               "🔍 Проверить доступность",
+	"admin_servers.back_to_list":        "◀️ Назад к списку серверов",
+	"admin_servers.back_to_server":      "◀️ Назад к серверу",
+	"admin_servers.subscriptions_error": "Ошибка при проверке подписок сервера",
+	"admin_servers.has_subscriptions":   "❌ Невозможно удалить сервер #%d, так как на нем есть %d активных подписок.\n\nСначала переместите или отмените все подписки на этом сервере.",
+	"admin_servers.confirm_delete":      "❓ Вы действительно хотите удалить сервер #%d (%s)?\n\nЭто действие нельзя отменить.",
+	"admin_servers.unknown_action":      "Неизвестное действие '%s' для сервера #%d",
+	"admin_servers.add_start":           "🖥️ *Добавление нового сервера*\n\nВведите IP-адрес сервера:\n_(например, 123.45.67.89)_",
+	"admin_servers.deleting":            "🗑️ Удаление сервера #%d (%s)...",
+	"admin_servers.delete_error":        "❌ Ошибка при удалении сервера #%d: %v",
+	"admin_servers.deleted":             "✅ Сервер #%d (%s) успешно удален",
+
+	// Подписки и пользователи в панели администратора
+	"admin_subs.not_found":               "Ошибка: не удалось найти подписку #%d",
+	"admin_subs.user_not_found":          "Ошибка: не удалось найти пользователя подписки",
+	"admin_subs.plan_not_found":          "Ошибка: не удалось найти план подписки",
+	"admin_subs.processing":              "⏳ Выполняется операция с подпиской #%d пользователя %s...",
+	"admin_subs.block_error":             "❌ Ошибка при блокировке подписки #%d: не удалось подключиться к серверу VPN.\n\nВозможно, сервер временно недоступен. Пожалуйста, повторите попытку позже.",
+	"admin_subs.blocked":                 "✅ Подписка #%d пользователя %s успешно заблокирована",
+	"admin_subs.block_timeout":           "⚠️ Превышено время ожидания при попытке заблокировать подписку #%d.\n\nСервер VPN не отвечает. Попробуйте повторить операцию позже.",
+	"admin_subs.unblock_error":           "❌ Ошибка при разблокировке подписки #%d: не удалось подключиться к серверу VPN.\n\nВозможно, сервер временно недоступен. Пожалуйста, повторите попытку позже.",
+	"admin_subs.unblocked":               "✅ Подписка #%d пользователя %s успешно разблокирована",
+	"admin_subs.unblock_timeout":         "⚠️ Превышено время ожидания при попытке разблокировать подписку #%d.\n\nСервер VPN не отвечает. Попробуйте повторить операцию позже.",
+	"admin_subs.revoke_invalid":          "❌ Подписку #%d в статусе %s нельзя отозвать",
+	"admin_subs.revoke_peer_unavailable": "❌ Подписка #%d пользователя %s не отозвана: сервер VPN недоступен, и конфигурация клиента осталась на сервере. Статус подписки не изменился, повторите попытку позже.",
+	"admin_subs.revoke_timeout":          "⏳ Сервер VPN не ответил вовремя на отзыв подписки #%d пользователя %s. Команда продолжает выполняться: подписка будет помечена отозванной, как только сервер удалит конфигурацию клиента.",
+	"admin_subs.status_error":            "❌ Ошибка при обновлении статуса подписки #%d",
+	"admin_subs.revoked":                 "✅ Подписка #%d пользователя %s успешно отозвана",
+	"admin_subs.unknown_action":          "Неизвестное действие '%s' для подписки #%d",
+	"admin_users.not_found":              "Ошибка: не удалось найти пользователя #%d",
+	"admin_users.subscriptions_error":    "Ошибка при получении подписок пользователя",
+	"admin_users.no_subscriptions":       "У пользователя %s нет подписок",
+	"admin_users.subscriptions_header":   "📋 Подписки пользователя %s:\n\n",
+	"admin_users.block_status_unknown":   " [статус блокировки: неизвестен]",
+	"admin_users.subscription_item":      "%d. #%d - %s %s%s\n   План: %s\n   Дата окончания: %s\n\n",
+	"admin_users.block_subscription":     "🔒 Заблокировать #%d",
+	"admin_users.unblock_subscription":   "🔓 Разблокировать #%d",
+	"admin_users.delete_subscription":    "❌ Удалить #%d",
+	"admin_users.unknown_action":         "Неизвестное действие для пользователя #%d",
+
+	// Проверка доступности сервера
+	"admin_check.started":           "🔄 Проверка доступности сервера...",
+	"admin_check.header":            "🔍 Проверка доступности сервера:\n\n",
+	"admin_check.not_found":         "❌ Ошибка: сервер не найден в базе данных",
+	"admin_check.server":            "🖥️ Сервер: %s (ID: %d)\n",
+	"admin_check.tcp":               "🔄 Проверка TCP-соединения...\n",
+	"admin_check.tcp_error":         "❌ TCP-соединение: Ошибка - %v\n",
+	"admin_check.tcp_ok":            "✅ TCP-соединение: Установлено\n",
+	"admin_check.ssh":               "🔄 Проверка SSH-соединения...\n",
+	"admin_check.ssh_error":         "❌ SSH-соединение: Ошибка - %v\n",
+	"admin_check.ssh_ok":            "✅ SSH-соединение: Установлено\n",
+	"admin_check.wireguard":         "🔄 Проверка Wireguard...\n",
+	"admin_check.session_error":     "❌ Создание SSH-сессии: Ошибка - %v\n",
+	"admin_check.wireguard_missing": "❌ Wireguard: Не установлен\n",
+	"admin_check.wireguard_ok":      "✅ Wireguard: Установлен\n",
+	"admin_check.config":            "🔄 Проверка конфигурации Wireguard...\n",
+	"admin_check.config_missing":    "❌ Конфигурация Wireguard: Не найдена\n",
+	"admin_check.config_ok":         "✅ Конфигурация Wireguard: Найдена\n",
+	"admin_check.peers":             "👥 Активных клиентов: %s\n",
+	"admin_check.done":              "\n✅ Проверка завершена успешно!\n⏱️ Время: %s",
+	"admin_check.retry":             "🔄 Повторить проверку",
+
+	// Статистика доходов
+	"revenue.page_summary":      "Сводка",
+	"revenue.page_plans":        "По планам",
+	"revenue.page_days":         "По дням",
+	"revenue.page_weeks":        "По неделям",
+	"revenue.page_months":       "По месяцам",
+	"revenue.no_payments":       "Поступлений за период нет.\n",
+	"revenue.week_of":           "неделя с %s",
+	"revenue.hidden_rows":       "…и еще %d строк в CSV-выгрузке\n",
+	"revenue.header":            "💰 *Статистика доходов за %d дней* (%s)\n\n",
+	"revenue.stats_error":       "Ошибка при получении статистики доходов: %v",
+	"revenue.currency_summary":  "*%s*\n*Поступления:* %s (%d платежей)\n*Покупки планов:* %d на сумму %s\n*Средний чек:* %s\n*MRR (оценка):* %s\n\n",
+	"revenue.summary":           "*Активных платных подписок:* %d\n\n*Продлений:* %d\n*Истекло подписок:* %d\n*Доля продлений:* %.1f%%\n*Отток:* %.1f%%",
+	"revenue.plans_error":       "Ошибка при получении продаж по планам: %v",
+	"revenue.no_orders":         "Покупок за период нет.",
+	"revenue.plan_row":          "*%s:* %d покупок, %s\n",
+	"revenue.payments_error":    "Ошибка при получении поступлений: %v",
+	"revenue.period":            "%d дн.",
+	"revenue.export_csv":        "📥 Выгрузить CSV",
+	"revenue.export_error":      "Ошибка при формировании выгрузки.",
+	"revenue.export_caption":    "Статистика доходов за %d дней",
+	"revenue.export_send_error": "Ошибка при отправке выгрузки: %v",
+
+	// Когорты и удержание
+	"cohorts.error":          "Ошибка при построении когортного отчета: %v",
+	"cohorts.header":         "📅 *Когорты по неделям регистрации (%d нед.)*\n\n",
+	"cohorts.empty":          "Нет пользователей за этот период.",
+	"cohorts.legend":         "M1-M3 - доля пользователей с платной подпиской через 1-3 месяца после регистрации, LTV - накопленная выручка на пользователя в каждой валюте.\n\n",
+	"cohorts.export_caption": "Когортный отчет по удержанию и LTV",
+	"cohorts.column_week":    "Неделя",
+	"cohorts.column_users":   "Польз",
+
+	// Журнал действий сотрудников
+	"audit.error":                       "Ошибка при получении журнала действий: %v",
+	"audit.header":                      "📜 Журнал действий сотрудников (страница %d из %d, всего записей: %d)\n\n",
+	"audit.empty":                       "Записей пока нет.",
+	"audit.before":                      "До: %s\n",
+	"audit.after":                       "После: %s\n",
+	"audit.newer":                       "◀️ Новее",
+	"audit.older":                       "Старее ▶️",
+	"audit.export_caption":              "Журнал действий сотрудников (%d записей)",
+	"audit.action_server_add":           "Добавление сервера",
+	"audit.action_server_edit":          "Изменение сервера",
+	"audit.action_server_reissue":       "Перевыпуск конфигураций",
+	"audit.action_server_delete":        "Удаление сервера",
+	"audit.action_plan_add":             "Создание плана",
+	"audit.action_plan_edit":            "Изменение плана",
+	"audit.action_plan_delete":          "Удаление плана",
+	"audit.action_subscription_block":   "Блокировка подписки",
+	"audit.action_subscription_unblock": "Разблокировка подписки",
+	"audit.action_subscription_revoke":  "Отзыв подписки",
+	"audit.action_role_change":          "Изменение роли",
+	"audit.action_balance_change":       "Изменение баланса",
+	"audit.action_broadcast":            "Запуск рассылки",
+	"audit.target_server":               "сервер",
+	"audit.target_plan":                 "план",
+	"audit.target_subscription":         "подписка",
+	"audit.target_user":                 "пользователь",
+	"audit.target_broadcast":            "рассылка",
+
+	// Список и карточка пользователей
+	"users.filter_all":                "Все",
+	"users.filter_active":             "Активные",
+	"users.filter_expired":            "Истекшие",
+	"users.filter_staff":              "Сотрудники",
+	"users.filter_new":                "Новые за неделю",
+	"users.sort_newest":               "Новые",
+	"users.sort_payments":             "Платежи",
+	"users.sort_name":                 "Имя",
+	"users.list_line":                 "%s\n   активных подписок: %d, платежи: %s, с %s",
+	"users.list_error":                "Ошибка при получении списка пользователей: %v",
+	"users.list_header":               "👥 Пользователи: %d\nФильтр: %s, сортировка: %s\nСтраница %d из %d\n\n",
+	"users.not_found":                 "Пользователи не найдены.",
+	"users.search":                    "🔎 Поиск",
+	"users.search_prompt":             "🔎 Введите имя пользователя, Telegram ID или ID подписки:",
+	"users.search_empty":              "Строка поиска не может быть пустой. Введите имя пользователя, Telegram ID или ID подписки:",
+	"users.search_error":              "Ошибка при поиске пользователей: %v",
+	"users.search_header":             "🔎 Результаты поиска «%s»: %d\n\n",
+	"users.search_truncated":          "\nПоказаны первые %d, уточните запрос.",
+	"users.search_again":              "🔎 Искать снова",
+	"users.all":                       "👥 Все пользователи",
+	"users.stats_error":               "Ошибка при получении статистики пользователя: %v",
+	"users.card_header":               "👤 Пользователь #%d\n\n",
+	"users.card_name":                 "Имя: %s\n",
+	"users.card_role":                 "Роль: %s\n",
+	"users.card_language":             "Язык: %s\n",
+	"users.card_registered":           "Дата регистрации: %s\n",
+	"users.card_balance":              "Баланс: %s\n",
+	"users.card_subscriptions":        "Подписок: %d (активных: %d)\n",
+	"users.card_data_usage":           "Использовано данных: %.2f GB\n",
+	"users.card_payments_total":       "Сумма платежей: %s\n",
+	"users.card_subscriptions_header": "\n📋 Подписки:\n",
+	"users.card_none":                 "нет\n",
+	"users.card_more":                 "…и еще %d\n",
+	"users.card_plan_id":              "план #%d",
+	"users.card_subscription":         "#%d %s — %s, до %s\n",
+	"users.card_payments_header":      "\n💳 Последние платежи:\n",
+	"users.subscriptions":             "🔍 Подписки",
+	"users.balance":                   "💼 Баланс",
+	"users.role":                      "🛡 Роль",
+	"users.back_to_list":              "🔙 К списку",
+
+	// Редактирование сервера
+	"server_edit.prompt_ip":                 "Введите новый IP-адрес или доменное имя сервера (текущее значение: `%s`):",
+	"server_edit.prompt_port":               "Введите новый SSH-порт сервера (текущее значение: `%s`):",
+	"server_edit.prompt_user":               "Введите нового SSH-пользователя (текущее значение: `%s`):",
+	"server_edit.prompt_password":           "Введите новый SSH-пароль (текущее значение: `%s`).\nСообщение с паролем будет удалено из чата.",
+	"server_edit.prompt_max_clients":        "Введите максимальное количество клиентов (текущее значение: `%s`):",
+	"server_edit.cancel_hint":               "\n\nДля отмены отправьте /cancel",
+	"server_edit.invalid_ip":                "введите корректный IP-адрес или доменное имя",
+	"server_edit.invalid_port":              "порт должен быть числом от 1 до 65535",
+	"server_edit.invalid_user":              "имя пользователя не может быть пустым или содержать пробелы",
+	"server_edit.invalid_password":          "пароль не может быть пустым",
+	"server_edit.invalid_max_clients":       "введите целое число больше нуля",
+	"server_edit.max_clients_below_current": "значение не может быть меньше текущего количества клиентов (%d)",
+	"server_edit.unknown_field_name":        "неизвестный параметр сервера: %s",
+	"server_edit.invalid_value":             "❌ %s. Попробуйте еще раз или отправьте /cancel:",
+	"server_edit.menu":                      "📝 *Редактирование сервера #%d*\n\nВыберите, что хотите изменить:\n\n1. IP: `%s`\n2. Порт: `%d`\n3. SSH пользователь: `%s`\n4. SSH пароль: `%s`\n5. Максимум клиентов: `%d`\n6. Статус: %s\n\nПеред сохранением IP, порта, пользователя или пароля выполняется проверка SSH-подключения.",
+	"server_edit.button_port":               "2️⃣ Порт",
+	"server_edit.button_user":               "3️⃣ SSH пользователь",
+	"server_edit.button_password":           "4️⃣ SSH пароль",
+	"server_edit.button_max_clients":        "5️⃣ Макс. клиентов",
+	"server_edit.button_status":             "6️⃣ Статус",
+	"server_edit.status_error":              "Ошибка при изменении статуса сервера: %v",
+	"server_edit.status_changed":            "✅ Статус сервера #%d: %s",
+	"server_edit.unknown_field":             "Неизвестный параметр сервера.",
+	"server_edit.checking":                  "🔄 Проверяю SSH-подключение с новыми параметрами...",
+	"server_edit.check_failed":              "❌ Не удалось подключиться к серверу с новыми параметрами: %v\n\nИзменения не сохранены.",
+	"server_edit.save_error":                "Ошибка при сохранении сервера: %v",
+	"server_edit.saved":                     "✅ Сервер #%d обновлен",
+	"server_edit.subscriptions_error":       "Ошибка при получении подписок сервера: %v",
+	"server_edit.reissue_not_needed":        "На сервере нет действующих подписок, перевыпуск конфигураций не требуется.",
+	"server_edit.reissue":                   "🔄 Перевыпустить (%d)",
+	"server_edit.reissue_later":             "Не сейчас",
+	"server_edit.reissue_offer":             "Адрес сервера изменился на %s. У %d подписок в конфигурации указан старый адрес.\n\nПеревыпустить конфигурации с новым адресом и отправить их подписчикам?",
+	"server_edit.reissuing":                 "⏳ Перевыпускаю конфигурации для %d подписок...",
+	"server_edit.reissued":                  "✅ Конфигурации перевыпущены: %d",
+	"server_edit.reissue_failed":            "\n❌ Ошибок: %d (подробности в логах)",
+
+	// Рассылки
+	"broadcast.start":                  "📢 *Новая рассылка*\n\nОтправьте сообщение для рассылки: текст с форматированием Telegram (жирный, курсив, ссылки) или фото с подписью. Сообщение будет скопировано получателям без изменений.",
+	"broadcast.unsupported_message":    "Поддерживаются только текст и фото. Отправьте сообщение для рассылки:",
+	"broadcast.buttons_prompt":         "Добавьте кнопки-ссылки под сообщением: по одной на строке в формате\nТекст кнопки - https://example.com\n\nОтправьте «-», если кнопки не нужны.",
+	"broadcast.invalid_button_line":    "строка «%s» не в формате «Текст - URL»",
+	"broadcast.too_many_buttons":       "слишком много кнопок, максимум %d",
+	"broadcast.buttons_error":          "Ошибка: %s\n\nОтправьте кнопки еще раз или «-», если кнопки не нужны:",
+	"broadcast.buttons_save_error":     "Ошибка при сохранении кнопок.",
+	"broadcast.buttons_read_error":     "Не удалось прочитать кнопки рассылки. Начните рассылку заново.",
+	"broadcast.choose_segment":         "👥 Кому отправить рассылку?",
+	"broadcast.segment_all_button":     "Всем пользователям",
+	"broadcast.segment_active_button":  "Активным подписчикам",
+	"broadcast.segment_expired_button": "Подписка истекла за N дней",
+	"broadcast.segment_plan_button":    "Подписчикам плана",
+	"broadcast.segment_server_button":  "Подписчикам сервера",
+	"broadcast.not_found":              "Рассылка не найдена или устарела. Начните заново из меню администратора.",
+	"broadcast.days_prompt":            "Введите количество дней: рассылка уйдет пользователям, у которых подписка закончилась за это время и не была продлена.",
+	"broadcast.choose_plan":            "Выберите план: рассылка уйдет пользователям с активной подпиской на него.",
+	"broadcast.choose_server":          "Выберите сервер: рассылка уйдет пользователям с активной подпиской на нем.",
+	"broadcast.unknown_segment":        "Неизвестный сегмент получателей.",
+	"broadcast.invalid_days":           "Пожалуйста, введите положительное число дней:",
+	"broadcast.segment_all":            "все пользователи",
+	"broadcast.segment_active":         "активные подписчики",
+	"broadcast.segment_expired":        "подписка истекла за последние %d дн.",
+	"broadcast.segment_plan":           "подписчики плана «%s»",
+	"broadcast.segment_plan_id":        "подписчики плана #%d",
+	"broadcast.segment_server":         "подписчики сервера #%d %s",
+	"broadcast.segment_server_id":      "подписчики сервера #%d",
+	"broadcast.audience_error":         "Ошибка при получении получателей: %v",
+	"broadcast.preview_error":          "Не удалось показать предпросмотр: %v\nНачните рассылку заново.",
+	"broadcast.preview":                "👆 Так сообщение увидят получатели.\n\nПолучатели: %s\nКоличество: %d\n\nОтправить рассылку?",
+	"broadcast.send":                   "✅ Отправить",
+	"broadcast.choose_segment_first":   "Сначала выберите получателей рассылки.",
+	"broadcast.no_audience":            "Получателей нет, рассылка не отправлена.",
+	"broadcast.save_error":             "Ошибка при сохранении рассылки.",
+	"broadcast.started":                "🚀 Рассылка #%d запущена: %d получателей, примерно %s. Когда она завершится, я пришлю статистику.",
+	"broadcast.cancelled":              "Рассылка отменена.",
+	"broadcast.finished":               "📢 Рассылка #%d завершена за %s\n\nПолучателей: %d\n✅ Доставлено: %d\n🚫 Заблокировали бота: %d\n❌ Ошибок: %d",
+	"broadcast.resumed":                "▶️ Рассылка #%d продолжена после перезапуска бота: осталось получателей - %d.",
+	"broadcast.crashed":                "⚠️ Рассылка #%d прервана аварийной остановкой бота и не будет продолжена, чтобы никому не отправить сообщение дважды.\n\nПолучателей: %d\n✅ Доставлено: %d\n🚫 Заблокировали бота: %d\n❌ Ошибок: %d",
+	"broadcast.buttons_corrupted":      "⚠️ Рассылка #%d не отправлена: не удалось прочитать ее кнопки.",
+
+	// Обращения в поддержку: сообщения сотрудникам
+	"support_staff.history_button":         "📜 История",
+	"support_staff.close_button":           "✅ Закрыть",
+	"support_staff.ticket":                 "🆘 Обращение #%d\n\n",
+	"support_staff.user":                   "Пользователь: %s (Telegram ID %d, #%d)\n",
+	"support_staff.ticket_subscription":    "Подписка обращения: #%d\n",
+	"support_staff.no_ticket_subscription": "Подписка обращения: не указана\n",
+	"support_staff.no_subscriptions":       "\nПодписок нет.\n",
+	"support_staff.subscriptions":          "\nПодписки:\n",
+	"support_staff.plan_id":                "план #%d",
+	"support_staff.subscription":           "• #%d %s — %s до %s\n",
+	"support_staff.server_not_found":       "  Сервер #%d не найден\n",
+	"support_staff.server":                 "  Сервер #%d %s\n",
+	"support_staff.handshake_error":        "  Последнее рукопожатие: не удалось получить\n",
+	"support_staff.handshake_never":        "  Последнее рукопожатие: не было\n",
+	"support_staff.handshake":              "  Последнее рукопожатие: %s (%s назад)\n",
+	"support_staff.reply_hint":             "\nОтветьте на любое сообщение обращения, чтобы ответ получил пользователь.",
+	"support_staff.message_from":           "💬 #%d от %s:",
+	"support_staff.closed_not_sent":        "Обращение #%d закрыто, ответ не отправлен.",
+	"support_staff.text_or_photo":          "Пользователю можно отправить только текст или фото.",
+	"support_staff.author_not_found":       "Ошибка: не удалось найти автора обращения #%d",
+	"support_staff.reply_error":            "❌ Не удалось отправить ответ на обращение #%d: %v",
+	"support_staff.reply_sent":             "✅ Ответ по обращению #%d отправлен",
+	"support_staff.not_found":              "Ошибка: не удалось найти обращение #%d",
+	"support_staff.already_closed":         "Обращение #%d уже закрыто.",
+	"support_staff.close_error":            "Ошибка при закрытии обращения.",
+	"support_staff.closed":                 "✅ Обращение #%d закрыто (%s)",
+	"support_staff.closed_by_user":         "✅ %s закрыл(а) обращение #%d",
+	"support_staff.history_error":          "Ошибка при получении истории обращения: %v",
+	"support_staff.history":                "📜 История обращения #%d (%s, создано %s)\n\n",
+	"support_staff.history_truncated":      "Показаны последние %d из %d сообщений.\n\n",
+	"support_staff.author_user":            "Пользователь",
+	"support_staff.author_staff":           "Поддержка",
+	"support_staff.photo":                  " 📎 фото",
+
+	// Цены плана в дополнительных валютах
+	"plan_prices.title":            "💱 *Цены плана %s*\n\n",
+	"plan_prices.base":             "Основная цена: %s\n",
+	"plan_prices.not_set":          "не задана",
+	"plan_prices.edit_button":      "✏️ Цена в %s",
+	"plan_prices.note":             "\nСчета за подписки выставляются в валюте из настроек платежей, если для плана задана цена в ней, иначе в основной валюте. Оплата с баланса и смена плана доступны при цене в рублях.",
+	"plan_prices.back":             "🔙 К плану",
+	"plan_prices.unknown_currency": "Неизвестная валюта цены плана.",
+	"plan_prices.prompt":           "💱 Текущая цена плана *%s* в %s: %s.\n\nВведите новую цену в %s или отправьте '-', чтобы убрать цену в этой валюте:",
+	"plan_prices.invalid_amount":   "Пожалуйста, введите положительную цену в %s или '-':",
+	"plan_prices.save_error":       "Ошибка при изменении цены плана: %v",
+
+	// Роли сотрудников
+	"roles.name_owner":    "👑 Владелец",
+	"roles.name_admin":    "🛠 Администратор",
+	"roles.name_support":  "🎧 Поддержка",
+	"roles.name_finance":  "💰 Финансы",
+	"roles.name_user":     "Пользователь",
+	"roles.list_error":    "Ошибка при получении списка сотрудников: %v",
+	"roles.title":         "🛡 Сотрудники и роли\n\n",
+	"roles.empty":         "Сотрудников пока нет.\n",
+	"roles.hint":          "\nЧтобы назначить или снять роль, откройте пользователя в разделе «Пользователи» и нажмите «🛡 Роль».\n\n",
+	"roles.permissions":   "Права ролей:\n• Владелец — все разделы и назначение ролей\n• Администратор — все разделы, кроме назначения ролей\n• Поддержка — пользователи и их подписки, статистика\n• Финансы — платежи, аналитика, корректировки баланса, статистика",
+	"roles.remove_button": "❌ Снять роль",
+	"roles.choose":        "🛡 Роль пользователя %s\n\nТекущая роль: %s\nВыберите новую роль:",
+	"roles.unknown":       "Неизвестная роль.",
+	"roles.own_role":      "Нельзя изменить собственную роль.",
+	"roles.save_error":    "Ошибка при изменении роли пользователя.",
+	"roles.changed":       "✅ Роль пользователя %s: %s",
+
+	// Лимиты плана в днях
+	"plan_limits.max_pause_prompt": "⏸️ Текущий лимит паузы для плана *%s*: %d дней.\n\nВведите максимальную длительность паузы в днях (0 - пауза недоступна):",
+	"plan_limits.grace_prompt":     "⌛ Текущий льготный период для плана *%s*: %d дней.\n\nВведите длительность льготного периода в днях. В это время пир заблокирован, но конфигурация сохраняется до продления (0 - отзывать сразу по окончании срока):",
+	"plan_limits.unknown":          "Неизвестный параметр плана.",
+	"plan_limits.invalid_days":     "Пожалуйста, введите целое число от 0 до %d:",
+	"plan_limits.save_error":       "Ошибка при изменении плана: %v",
+
+	// Статистика пробного периода
+	"admin_stats.trial_error": "Ошибка при получении статистики пробного периода: %v",
+	"admin_stats.trial_text":  "🎁 *Статистика пробного периода*\n\n- Активировали пробный период: %d\n- Действующих пробных подписок: %d\n- Оплатили после пробного периода: %d\n- Конверсия в платные подписки: %.1f%%",
+
+	// Баланс пользователя в панели администратора
+	"balance_admin.error":          "Ошибка при получении баланса: %v",
+	"balance_admin.text":           "💼 Баланс пользователя %s (#%d): %s\n\nПоследние операции:\n%s",
+	"balance_admin.adjust_button":  "✏️ Корректировка",
+	"balance_admin.bonus_button":   "🎁 Бонус",
+	"balance_admin.adjust_prompt":  "Введите сумму корректировки в рублях (например, 150 или -50):",
+	"balance_admin.bonus_prompt":   "Введите сумму бонуса в рублях:",
+	"balance_admin.start":          "💼 Изменение баланса пользователя %s (#%d)\n\n%s",
+	"balance_admin.invalid_amount": "Пожалуйста, введите корректную сумму (число, не равное нулю):",
+	"balance_admin.negative_bonus": "Сумма бонуса должна быть положительной:",
+	"balance_admin.reason_prompt":  "Укажите причину изменения баланса:",
+	"balance_admin.empty_reason":   "Причина не может быть пустой. Укажите причину изменения баланса:",
+	"balance_admin.save_error":     "❌ Ошибка при изменении баланса: %v",
+	"balance_admin.changed":        "✅ Баланс пользователя #%d изменен на %s. Текущий баланс: %s",
+
+	// Отклоненные нажатия кнопок: сообщение сотрудникам
+	"callback.rejected_report": "🚨 Отклонено нажатие кнопки\n\nПользователь: @%s (ID %d)\nПричина: %s\nДанные: %s",
+}
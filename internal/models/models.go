@@ -1,43 +1,78 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/ilokitv/botVPN/internal/money"
+)
 
 // Server представляет VPN-сервер
 type Server struct {
-	ID             int       `db:"id" json:"id"`
-	IP             string    `db:"ip" json:"ip"`
-	Port           int       `db:"port" json:"port"`
-	SSHUser        string    `db:"ssh_user" json:"ssh_user"`
-	SSHPassword    string    `db:"ssh_password" json:"-"`
-	MaxClients     int       `db:"max_clients" json:"max_clients"`
-	CurrentClients int       `db:"current_clients" json:"current_clients"`
-	IsActive       bool      `db:"is_active" json:"is_active"`
-	CreatedAt      time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+	ID             int        `db:"id" json:"id"`
+	IP             string     `db:"ip" json:"ip"`
+	Port           int        `db:"port" json:"port"`
+	SSHUser        string     `db:"ssh_user" json:"ssh_user"`
+	SSHPassword    string     `db:"ssh_password" json:"-"`
+	MaxClients     int        `db:"max_clients" json:"max_clients"`
+	CurrentClients int        `db:"current_clients" json:"current_clients"`
+	IsActive       bool       `db:"is_active" json:"is_active"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time  `db:"updated_at" json:"updated_at"`
+	DeletedAt      *time.Time `db:"deleted_at" json:"deleted_at"` // Когда сервер удален (запись остается для истории подписок)
 }
 
 // SubscriptionPlan представляет план подписки
 type SubscriptionPlan struct {
-	ID          int       `db:"id" json:"id"`
-	Name        string    `db:"name" json:"name"`
-	Description string    `db:"description" json:"description"`
-	Price       float64   `db:"price" json:"price"`
-	Duration    int       `db:"duration" json:"duration"` // Длительность в днях
-	IsActive    bool      `db:"is_active" json:"is_active"`
-	CreatedAt   time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	ID           int         `db:"id" json:"id"`
+	Name         string      `db:"name" json:"name"`
+	Description  string      `db:"description" json:"description"`
+	Price        int64       `db:"price" json:"price"`       // Основная цена в минимальных единицах валюты Currency
+	Currency     string      `db:"currency" json:"currency"` // Валюта основной цены
+	Duration     int         `db:"duration" json:"duration"` // Длительность в днях
+	IsActive     bool        `db:"is_active" json:"is_active"`
+	IsTrial      bool        `db:"is_trial" json:"is_trial"`             // Бесплатный пробный план
+	MaxPauseDays int         `db:"max_pause_days" json:"max_pause_days"` // Максимальная длительность паузы, 0 - пауза недоступна
+	GraceDays    int         `db:"grace_days" json:"grace_days"`         // Льготный период после окончания, когда пир заблокирован, но не удален
+	CreatedAt    time.Time   `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time   `db:"updated_at" json:"updated_at"`
+	Prices       []PlanPrice `db:"-" json:"prices"` // Цены плана в других валютах
+}
+
+// PlanPrice представляет цену плана подписки в дополнительной валюте
+type PlanPrice struct {
+	PlanID   int    `db:"plan_id" json:"plan_id"`
+	Currency string `db:"currency" json:"currency"`
+	Amount   int64  `db:"amount" json:"amount"` // В минимальных единицах валюты
+}
+
+// PriceIn возвращает цену плана в валюте currency: основную или одну из дополнительных
+func (p *SubscriptionPlan) PriceIn(currency string) (int64, bool) {
+	if p.Currency == currency {
+		return p.Price, true
+	}
+	for _, price := range p.Prices {
+		if price.Currency == currency {
+			return price.Amount, true
+		}
+	}
+	return 0, false
 }
 
 // User представляет пользователя бота
 type User struct {
-	ID         int       `db:"id" json:"id"`
-	TelegramID int64     `db:"telegram_id" json:"telegram_id"`
-	Username   string    `db:"username" json:"username"`
-	FirstName  string    `db:"first_name" json:"first_name"`
-	LastName   string    `db:"last_name" json:"last_name"`
-	IsAdmin    bool      `db:"is_admin" json:"is_admin"`
-	CreatedAt  time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+	ID           int        `db:"id" json:"id"`
+	TelegramID   int64      `db:"telegram_id" json:"telegram_id"`
+	Username     string     `db:"username" json:"username"`
+	FirstName    string     `db:"first_name" json:"first_name"`
+	LastName     string     `db:"last_name" json:"last_name"`
+	IsAdmin      bool       `db:"is_admin" json:"is_admin"`             // Есть ли у пользователя роль сотрудника (совпадает с Role != "")
+	Role         string     `db:"role" json:"role"`                     // Роль сотрудника (см. пакет roles), пусто - обычный пользователь
+	TrialUsedAt  *time.Time `db:"trial_used_at" json:"trial_used_at"`   // Когда был активирован пробный период
+	Language     string     `db:"language" json:"language"`             // Язык, выбранный пользователем (пусто - как в Telegram)
+	LanguageCode string     `db:"language_code" json:"language_code"`   // Язык из настроек Telegram
+	BotBlockedAt *time.Time `db:"bot_blocked_at" json:"bot_blocked_at"` // Когда рассылка обнаружила, что пользователь заблокировал бота
+	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time  `db:"updated_at" json:"updated_at"`
 }
 
 // Subscription представляет подписку пользователя
@@ -48,45 +83,331 @@ type Subscription struct {
 	PlanID           int        `db:"plan_id" json:"plan_id"`
 	StartDate        time.Time  `db:"start_date" json:"start_date"`
 	EndDate          time.Time  `db:"end_date" json:"end_date"`
-	Status           string     `db:"status" json:"status"` // active, expired, cancelled
+	Status           string     `db:"status" json:"status"` // active, paused, grace, expired, cancelled
 	ConfigFilePath   string     `db:"config_file_path" json:"-"`
 	DataUsage        int64      `db:"data_usage" json:"data_usage"` // Использование данных в байтах
 	LastConnectionAt *time.Time `db:"last_connection_at" json:"last_connection_at"`
+	IsTrial          bool       `db:"is_trial" json:"is_trial"`   // Подписка выдана по пробному периоду
+	PausedAt         *time.Time `db:"paused_at" json:"paused_at"` // Когда подписка была приостановлена
 	CreatedAt        time.Time  `db:"created_at" json:"created_at"`
 	UpdatedAt        time.Time  `db:"updated_at" json:"updated_at"`
 }
 
+// SubscriptionStatusChange представляет запись истории смены статуса подписки
+type SubscriptionStatusChange struct {
+	ID             int       `db:"id" json:"id"`
+	SubscriptionID int       `db:"subscription_id" json:"subscription_id"`
+	OldStatus      string    `db:"old_status" json:"old_status"`
+	NewStatus      string    `db:"new_status" json:"new_status"`
+	Reason         string    `db:"reason" json:"reason"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+}
+
 // Payment представляет платеж пользователя
 type Payment struct {
 	ID             int       `db:"id" json:"id"`
 	UserID         int       `db:"user_id" json:"user_id"`
-	SubscriptionID int       `db:"subscription_id" json:"subscription_id"`
-	Amount         float64   `db:"amount" json:"amount"`
-	PaymentMethod  string    `db:"payment_method" json:"payment_method"` // telegram_stars
+	SubscriptionID *int      `db:"subscription_id" json:"subscription_id"` // Пусто для подарочных покупок
+	Amount         int64     `db:"amount" json:"amount"`                   // В минимальных единицах валюты
+	Currency       string    `db:"currency" json:"currency"`               // RUB, USD, XTR
+	PaymentMethod  string    `db:"payment_method" json:"payment_method"`   // telegram_stars, telegram_provider, balance
 	PaymentID      string    `db:"payment_id" json:"payment_id"`
 	Status         string    `db:"status" json:"status"` // pending, completed, failed
 	CreatedAt      time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
 }
 
+// Способы оплаты
+const (
+	PaymentMethodTelegramStars    = "telegram_stars"    // Telegram Stars (XTR)
+	PaymentMethodTelegramProvider = "telegram_provider" // Платежный провайдер Telegram (RUB, USD)
+	PaymentMethodBalance          = "balance"           // Списание с баланса пользователя
+)
+
+// GiftCode представляет подарочный код на подписку
+type GiftCode struct {
+	ID               int        `db:"id" json:"id"`
+	Code             string     `db:"code" json:"code"`
+	PlanID           int        `db:"plan_id" json:"plan_id"`
+	BuyerUserID      int        `db:"buyer_user_id" json:"buyer_user_id"`
+	PaymentID        *int       `db:"payment_id" json:"payment_id"`
+	Status           string     `db:"status" json:"status"` // active, redeemed, expired
+	ExpiresAt        time.Time  `db:"expires_at" json:"expires_at"`
+	RedeemedByUserID *int       `db:"redeemed_by_user_id" json:"redeemed_by_user_id"`
+	SubscriptionID   *int       `db:"subscription_id" json:"subscription_id"`
+	RedeemedAt       *time.Time `db:"redeemed_at" json:"redeemed_at"`
+	CreatedAt        time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// Типы операций по балансу пользователя
+const (
+	LedgerTopUp      = "topup"      // Пополнение баланса
+	LedgerPurchase   = "purchase"   // Оплата подписки с баланса
+	LedgerRefund     = "refund"     // Возврат средств на баланс
+	LedgerBonus      = "bonus"      // Бонусное начисление
+	LedgerAdjustment = "adjustment" // Ручная корректировка администратором
+)
+
+// LedgerCurrency - валюта баланса пользователя: все суммы операций по балансу указаны в копейках
+const LedgerCurrency = money.RUB
+
+// LedgerTransaction представляет операцию по балансу пользователя.
+// Каждая операция состоит из двух проводок с нулевой суммой (двойная запись).
+type LedgerTransaction struct {
+	ID           int       `db:"id" json:"id"`
+	UserID       int       `db:"user_id" json:"user_id"`
+	Type         string    `db:"type" json:"type"`
+	Amount       int64     `db:"amount" json:"amount"` // Изменение баланса пользователя (со знаком)
+	BalanceAfter int64     `db:"balance_after" json:"balance_after"`
+	Description  string    `db:"description" json:"description"`
+	PaymentID    *int      `db:"payment_id" json:"payment_id"`       // Связанный платеж (для пополнений)
+	ActorUserID  *int      `db:"actor_user_id" json:"actor_user_id"` // Кто выполнил операцию (для ручных операций)
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+}
+
+// LedgerEntry представляет проводку по счету в рамках операции
+type LedgerEntry struct {
+	ID            int       `db:"id" json:"id"`
+	TransactionID int       `db:"transaction_id" json:"transaction_id"`
+	Account       string    `db:"account" json:"account"` // user:<id>, external, revenue, bonus, adjustment
+	Amount        int64     `db:"amount" json:"amount"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+}
+
+// ConversationState представляет сохраненное состояние диалога пользователя с ботом (например, шаг мастера)
+type ConversationState struct {
+	ChatID        int64     `db:"chat_id" json:"chat_id"`
+	State         string    `db:"state" json:"state"`
+	PreviousState string    `db:"previous_state" json:"previous_state"`
+	Data          string    `db:"data" json:"data"` // Данные шага в формате JSON
+	ExpiresAt     time.Time `db:"expires_at" json:"expires_at"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Действия сотрудников, которые записываются в журнал
+const (
+	AuditServerAdd           = "server_add"           // Добавление сервера
+	AuditServerEdit          = "server_edit"          // Изменение параметров сервера
+	AuditServerReissue       = "server_reissue"       // Перевыпуск конфигураций после смены адреса сервера
+	AuditServerDelete        = "server_delete"        // Удаление сервера
+	AuditPlanAdd             = "plan_add"             // Создание плана подписки
+	AuditPlanEdit            = "plan_edit"            // Изменение плана подписки
+	AuditPlanDelete          = "plan_delete"          // Удаление плана подписки
+	AuditSubscriptionBlock   = "subscription_block"   // Блокировка подписки
+	AuditSubscriptionUnblock = "subscription_unblock" // Разблокировка подписки
+	AuditSubscriptionRevoke  = "subscription_revoke"  // Отзыв подписки
+	AuditRoleChange          = "role_change"          // Назначение или снятие роли сотрудника
+	AuditBalanceChange       = "balance_change"       // Ручное начисление, возврат или корректировка баланса
+	AuditBroadcast           = "broadcast"            // Запуск рассылки пользователям
+)
+
+// Типы объектов, над которыми выполняются действия из журнала
+const (
+	AuditTargetServer       = "server"
+	AuditTargetPlan         = "plan"
+	AuditTargetSubscription = "subscription"
+	AuditTargetUser         = "user"
+	AuditTargetBroadcast    = "broadcast"
+)
+
+// AuditEntry представляет запись журнала действий сотрудников
+type AuditEntry struct {
+	ID              int       `db:"id" json:"id"`
+	ActorUserID     *int      `db:"actor_user_id" json:"actor_user_id"` // Сотрудник, выполнивший действие (если он есть в базе)
+	ActorTelegramID int64     `db:"actor_telegram_id" json:"actor_telegram_id"`
+	ActorUsername   string    `db:"actor_username" json:"actor_username"` // Заполняется только при чтении журнала
+	Action          string    `db:"action" json:"action"`
+	TargetType      string    `db:"target_type" json:"target_type"`
+	TargetID        int       `db:"target_id" json:"target_id"`
+	Before          string    `db:"before_value" json:"before_value"` // Значения до изменения в формате JSON
+	After           string    `db:"after_value" json:"after_value"`   // Значения после изменения в формате JSON
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+}
+
+// Сегменты получателей рассылки
+const (
+	BroadcastSegmentAll     = "all"     // Все пользователи
+	BroadcastSegmentActive  = "active"  // С активной подпиской
+	BroadcastSegmentExpired = "expired" // Подписка закончилась за последние N дней (N в SegmentValue)
+	BroadcastSegmentPlan    = "plan"    // С активной подпиской на план (ID плана в SegmentValue)
+	BroadcastSegmentServer  = "server"  // С активной подпиской на сервере (ID сервера в SegmentValue)
+)
+
+// Статусы рассылки
+const (
+	BroadcastRunning     = "running"
+	BroadcastInterrupted = "interrupted" // Остановлена вместе с ботом, продолжится после запуска
+	BroadcastFinished    = "finished"
+	BroadcastFailed      = "failed" // Прервана аварийно: неизвестно, кому из оставшихся получателей сообщение уже ушло
+)
+
+// Broadcast представляет рассылку сообщения пользователям
+type Broadcast struct {
+	ID              int        `db:"id" json:"id"`
+	CreatedBy       int64      `db:"created_by" json:"created_by"`               // Telegram ID сотрудника, запустившего рассылку
+	SourceChatID    int64      `db:"source_chat_id" json:"source_chat_id"`       // Чат, из которого копируется сообщение
+	SourceMessageID int        `db:"source_message_id" json:"source_message_id"` // Сообщение, которое копируется получателям
+	Buttons         string     `db:"buttons" json:"buttons"`                     // Кнопки-ссылки в формате JSON
+	Segment         string     `db:"segment" json:"segment"`
+	SegmentValue    int        `db:"segment_value" json:"segment_value"`
+	Status          string     `db:"status" json:"status"`
+	Total           int        `db:"total" json:"total"`
+	Sent            int        `db:"sent" json:"sent"`
+	Failed          int        `db:"failed" json:"failed"`
+	Blocked         int        `db:"blocked" json:"blocked"`           // Пользователи, заблокировавшие бота
+	LastUserID      int        `db:"last_user_id" json:"last_user_id"` // Последний обработанный получатель, с него рассылка продолжается после перезапуска
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+	FinishedAt      *time.Time `db:"finished_at" json:"finished_at"`
+}
+
+// BroadcastButton представляет кнопку-ссылку под сообщением рассылки
+type BroadcastButton struct {
+	Text string `json:"text"`
+	URL  string `json:"url"`
+}
+
+// Статусы обращений в поддержку
+const (
+	TicketOpen    = "open"    // Ожидает ответа поддержки
+	TicketPending = "pending" // Поддержка ответила, ожидается ответ пользователя
+	TicketClosed  = "closed"  // Обращение закрыто
+)
+
+// SupportTicket представляет обращение пользователя в поддержку
+type SupportTicket struct {
+	ID             int        `db:"id" json:"id"`
+	UserID         int        `db:"user_id" json:"user_id"`
+	SubscriptionID *int       `db:"subscription_id" json:"subscription_id"` // Подписка, к которой относится обращение
+	Status         string     `db:"status" json:"status"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time  `db:"updated_at" json:"updated_at"`
+	ClosedAt       *time.Time `db:"closed_at" json:"closed_at"`
+}
+
+// SupportMessage представляет сообщение в истории обращения
+type SupportMessage struct {
+	ID               int       `db:"id" json:"id"`
+	TicketID         int       `db:"ticket_id" json:"ticket_id"`
+	SenderTelegramID int64     `db:"sender_telegram_id" json:"sender_telegram_id"`
+	FromStaff        bool      `db:"from_staff" json:"from_staff"`
+	Text             string    `db:"text" json:"text"`                   // Текст или подпись к фото
+	PhotoFileID      string    `db:"photo_file_id" json:"photo_file_id"` // Скриншот (file_id Telegram)
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+}
+
+// FailedMessage представляет сообщение, которое не удалось доставить после всех попыток
+type FailedMessage struct {
+	ID        int       `db:"id" json:"id"`
+	ChatID    int64     `db:"chat_id" json:"chat_id"`
+	Method    string    `db:"method" json:"method"`         // Тип запроса, например tgbotapi.MessageConfig
+	Payload   string    `db:"payload" json:"payload"`       // Параметры запроса в JSON
+	ErrorCode int       `db:"error_code" json:"error_code"` // Код ошибки Telegram, 0 для сетевых ошибок
+	Error     string    `db:"error" json:"error"`
+	Attempts  int       `db:"attempts" json:"attempts"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
 // UserStats представляет статистику для конкретного пользователя
 type UserStats struct {
-	UserID                   int     `json:"user_id"`
-	SubscriptionsCount       int     `json:"subscriptions_count"`
-	ActiveSubscriptionsCount int     `json:"active_subscriptions_count"`
-	TotalPayments            float64 `json:"total_payments"`
-	TotalDataUsage           int64   `json:"total_data_usage"`
+	UserID                   int          `json:"user_id"`
+	SubscriptionsCount       int          `json:"subscriptions_count"`
+	ActiveSubscriptionsCount int          `json:"active_subscriptions_count"`
+	TotalPayments            money.Totals `json:"total_payments"`
+	TotalDataUsage           int64        `json:"total_data_usage"`
+}
+
+// Фильтры списка пользователей в панели управления
+const (
+	UserFilterAll     = "all"     // Все пользователи
+	UserFilterActive  = "active"  // С активной подпиской
+	UserFilterExpired = "expired" // С истекшими подписками и без активной
+	UserFilterStaff   = "staff"   // Сотрудники
+	UserFilterNew     = "new"     // Зарегистрированные за последнюю неделю
+)
+
+// Сортировки списка пользователей в панели управления
+const (
+	UserSortNewest   = "newest"   // Сначала новые
+	UserSortPayments = "payments" // По количеству платежей
+	UserSortName     = "name"     // По имени пользователя
+)
+
+// UserListQuery описывает страницу списка пользователей
+type UserListQuery struct {
+	Filter string
+	Sort   string
+	Search string // Имя пользователя, Telegram ID или ID подписки
+	Limit  int
+	Offset int
+}
+
+// UserListItem представляет пользователя в списке панели управления
+type UserListItem struct {
+	User
+	ActiveSubscriptions int          `db:"active_subscriptions" json:"active_subscriptions"`
+	PaymentsCount       int          `db:"payments_count" json:"payments_count"`
+	TotalPayments       money.Totals `db:"-" json:"total_payments"`
 }
 
 // SystemStats представляет общую статистику по системе
 type SystemStats struct {
-	TotalUsers            int     `json:"total_users"`
-	ActiveSubscriptions   int     `json:"active_subscriptions"`
-	TotalRevenue          float64 `json:"total_revenue"`
-	MonthlyRevenue        float64 `json:"monthly_revenue"`
-	TotalServers          int     `json:"total_servers"`
-	TotalClients          int     `json:"total_clients"`
-	TotalCapacity         int     `json:"total_capacity"`
-	NewUsers7Days         int     `json:"new_users_7days"`
-	NewSubscriptions7Days int     `json:"new_subscriptions_7days"`
+	TotalUsers            int          `json:"total_users"`
+	ActiveSubscriptions   int          `json:"active_subscriptions"`
+	TotalRevenue          money.Totals `json:"total_revenue"`
+	MonthlyRevenue        money.Totals `json:"monthly_revenue"`
+	TotalServers          int          `json:"total_servers"`
+	TotalClients          int          `json:"total_clients"`
+	TotalCapacity         int          `json:"total_capacity"`
+	NewUsers7Days         int          `json:"new_users_7days"`
+	NewSubscriptions7Days int          `json:"new_subscriptions_7days"`
+}
+
+// RevenueStats представляет сводные финансовые показатели за период
+type RevenueStats struct {
+	PeriodStart             time.Time         `json:"period_start"`
+	PeriodEnd               time.Time         `json:"period_end"`
+	Currencies              []CurrencyRevenue `json:"currencies"`                // Денежные показатели по валютам
+	ActivePaidSubscriptions int               `json:"active_paid_subscriptions"` // Активные платные подписки
+	Renewals                int               `json:"renewals"`                  // Продления за период
+	Expirations             int               `json:"expirations"`               // Истекшие платные подписки за период
+	RenewalRate             float64           `json:"renewal_rate"`              // Процент продлений среди подписок, дошедших до окончания
+	ChurnRate               float64           `json:"churn_rate"`                // Процент оттока от платных подписок на начало периода
+}
+
+// CurrencyRevenue представляет денежные показатели за период в одной валюте.
+// Суммы указаны в минимальных единицах валюты.
+type CurrencyRevenue struct {
+	Currency          string `json:"currency"`
+	Revenue           int64  `json:"revenue"`             // Поступления от платежей Telegram
+	PaymentsCount     int    `json:"payments_count"`      // Количество платежей Telegram
+	OrdersCount       int    `json:"orders_count"`        // Покупки планов, включая оплату с баланса
+	OrdersRevenue     int64  `json:"orders_revenue"`      // Сумма покупок планов
+	AverageOrderValue int64  `json:"average_order_value"` // Средний чек
+	MRR               int64  `json:"mrr"`                 // Оценка ежемесячной выручки по активным подпискам
+}
+
+// RevenuePoint представляет поступления за один интервал (день, неделю или месяц)
+type RevenuePoint struct {
+	Period   time.Time `db:"period" json:"period"`
+	Currency string    `db:"currency" json:"currency"`
+	Revenue  int64     `db:"revenue" json:"revenue"`
+	Payments int       `db:"payments" json:"payments"`
+}
+
+// PlanRevenue представляет продажи одного плана подписки
+type PlanRevenue struct {
+	PlanID   int    `db:"plan_id" json:"plan_id"`
+	PlanName string `db:"plan_name" json:"plan_name"`
+	Currency string `db:"currency" json:"currency"`
+	Orders   int    `db:"orders" json:"orders"`
+	Revenue  int64  `db:"revenue" json:"revenue"`
+}
+
+// TrialStats представляет статистику конверсии пробного периода в платные подписки
+type TrialStats struct {
+	TrialUsers     int     `json:"trial_users"`     // Пользователи, активировавшие пробный период
+	ActiveTrials   int     `json:"active_trials"`   // Действующие пробные подписки
+	ConvertedUsers int     `json:"converted_users"` // Пользователи, оплатившие подписку после пробного периода
+	ConversionRate float64 `json:"conversion_rate"` // Процент конверсии
 }
@@ -0,0 +1,204 @@
+// Package money содержит работу с денежными суммами.
+// Суммы хранятся целым числом минимальных единиц валюты (копейки, центы, звезды),
+// чтобы при расчетах не накапливались ошибки округления чисел с плавающей точкой.
+package money
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Поддерживаемые валюты (коды ISO 4217 и XTR для Telegram Stars)
+const (
+	RUB = "RUB" // Российский рубль, 1 рубль = 100 копеек
+	USD = "USD" // Доллар США, 1 доллар = 100 центов
+	XTR = "XTR" // Telegram Stars, дробных звезд не бывает
+)
+
+// currency описывает формат сумм в валюте
+type currency struct {
+	decimals int    // Количество знаков после запятой
+	symbol   string // Обозначение валюты при выводе суммы
+}
+
+var currencies = map[string]currency{
+	RUB: {decimals: 2, symbol: "₽"},
+	USD: {decimals: 2, symbol: "$"},
+	XTR: {decimals: 0, symbol: "⭐"},
+}
+
+// currencyOrder - порядок вывода валют в отчетах
+var currencyOrder = []string{RUB, USD, XTR}
+
+// Currencies возвращает поддерживаемые валюты
+func Currencies() []string {
+	return append([]string(nil), currencyOrder...)
+}
+
+// ParseCurrency приводит код валюты к верхнему регистру и проверяет, что валюта поддерживается
+func ParseCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if _, ok := currencies[code]; !ok {
+		return "", fmt.Errorf("unsupported currency %q: expected one of %s", code, strings.Join(currencyOrder, ", "))
+	}
+	return code, nil
+}
+
+// info возвращает формат валюты. Для неизвестных валют используются два знака после запятой и код валюты.
+func info(code string) currency {
+	if c, ok := currencies[code]; ok {
+		return c
+	}
+	return currency{decimals: 2, symbol: code}
+}
+
+// Decimal возвращает сумму в основных единицах валюты без обозначения, например "199.00"
+func Decimal(amount int64, code string) string {
+	decimals := info(code).decimals
+	if decimals == 0 {
+		return strconv.FormatInt(amount, 10)
+	}
+
+	sign := ""
+	value := new(big.Int).SetInt64(amount)
+	if value.Sign() < 0 {
+		sign = "-"
+		value.Neg(value)
+	}
+
+	digits := value.String()
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	point := len(digits) - decimals
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// Format возвращает сумму с обозначением валюты, например "199.00 ₽" или "150 ⭐"
+func Format(amount int64, code string) string {
+	return Decimal(amount, code) + " " + info(code).symbol
+}
+
+// FormatSigned возвращает сумму со знаком, например "+150.00 ₽" или "-50.00 ₽"
+func FormatSigned(amount int64, code string) string {
+	if amount > 0 {
+		return "+" + Format(amount, code)
+	}
+	return Format(amount, code)
+}
+
+// Parse разбирает сумму в основных единицах валюты ("199", "199.5", "199,50", "-50")
+// и возвращает ее в минимальных единицах. Разбор точный: лишние знаки после запятой считаются ошибкой.
+func Parse(s string, code string) (int64, error) {
+	decimals := info(code).decimals
+	value := strings.Replace(strings.TrimSpace(s), ",", ".", 1)
+
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "-"), "+")
+
+	whole, fraction, hasPoint := strings.Cut(value, ".")
+	if whole == "" && fraction == "" || !isDigits(whole) || !isDigits(fraction) || hasPoint && fraction == "" {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	if len(fraction) > decimals {
+		return 0, fmt.Errorf("invalid amount %q: at most %d decimal places allowed for %s", s, decimals, code)
+	}
+
+	digits := whole + fraction + strings.Repeat("0", decimals-len(fraction))
+	amount, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q: %w", s, err)
+	}
+
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// isDigits проверяет, что строка состоит только из десятичных цифр
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Prorate возвращает долю part/total от суммы amount, округленную до минимальной единицы валюты
+// (половина округляется от нуля). Промежуточные вычисления выполняются без переполнения.
+func Prorate(amount int64, part, total int64) int64 {
+	if total == 0 {
+		return 0
+	}
+
+	numerator := new(big.Int).Mul(big.NewInt(amount), big.NewInt(part))
+	denominator := big.NewInt(total)
+	if denominator.Sign() < 0 {
+		numerator.Neg(numerator)
+		denominator.Neg(denominator)
+	}
+
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(denominator) >= 0 {
+		if numerator.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return quotient.Int64()
+}
+
+// SortCurrencies упорядочивает коды валют для вывода: сначала поддерживаемые, затем остальные по алфавиту
+func SortCurrencies(codes []string) {
+	rank := func(code string) int {
+		for i, known := range currencyOrder {
+			if known == code {
+				return i
+			}
+		}
+		return len(currencyOrder)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		if rank(codes[i]) != rank(codes[j]) {
+			return rank(codes[i]) < rank(codes[j])
+		}
+		return codes[i] < codes[j]
+	})
+}
+
+// Totals содержит суммы в нескольких валютах: код валюты -> сумма в минимальных единицах
+type Totals map[string]int64
+
+// Add прибавляет сумму в валюте code
+func (t Totals) Add(code string, amount int64) {
+	t[code] += amount
+}
+
+// Currencies возвращает валюты сумм в порядке SortCurrencies
+func (t Totals) Currencies() []string {
+	codes := make([]string, 0, len(t))
+	for code := range t {
+		codes = append(codes, code)
+	}
+	SortCurrencies(codes)
+	return codes
+}
+
+// String возвращает суммы через запятую, например "1500.00 ₽, 12.00 $". Пустые суммы выводятся как "0".
+func (t Totals) String() string {
+	if len(t) == 0 {
+		return "0"
+	}
+
+	parts := make([]string, 0, len(t))
+	for _, code := range t.Currencies() {
+		parts = append(parts, Format(t[code], code))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,73 @@
+package roles
+
+// Роли сотрудников. Пустая роль означает обычного пользователя.
+const (
+	Owner   = "owner"   // Владелец: все права, включая назначение ролей
+	Admin   = "admin"   // Администратор: все права, кроме назначения ролей
+	Support = "support" // Поддержка: пользователи и их подписки
+	Finance = "finance" // Финансы: платежи, аналитика и корректировки баланса
+)
+
+// Permission - право на раздел или действие в панели управления
+type Permission string
+
+// Права в панели управления
+const (
+	PermPanel      Permission = "panel"      // Вход в панель управления
+	PermServers    Permission = "servers"    // Управление серверами
+	PermPlans      Permission = "plans"      // Управление планами подписок
+	PermUsers      Permission = "users"      // Пользователи и их подписки (блокировка, отзыв)
+	PermPayments   Permission = "payments"   // Платежи, аналитика доходов и выгрузки
+	PermRefunds    Permission = "refunds"    // Возвраты, бонусы и ручные корректировки баланса
+	PermBroadcasts Permission = "broadcasts" // Рассылки пользователям
+	PermStats      Permission = "stats"      // Общая статистика
+	PermRoles      Permission = "roles"      // Назначение ролей сотрудникам
+	PermAudit      Permission = "audit"      // Просмотр и выгрузка журнала действий сотрудников
+)
+
+// all - роли в порядке убывания прав
+var all = []string{Owner, Admin, Support, Finance}
+
+// matrix - права каждой роли
+var matrix = map[string][]Permission{
+	Owner:   {PermPanel, PermServers, PermPlans, PermUsers, PermPayments, PermRefunds, PermBroadcasts, PermStats, PermRoles, PermAudit},
+	Admin:   {PermPanel, PermServers, PermPlans, PermUsers, PermPayments, PermRefunds, PermBroadcasts, PermStats, PermAudit},
+	Support: {PermPanel, PermUsers, PermStats},
+	Finance: {PermPanel, PermPayments, PermRefunds, PermStats},
+}
+
+// All возвращает все роли в порядке убывания прав
+func All() []string {
+	return append([]string(nil), all...)
+}
+
+// IsValid проверяет, существует ли роль
+func IsValid(role string) bool {
+	_, ok := matrix[role]
+	return ok
+}
+
+// IsStaff проверяет, есть ли у пользователя с ролью доступ к панели управления
+func IsStaff(role string) bool {
+	return Can(role, PermPanel)
+}
+
+// Can проверяет, есть ли у роли право
+func Can(role string, permission Permission) bool {
+	for _, p := range matrix[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAny проверяет, есть ли у роли хотя бы одно из прав
+func CanAny(role string, permissions ...Permission) bool {
+	for _, permission := range permissions {
+		if Can(role, permission) {
+			return true
+		}
+	}
+	return false
+}
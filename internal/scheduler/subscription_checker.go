@@ -7,45 +7,61 @@ import (
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
+	"github.com/ilokitv/botVPN/internal/callbackdata"
 	"github.com/ilokitv/botVPN/internal/database"
+	"github.com/ilokitv/botVPN/internal/i18n"
 	"github.com/ilokitv/botVPN/internal/models"
-	"github.com/ilokitv/botVPN/internal/vpn"
+	"github.com/ilokitv/botVPN/internal/roles"
+	"github.com/ilokitv/botVPN/internal/sender"
+	"github.com/ilokitv/botVPN/internal/service"
 )
 
 // SubscriptionChecker - структура для проверки истекших подписок
 type SubscriptionChecker struct {
-	db         *database.DB
-	vpnManager *vpn.WireguardManager
-	bot        *tgbotapi.BotAPI
-	interval   time.Duration // Интервал между проверками
-	stop       chan struct{} // Канал для остановки проверок
+	db              *database.DB
+	subscriptions   *service.Subscriptions // Смена статусов подписок вместе с командами серверу VPN
+	sender          *sender.Sender         // Отправка уведомлений с ограничением скорости и повторами
+	callbacks       *callbackdata.Signer   // Подпись данных инлайн-кнопок в уведомлениях
+	interval        time.Duration          // Интервал между проверками
+	reminderOffsets []time.Duration        // За сколько до окончания подписки отправлять напоминания
+	now             func() time.Time       // Источник текущего времени (подменяется в тестах)
+	stop            chan struct{}          // Канал для остановки проверок
+
+	// notifyExpiring отправляет пользователю напоминание о скором окончании подписки (подменяется в тестах)
+	notifyExpiring func(subscription *models.Subscription, remaining time.Duration) error
 }
 
 // NewSubscriptionChecker создает новый объект для проверки подписок
-func NewSubscriptionChecker(db *database.DB, vpnManager *vpn.WireguardManager, bot *tgbotapi.BotAPI, interval time.Duration) *SubscriptionChecker {
-	return &SubscriptionChecker{
-		db:         db,
-		vpnManager: vpnManager,
-		bot:        bot,
-		interval:   interval,
-		stop:       make(chan struct{}),
+func NewSubscriptionChecker(db *database.DB, subscriptions *service.Subscriptions, sender *sender.Sender, callbacks *callbackdata.Signer, interval time.Duration, reminderOffsets []time.Duration) *SubscriptionChecker {
+	sc := &SubscriptionChecker{
+		db:              db,
+		subscriptions:   subscriptions,
+		sender:          sender,
+		callbacks:       callbacks,
+		interval:        interval,
+		reminderOffsets: reminderOffsets,
+		now:             time.Now,
+		stop:            make(chan struct{}),
 	}
+	sc.notifyExpiring = sc.notifyUserAboutExpiration
+	return sc
 }
 
-// Start запускает фоновую задачу для проверки подписок
+// Start запускает фоновую задачу для проверки подписок.
+// Проверки выполняются по очереди в одной горутине: если проверка длится дольше интервала,
+// следующая начнется после ее завершения, поэтому напоминания и завершение подписок не дублируются.
 func (sc *SubscriptionChecker) Start() {
 	log.Println("Запуск фоновой задачи проверки подписок")
 
-	// Сразу запускаем первую проверку
-	go sc.checkExpiredSubscriptions()
-
-	// Настраиваем периодическую проверку
 	ticker := time.NewTicker(sc.interval)
 	go func() {
+		// Сразу выполняем первую проверку
+		sc.checkExpiredSubscriptions()
+
 		for {
 			select {
 			case <-ticker.C:
-				go sc.checkExpiredSubscriptions()
+				sc.checkExpiredSubscriptions()
 			case <-sc.stop:
 				ticker.Stop()
 				return
@@ -64,6 +80,10 @@ func (sc *SubscriptionChecker) Stop() {
 func (sc *SubscriptionChecker) checkExpiredSubscriptions() {
 	log.Println("Проверка истекших подписок...")
 
+	// Возобновляем подписки, у которых закончился максимальный срок паузы,
+	// чтобы они участвовали в проверке ниже
+	sc.resumeOverduePauses(sc.now())
+
 	// Получаем все активные подписки
 	subscriptions, err := sc.getActiveSubscriptions()
 	if err != nil {
@@ -73,7 +93,7 @@ func (sc *SubscriptionChecker) checkExpiredSubscriptions() {
 
 	log.Printf("Найдено %d активных подписок для проверки", len(subscriptions))
 
-	now := time.Now()
+	now := sc.now()
 	expiredCount := 0
 	var expiredSubscriptions []models.Subscription
 
@@ -83,43 +103,58 @@ func (sc *SubscriptionChecker) checkExpiredSubscriptions() {
 			log.Printf("Обнаружена истекшая подписка #%d, пользователь #%d, дата окончания: %s",
 				subscription.ID, subscription.UserID, subscription.EndDate.Format("02.01.2006"))
 
-			// Обновляем статус подписки на "expired"
-			err = sc.expireSubscription(&subscription)
-			if err != nil {
-				log.Printf("Ошибка при обновлении статуса подписки #%d: %v", subscription.ID, err)
+			// Если у плана есть льготный период, блокируем пир, но сохраняем конфигурацию до продления.
+			// Без плана неизвестно, положен ли льготный период, поэтому подписка проверяется снова при следующем запуске
+			graceDays := 0
+			if plan, err := sc.db.GetSubscriptionPlanByID(subscription.PlanID); err == nil {
+				graceDays = plan.GraceDays
+			} else {
+				log.Printf("Ошибка при получении плана #%d: %v", subscription.PlanID, err)
 				continue
 			}
 
-			// Отзываем конфигурацию VPN
-			err = sc.revokeVPNConfig(&subscription)
-			if err != nil {
-				log.Printf("Ошибка при отзыве конфигурации VPN для подписки #%d: %v", subscription.ID, err)
+			if graceDays > 0 {
+				err = sc.startGracePeriod(&subscription, graceDays)
+				if err != nil {
+					log.Printf("Ошибка при переводе подписки #%d в льготный период: %v", subscription.ID, err)
+				}
 				continue
 			}
 
-			// Отправляем уведомление пользователю
-			err = sc.notifyUser(&subscription)
-			if err != nil {
-				log.Printf("Ошибка при отправке уведомления пользователю #%d: %v", subscription.UserID, err)
+			if sc.finishSubscription(&subscription, "Срок действия истек") {
+				expiredCount++
+				expiredSubscriptions = append(expiredSubscriptions, subscription)
 			}
+		} else {
+			// Отправляем напоминание о скором истечении, если подошло время по расписанию
+			sc.sendExpirationReminder(&subscription, now)
+		}
+	}
 
-			log.Printf("Подписка #%d успешно помечена как истекшая и VPN-конфигурация отозвана", subscription.ID)
-
+	// Отзываем подписки, льготный период которых закончился
+	for _, subscription := range sc.getOverdueGraceSubscriptions(now) {
+		if sc.finishSubscription(&subscription, "Льготный период истек") {
 			expiredCount++
 			expiredSubscriptions = append(expiredSubscriptions, subscription)
-		} else {
-			// Проверяем, скоро ли истечет подписка (осталось менее 3 дней)
-			daysLeft := int(subscription.EndDate.Sub(now).Hours() / 24)
-			if daysLeft <= 3 && daysLeft >= 0 {
-				// Отправляем предупреждение о скором истечении
-				err = sc.notifyUserAboutExpiration(&subscription, daysLeft)
-				if err != nil {
-					log.Printf("Ошибка при отправке предупреждения о скором истечении пользователю #%d: %v", subscription.UserID, err)
-				}
-			}
 		}
 	}
 
+	// Помечаем просроченные подарочные коды
+	expiredGifts, err := sc.db.ExpireGiftCodes()
+	if err != nil {
+		log.Printf("Ошибка при обработке просроченных подарочных кодов: %v", err)
+	} else if expiredGifts > 0 {
+		log.Printf("Помечено истекшими подарочных кодов: %d", expiredGifts)
+	}
+
+	// Удаляем брошенные незавершенные диалоги с ботом
+	expiredStates, err := sc.db.DeleteExpiredConversationStates(now)
+	if err != nil {
+		log.Printf("Ошибка при удалении устаревших состояний диалогов: %v", err)
+	} else if expiredStates > 0 {
+		log.Printf("Удалено устаревших состояний диалогов: %d", expiredStates)
+	}
+
 	// Если были найдены истекшие подписки, отправляем отчет администраторам
 	if expiredCount > 0 {
 		err = sc.notifyAdmins(expiredSubscriptions)
@@ -131,6 +166,136 @@ func (sc *SubscriptionChecker) checkExpiredSubscriptions() {
 	log.Println("Проверка истекших подписок завершена")
 }
 
+// resumeOverduePauses автоматически возобновляет подписки, пробывшие на паузе максимально допустимый срок
+func (sc *SubscriptionChecker) resumeOverduePauses(now time.Time) {
+	subscriptions, err := sc.db.GetSubscriptionsByStatus("paused")
+	if err != nil {
+		log.Printf("Ошибка при получении приостановленных подписок: %v", err)
+		return
+	}
+
+	for _, subscription := range subscriptions {
+		if subscription.PausedAt == nil {
+			continue
+		}
+
+		plan, err := sc.db.GetSubscriptionPlanByID(subscription.PlanID)
+		if err != nil {
+			log.Printf("Ошибка при получении плана #%d: %v", subscription.PlanID, err)
+			continue
+		}
+
+		// Пауза не может длиться дольше лимита плана: срок продлевается ровно на время до его окончания
+		resumeAt := subscription.PausedAt.AddDate(0, 0, plan.MaxPauseDays)
+		if now.Before(resumeAt) {
+			continue
+		}
+
+		resumed, err := sc.subscriptions.Resume(&subscription, resumeAt, "Автоматически: истек лимит паузы")
+		if err != nil {
+			log.Printf("Ошибка при автоматическом возобновлении подписки #%d: %v", subscription.ID, err)
+			continue
+		}
+
+		log.Printf("Подписка #%d автоматически возобновлена после паузы, новая дата окончания: %s",
+			resumed.ID, resumed.EndDate.Format("02.01.2006"))
+
+		if err := sc.notifyUserAboutResume(resumed); err != nil {
+			log.Printf("Ошибка при отправке уведомления о возобновлении пользователю #%d: %v", resumed.UserID, err)
+		}
+	}
+}
+
+// notifyUserAboutResume уведомляет пользователя об автоматическом возобновлении подписки
+func (sc *SubscriptionChecker) notifyUserAboutResume(subscription *models.Subscription) error {
+	user, err := sc.db.GetUserByID(subscription.UserID)
+	if err != nil {
+		return err
+	}
+
+	message := i18n.T(userLang(user), "notify.resumed",
+		subscription.ID,
+		subscription.EndDate.Format("02.01.2006"),
+	)
+
+	msg := tgbotapi.NewMessage(user.TelegramID, message)
+	msg.ParseMode = "Markdown"
+
+	_, err = sc.sender.Send(msg)
+	return err
+}
+
+// dueReminderOffset возвращает наименьшее смещение из расписания, время которого уже наступило.
+// Например, при расписании 7d, 3d, 1d и оставшихся 2 днях вернется 3d. Если до окончания
+// подписки больше любого смещения или срок уже истек, возвращается false.
+func dueReminderOffset(offsets []time.Duration, endDate, now time.Time) (time.Duration, bool) {
+	remaining := endDate.Sub(now)
+	if remaining <= 0 {
+		return 0, false
+	}
+
+	var due time.Duration
+	found := false
+	for _, offset := range offsets {
+		if remaining <= offset && (!found || offset < due) {
+			due = offset
+			found = true
+		}
+	}
+	return due, found
+}
+
+// sendExpirationReminder отправляет напоминание о скором окончании подписки.
+// Каждое напоминание отправляется один раз: перед отправкой оно отмечается в журнале уведомлений.
+func (sc *SubscriptionChecker) sendExpirationReminder(subscription *models.Subscription, now time.Time) {
+	offset, ok := dueReminderOffset(sc.reminderOffsets, subscription.EndDate, now)
+	if !ok {
+		return
+	}
+
+	claimed, err := sc.db.ClaimSubscriptionNotification(subscription.ID, offset, subscription.EndDate)
+	if err != nil {
+		log.Printf("Ошибка при записи напоминания для подписки #%d: %v", subscription.ID, err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	err = sc.notifyExpiring(subscription, subscription.EndDate.Sub(now))
+	if err != nil {
+		log.Printf("Ошибка при отправке предупреждения о скором истечении пользователю #%d: %v", subscription.UserID, err)
+		// Снимаем отметку, чтобы повторить отправку на следующей проверке
+		if releaseErr := sc.db.ReleaseSubscriptionNotification(subscription.ID, offset, subscription.EndDate); releaseErr != nil {
+			log.Printf("Ошибка при удалении записи о напоминании для подписки #%d: %v", subscription.ID, releaseErr)
+		}
+		return
+	}
+
+	log.Printf("Отправлено напоминание за %s до окончания подписки #%d", offset, subscription.ID)
+}
+
+// formatTimeLeft возвращает оставшееся до окончания подписки время в днях или часах на языке lang
+func formatTimeLeft(lang string, remaining time.Duration) string {
+	if remaining >= 24*time.Hour {
+		return i18n.T(lang, "time.days", int(remaining.Hours()/24))
+	}
+	if remaining >= time.Hour {
+		return i18n.T(lang, "time.hours", int(remaining.Hours()))
+	}
+	return i18n.T(lang, "time.less_than_hour")
+}
+
+// button создает инлайн-кнопку, данные которой подписаны для чата chatID
+func (sc *SubscriptionChecker) button(chatID int64, text, payload string) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(text, sc.callbacks.Sign(chatID, payload))
+}
+
+// userLang возвращает язык интерфейса пользователя для уведомлений
+func userLang(user *models.User) string {
+	return i18n.Resolve(user.Language, user.LanguageCode)
+}
+
 // getActiveSubscriptions получает все активные подписки
 func (sc *SubscriptionChecker) getActiveSubscriptions() ([]models.Subscription, error) {
 	// Получаем все подписки со статусом "active"
@@ -143,22 +308,64 @@ func (sc *SubscriptionChecker) getActiveSubscriptions() ([]models.Subscription,
 	return subscriptions, nil
 }
 
-// expireSubscription устанавливает статус подписки как "expired"
-func (sc *SubscriptionChecker) expireSubscription(subscription *models.Subscription) error {
-	subscription.Status = "expired"
-	return sc.db.UpdateSubscription(subscription)
-}
+// finishSubscription помечает подписку истекшей, отзывает конфигурацию VPN и уведомляет пользователя.
+// Возвращает true, если подписка успешно обработана.
+func (sc *SubscriptionChecker) finishSubscription(subscription *models.Subscription, reason string) bool {
+	// Помечаем подписку истекшей и отзываем конфигурацию VPN
+	if err := sc.subscriptions.Expire(subscription, reason); err != nil {
+		log.Printf("Ошибка при завершении подписки #%d: %v", subscription.ID, err)
+		return false
+	}
 
-// revokeVPNConfig отзывает конфигурацию VPN с сервера
-func (sc *SubscriptionChecker) revokeVPNConfig(subscription *models.Subscription) error {
-	// Получаем информацию о сервере
-	server, err := sc.db.GetServerByID(subscription.ServerID)
+	// Отправляем уведомление пользователю
+	err := sc.notifyUser(subscription)
 	if err != nil {
+		log.Printf("Ошибка при отправке уведомления пользователю #%d: %v", subscription.UserID, err)
+	}
+
+	log.Printf("Подписка #%d успешно помечена как истекшая и VPN-конфигурация отозвана", subscription.ID)
+	return true
+}
+
+// startGracePeriod блокирует пир истекшей подписки и переводит ее в льготный период.
+// Конфигурация сохраняется, поэтому при продлении доступ восстанавливается без переустановки.
+func (sc *SubscriptionChecker) startGracePeriod(subscription *models.Subscription, graceDays int) error {
+	if err := sc.subscriptions.EnterGrace(subscription, graceDays); err != nil {
 		return err
 	}
 
-	// Отзываем конфигурацию клиента
-	return sc.vpnManager.RevokeClientConfig(server, subscription.ConfigFilePath)
+	log.Printf("Подписка #%d переведена в льготный период на %d дней", subscription.ID, graceDays)
+
+	if err := sc.notifyUserAboutGracePeriod(subscription, graceDays); err != nil {
+		log.Printf("Ошибка при отправке уведомления о льготном периоде пользователю #%d: %v", subscription.UserID, err)
+	}
+
+	return nil
+}
+
+// getOverdueGraceSubscriptions возвращает подписки, льготный период которых закончился
+func (sc *SubscriptionChecker) getOverdueGraceSubscriptions(now time.Time) []models.Subscription {
+	subscriptions, err := sc.db.GetSubscriptionsByStatus("grace")
+	if err != nil {
+		log.Printf("Ошибка при получении подписок в льготном периоде: %v", err)
+		return nil
+	}
+
+	var overdue []models.Subscription
+	for _, subscription := range subscriptions {
+		graceDays := 0
+		if plan, err := sc.db.GetSubscriptionPlanByID(subscription.PlanID); err == nil {
+			graceDays = plan.GraceDays
+		} else {
+			log.Printf("Ошибка при получении плана #%d: %v", subscription.PlanID, err)
+			continue
+		}
+
+		if now.After(subscription.EndDate.AddDate(0, 0, graceDays)) {
+			overdue = append(overdue, subscription)
+		}
+	}
+	return overdue
 }
 
 // notifyUser отправляет уведомление пользователю об истечении подписки
@@ -175,15 +382,15 @@ func (sc *SubscriptionChecker) notifyUser(subscription *models.Subscription) err
 		return err
 	}
 
+	lang := userLang(user)
+
+	// Для пробной подписки отправляем отдельное сообщение с предложением оформить платный план
+	if subscription.IsTrial {
+		return sc.notifyTrialUser(user.TelegramID, lang, i18n.T(lang, "notify.trial_ended"))
+	}
+
 	// Формируем сообщение об истечении подписки
-	message := fmt.Sprintf(
-		"❗️ *Срок действия вашей подписки истек* ❗️\n\n"+
-			"Подписка: #%d\n"+
-			"План: %s\n"+
-			"Дата начала: %s\n"+
-			"Дата окончания: %s\n\n"+
-			"Ваше VPN-соединение было автоматически отключено.\n"+
-			"Для продолжения использования VPN, пожалуйста, оформите новую подписку с помощью команды /buy.",
+	message := i18n.T(lang, "notify.expired",
 		subscription.ID,
 		plan.Name,
 		subscription.StartDate.Format("02.01.2006"),
@@ -194,12 +401,37 @@ func (sc *SubscriptionChecker) notifyUser(subscription *models.Subscription) err
 	msg := tgbotapi.NewMessage(user.TelegramID, message)
 	msg.ParseMode = "Markdown"
 
-	_, err = sc.bot.Send(msg)
+	_, err = sc.sender.Send(msg)
+	return err
+}
+
+// notifyUserAboutGracePeriod уведомляет пользователя о начале льготного периода
+func (sc *SubscriptionChecker) notifyUserAboutGracePeriod(subscription *models.Subscription, graceDays int) error {
+	user, err := sc.db.GetUserByID(subscription.UserID)
+	if err != nil {
+		return err
+	}
+
+	lang := userLang(user)
+	message := i18n.T(lang, "notify.grace",
+		subscription.ID,
+		subscription.EndDate.AddDate(0, 0, graceDays).Format("02.01.2006"),
+	)
+
+	msg := tgbotapi.NewMessage(user.TelegramID, message)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			sc.button(user.TelegramID, i18n.T(lang, "button.renew_long"), fmt.Sprintf("renew:%d", subscription.ID)),
+		),
+	)
+
+	_, err = sc.sender.Send(msg)
 	return err
 }
 
 // notifyUserAboutExpiration отправляет предупреждение пользователю о скором истечении подписки
-func (sc *SubscriptionChecker) notifyUserAboutExpiration(subscription *models.Subscription, daysLeft int) error {
+func (sc *SubscriptionChecker) notifyUserAboutExpiration(subscription *models.Subscription, remaining time.Duration) error {
 	// Получаем информацию о пользователе
 	user, err := sc.db.GetUserByID(subscription.UserID)
 	if err != nil {
@@ -212,26 +444,50 @@ func (sc *SubscriptionChecker) notifyUserAboutExpiration(subscription *models.Su
 		return err
 	}
 
+	lang := userLang(user)
+
+	// Для пробной подписки напоминаем о переходе на платный план
+	if subscription.IsTrial {
+		return sc.notifyTrialUser(user.TelegramID, lang, i18n.T(lang, "notify.trial_expiring",
+			subscription.EndDate.Format("02.01.2006"),
+			formatTimeLeft(lang, remaining),
+		))
+	}
+
 	// Формируем сообщение о скором истечении подписки
-	message := fmt.Sprintf(
-		"⚠️ *Внимание! Ваша подписка скоро истечет* ⚠️\n\n"+
-			"Подписка: #%d\n"+
-			"План: %s\n"+
-			"Дата окончания: %s\n\n"+
-			"Осталось дней: *%d*\n\n"+
-			"Для продления подписки используйте команду /buy.\n"+
-			"Если не продлить подписку, ваше VPN-соединение будет автоматически отключено по истечении срока.",
+	message := i18n.T(lang, "notify.expiring",
 		subscription.ID,
 		plan.Name,
 		subscription.EndDate.Format("02.01.2006"),
-		daysLeft,
+		formatTimeLeft(lang, remaining),
 	)
 
 	// Отправляем сообщение пользователю
 	msg := tgbotapi.NewMessage(user.TelegramID, message)
 	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			sc.button(user.TelegramID, i18n.T(lang, "button.renew_long"), fmt.Sprintf("renew:%d", subscription.ID)),
+		),
+	)
+
+	_, err = sc.sender.Send(msg)
+	return err
+}
+
+// notifyTrialUser отправляет пользователю пробного периода сообщение с кнопкой выбора платного плана
+func (sc *SubscriptionChecker) notifyTrialUser(telegramID int64, lang, message string) error {
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			sc.button(telegramID, i18n.T(lang, "button.choose_tariff"), "show_buy_plans"),
+		),
+	)
+
+	msg := tgbotapi.NewMessage(telegramID, message)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
 
-	_, err = sc.bot.Send(msg)
+	_, err := sc.sender.Send(msg)
 	return err
 }
 
@@ -248,13 +504,9 @@ func (sc *SubscriptionChecker) notifyAdmins(expiredSubscriptions []models.Subscr
 		return nil
 	}
 
-	// Формируем сообщение с отчетом
-	message := fmt.Sprintf(
-		"📊 *Отчет о истекших подписках*\n\n"+
-			"Обнаружено и обработано истекших подписок: %d\n\n"+
-			"*Список обработанных подписок:*\n",
-		len(expiredSubscriptions),
-	)
+	// Формируем сообщение с отчетом на языке сотрудников
+	lang := i18n.DefaultLanguage
+	message := i18n.T(lang, "notify.admin_report", len(expiredSubscriptions))
 
 	// Добавляем информацию о каждой подписке
 	for i, subscription := range expiredSubscriptions {
@@ -272,13 +524,12 @@ func (sc *SubscriptionChecker) notifyAdmins(expiredSubscriptions []models.Subscr
 			continue
 		}
 
-		userInfo := fmt.Sprintf("%s", user.Username)
+		userInfo := user.Username
 		if userInfo == "" {
 			userInfo = fmt.Sprintf("ID: %d", user.TelegramID)
 		}
 
-		message += fmt.Sprintf(
-			"%d. Подписка #%d - Пользователь: %s - План: %s - Дата окончания: %s\n",
+		message += i18n.T(lang, "notify.admin_report_line",
 			i+1,
 			subscription.ID,
 			userInfo,
@@ -287,14 +538,17 @@ func (sc *SubscriptionChecker) notifyAdmins(expiredSubscriptions []models.Subscr
 		)
 	}
 
-	message += "\nВсе указанные подписки были автоматически помечены как истекшие, и соответствующие VPN-конфигурации были отозваны."
+	message += i18n.T(lang, "notify.admin_report_footer")
 
-	// Отправляем сообщение каждому администратору
+	// Отправляем сообщение каждому сотруднику, который управляет подписками пользователей
 	for _, admin := range admins {
+		if !roles.Can(admin.Role, roles.PermUsers) {
+			continue
+		}
 		msg := tgbotapi.NewMessage(admin.TelegramID, message)
 		msg.ParseMode = "Markdown"
 
-		_, err := sc.bot.Send(msg)
+		_, err := sc.sender.Send(msg)
 		if err != nil {
 			log.Printf("Ошибка при отправке отчета администратору #%d: %v", admin.TelegramID, err)
 		}
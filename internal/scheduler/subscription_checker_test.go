@@ -0,0 +1,177 @@
+package scheduler
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ilokitv/botVPN/internal/config"
+	"github.com/ilokitv/botVPN/internal/database"
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+const day = 24 * time.Hour
+
+var testOffsets = []time.Duration{7 * day, 3 * day, day, 2 * time.Hour}
+
+func TestDueReminderOffset(t *testing.T) {
+	endDate := time.Date(2024, 5, 20, 18, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		offsets   []time.Duration
+		remaining time.Duration
+		want      time.Duration
+		wantOK    bool
+	}{
+		{name: "before first offset", offsets: testOffsets, remaining: 8 * day},
+		{name: "exactly 7d", offsets: testOffsets, remaining: 7 * day, want: 7 * day, wantOK: true},
+		{name: "between 7d and 3d", offsets: testOffsets, remaining: 5 * day, want: 7 * day, wantOK: true},
+		{name: "exactly 3d", offsets: testOffsets, remaining: 3 * day, want: 3 * day, wantOK: true},
+		{name: "between 3d and 1d", offsets: testOffsets, remaining: 30 * time.Hour, want: 3 * day, wantOK: true},
+		{name: "exactly 1d", offsets: testOffsets, remaining: day, want: day, wantOK: true},
+		{name: "exactly 2h", offsets: testOffsets, remaining: 2 * time.Hour, want: 2 * time.Hour, wantOK: true},
+		{name: "last minute", offsets: testOffsets, remaining: time.Minute, want: 2 * time.Hour, wantOK: true},
+		{name: "at expiry", offsets: testOffsets, remaining: 0},
+		{name: "past expiry", offsets: testOffsets, remaining: -time.Hour},
+		{name: "unsorted offsets", offsets: []time.Duration{day, 7 * day, 3 * day}, remaining: 2 * day, want: 3 * day, wantOK: true},
+		{name: "no offsets", offsets: nil, remaining: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := dueReminderOffset(tt.offsets, endDate, endDate.Add(-tt.remaining))
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("dueReminderOffset() = %s, %v; want %s, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// sentReminder - напоминание, отправленное через подмененный notifyExpiring
+type sentReminder struct {
+	subscriptionID int
+	remaining      time.Duration
+}
+
+// newTestChecker создает проверку подписок поверх временной базы SQLite с подпиской #1,
+// заканчивающейся в endDate. Напоминания не отправляются в Telegram, а записываются в sent.
+func newTestChecker(t *testing.T, endDate time.Time) (*SubscriptionChecker, *models.Subscription, *[]sentReminder) {
+	t.Helper()
+
+	db, err := database.New(&config.DatabaseConfig{Driver: config.DriverSQLite, Path: filepath.Join(t.TempDir(), "bot.db")})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.MigrateUp(); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	statements := []string{
+		"INSERT INTO users (id, telegram_id, username, first_name, last_name) VALUES (1, 100, 'user', 'User', '')",
+		"INSERT INTO servers (id, ip, port, ssh_user, ssh_password) VALUES (1, '10.0.0.1', 22, 'root', 'secret')",
+		"INSERT INTO subscription_plans (id, name, description, price, duration) VALUES (1, 'Месяц', '', 19900, 30)",
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	subscription := &models.Subscription{ID: 1, UserID: 1, ServerID: 1, PlanID: 1,
+		StartDate: endDate.AddDate(0, 0, -30), EndDate: endDate, Status: "active"}
+	_, err = db.Exec(`INSERT INTO subscriptions (id, user_id, server_id, plan_id, start_date, end_date, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`, subscription.ID, subscription.UserID, subscription.ServerID,
+		subscription.PlanID, subscription.StartDate, subscription.EndDate, subscription.Status)
+	if err != nil {
+		t.Fatalf("seed subscription: %v", err)
+	}
+
+	sent := &[]sentReminder{}
+	sc := NewSubscriptionChecker(db, nil, nil, nil, time.Minute, testOffsets)
+	sc.notifyExpiring = func(subscription *models.Subscription, remaining time.Duration) error {
+		*sent = append(*sent, sentReminder{subscriptionID: subscription.ID, remaining: remaining})
+		return nil
+	}
+	return sc, subscription, sent
+}
+
+func TestSendExpirationReminderSchedule(t *testing.T) {
+	endDate := time.Date(2024, 5, 20, 18, 30, 0, 0, time.UTC)
+	sc, subscription, sent := newTestChecker(t, endDate)
+
+	steps := []struct {
+		name      string
+		remaining time.Duration
+		wantSent  bool
+	}{
+		{name: "8d left, nothing due", remaining: 8 * day},
+		{name: "7d reminder", remaining: 7 * day, wantSent: true},
+		{name: "7d already sent", remaining: 6 * day},
+		{name: "3d reminder", remaining: 3 * day, wantSent: true},
+		{name: "3d already sent", remaining: 2 * day},
+		{name: "1d reminder", remaining: 20 * time.Hour, wantSent: true},
+		{name: "2h reminder", remaining: 90 * time.Minute, wantSent: true},
+		{name: "2h already sent", remaining: time.Minute},
+		{name: "past expiry", remaining: -time.Hour},
+	}
+
+	for _, step := range steps {
+		now := endDate.Add(-step.remaining)
+		sc.now = func() time.Time { return now }
+
+		before := len(*sent)
+		sc.sendExpirationReminder(subscription, sc.now())
+
+		gotSent := len(*sent) > before
+		if gotSent != step.wantSent {
+			t.Fatalf("%s: sent = %v, want %v", step.name, gotSent, step.wantSent)
+		}
+		if gotSent && (*sent)[before].remaining != step.remaining {
+			t.Errorf("%s: remaining = %s, want %s", step.name, (*sent)[before].remaining, step.remaining)
+		}
+	}
+
+	if len(*sent) != 4 {
+		t.Errorf("sent %d reminders, want 4", len(*sent))
+	}
+}
+
+func TestSendExpirationReminderRetriesFailedSend(t *testing.T) {
+	endDate := time.Date(2024, 5, 20, 18, 30, 0, 0, time.UTC)
+	sc, subscription, sent := newTestChecker(t, endDate)
+	now := endDate.Add(-3 * day)
+
+	record := sc.notifyExpiring
+	sc.notifyExpiring = func(*models.Subscription, time.Duration) error {
+		return errors.New("telegram unavailable")
+	}
+	sc.sendExpirationReminder(subscription, now)
+
+	// Неудачная отправка снимает отметку, и напоминание отправляется на следующей проверке
+	sc.notifyExpiring = record
+	sc.sendExpirationReminder(subscription, now.Add(time.Minute))
+	sc.sendExpirationReminder(subscription, now.Add(2*time.Minute))
+
+	if len(*sent) != 1 {
+		t.Errorf("sent %d reminders, want 1", len(*sent))
+	}
+}
+
+func TestSendExpirationReminderAfterRenewal(t *testing.T) {
+	endDate := time.Date(2024, 5, 20, 18, 30, 0, 0, time.UTC)
+	sc, subscription, sent := newTestChecker(t, endDate)
+
+	sc.sendExpirationReminder(subscription, endDate.Add(-day))
+
+	// После продления дата окончания другая, поэтому расписание напоминаний начинается заново
+	subscription.EndDate = endDate.AddDate(0, 0, 30)
+	sc.sendExpirationReminder(subscription, subscription.EndDate.Add(-7*day))
+	sc.sendExpirationReminder(subscription, subscription.EndDate.Add(-day))
+
+	if len(*sent) != 3 {
+		t.Errorf("sent %d reminders, want 3", len(*sent))
+	}
+}
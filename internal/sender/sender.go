@@ -0,0 +1,358 @@
+package sender
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/ilokitv/botVPN/internal/config"
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+// groupChatRate - ограничение Telegram для групп: не больше 20 сообщений в минуту
+const groupChatRate = 20.0 / 60
+
+// chatIdleTTL - через сколько простоя ограничитель чата удаляется (к этому времени он полностью восстановлен)
+const chatIdleTTL = time.Minute
+
+// botBlockedDescription - описание ошибки 403, которой Telegram отвечает, если пользователь заблокировал бота.
+// С тем же кодом приходят и другие отказы (бота удалили из группы, пользователь не начинал диалог).
+const botBlockedDescription = "bot was blocked by the user"
+
+// API выполняет запросы к Telegram (реализуется tgbotapi.BotAPI)
+type API interface {
+	Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error)
+}
+
+// Store сохраняет последствия неудачной отправки
+type Store interface {
+	AddFailedMessage(message *models.FailedMessage) error
+	MarkUserBotBlocked(telegramID int64) error
+}
+
+// Sender отправляет запросы в Telegram с ограничением скорости: общим для бота и отдельным для каждого чата.
+// Если Telegram просит подождать (429 с retry_after), общий ограничитель останавливается на это время для всех
+// отправок, а запрос повторяется. После сетевой ошибки запрос повторяется с нарастающей задержкой.
+// Сообщения, которые так и не удалось доставить, сохраняются в базе, а пользователи, заблокировавшие бота, отмечаются.
+type Sender struct {
+	bot        API
+	store      Store
+	maxRetries int
+	chatRate   float64
+	chatBurst  float64
+	now        func() time.Time    // Источник текущего времени (подменяется в тестах)
+	sleep      func(time.Duration) // Ожидание (подменяется в тестах)
+
+	mu        sync.Mutex
+	global    *bucket
+	chats     map[int64]*bucket
+	lastPrune time.Time
+}
+
+// New создает отправителя с ограничениями из конфигурации
+func New(bot API, store Store, cfg config.SenderConfig) *Sender {
+	globalRate := float64(cfg.GetGlobalRatePerSecond())
+	return &Sender{
+		bot:        bot,
+		store:      store,
+		maxRetries: cfg.GetMaxRetries(),
+		chatRate:   float64(cfg.GetChatRatePerSecond()),
+		chatBurst:  float64(cfg.GetChatBurst()),
+		now:        time.Now,
+		sleep:      time.Sleep,
+		global:     newBucket(globalRate, globalRate),
+		chats:      make(map[int64]*bucket),
+		lastPrune:  time.Now(),
+	}
+}
+
+// Send отправляет сообщение и возвращает его так же, как tgbotapi.BotAPI.Send
+func (s *Sender) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	resp, err := s.Request(c)
+	if err != nil {
+		return tgbotapi.Message{}, err
+	}
+
+	var message tgbotapi.Message
+	err = json.Unmarshal(resp.Result, &message)
+	return message, err
+}
+
+// Request выполняет запрос так же, как tgbotapi.BotAPI.Request, дожидаясь своей очереди и повторяя его после временных ошибок
+func (s *Sender) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	chatID := chatIDOf(c)
+
+	for attempt := 1; ; attempt++ {
+		s.wait(chatID)
+
+		resp, err := s.bot.Request(c)
+		if err == nil {
+			return resp, nil
+		}
+
+		delay, ok := retryDelay(err, attempt)
+
+		// Ограничение Telegram действует на весь бот: остальные отправки ждут окончания паузы,
+		// даже если этот запрос больше не повторяется
+		flood := isFloodWait(err)
+		if flood {
+			now := s.now()
+			s.mu.Lock()
+			s.global.pause(now, now.Add(delay))
+			s.mu.Unlock()
+		}
+
+		if !ok || attempt > s.maxRetries {
+			s.fail(c, chatID, err, attempt)
+			return resp, err
+		}
+		log.Printf("Повтор отправки в чат %d через %s (попытка %d): %v", chatID, delay, attempt, err)
+
+		// После паузы по запросу Telegram повтор дождется ее окончания в wait
+		if !flood {
+			s.sleep(delay)
+		}
+	}
+}
+
+// IsBotBlocked проверяет, что сообщение не доставлено, потому что пользователь заблокировал бота
+func IsBotBlocked(err error) bool {
+	var apiErr *tgbotapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusForbidden &&
+		strings.Contains(apiErr.Message, botBlockedDescription)
+}
+
+// isFloodWait проверяет, что Telegram отклонил запрос из-за превышения ограничений и просит подождать
+func isFloodWait(err error) bool {
+	var apiErr *tgbotapi.Error
+	return errors.As(err, &apiErr) && apiErr.RetryAfter > 0
+}
+
+// wait дожидается, пока отправку разрешат ограничители чата и бота.
+// Запросы без чата (ответы на нажатия кнопок, настройки бота) не расходуют лимит, но ждут окончания паузы,
+// которую запросил Telegram.
+func (s *Sender) wait(chatID int64) {
+	if chatID == 0 {
+		s.mu.Lock()
+		delay := s.global.pausedFor(s.now())
+		s.mu.Unlock()
+		s.sleep(delay)
+		return
+	}
+
+	s.mu.Lock()
+	delay := s.chatBucket(chatID).reserve(s.now())
+	s.mu.Unlock()
+	s.sleep(delay)
+
+	s.mu.Lock()
+	delay = s.global.reserve(s.now())
+	s.mu.Unlock()
+	s.sleep(delay)
+}
+
+// chatBucket возвращает ограничитель чата, заодно удаляя давно простаивающие. Вызывается под s.mu.
+func (s *Sender) chatBucket(chatID int64) *bucket {
+	now := s.now()
+	if now.Sub(s.lastPrune) >= chatIdleTTL {
+		for id, b := range s.chats {
+			if now.Sub(b.last) >= chatIdleTTL {
+				delete(s.chats, id)
+			}
+		}
+		s.lastPrune = now
+	}
+
+	b, ok := s.chats[chatID]
+	if !ok {
+		rate := s.chatRate
+		if chatID < 0 {
+			rate = groupChatRate
+		}
+		b = newBucket(rate, s.chatBurst)
+		s.chats[chatID] = b
+	}
+	return b
+}
+
+// fail логирует и сохраняет запрос, который не удалось выполнить
+func (s *Sender) fail(c tgbotapi.Chattable, chatID int64, err error, attempts int) {
+	var apiErr *tgbotapi.Error
+	code := 0
+	if errors.As(err, &apiErr) {
+		code = apiErr.Code
+		// Повторное редактирование сообщения тем же текстом - не ошибка доставки
+		if strings.Contains(apiErr.Message, "message is not modified") {
+			return
+		}
+	}
+
+	log.Printf("Не удалось отправить %T в чат %d (попыток: %d): %v", c, chatID, attempts, err)
+
+	// Сохраняются только сообщения в чаты: ответы на нажатия кнопок и служебные запросы некому доставлять повторно
+	if chatID == 0 || s.store == nil {
+		return
+	}
+
+	// Отрицательные идентификаторы принадлежат группам и каналам: отказ в них не означает блокировку пользователем
+	if IsBotBlocked(err) && chatID > 0 {
+		if err := s.store.MarkUserBotBlocked(chatID); err != nil {
+			log.Printf("Ошибка при отметке пользователя %d как заблокировавшего бота: %v", chatID, err)
+		}
+	}
+
+	payload, marshalErr := json.Marshal(withoutFileContents(c))
+	if marshalErr != nil {
+		payload = []byte(fmt.Sprintf("%q", marshalErr.Error()))
+	}
+	failed := &models.FailedMessage{
+		ChatID:    chatID,
+		Method:    fmt.Sprintf("%T", c),
+		Payload:   string(payload),
+		ErrorCode: code,
+		Error:     err.Error(),
+		Attempts:  attempts,
+	}
+	if err := s.store.AddFailedMessage(failed); err != nil {
+		log.Printf("Ошибка при сохранении недоставленного сообщения: %v", err)
+	}
+}
+
+// retryDelay определяет, стоит ли повторять запрос после ошибки, и сколько перед этим ждать
+func retryDelay(err error, attempt int) (time.Duration, bool) {
+	backoff := time.Duration(1<<(attempt-1)) * time.Second
+
+	var apiErr *tgbotapi.Error
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.RetryAfter > 0:
+			return time.Duration(apiErr.RetryAfter) * time.Second, true
+		case apiErr.Code >= http.StatusInternalServerError:
+			return backoff, true
+		default:
+			return 0, false
+		}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return backoff, true
+	}
+	return 0, false
+}
+
+// chatIDOf возвращает чат, в который отправляется запрос, или 0, если запрос не относится к чату
+func chatIDOf(c tgbotapi.Chattable) int64 {
+	switch v := c.(type) {
+	case tgbotapi.MessageConfig:
+		return v.ChatID
+	case tgbotapi.CopyMessageConfig:
+		return v.ChatID
+	case tgbotapi.ForwardConfig:
+		return v.ChatID
+	case tgbotapi.PhotoConfig:
+		return v.ChatID
+	case tgbotapi.DocumentConfig:
+		return v.ChatID
+	case tgbotapi.InvoiceConfig:
+		return v.ChatID
+	case tgbotapi.EditMessageTextConfig:
+		return v.ChatID
+	case tgbotapi.EditMessageCaptionConfig:
+		return v.ChatID
+	case tgbotapi.EditMessageReplyMarkupConfig:
+		return v.ChatID
+	case tgbotapi.DeleteMessageConfig:
+		return v.ChatID
+	default:
+		return 0
+	}
+}
+
+// withoutFileContents заменяет содержимое файлов, сформированных в памяти, их именами, чтобы не сохранять его в базе
+func withoutFileContents(c tgbotapi.Chattable) tgbotapi.Chattable {
+	switch v := c.(type) {
+	case tgbotapi.DocumentConfig:
+		if file, ok := v.File.(tgbotapi.FileBytes); ok {
+			v.File = tgbotapi.FilePath(file.Name)
+		}
+		return v
+	case tgbotapi.PhotoConfig:
+		if file, ok := v.File.(tgbotapi.FileBytes); ok {
+			v.File = tgbotapi.FilePath(file.Name)
+		}
+		return v
+	default:
+		return c
+	}
+}
+
+// bucket - ограничитель скорости по алгоритму token bucket
+type bucket struct {
+	rate        float64 // Сколько токенов восстанавливается в секунду
+	burst       float64 // Максимальный запас токенов
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time // До этого момента токены не выдаются и не восстанавливаются
+}
+
+func newBucket(rate, burst float64) *bucket {
+	return &bucket{rate: rate, burst: burst, tokens: burst}
+}
+
+// reserve забирает токен и возвращает, сколько нужно подождать, пока он станет доступен.
+// Запас может уйти в минус: так следующие вызовы встают в очередь за уже выданными токенами.
+// Во время паузы токены выдаются в очередь, которая начинается с ее окончания.
+func (b *bucket) reserve(now time.Time) time.Duration {
+	at := now
+	if at.Before(b.pausedUntil) {
+		at = b.pausedUntil
+	}
+	b.refill(at)
+
+	b.tokens--
+	delay := at.Sub(now)
+	if b.tokens < 0 {
+		delay += time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	return delay
+}
+
+// pause останавливает выдачу токенов до until. Запас, восстановленный к now, сохраняется,
+// а за время паузы не пополняется.
+func (b *bucket) pause(now, until time.Time) {
+	if !until.After(b.pausedUntil) {
+		return
+	}
+	b.refill(now)
+	b.pausedUntil = until
+	if b.last.Before(until) {
+		b.last = until
+	}
+}
+
+// pausedFor возвращает, сколько осталось до окончания паузы
+func (b *bucket) pausedFor(now time.Time) time.Duration {
+	if now.Before(b.pausedUntil) {
+		return b.pausedUntil.Sub(now)
+	}
+	return 0
+}
+
+// refill пополняет запас токенов за время с последнего обращения до at
+func (b *bucket) refill(at time.Time) {
+	if !b.last.IsZero() && at.After(b.last) {
+		b.tokens = math.Min(b.burst, b.tokens+at.Sub(b.last).Seconds()*b.rate)
+	}
+	if at.After(b.last) {
+		b.last = at
+	}
+}
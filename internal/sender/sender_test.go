@@ -0,0 +1,247 @@
+package sender
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/ilokitv/botVPN/internal/config"
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+var testStart = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// fakeClock - часы, которые идут только во время ожидания отправителя
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// apiCall - запрос, дошедший до подмененного API
+type apiCall struct {
+	chatID int64
+	at     time.Time
+}
+
+// fakeAPI возвращает для каждого чата заданные ошибки по очереди, а затем успешные ответы
+type fakeAPI struct {
+	clock *fakeClock
+	errs  map[int64][]error
+	calls []apiCall
+}
+
+func (a *fakeAPI) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	chatID := chatIDOf(c)
+	a.calls = append(a.calls, apiCall{chatID: chatID, at: a.clock.Now()})
+	if errs := a.errs[chatID]; len(errs) > 0 {
+		a.errs[chatID] = errs[1:]
+		return &tgbotapi.APIResponse{Ok: false}, errs[0]
+	}
+	return &tgbotapi.APIResponse{Ok: true, Result: []byte(`{"message_id":1}`)}, nil
+}
+
+// fakeStore записывает недоставленные сообщения и пользователей, заблокировавших бота
+type fakeStore struct {
+	failed  []models.FailedMessage
+	blocked []int64
+}
+
+func (s *fakeStore) AddFailedMessage(message *models.FailedMessage) error {
+	s.failed = append(s.failed, *message)
+	return nil
+}
+
+func (s *fakeStore) MarkUserBotBlocked(telegramID int64) error {
+	s.blocked = append(s.blocked, telegramID)
+	return nil
+}
+
+func newTestSender(cfg config.SenderConfig) (*Sender, *fakeAPI, *fakeStore) {
+	clock := &fakeClock{now: testStart}
+	api := &fakeAPI{clock: clock, errs: make(map[int64][]error)}
+	store := &fakeStore{}
+	s := New(api, store, cfg)
+	s.now = clock.Now
+	s.sleep = clock.Sleep
+	return s, api, store
+}
+
+func floodError(seconds int) error {
+	return &tgbotapi.Error{Code: http.StatusTooManyRequests, Message: "Too Many Requests: retry after",
+		ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: seconds}}
+}
+
+func TestBucketReserve(t *testing.T) {
+	b := newBucket(2, 2)
+
+	delays := []time.Duration{
+		b.reserve(testStart),
+		b.reserve(testStart),
+		b.reserve(testStart),
+		b.reserve(testStart),
+	}
+	want := []time.Duration{0, 0, 500 * time.Millisecond, time.Second}
+	for i := range want {
+		if delays[i] != want[i] {
+			t.Errorf("reserve #%d = %s, want %s", i+1, delays[i], want[i])
+		}
+	}
+
+	// За 10 секунд простоя запас восстанавливается только до burst
+	if got := b.reserve(testStart.Add(10 * time.Second)); got != 0 {
+		t.Errorf("reserve after idle = %s, want 0", got)
+	}
+	if got := b.reserve(testStart.Add(10 * time.Second)); got != 0 {
+		t.Errorf("second reserve after idle = %s, want 0", got)
+	}
+	if got := b.reserve(testStart.Add(10 * time.Second)); got != 500*time.Millisecond {
+		t.Errorf("third reserve after idle = %s, want 500ms", got)
+	}
+}
+
+func TestBucketPause(t *testing.T) {
+	b := newBucket(1, 1)
+	b.reserve(testStart)
+
+	b.pause(testStart, testStart.Add(5*time.Second))
+	if got := b.pausedFor(testStart.Add(time.Second)); got != 4*time.Second {
+		t.Errorf("pausedFor() = %s, want 4s", got)
+	}
+
+	// Запас не пополняется во время паузы: после нее запросы идут с обычной скоростью
+	if got := b.reserve(testStart.Add(time.Second)); got != 5*time.Second {
+		t.Errorf("reserve during pause = %s, want 5s", got)
+	}
+	if got := b.reserve(testStart.Add(time.Second)); got != 6*time.Second {
+		t.Errorf("second reserve during pause = %s, want 6s", got)
+	}
+
+	// Более короткая пауза не сокращает уже действующую
+	b.pause(testStart.Add(2*time.Second), testStart.Add(3*time.Second))
+	if got := b.pausedFor(testStart.Add(2 * time.Second)); got != 3*time.Second {
+		t.Errorf("pausedFor() after shorter pause = %s, want 3s", got)
+	}
+	if got := b.pausedFor(testStart.Add(6 * time.Second)); got != 0 {
+		t.Errorf("pausedFor() after the pause = %s, want 0", got)
+	}
+}
+
+func TestRequestRetriesAfterFloodWait(t *testing.T) {
+	s, api, store := newTestSender(config.SenderConfig{MaxRetries: 3})
+	api.errs[100] = []error{floodError(3)}
+
+	if _, err := s.Send(tgbotapi.NewMessage(100, "text")); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+	if len(api.calls) != 2 || api.calls[1].at.Sub(api.calls[0].at) != 3*time.Second {
+		t.Errorf("calls = %+v, want a retry 3s after the first call", api.calls)
+	}
+	if len(store.failed) != 0 {
+		t.Errorf("failed messages = %+v, want none", store.failed)
+	}
+}
+
+func TestFloodWaitPausesOtherChats(t *testing.T) {
+	s, api, store := newTestSender(config.SenderConfig{MaxRetries: -1})
+	api.errs[100] = []error{floodError(5)}
+
+	// Запрос без повторов сохраняется как недоставленный, но пауза действует и после него
+	if _, err := s.Send(tgbotapi.NewMessage(100, "text")); err == nil {
+		t.Fatal("Send() to the limited chat succeeded, want an error")
+	}
+	if len(store.failed) != 1 || store.failed[0].ErrorCode != http.StatusTooManyRequests {
+		t.Errorf("failed messages = %+v, want the limited message", store.failed)
+	}
+
+	if _, err := s.Send(tgbotapi.NewMessage(200, "text")); err != nil {
+		t.Fatalf("Send() to another chat error: %v", err)
+	}
+	if _, err := s.Request(tgbotapi.NewCallback("query", "")); err != nil {
+		t.Fatalf("Request() without chat error: %v", err)
+	}
+
+	for _, call := range api.calls[1:] {
+		if call.at.Before(testStart.Add(5 * time.Second)) {
+			t.Errorf("call to chat %d at %s, before the flood wait ended", call.chatID, call.at.Sub(testStart))
+		}
+	}
+}
+
+func TestRequestRetriesServerAndNetworkErrors(t *testing.T) {
+	s, api, _ := newTestSender(config.SenderConfig{MaxRetries: 3})
+	api.errs[100] = []error{
+		&tgbotapi.Error{Code: http.StatusBadGateway, Message: "Bad Gateway"},
+		&net.OpError{Op: "dial", Err: errors.New("connection refused")},
+	}
+
+	if _, err := s.Send(tgbotapi.NewMessage(100, "text")); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+	if len(api.calls) != 3 {
+		t.Fatalf("calls = %d, want 3", len(api.calls))
+	}
+	// Задержка между повторами растет: 1s, затем 2s
+	if d := api.calls[1].at.Sub(api.calls[0].at); d != time.Second {
+		t.Errorf("first retry after %s, want 1s", d)
+	}
+	if d := api.calls[2].at.Sub(api.calls[1].at); d != 2*time.Second {
+		t.Errorf("second retry after %s, want 2s", d)
+	}
+}
+
+func TestForbiddenErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		chatID      int64
+		description string
+		wantBlocked bool
+	}{
+		{name: "user blocked the bot", chatID: 100, description: "Forbidden: bot was blocked by the user", wantBlocked: true},
+		{name: "user never started the bot", chatID: 100, description: "Forbidden: bot can't initiate conversation with a user"},
+		{name: "bot kicked from group", chatID: -100, description: "Forbidden: bot was kicked from the group chat"},
+		{name: "blocked text in a group", chatID: -100, description: "Forbidden: bot was blocked by the user"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, api, store := newTestSender(config.SenderConfig{MaxRetries: 3})
+			api.errs[tt.chatID] = []error{&tgbotapi.Error{Code: http.StatusForbidden, Message: tt.description}}
+
+			_, err := s.Send(tgbotapi.NewMessage(tt.chatID, "text"))
+			if err == nil {
+				t.Fatal("Send() succeeded, want an error")
+			}
+			if len(api.calls) != 1 {
+				t.Errorf("calls = %d, want no retries", len(api.calls))
+			}
+			if len(store.failed) != 1 {
+				t.Errorf("failed messages = %d, want 1", len(store.failed))
+			}
+
+			blocked := len(store.blocked) == 1 && store.blocked[0] == tt.chatID
+			if blocked != tt.wantBlocked || (!tt.wantBlocked && len(store.blocked) != 0) {
+				t.Errorf("blocked users = %v, want blocked %v", store.blocked, tt.wantBlocked)
+			}
+			if tt.chatID > 0 && IsBotBlocked(err) != tt.wantBlocked {
+				t.Errorf("IsBotBlocked() = %v, want %v", IsBotBlocked(err), tt.wantBlocked)
+			}
+		})
+	}
+}
@@ -0,0 +1,435 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ilokitv/botVPN/internal/database"
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+// Статусы подписки
+const (
+	StatusPending = "pending" // Место на сервере занято, конфигурация еще не выдана
+	StatusActive  = "active"
+	StatusPaused  = "paused"
+	StatusGrace   = "grace" // Срок истек, пир заблокирован, но конфигурация сохранена до конца льготного периода
+	StatusExpired = "expired"
+	StatusRevoked = "revoked"
+)
+
+// transitions - допустимые переходы между статусами подписки.
+// Истекшая и отозванная подписки больше не меняются.
+var transitions = map[string][]string{
+	StatusPending: {StatusActive, StatusRevoked},
+	StatusActive:  {StatusPaused, StatusGrace, StatusExpired, StatusRevoked},
+	StatusPaused:  {StatusActive, StatusRevoked},
+	StatusGrace:   {StatusActive, StatusExpired, StatusRevoked},
+}
+
+// defaultPeerTimeout - сколько ждать ответа сервера VPN на команды администратора
+const defaultPeerTimeout = 10 * time.Second
+
+var (
+	// ErrNoAvailableServer возвращается, если ни на одном сервере нет свободных мест
+	ErrNoAvailableServer = database.ErrNoAvailableServer
+	// ErrInvalidTransition возвращается при попытке недопустимой смены статуса
+	ErrInvalidTransition = errors.New("invalid subscription status transition")
+	// ErrPeerUnavailable возвращается, если сервер VPN не выполнил команду для пира подписки
+	ErrPeerUnavailable = errors.New("vpn server unavailable")
+	// ErrPeerTimeout возвращается, если сервер VPN не ответил за peerTimeout: команда продолжает выполняться в фоне
+	ErrPeerTimeout = errors.New("vpn server did not respond")
+)
+
+// Шаги выдачи подписки, на которых может отказать сервер VPN
+const (
+	OpServerSetup  = "server setup"
+	OpClientConfig = "client config"
+)
+
+// ProvisionError - ошибка сервера VPN при выдаче подписки
+type ProvisionError struct {
+	Op  string // OpServerSetup или OpClientConfig
+	Err error
+}
+
+func (e *ProvisionError) Error() string {
+	return e.Op + ": " + e.Err.Error()
+}
+
+func (e *ProvisionError) Unwrap() error {
+	return e.Err
+}
+
+// Store - хранилище, через которое сервис читает и изменяет подписки
+type Store interface {
+	database.ServerRepository
+	database.PlanRepository
+	database.SubscriptionRepository
+}
+
+// VPN управляет пирами подписок на серверах
+type VPN interface {
+	SetupServer(server *models.Server) error
+	CreateClientConfig(server *models.Server, clientName string) (string, error)
+	RevokeClientConfig(server *models.Server, configFilePath string) error
+	BlockClient(server *models.Server, configFilePath string) error
+	UnblockClient(server *models.Server, configFilePath string) error
+}
+
+// CanTransition проверяет, может ли подписка перейти из статуса from в статус to
+func CanTransition(from, to string) bool {
+	for _, status := range transitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscriptions управляет жизненным циклом подписок: pending -> active -> grace -> expired/revoked,
+// а также паузой и продлением. Смена статуса, счетчик клиентов сервера и платеж сохраняются в одной транзакции,
+// а команды серверу VPN выполняются так, чтобы при их ошибке подписка не оставалась в противоречивом состоянии.
+type Subscriptions struct {
+	store Store
+	vpn   VPN
+	now   func() time.Time // Источник текущего времени (подменяется в тестах)
+
+	peerTimeout time.Duration  // Сколько ждать ответа сервера VPN на команды администратора
+	background  sync.WaitGroup // Команды сервера VPN, продолжающиеся после peerTimeout
+}
+
+// NewSubscriptions создает сервис подписок
+func NewSubscriptions(store Store, vpn VPN) *Subscriptions {
+	return &Subscriptions{
+		store: store,
+		vpn:   vpn,
+		now:   time.Now,
+
+		peerTimeout: defaultPeerTimeout,
+	}
+}
+
+// ProvisionRequest описывает выдачу новой подписки
+type ProvisionRequest struct {
+	User       *models.User
+	Plan       *models.SubscriptionPlan
+	ClientName string          // Имя клиента в конфигурации WireGuard
+	IsTrial    bool            // Пробная подписка
+	Payment    *models.Payment // Платеж за подписку, сохраняется вместе с активацией (nil - без платежа)
+}
+
+// checkTransition возвращает ErrInvalidTransition, если переход subscription в статус to недопустим
+func checkTransition(subscription *models.Subscription, to string) error {
+	if !CanTransition(subscription.Status, to) {
+		return fmt.Errorf("%w: subscription #%d %s -> %s", ErrInvalidTransition, subscription.ID, subscription.Status, to)
+	}
+	return nil
+}
+
+// AvailableServer возвращает первый активный сервер со свободными местами или nil, если таких серверов нет.
+// Место окончательно занимается только при выдаче подписки.
+func (s *Subscriptions) AvailableServer() (*models.Server, error) {
+	servers, err := s.store.GetAllServers()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, server := range servers {
+		if server.IsActive && server.CurrentClients < server.MaxClients {
+			availableServer := server
+			return &availableServer, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Provision выдает новую подписку: занимает место на сервере (pending), создает конфигурацию клиента
+// и активирует подписку вместе с сохранением платежа. При ошибке место освобождается, а подписка отзывается.
+func (s *Subscriptions) Provision(req ProvisionRequest) (*models.Subscription, error) {
+	startDate := s.now()
+	subscription := &models.Subscription{
+		UserID:    req.User.ID,
+		PlanID:    req.Plan.ID,
+		StartDate: startDate,
+		EndDate:   startDate.AddDate(0, 0, req.Plan.Duration),
+		IsTrial:   req.IsTrial,
+	}
+
+	if err := s.store.ReserveSubscription(subscription, "Оформление подписки"); err != nil {
+		return nil, err
+	}
+
+	server, err := s.store.GetServerByID(subscription.ServerID)
+	if err != nil {
+		s.cancel(subscription, "Отмена: сервер не найден")
+		return nil, err
+	}
+
+	// Проверяем, что сервер правильно настроен
+	if err := s.vpn.SetupServer(server); err != nil {
+		s.cancel(subscription, "Отмена: не удалось настроить сервер")
+		return nil, &ProvisionError{Op: OpServerSetup, Err: err}
+	}
+
+	configPath, err := s.vpn.CreateClientConfig(server, req.ClientName)
+	if err != nil {
+		s.cancel(subscription, "Отмена: не удалось создать конфигурацию")
+		return nil, &ProvisionError{Op: OpClientConfig, Err: err}
+	}
+
+	if err := s.store.ActivateSubscription(subscription.ID, configPath, req.Payment); err != nil {
+		// Конфигурация выдана, но подписка не сохранена - удаляем пир, чтобы не оставлять бесплатный доступ
+		if revokeErr := s.vpn.RevokeClientConfig(server, configPath); revokeErr != nil {
+			log.Printf("Ошибка при отзыве конфигурации неактивированной подписки #%d: %v", subscription.ID, revokeErr)
+		}
+		s.cancel(subscription, "Отмена: не удалось активировать подписку")
+		return nil, err
+	}
+
+	subscription.Status = StatusActive
+	subscription.ConfigFilePath = configPath
+	return subscription, nil
+}
+
+// cancel отзывает подписку, которую не удалось выдать, и освобождает ее место на сервере
+func (s *Subscriptions) cancel(subscription *models.Subscription, reason string) {
+	if err := s.store.FinishSubscription(subscription.ID, StatusPending, StatusRevoked, reason); err != nil {
+		log.Printf("Ошибка при отмене подписки #%d: %v", subscription.ID, err)
+		return
+	}
+	subscription.Status = StatusRevoked
+}
+
+// Renew продлевает активную подписку или подписку в льготном периоде на days дней и сохраняет платеж.
+// Если подписка была в льготном периоде, пир разблокируется; при ошибке разблокировки подписка
+// остается продленной, а возвращается ErrPeerUnavailable. Возвращает статус подписки до продления.
+func (s *Subscriptions) Renew(subscriptionID int, days int, payment *models.Payment) (*models.Subscription, string, error) {
+	renewed, oldStatus, err := s.store.RenewSubscription(subscriptionID, days, s.now(), payment)
+	if err != nil {
+		return nil, oldStatus, err
+	}
+
+	// В льготном периоде пир заблокирован - разблокируем его, конфигурация остается прежней
+	if oldStatus == StatusGrace {
+		if err := s.withServer(renewed, s.vpn.UnblockClient); err != nil {
+			return renewed, oldStatus, fmt.Errorf("%w: %w", ErrPeerUnavailable, err)
+		}
+	}
+
+	return renewed, oldStatus, nil
+}
+
+// PlanChangeRequest описывает перевод подписки на другой план. Суммы указаны в копейках.
+type PlanChangeRequest struct {
+	Subscription *models.Subscription
+	Plan         *models.SubscriptionPlan
+	StartDate    time.Time       // Начало нового периода
+	EndDate      time.Time       // Окончание нового периода
+	Payment      *models.Payment // Платеж за доплату (nil - без платежа)
+	Refund       int64           // Излишек, возвращаемый на баланс (0 - без возврата)
+}
+
+// ChangePlan переводит активную подписку на другой план на том же пире. Платеж за доплату, смена плана
+// и возврат излишка на баланс сохраняются в одной транзакции: при ошибке подписка остается на прежнем плане,
+// а ни платеж, ни возврат не сохраняются.
+func (s *Subscriptions) ChangePlan(req PlanChangeRequest) error {
+	var refund *models.LedgerTransaction
+	if req.Refund > 0 {
+		refund = &models.LedgerTransaction{
+			UserID:      req.Subscription.UserID,
+			Type:        models.LedgerRefund,
+			Amount:      req.Refund,
+			Description: fmt.Sprintf("Перерасчет при переходе на план «%s»", req.Plan.Name),
+		}
+	}
+
+	err := s.store.ChangeSubscriptionPlan(req.Subscription.ID, req.Plan.ID, req.StartDate, req.EndDate, req.Payment, refund)
+	if err != nil {
+		return err
+	}
+
+	req.Subscription.PlanID = req.Plan.ID
+	req.Subscription.StartDate = req.StartDate
+	req.Subscription.EndDate = req.EndDate
+	req.Subscription.IsTrial = false
+	return nil
+}
+
+// Pause приостанавливает активную подписку и блокирует ее пир.
+// Если сервер VPN недоступен, пауза отменяется и возвращается ErrPeerUnavailable.
+func (s *Subscriptions) Pause(subscription *models.Subscription, reason string) error {
+	if err := checkTransition(subscription, StatusPaused); err != nil {
+		return err
+	}
+
+	server, err := s.store.GetServerByID(subscription.ServerID)
+	if err != nil {
+		return err
+	}
+
+	// Сначала меняем статус, чтобы повторное нажатие не заблокировало пир дважды
+	now := s.now()
+	if err := s.store.PauseSubscription(subscription.ID, now, reason); err != nil {
+		return err
+	}
+
+	if err := s.vpn.BlockClient(server, subscription.ConfigFilePath); err != nil {
+		// Возвращаем подписку в активное состояние
+		if _, resumeErr := s.store.ResumeSubscription(subscription.ID, now, "Отмена паузы: сервер VPN недоступен"); resumeErr != nil {
+			log.Printf("Ошибка при отмене паузы подписки #%d: %v", subscription.ID, resumeErr)
+		}
+		return fmt.Errorf("%w: %w", ErrPeerUnavailable, err)
+	}
+
+	subscription.Status = StatusPaused
+	subscription.PausedAt = &now
+	return nil
+}
+
+// Resume разблокирует пир приостановленной подписки и возобновляет ее, продлевая срок на время паузы до resumedAt
+func (s *Subscriptions) Resume(subscription *models.Subscription, resumedAt time.Time, reason string) (*models.Subscription, error) {
+	if err := checkTransition(subscription, StatusActive); err != nil {
+		return nil, err
+	}
+
+	if err := s.withServer(subscription, s.vpn.UnblockClient); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPeerUnavailable, err)
+	}
+
+	return s.store.ResumeSubscription(subscription.ID, resumedAt, reason)
+}
+
+// EnterGrace блокирует пир истекшей подписки и переводит ее в льготный период на graceDays дней.
+// Пир блокируется до смены статуса, чтобы при недоступности сервера повторить попытку позже.
+func (s *Subscriptions) EnterGrace(subscription *models.Subscription, graceDays int) error {
+	if err := checkTransition(subscription, StatusGrace); err != nil {
+		return err
+	}
+
+	if err := s.withServer(subscription, s.vpn.BlockClient); err != nil {
+		return err
+	}
+
+	err := s.store.TransitionSubscriptionStatus(subscription.ID, subscription.Status, StatusGrace,
+		fmt.Sprintf("Срок действия истек, льготный период %d дней", graceDays))
+	if err != nil {
+		return err
+	}
+
+	subscription.Status = StatusGrace
+	return nil
+}
+
+// Expire отзывает конфигурацию VPN истекшей подписки, помечает подписку истекшей и освобождает место на сервере.
+// Конфигурация отзывается до смены статуса: если сервер VPN недоступен, подписка остается в прежнем статусе,
+// возвращается ErrPeerUnavailable, и планировщик повторяет попытку при следующей проверке.
+func (s *Subscriptions) Expire(subscription *models.Subscription, reason string) error {
+	if err := checkTransition(subscription, StatusExpired); err != nil {
+		return err
+	}
+
+	if err := s.withServer(subscription, s.vpn.RevokeClientConfig); err != nil {
+		return fmt.Errorf("%w: %w", ErrPeerUnavailable, err)
+	}
+
+	if err := s.store.FinishSubscription(subscription.ID, subscription.Status, StatusExpired, reason); err != nil {
+		return err
+	}
+	subscription.Status = StatusExpired
+	return nil
+}
+
+// Revoke отзывает конфигурацию подписки на сервере и помечает подписку отозванной.
+// Отозванной подписка считается только после удаления пира: если сервер VPN недоступен,
+// подписка остается в прежнем статусе и возвращается ErrPeerUnavailable. Если сервер не ответил
+// за peerTimeout, возвращается ErrPeerTimeout, а подписка будет помечена отозванной,
+// когда команда успешно завершится в фоне.
+func (s *Subscriptions) Revoke(subscription *models.Subscription, reason string) error {
+	if err := checkTransition(subscription, StatusRevoked); err != nil {
+		return err
+	}
+
+	subscriptionID, fromStatus := subscription.ID, subscription.Status
+	finish := func() error {
+		return s.store.FinishSubscription(subscriptionID, fromStatus, StatusRevoked, reason)
+	}
+	if err := s.peerCommand(subscription, s.vpn.RevokeClientConfig, finish); err != nil {
+		return err
+	}
+	subscription.Status = StatusRevoked
+	return nil
+}
+
+// Block блокирует пир подписки по решению администратора. Статус подписки не меняется.
+// Если сервер VPN недоступен, возвращается ErrPeerUnavailable, если не ответил за peerTimeout - ErrPeerTimeout.
+func (s *Subscriptions) Block(subscription *models.Subscription) error {
+	return s.peerCommand(subscription, s.vpn.BlockClient, nil)
+}
+
+// Unblock разблокирует пир подписки, заблокированный администратором. Статус подписки не меняется.
+// Если сервер VPN недоступен, возвращается ErrPeerUnavailable, если не ответил за peerTimeout - ErrPeerTimeout.
+func (s *Subscriptions) Unblock(subscription *models.Subscription) error {
+	return s.peerCommand(subscription, s.vpn.UnblockClient, nil)
+}
+
+// peerCommand выполняет команду сервера VPN для пира подписки, ожидая ответа не дольше peerTimeout,
+// и после успешной команды вызывает finish (nil - ничего не делать). Команда, не уложившаяся в peerTimeout,
+// продолжает выполняться в фоне и сама вызывает finish, когда завершится успешно, а возвращается ErrPeerTimeout.
+func (s *Subscriptions) peerCommand(subscription *models.Subscription, command func(server *models.Server, configFilePath string) error, finish func() error) error {
+	done := make(chan error)
+	abandoned := make(chan struct{})
+	subscriptionID := subscription.ID
+
+	s.background.Add(1)
+	go func() {
+		defer s.background.Done()
+		err := s.withServer(subscription, command)
+		select {
+		case done <- err:
+			return
+		case <-abandoned:
+		}
+
+		// Ответа уже никто не ждет: доводим операцию до конца здесь
+		if err != nil {
+			log.Printf("Команда сервера VPN для подписки #%d завершилась ошибкой после таймаута: %v", subscriptionID, err)
+			return
+		}
+		log.Printf("Команда сервера VPN для подписки #%d выполнена после таймаута", subscriptionID)
+		if finish != nil {
+			if err := finish(); err != nil {
+				log.Printf("Ошибка при обновлении подписки #%d после команды сервера VPN: %v", subscriptionID, err)
+			}
+		}
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			log.Printf("Ошибка при выполнении команды сервера VPN для подписки #%d: %v", subscriptionID, err)
+			return fmt.Errorf("%w: %w", ErrPeerUnavailable, err)
+		}
+		if finish != nil {
+			return finish()
+		}
+		return nil
+	case <-time.After(s.peerTimeout):
+		close(abandoned)
+		log.Printf("Сервер VPN не ответил за %s на команду для подписки #%d, команда продолжает выполняться в фоне", s.peerTimeout, subscriptionID)
+		return ErrPeerTimeout
+	}
+}
+
+// withServer выполняет команду сервера VPN для пира подписки
+func (s *Subscriptions) withServer(subscription *models.Subscription, command func(server *models.Server, configFilePath string) error) error {
+	server, err := s.store.GetServerByID(subscription.ServerID)
+	if err != nil {
+		return err
+	}
+	return command(server, subscription.ConfigFilePath)
+}
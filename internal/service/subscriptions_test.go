@@ -0,0 +1,622 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ilokitv/botVPN/internal/database"
+	"github.com/ilokitv/botVPN/internal/models"
+)
+
+var testNow = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// fakeStore хранит серверы и подписки в памяти. Методы, которые сервис не вызывает,
+// достаются от встроенных пустых интерфейсов и при вызове завершают тест паникой.
+type fakeStore struct {
+	database.ServerRepository
+	database.PlanRepository
+	database.SubscriptionRepository
+
+	servers       map[int]*models.Server
+	subscriptions map[int]*models.Subscription
+	history       []string // Переходы в формате "#ID from -> to"
+	activateErr   error    // Ошибка ActivateSubscription
+	changePlanErr error    // Ошибка ChangeSubscriptionPlan
+
+	payments []*models.Payment           // Сохраненные платежи
+	refunds  []*models.LedgerTransaction // Проведенные возвраты на баланс
+}
+
+func newFakeStore(servers ...*models.Server) *fakeStore {
+	store := &fakeStore{servers: make(map[int]*models.Server), subscriptions: make(map[int]*models.Subscription)}
+	for _, server := range servers {
+		store.servers[server.ID] = server
+	}
+	return store
+}
+
+// addSubscription добавляет подписку, занимающую место на сервере #1
+func (f *fakeStore) addSubscription(status string) *models.Subscription {
+	subscription := &models.Subscription{ID: len(f.subscriptions) + 1, UserID: 1, ServerID: 1, PlanID: 1,
+		StartDate: testNow.AddDate(0, 0, -30), EndDate: testNow, Status: status, ConfigFilePath: "user_1.conf"}
+	f.subscriptions[subscription.ID] = subscription
+	f.servers[1].CurrentClients++
+	copied := *subscription
+	return &copied
+}
+
+func (f *fakeStore) change(subscriptionID int, from, to string) error {
+	subscription, ok := f.subscriptions[subscriptionID]
+	if !ok || subscription.Status != from {
+		return fmt.Errorf("failed to change subscription status: %w", sql.ErrNoRows)
+	}
+	subscription.Status = to
+	f.history = append(f.history, fmt.Sprintf("#%d %s -> %s", subscriptionID, from, to))
+	return nil
+}
+
+func (f *fakeStore) status(subscriptionID int) string {
+	return f.subscriptions[subscriptionID].Status
+}
+
+func (f *fakeStore) GetServerByID(id int) (*models.Server, error) {
+	server, ok := f.servers[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *server
+	return &copied, nil
+}
+
+func (f *fakeStore) GetAllServers() ([]models.Server, error) {
+	var servers []models.Server
+	for _, server := range f.servers {
+		servers = append(servers, *server)
+	}
+	return servers, nil
+}
+
+func (f *fakeStore) ReserveSubscription(subscription *models.Subscription, reason string) error {
+	server, ok := f.servers[1]
+	if !ok || server.CurrentClients >= server.MaxClients {
+		return database.ErrNoAvailableServer
+	}
+	server.CurrentClients++
+
+	subscription.ID = len(f.subscriptions) + 1
+	subscription.ServerID = server.ID
+	subscription.Status = StatusPending
+	copied := *subscription
+	f.subscriptions[subscription.ID] = &copied
+	f.history = append(f.history, fmt.Sprintf("#%d  -> %s", subscription.ID, StatusPending))
+	return nil
+}
+
+func (f *fakeStore) ActivateSubscription(subscriptionID int, configFilePath string, payment *models.Payment) error {
+	if f.activateErr != nil {
+		return f.activateErr
+	}
+	if err := f.change(subscriptionID, StatusPending, StatusActive); err != nil {
+		return err
+	}
+	f.subscriptions[subscriptionID].ConfigFilePath = configFilePath
+	return nil
+}
+
+func (f *fakeStore) FinishSubscription(subscriptionID int, fromStatus, toStatus, reason string) error {
+	if err := f.change(subscriptionID, fromStatus, toStatus); err != nil {
+		return err
+	}
+	f.servers[f.subscriptions[subscriptionID].ServerID].CurrentClients--
+	return nil
+}
+
+func (f *fakeStore) TransitionSubscriptionStatus(subscriptionID int, fromStatus, toStatus, reason string) error {
+	return f.change(subscriptionID, fromStatus, toStatus)
+}
+
+func (f *fakeStore) PauseSubscription(subscriptionID int, pausedAt time.Time, reason string) error {
+	if err := f.change(subscriptionID, StatusActive, StatusPaused); err != nil {
+		return err
+	}
+	f.subscriptions[subscriptionID].PausedAt = &pausedAt
+	return nil
+}
+
+func (f *fakeStore) ResumeSubscription(subscriptionID int, resumedAt time.Time, reason string) (*models.Subscription, error) {
+	if err := f.change(subscriptionID, StatusPaused, StatusActive); err != nil {
+		return nil, err
+	}
+	subscription := f.subscriptions[subscriptionID]
+	subscription.EndDate = subscription.EndDate.Add(resumedAt.Sub(*subscription.PausedAt))
+	subscription.PausedAt = nil
+	copied := *subscription
+	return &copied, nil
+}
+
+func (f *fakeStore) RenewSubscription(subscriptionID int, days int, now time.Time, payment *models.Payment) (*models.Subscription, string, error) {
+	subscription := f.subscriptions[subscriptionID]
+	oldStatus := subscription.Status
+	if err := f.change(subscriptionID, oldStatus, StatusActive); err != nil || (oldStatus != StatusActive && oldStatus != StatusGrace) {
+		return nil, oldStatus, fmt.Errorf("failed to renew subscription: %w", sql.ErrNoRows)
+	}
+	if subscription.EndDate.Before(now) {
+		subscription.EndDate = now
+	}
+	subscription.EndDate = subscription.EndDate.AddDate(0, 0, days)
+	copied := *subscription
+	return &copied, oldStatus, nil
+}
+
+// ChangeSubscriptionPlan сохраняет смену плана, платеж и возврат только вместе, как транзакция базы данных
+func (f *fakeStore) ChangeSubscriptionPlan(subscriptionID, planID int, startDate, endDate time.Time, payment *models.Payment, refund *models.LedgerTransaction) error {
+	subscription := f.subscriptions[subscriptionID]
+	if f.changePlanErr != nil {
+		return f.changePlanErr
+	}
+	if subscription.Status != StatusActive {
+		return fmt.Errorf("failed to change subscription plan: %w", sql.ErrNoRows)
+	}
+	subscription.PlanID, subscription.StartDate, subscription.EndDate = planID, startDate, endDate
+	if payment != nil {
+		f.payments = append(f.payments, payment)
+	}
+	if refund != nil {
+		f.refunds = append(f.refunds, refund)
+	}
+	return nil
+}
+
+// fakeVPN записывает команды серверу VPN и возвращает заданные для них ошибки
+type fakeVPN struct {
+	mu    sync.Mutex
+	calls []string         // Команды в формате "команда путь"
+	errs  map[string]error // Ошибки команд по названию
+	hang  chan struct{}    // Если задан, команды ждут его закрытия
+}
+
+func (v *fakeVPN) run(command, path string) error {
+	if v.hang != nil {
+		<-v.hang
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.calls = append(v.calls, command+" "+path)
+	return v.errs[command]
+}
+
+func (v *fakeVPN) commands() []string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return append([]string(nil), v.calls...)
+}
+
+func (v *fakeVPN) SetupServer(server *models.Server) error {
+	return v.run("setup", server.IP)
+}
+
+func (v *fakeVPN) CreateClientConfig(server *models.Server, clientName string) (string, error) {
+	if err := v.run("create", clientName); err != nil {
+		return "", err
+	}
+	return clientName + ".conf", nil
+}
+
+func (v *fakeVPN) RevokeClientConfig(server *models.Server, configFilePath string) error {
+	return v.run("revoke", configFilePath)
+}
+
+func (v *fakeVPN) BlockClient(server *models.Server, configFilePath string) error {
+	return v.run("block", configFilePath)
+}
+
+func (v *fakeVPN) UnblockClient(server *models.Server, configFilePath string) error {
+	return v.run("unblock", configFilePath)
+}
+
+// newTestSubscriptions создает сервис поверх сервера #1 на maxClients мест и часов, остановленных на testNow
+func newTestSubscriptions(maxClients int) (*Subscriptions, *fakeStore, *fakeVPN) {
+	store := newFakeStore(&models.Server{ID: 1, IP: "10.0.0.1", MaxClients: maxClients, IsActive: true})
+	vpn := &fakeVPN{errs: make(map[string]error)}
+	s := NewSubscriptions(store, vpn)
+	s.now = func() time.Time { return testNow }
+	return s, store, vpn
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{StatusPending, StatusActive, true},
+		{StatusPending, StatusRevoked, true},
+		{StatusActive, StatusPaused, true},
+		{StatusActive, StatusGrace, true},
+		{StatusActive, StatusExpired, true},
+		{StatusActive, StatusRevoked, true},
+		{StatusPaused, StatusActive, true},
+		{StatusPaused, StatusRevoked, true},
+		{StatusGrace, StatusActive, true},
+		{StatusGrace, StatusExpired, true},
+		{StatusGrace, StatusRevoked, true},
+
+		{StatusPending, StatusPaused, false},
+		{StatusPending, StatusExpired, false},
+		{StatusActive, StatusPending, false},
+		{StatusActive, StatusActive, false},
+		{StatusPaused, StatusGrace, false},
+		{StatusPaused, StatusExpired, false},
+		{StatusGrace, StatusPaused, false},
+		{StatusExpired, StatusActive, false},
+		{StatusExpired, StatusRevoked, false},
+		{StatusRevoked, StatusActive, false},
+		{"", StatusActive, false},
+	}
+
+	for _, tt := range tests {
+		if got := CanTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransition(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestProvision(t *testing.T) {
+	s, store, vpn := newTestSubscriptions(1)
+	req := ProvisionRequest{User: &models.User{ID: 1}, Plan: &models.SubscriptionPlan{ID: 1, Duration: 30}, ClientName: "user_1"}
+
+	subscription, err := s.Provision(req)
+	if err != nil {
+		t.Fatalf("Provision() error: %v", err)
+	}
+	if subscription.Status != StatusActive || subscription.ConfigFilePath != "user_1.conf" ||
+		!subscription.StartDate.Equal(testNow) || !subscription.EndDate.Equal(testNow.AddDate(0, 0, 30)) {
+		t.Errorf("Provision() = %+v", subscription)
+	}
+	if want := []string{"#1  -> pending", "#1 pending -> active"}; !equalStrings(store.history, want) {
+		t.Errorf("history = %q, want %q", store.history, want)
+	}
+	if want := []string{"setup 10.0.0.1", "create user_1"}; !equalStrings(vpn.commands(), want) {
+		t.Errorf("VPN commands = %q, want %q", vpn.commands(), want)
+	}
+
+	if _, err := s.Provision(req); !errors.Is(err, ErrNoAvailableServer) {
+		t.Errorf("Provision() on a full server error = %v, want ErrNoAvailableServer", err)
+	}
+}
+
+func TestProvisionRollback(t *testing.T) {
+	activateErr := errors.New("database is locked")
+
+	tests := []struct {
+		name         string
+		vpnErrs      map[string]error
+		activateErr  error
+		wantOp       string
+		wantCommands []string
+	}{
+		{
+			name:         "server setup fails",
+			vpnErrs:      map[string]error{"setup": errors.New("ssh: connection refused")},
+			wantOp:       OpServerSetup,
+			wantCommands: []string{"setup 10.0.0.1"},
+		},
+		{
+			name:         "client config fails",
+			vpnErrs:      map[string]error{"create": errors.New("wg: no free addresses")},
+			wantOp:       OpClientConfig,
+			wantCommands: []string{"setup 10.0.0.1", "create user_1"},
+		},
+		{
+			name:         "activation fails",
+			activateErr:  activateErr,
+			wantCommands: []string{"setup 10.0.0.1", "create user_1", "revoke user_1.conf"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, store, vpn := newTestSubscriptions(1)
+			vpn.errs = tt.vpnErrs
+			store.activateErr = tt.activateErr
+
+			_, err := s.Provision(ProvisionRequest{User: &models.User{ID: 1}, Plan: &models.SubscriptionPlan{ID: 1, Duration: 30}, ClientName: "user_1"})
+
+			var provisionErr *ProvisionError
+			switch {
+			case tt.wantOp != "":
+				if !errors.As(err, &provisionErr) || provisionErr.Op != tt.wantOp {
+					t.Errorf("Provision() error = %v, want ProvisionError %q", err, tt.wantOp)
+				}
+			case !errors.Is(err, tt.activateErr):
+				t.Errorf("Provision() error = %v, want %v", err, tt.activateErr)
+			}
+
+			if want := []string{"#1  -> pending", "#1 pending -> revoked"}; !equalStrings(store.history, want) {
+				t.Errorf("history = %q, want %q", store.history, want)
+			}
+			if store.servers[1].CurrentClients != 0 {
+				t.Errorf("server slot was not released: %d clients", store.servers[1].CurrentClients)
+			}
+			if !equalStrings(vpn.commands(), tt.wantCommands) {
+				t.Errorf("VPN commands = %q, want %q", vpn.commands(), tt.wantCommands)
+			}
+		})
+	}
+}
+
+func TestPauseAndResume(t *testing.T) {
+	s, store, vpn := newTestSubscriptions(10)
+	subscription := store.addSubscription(StatusActive)
+
+	if err := s.Pause(subscription, "test"); err != nil {
+		t.Fatalf("Pause() error: %v", err)
+	}
+	if subscription.Status != StatusPaused || subscription.PausedAt == nil || !subscription.PausedAt.Equal(testNow) {
+		t.Errorf("Pause() = %+v", subscription)
+	}
+	if err := s.Pause(subscription, "test"); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Pause() twice error = %v, want ErrInvalidTransition", err)
+	}
+
+	resumedAt := testNow.Add(48 * time.Hour)
+	resumed, err := s.Resume(subscription, resumedAt, "test")
+	if err != nil {
+		t.Fatalf("Resume() error: %v", err)
+	}
+	if want := testNow.Add(48 * time.Hour); resumed.Status != StatusActive || !resumed.EndDate.Equal(want) {
+		t.Errorf("Resume() = %+v, want active until %s", resumed, want)
+	}
+	if want := []string{"block user_1.conf", "unblock user_1.conf"}; !equalStrings(vpn.commands(), want) {
+		t.Errorf("VPN commands = %q, want %q", vpn.commands(), want)
+	}
+}
+
+func TestPauseRollsBackWhenPeerUnavailable(t *testing.T) {
+	s, store, vpn := newTestSubscriptions(10)
+	subscription := store.addSubscription(StatusActive)
+	vpn.errs["block"] = errors.New("ssh: connection refused")
+
+	if err := s.Pause(subscription, "test"); !errors.Is(err, ErrPeerUnavailable) {
+		t.Fatalf("Pause() error = %v, want ErrPeerUnavailable", err)
+	}
+	if subscription.Status != StatusActive || store.status(subscription.ID) != StatusActive {
+		t.Errorf("subscription status = %s, stored %s; want active", subscription.Status, store.status(subscription.ID))
+	}
+}
+
+func TestResumeKeepsPauseWhenPeerUnavailable(t *testing.T) {
+	s, store, vpn := newTestSubscriptions(10)
+	subscription := store.addSubscription(StatusActive)
+	if err := s.Pause(subscription, "test"); err != nil {
+		t.Fatalf("Pause() error: %v", err)
+	}
+	vpn.errs["unblock"] = errors.New("ssh: connection refused")
+
+	if _, err := s.Resume(subscription, testNow, "test"); !errors.Is(err, ErrPeerUnavailable) {
+		t.Fatalf("Resume() error = %v, want ErrPeerUnavailable", err)
+	}
+	if store.status(subscription.ID) != StatusPaused {
+		t.Errorf("stored status = %s, want paused", store.status(subscription.ID))
+	}
+}
+
+func TestEnterGraceAndExpire(t *testing.T) {
+	s, store, vpn := newTestSubscriptions(10)
+	subscription := store.addSubscription(StatusActive)
+
+	if err := s.EnterGrace(subscription, 3); err != nil {
+		t.Fatalf("EnterGrace() error: %v", err)
+	}
+	if err := s.Expire(subscription, "test"); err != nil {
+		t.Fatalf("Expire() error: %v", err)
+	}
+	if subscription.Status != StatusExpired || store.status(subscription.ID) != StatusExpired {
+		t.Errorf("subscription status = %s, stored %s; want expired", subscription.Status, store.status(subscription.ID))
+	}
+	if store.servers[1].CurrentClients != 0 {
+		t.Errorf("server slot was not released: %d clients", store.servers[1].CurrentClients)
+	}
+	if want := []string{"block user_1.conf", "revoke user_1.conf"}; !equalStrings(vpn.commands(), want) {
+		t.Errorf("VPN commands = %q, want %q", vpn.commands(), want)
+	}
+	if err := s.Expire(subscription, "test"); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expire() twice error = %v, want ErrInvalidTransition", err)
+	}
+}
+
+// Пир блокируется или удаляется до смены статуса, поэтому при недоступном сервере
+// подписка остается в прежнем статусе и планировщик повторяет попытку
+func TestPeerUnavailableKeepsStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  string
+		command string
+		run     func(s *Subscriptions, subscription *models.Subscription) error
+	}{
+		{name: "enter grace", status: StatusActive, command: "block", run: func(s *Subscriptions, subscription *models.Subscription) error {
+			return s.EnterGrace(subscription, 3)
+		}},
+		{name: "expire", status: StatusGrace, command: "revoke", run: func(s *Subscriptions, subscription *models.Subscription) error {
+			return s.Expire(subscription, "test")
+		}},
+		{name: "revoke", status: StatusActive, command: "revoke", run: func(s *Subscriptions, subscription *models.Subscription) error {
+			return s.Revoke(subscription, "test")
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, store, vpn := newTestSubscriptions(10)
+			subscription := store.addSubscription(tt.status)
+			vpn.errs[tt.command] = errors.New("ssh: connection refused")
+
+			if err := tt.run(s, subscription); err == nil {
+				t.Fatal("expected an error")
+			}
+			if subscription.Status != tt.status || store.status(subscription.ID) != tt.status {
+				t.Errorf("subscription status = %s, stored %s; want %s", subscription.Status, store.status(subscription.ID), tt.status)
+			}
+			if store.servers[1].CurrentClients != 1 {
+				t.Errorf("server slot was released: %d clients", store.servers[1].CurrentClients)
+			}
+
+			// Сервер снова доступен: повторная попытка завершается успешно
+			delete(vpn.errs, tt.command)
+			if err := tt.run(s, subscription); err != nil {
+				t.Errorf("retry error: %v", err)
+			}
+		})
+	}
+}
+
+func TestRevoke(t *testing.T) {
+	s, store, vpn := newTestSubscriptions(10)
+	subscription := store.addSubscription(StatusPaused)
+
+	if err := s.Revoke(subscription, "test"); err != nil {
+		t.Fatalf("Revoke() error: %v", err)
+	}
+	if store.status(subscription.ID) != StatusRevoked || store.servers[1].CurrentClients != 0 {
+		t.Errorf("stored status = %s, %d clients; want revoked and a free slot", store.status(subscription.ID), store.servers[1].CurrentClients)
+	}
+	if want := []string{"revoke user_1.conf"}; !equalStrings(vpn.commands(), want) {
+		t.Errorf("VPN commands = %q, want %q", vpn.commands(), want)
+	}
+	if err := s.Revoke(subscription, "test"); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Revoke() twice error = %v, want ErrInvalidTransition", err)
+	}
+}
+
+func TestRevokeTimeout(t *testing.T) {
+	tests := []struct {
+		name       string
+		commandErr error
+		wantStatus string
+	}{
+		{name: "slow command succeeds", wantStatus: StatusRevoked},
+		{name: "slow command fails", commandErr: errors.New("ssh: connection reset"), wantStatus: StatusActive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, store, vpn := newTestSubscriptions(10)
+			s.peerTimeout = 10 * time.Millisecond
+			vpn.hang = make(chan struct{})
+			vpn.errs["revoke"] = tt.commandErr
+			subscription := store.addSubscription(StatusActive)
+
+			err := s.Revoke(subscription, "test")
+			if !errors.Is(err, ErrPeerTimeout) || errors.Is(err, ErrPeerUnavailable) {
+				t.Fatalf("Revoke() error = %v, want ErrPeerTimeout only", err)
+			}
+			if subscription.Status != StatusActive {
+				t.Errorf("subscription status after timeout = %s, want active", subscription.Status)
+			}
+
+			// Команда завершается уже после того, как Revoke вернул таймаут
+			close(vpn.hang)
+			s.background.Wait()
+
+			if got := store.status(subscription.ID); got != tt.wantStatus {
+				t.Errorf("stored status = %s, want %s", got, tt.wantStatus)
+			}
+			wantClients := 1
+			if tt.wantStatus == StatusRevoked {
+				wantClients = 0
+			}
+			if store.servers[1].CurrentClients != wantClients {
+				t.Errorf("server clients = %d, want %d", store.servers[1].CurrentClients, wantClients)
+			}
+		})
+	}
+}
+
+func TestBlockAndUnblock(t *testing.T) {
+	s, store, vpn := newTestSubscriptions(10)
+	subscription := store.addSubscription(StatusActive)
+
+	if err := s.Block(subscription); err != nil {
+		t.Fatalf("Block() error: %v", err)
+	}
+	vpn.errs["unblock"] = errors.New("ssh: connection refused")
+	if err := s.Unblock(subscription); !errors.Is(err, ErrPeerUnavailable) || errors.Is(err, ErrPeerTimeout) {
+		t.Errorf("Unblock() error = %v, want ErrPeerUnavailable without timeout", err)
+	}
+	if want := []string{"block user_1.conf", "unblock user_1.conf"}; !equalStrings(vpn.commands(), want) {
+		t.Errorf("VPN commands = %q, want %q", vpn.commands(), want)
+	}
+	if store.status(subscription.ID) != StatusActive || len(store.history) != 0 {
+		t.Errorf("block changed the subscription status: %q", store.history)
+	}
+}
+
+func TestRenewFromGraceUnblocksPeer(t *testing.T) {
+	s, store, vpn := newTestSubscriptions(10)
+	subscription := store.addSubscription(StatusGrace)
+
+	renewed, oldStatus, err := s.Renew(subscription.ID, 30, nil)
+	if err != nil {
+		t.Fatalf("Renew() error: %v", err)
+	}
+	if want := testNow.AddDate(0, 0, 30); oldStatus != StatusGrace || renewed.Status != StatusActive || !renewed.EndDate.Equal(want) {
+		t.Errorf("Renew() = %+v, %q; want active until %s", renewed, oldStatus, want)
+	}
+	if want := []string{"unblock user_1.conf"}; !equalStrings(vpn.commands(), want) {
+		t.Errorf("VPN commands = %q, want %q", vpn.commands(), want)
+	}
+}
+
+func TestChangePlan(t *testing.T) {
+	s, store, _ := newTestSubscriptions(10)
+	subscription := store.addSubscription(StatusActive)
+	plan := &models.SubscriptionPlan{ID: 2, Name: "Год", Duration: 365}
+	payment := &models.Payment{UserID: 1, Amount: 50000, Currency: models.LedgerCurrency}
+	req := PlanChangeRequest{
+		Subscription: subscription,
+		Plan:         plan,
+		StartDate:    testNow,
+		EndDate:      testNow.AddDate(0, 0, plan.Duration),
+		Payment:      payment,
+		Refund:       1500,
+	}
+
+	// Если план сменить не удалось, ни платеж, ни возврат не сохраняются, а подписка остается прежней
+	store.changePlanErr = errors.New("database is locked")
+	if err := s.ChangePlan(req); !errors.Is(err, store.changePlanErr) {
+		t.Fatalf("ChangePlan() error = %v, want %v", err, store.changePlanErr)
+	}
+	if subscription.PlanID != 1 || len(store.payments) != 0 || len(store.refunds) != 0 {
+		t.Errorf("failed change left plan #%d, %d payments, %d refunds; want plan #1 and nothing saved",
+			subscription.PlanID, len(store.payments), len(store.refunds))
+	}
+
+	store.changePlanErr = nil
+	if err := s.ChangePlan(req); err != nil {
+		t.Fatalf("ChangePlan() error: %v", err)
+	}
+	if subscription.PlanID != plan.ID || !subscription.EndDate.Equal(req.EndDate) {
+		t.Errorf("subscription = %+v, want plan #%d until %s", subscription, plan.ID, req.EndDate)
+	}
+	if len(store.payments) != 1 || store.payments[0] != payment {
+		t.Errorf("payments = %+v, want the plan change payment", store.payments)
+	}
+	if len(store.refunds) != 1 || store.refunds[0].Type != models.LedgerRefund || store.refunds[0].Amount != 1500 {
+		t.Errorf("refunds = %+v, want a refund of 1500", store.refunds)
+	}
+}
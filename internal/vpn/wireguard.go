@@ -3,6 +3,7 @@ package vpn
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -11,8 +12,10 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"golang.org/x/crypto/curve25519"
 	"golang.org/x/crypto/ssh"
 
 	"github.com/ilokitv/botVPN/internal/models"
@@ -20,7 +23,17 @@ import (
 
 // WireguardManager управляет VPN сервером Wireguard
 type WireguardManager struct {
-	ConfigDir string // Директория для хранения файлов конфигурации
+	ConfigDir   string   // Директория для хранения файлов конфигурации
+	serverLocks sync.Map // Блокировки серверов: изменения wg0.conf на одном сервере выполняются по очереди
+}
+
+// lockServer захватывает блокировку сервера и возвращает функцию для ее освобождения
+func (wg *WireguardManager) lockServer(server *models.Server) func() {
+	key := fmt.Sprintf("%s:%d", server.IP, server.Port)
+	value, _ := wg.serverLocks.LoadOrStore(key, &sync.Mutex{})
+	mu := value.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
 }
 
 // NewWireguardManager создает нового менеджера Wireguard
@@ -42,6 +55,9 @@ func NewWireguardManager(configDir string) *WireguardManager {
 func (wg *WireguardManager) SetupServer(server *models.Server) error {
 	log.Printf("Начинаю настройку сервера %s:%d", server.IP, server.Port)
 
+	unlock := wg.lockServer(server)
+	defer unlock()
+
 	// Устанавливаем соединение SSH с сервером с таймаутом
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -106,6 +122,10 @@ func (wg *WireguardManager) SetupServer(server *models.Server) error {
 
 // CreateClientConfig создает конфигурацию для нового клиента
 func (wg *WireguardManager) CreateClientConfig(server *models.Server, clientName string) (string, error) {
+	// Выбор свободного IP и добавление пира в wg0.conf не должны пересекаться с другими изменениями сервера
+	unlock := wg.lockServer(server)
+	defer unlock()
+
 	// Устанавливаем соединение SSH с сервером
 	client, err := connectToServer(server)
 	if err != nil {
@@ -154,6 +174,9 @@ func (wg *WireguardManager) CreateClientConfig(server *models.Server, clientName
 
 // RemoveClient удаляет клиента с сервера
 func (wg *WireguardManager) RemoveClient(server *models.Server, clientName string) error {
+	unlock := wg.lockServer(server)
+	defer unlock()
+
 	// Устанавливаем соединение SSH с сервером
 	client, err := connectToServer(server)
 	if err != nil {
@@ -227,6 +250,9 @@ func (wg *WireguardManager) BlockClient(server *models.Server, configFilePath st
 
 	log.Printf("Блокировка доступа для клиента %s (файл: %s)", clientName, configFilePath)
 
+	unlock := wg.lockServer(server)
+	defer unlock()
+
 	// Устанавливаем соединение SSH с сервером
 	client, err := connectToServer(server)
 	if err != nil {
@@ -269,6 +295,9 @@ func (wg *WireguardManager) UnblockClient(server *models.Server, configFilePath
 
 	log.Printf("Разблокировка доступа для клиента %s (файл: %s)", clientName, configFilePath)
 
+	unlock := wg.lockServer(server)
+	defer unlock()
+
 	// Устанавливаем соединение SSH с сервером
 	client, err := connectToServer(server)
 	if err != nil {
@@ -332,6 +361,97 @@ func (wg *WireguardManager) IsClientBlocked(server *models.Server, configFilePat
 	return count > 0, nil
 }
 
+// CheckConnection проверяет, что к серверу можно подключиться по SSH и у пользователя есть права sudo
+func (wg *WireguardManager) CheckConnection(server *models.Server) error {
+	client, err := connectToServer(server)
+	if err != nil {
+		return err
+	}
+	return client.Close()
+}
+
+// UpdateClientEndpoint заменяет адрес сервера в файле конфигурации клиента, сохраняя порт Wireguard и ключи.
+// Используется после смены IP-адреса сервера: пир на сервере остается прежним.
+func (wg *WireguardManager) UpdateClientEndpoint(configFilePath, host string) error {
+	if configFilePath == "" {
+		return fmt.Errorf("empty config file path")
+	}
+
+	content, err := ioutil.ReadFile(configFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read client config file: %w", err)
+	}
+
+	if strings.Contains(host, ":") && !strings.HasPrefix(host, "[") {
+		host = "[" + host + "]" // IPv6-адрес в Endpoint записывается в квадратных скобках
+	}
+
+	lines := strings.Split(string(content), "\n")
+	updated := false
+	for i, line := range lines {
+		key, value, found := strings.Cut(line, "=")
+		if !found || strings.TrimSpace(key) != "Endpoint" {
+			continue
+		}
+
+		port := "51820"
+		if index := strings.LastIndex(value, ":"); index >= 0 {
+			port = strings.TrimSpace(value[index+1:])
+		}
+		lines[i] = fmt.Sprintf("Endpoint = %s:%s", host, port)
+		updated = true
+	}
+	if !updated {
+		return fmt.Errorf("endpoint not found in client config file: %s", configFilePath)
+	}
+
+	err = ioutil.WriteFile(configFilePath, []byte(strings.Join(lines, "\n")), 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write client config file: %w", err)
+	}
+
+	return nil
+}
+
+// GetLastHandshake возвращает время последнего рукопожатия клиента с сервером (nil, если клиент еще не подключался).
+// Публичный ключ клиента вычисляется из приватного ключа в файле конфигурации.
+func (wg *WireguardManager) GetLastHandshake(server *models.Server, configFilePath string) (*time.Time, error) {
+	publicKey, err := clientPublicKey(configFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := connectToServer(server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to server: %w", err)
+	}
+	defer client.Close()
+
+	output, err := executeCommand(client, "wg show wg0 latest-handshakes")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest handshakes: %w", err)
+	}
+
+	// Формат вывода: "<публичный ключ>\t<время в секундах Unix>" для каждого пира
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] != publicKey {
+			continue
+		}
+		seconds, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse handshake time: %w", err)
+		}
+		if seconds == 0 {
+			return nil, nil
+		}
+		handshake := time.Unix(seconds, 0)
+		return &handshake, nil
+	}
+
+	return nil, nil
+}
+
 // Вспомогательные функции
 
 // connectToServer устанавливает SSH соединение с сервером
@@ -647,6 +767,38 @@ func writeFileToServer(client *ssh.Client, path, content string) error {
 	return nil
 }
 
+// clientPublicKey вычисляет публичный ключ клиента из приватного ключа в файле конфигурации
+func clientPublicKey(configFilePath string) (string, error) {
+	if configFilePath == "" {
+		return "", fmt.Errorf("empty config file path")
+	}
+
+	content, err := ioutil.ReadFile(configFilePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read client config file: %w", err)
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		key, value, found := strings.Cut(line, "=")
+		if !found || strings.TrimSpace(key) != "PrivateKey" {
+			continue
+		}
+
+		// Ключи Wireguard в base64 заканчиваются на "=", поэтому значение берется после первого "="
+		privateKey, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+		if err != nil || len(privateKey) != curve25519.ScalarSize {
+			return "", fmt.Errorf("invalid private key in client config file: %s", configFilePath)
+		}
+		publicKey, err := curve25519.X25519(privateKey, curve25519.Basepoint)
+		if err != nil {
+			return "", fmt.Errorf("failed to derive public key: %w", err)
+		}
+		return base64.StdEncoding.EncodeToString(publicKey), nil
+	}
+
+	return "", fmt.Errorf("private key not found in client config file: %s", configFilePath)
+}
+
 // generateClientKeys генерирует ключи для клиента
 func generateClientKeys(client *ssh.Client) (string, string, error) {
 	// Генерируем приватный ключ
@@ -0,0 +1,187 @@
+package webhook
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// secretHeader - заголовок, в котором Telegram передает секрет, указанный при регистрации вебхука
+const secretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// maxUpdateSize - максимальный размер тела запроса с обновлением
+const maxUpdateSize = 1 << 20
+
+// readHeaderTimeout - сколько ждать заголовков запроса, чтобы медленные клиенты не занимали соединения
+const readHeaderTimeout = 10 * time.Second
+
+// Server принимает обновления Telegram по HTTP и передает их в канал Updates.
+// Запрос подтверждается только после того, как обновление принято в канал,
+// поэтому при остановке сервера неподтвержденные обновления Telegram отправит повторно.
+type Server struct {
+	bot      *tgbotapi.BotAPI
+	secret   string
+	path     string
+	certFile string
+	keyFile  string
+	server   *http.Server
+	updates  chan tgbotapi.Update
+
+	done   chan struct{} // Закрывается в начале остановки: ожидающие запросы не передают обновления
+	mu     sync.RWMutex  // Защищает закрытие канала updates от одновременной отправки в него
+	closed bool
+}
+
+// New создает сервер вебхука. Путь обработчика берется из публичного адреса вебхука.
+// Если certFile и keyFile заданы, сервер работает по HTTPS, иначе по HTTP за обратным прокси.
+func New(bot *tgbotapi.BotAPI, publicURL, listen, secret, certFile, keyFile string) (*Server, error) {
+	parsed, err := url.Parse(publicURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook url: %w", err)
+	}
+	if parsed.Scheme != "https" {
+		return nil, fmt.Errorf("invalid webhook url %q: telegram requires https", publicURL)
+	}
+
+	path := parsed.Path
+	if path == "" {
+		path = "/"
+	}
+
+	s := &Server{
+		bot:      bot,
+		secret:   secret,
+		path:     path,
+		certFile: certFile,
+		keyFile:  keyFile,
+		updates:  make(chan tgbotapi.Update),
+		done:     make(chan struct{}),
+	}
+	s.server = &http.Server{
+		Addr:              listen,
+		Handler:           s,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+	return s, nil
+}
+
+// Updates возвращает канал обновлений. Канал закрывается после остановки сервера.
+func (s *Server) Updates() <-chan tgbotapi.Update {
+	return s.updates
+}
+
+// Register регистрирует вебхук в Telegram с секретом для заголовка X-Telegram-Bot-Api-Secret-Token.
+// Если uploadCertificate задан, Telegram получает сертификат сервера (нужно для самоподписанного сертификата).
+func (s *Server) Register(publicURL string, uploadCertificate bool) error {
+	// WebhookConfig из tgbotapi не поддерживает secret_token, поэтому запрос формируется вручную
+	params := tgbotapi.Params{
+		"url":          publicURL,
+		"secret_token": s.secret,
+	}
+
+	var err error
+	if uploadCertificate {
+		if s.certFile == "" {
+			return fmt.Errorf("failed to set webhook: certificate upload requires cert_file")
+		}
+		_, err = s.bot.UploadFiles("setWebhook", params, []tgbotapi.RequestFile{
+			{Name: "certificate", Data: tgbotapi.FilePath(s.certFile)},
+		})
+	} else {
+		_, err = s.bot.MakeRequest("setWebhook", params)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set webhook: %w", err)
+	}
+	return nil
+}
+
+// Start начинает принимать соединения. Ошибка занятого адреса возвращается сразу,
+// остальные ошибки сервера после запуска только логируются.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.server.Addr, err)
+	}
+
+	go func() {
+		var err error
+		if s.certFile != "" {
+			err = s.server.ServeTLS(listener, s.certFile, s.keyFile)
+		} else {
+			err = s.server.Serve(listener)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Ошибка HTTP-сервера вебхука: %v", err)
+		}
+	}()
+	return nil
+}
+
+// Shutdown перестает принимать новые запросы, дожидается завершения текущих и закрывает канал обновлений.
+// Обновления, которые не успели попасть в канал, не подтверждаются, и Telegram отправит их повторно.
+func (s *Server) Shutdown(ctx context.Context) error {
+	close(s.done)
+	err := s.server.Shutdown(ctx)
+
+	s.mu.Lock()
+	s.closed = true
+	close(s.updates)
+	s.mu.Unlock()
+
+	return err
+}
+
+// ServeHTTP проверяет секрет запроса и передает обновление в канал Updates
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != s.path {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := r.Header.Get(secretHeader)
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.secret)) != 1 {
+		log.Printf("Запрос к вебхуку с неверным секретом от %s", r.RemoteAddr)
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	var update tgbotapi.Update
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxUpdateSize)).Decode(&update); err != nil {
+		log.Printf("Некорректное обновление в запросе к вебхуку: %v", err)
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	}
+
+	// Если обновление не принято, Telegram не получит подтверждения и отправит его повторно
+	select {
+	case s.updates <- update:
+		w.WriteHeader(http.StatusOK)
+	case <-s.done:
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+	case <-r.Context().Done():
+	}
+}
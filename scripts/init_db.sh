@@ -2,6 +2,9 @@
 
 # Запускаем скрипт инициализации базы данных
 echo "Инициализация базы данных..."
+go run ./cmd/bot -config config.yaml migrate up || exit 1
+
+# Добавляем тестовые данные
 psql -U postgres -f scripts/init_db.sql
 
 echo "База данных успешно инициализирована!" 
\ No newline at end of file
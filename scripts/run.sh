@@ -2,7 +2,11 @@
 
 # Проверка обновлений и сборка
 echo "Собираем проект..."
-go build -o bin/vpnbot cmd/bot/main.go
+go build -o bin/vpnbot ./cmd/bot
+
+# Применение миграций базы данных
+echo "Применяем миграции..."
+./bin/vpnbot -config config.yaml migrate up || exit 1
 
 # Запуск бота
 echo "Запускаем бота..."